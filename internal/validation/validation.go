@@ -54,6 +54,10 @@ func Validate(ds *types.DimensionSet) error {
 			if err := validateHierarchicalDim(&dim); err != nil {
 				return err
 			}
+		case types.Set:
+			if err := validateSetDim(&dim); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf("invalid dimension type %d for %s", dim.Type, dim.Name)
 		}
@@ -112,6 +116,15 @@ func validateEnumeratedDim(dim *types.Dimension, prefixesSeen map[string]string)
 		prefixesSeen[prefix] = dim.Name
 	}
 
+	// Transition rules must name values of the dimension
+	for _, t := range dim.Transitions {
+		for _, value := range []string{t.From, t.To} {
+			if value != types.AnyValue && !dim.IsValid(value) {
+				return fmt.Errorf("dimension %s: transition '%s' uses invalid value '%s'", dim.Name, t, value)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -134,6 +147,50 @@ func validateHierarchicalDim(dim *types.Dimension) error {
 	if len(dim.Prefixes) > 0 {
 		return fmt.Errorf("dimension %s: hierarchical dimensions should not have prefixes", dim.Name)
 	}
+	if dim.IsComputed() {
+		return fmt.Errorf("dimension %s: hierarchical dimensions cannot be computed", dim.Name)
+	}
+	if dim.HasTransitions() || dim.TrackChanges {
+		return fmt.Errorf("dimension %s: hierarchical dimensions cannot have transitions", dim.Name)
+	}
+
+	return nil
+}
+
+// validateSetDim validates a set dimension
+func validateSetDim(dim *types.Dimension) error {
+	// Values are optional: without them any member is accepted
+	valuesSeen := make(map[string]bool)
+	for _, value := range dim.Values {
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("dimension %s: values cannot be empty", dim.Name)
+		}
+		// Members are also written as comma-separated lists
+		if strings.Contains(value, ",") {
+			return fmt.Errorf("dimension %s: value '%s' cannot contain a comma", dim.Name, value)
+		}
+		if valuesSeen[value] {
+			return fmt.Errorf("dimension %s: duplicate value '%s'", dim.Name, value)
+		}
+		valuesSeen[value] = true
+	}
+
+	// Sets never appear in SimpleIDs and have no single value
+	if len(dim.Prefixes) > 0 {
+		return fmt.Errorf("dimension %s: set dimensions should not have prefixes", dim.Name)
+	}
+	if dim.DefaultValue != "" {
+		return fmt.Errorf("dimension %s: set dimensions should not have a default value", dim.Name)
+	}
+	if dim.RefField != "" {
+		return fmt.Errorf("dimension %s: set dimensions should not have a RefField", dim.Name)
+	}
+	if dim.IsComputed() {
+		return fmt.Errorf("dimension %s: set dimensions cannot be computed", dim.Name)
+	}
+	if dim.HasTransitions() || dim.TrackChanges {
+		return fmt.Errorf("dimension %s: set dimensions cannot have transitions", dim.Name)
+	}
 
 	return nil
 }
@@ -1,12 +1,17 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/query"
 	"github.com/arthur-debert/nanostore/nanostore/store"
 	"github.com/arthur-debert/nanostore/types"
 )
@@ -62,7 +67,7 @@ type RuntimeDebugStats struct {
 // StoreStats contains statistical information about store contents
 type StoreStats struct {
 	TotalDocuments        int                       // Total number of documents
-	DimensionDistribution map[string]map[string]int // Distribution of values per dimension
+	DimensionDistribution map[string]map[string]int // Distribution of values per dimension, per member for sets
 	DataFieldCoverage     map[string]float64        // Percentage coverage of data fields
 	DataFieldDistribution map[string]map[string]int // Value distribution for data fields
 }
@@ -113,8 +118,8 @@ type FieldUsageStats struct {
 // DimensionUsageInfo contains usage statistics for a dimension field
 type DimensionUsageInfo struct {
 	DimensionName string         // Name of the dimension
-	Type          string         // Type of dimension (enumerated/hierarchical)
-	ValueCounts   map[string]int // Count of each value across all documents
+	Type          string         // Type of dimension (enumerated/hierarchical/set)
+	ValueCounts   map[string]int // Count of each value across all documents, per member for sets
 	NonEmptyCount int            // Number of documents with non-empty values
 }
 
@@ -253,6 +258,9 @@ type Store[T any] struct {
 	store  store.Store      // Underlying nanostore implementation
 	config nanostore.Config // Generated configuration from struct tags
 	typ    reflect.Type     // Cached type information for T
+	now    func() time.Time // Clock for relative date expressions, set by SetTimeFunc
+
+	validations []fieldValidation // Rules from the validate tags of data fields
 }
 
 // New creates a new Store for the given type T, automatically generating
@@ -282,6 +290,21 @@ type Store[T any] struct {
 //	- First part: Reference field name in document dimensions
 //	- "ref" flag: Indicates this is a hierarchical reference field
 //
+// ## Data Field Validation
+//
+//	Assignee string `validate:"required,max=50"`
+//	- validate: Rules checked by Create, the Update methods, Import and
+//	  ValidateStoreIntegrity (required, min, max, regex, oneof)
+//
+// ## Unique Data Fields
+//
+//	Slug    string `unique:"true"`
+//	JiraKey string `unique:"external,parent"`
+//	- unique: "true" or a group name shared by the fields of a compound
+//	  constraint; ",parent" only requires siblings to differ. Checked by
+//	  Create, the Update methods and Import; see GetBy and Upsert
+//
+
 // # Configuration Generation Process
 //
 // 1. **Field Enumeration**: Iterates through all struct fields using reflection
@@ -333,6 +356,12 @@ func New[T any](filePath string) (*Store[T], error) {
 		return nil, fmt.Errorf("failed to generate config: %w", err)
 	}
 
+	// Parse validate tags of data fields
+	validations, err := parseValidationRules(typ)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate config: %w", err)
+	}
+
 	// Create underlying store
 	store, err := store.New(filePath, &config)
 	if err != nil {
@@ -340,9 +369,10 @@ func New[T any](filePath string) (*Store[T], error) {
 	}
 
 	return &Store[T]{
-		store:  store,
-		config: config,
-		typ:    typ,
+		store:       store,
+		config:      config,
+		typ:         typ,
+		validations: validations,
 	}, nil
 }
 
@@ -401,8 +431,9 @@ func New[T any](filePath string) (*Store[T], error) {
 //
 // **Body Preservation**:
 // - Body content from embedded Document.Body is always preserved
-// - This eliminates the need for workarounds like two-phase create+update operations
+// - The body is written with the document, never by a follow-up update
 // - Empty body fields are ignored (not stored as empty strings)
+// - CreateDocument also takes an explicit body, parent and creation time
 //
 // **Backward Compatibility**:
 // - Existing two-phase workarounds continue to work unchanged
@@ -454,44 +485,29 @@ func New[T any](filePath string) (*Store[T], error) {
 //	childID, err := store.Create("Subtask of feature X", subtask)
 //	// childID might be "1.1" (first child of parent "1")
 func (ts *Store[T]) Create(title string, data *T) (string, error) {
+	if data == nil {
+		return "", fmt.Errorf("data cannot be nil")
+	}
+
+	// The title parameter takes precedence over the struct title, and the
+	// body is written with the document rather than by a follow-up update
+	return ts.CreateDocument(NewDoc[T]{Title: title, Data: data})
+}
+
+// createDimensions marshals typed data into the dimensions of a new document,
+// with data fields under the "_data." prefix
+func (ts *Store[T]) createDimensions(data *T) (map[string]interface{}, error) {
 	dimensions, extraData, err := MarshalDimensions(data)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal dimensions: %w", err)
+		return nil, fmt.Errorf("failed to marshal dimensions: %w", err)
 	}
+	ts.omitComputed(dimensions)
 
 	// Store extra data in dimensions with a special prefix
 	for key, value := range extraData {
 		dimensions["_data."+key] = value
 	}
-
-	// Extract Document fields (title, body) from the embedded Document
-	structTitle, structBody, hasDocument := extractDocumentFields(data)
-
-	// Determine final title: use parameter if non-empty, otherwise use struct title
-	finalTitle := title
-	if title == "" && hasDocument && structTitle != "" {
-		finalTitle = structTitle
-	}
-
-	// Add the document with proper title and body handling
-	uuid, err := ts.store.Add(finalTitle, dimensions)
-	if err != nil {
-		return "", err
-	}
-
-	// If we have body content from the struct, update the document to include it
-	if hasDocument && structBody != "" {
-		// Update the document with the body content
-		// The Add method doesn't handle body content directly, so we need a follow-up update
-		err = ts.store.Update(uuid, types.UpdateRequest{
-			Body: &structBody,
-		})
-		if err != nil {
-			return "", fmt.Errorf("failed to set body content: %w", err)
-		}
-	}
-
-	return uuid, nil
+	return dimensions, nil
 }
 
 // Get retrieves a document by ID and unmarshals it into the typed structure.
@@ -625,12 +641,18 @@ func (ts *Store[T]) Get(id string) (*T, error) {
 // This helper eliminates ~100 lines of code duplication across Update methods
 // by centralizing the complex struct-to-update-request conversion logic
 func (ts *Store[T]) buildUpdateRequest(data *T) (nanostore.UpdateRequest, error) {
+	// Data fields are written as a whole, so every rule applies to updates too
+	if err := ts.validateData(data); err != nil {
+		return nanostore.UpdateRequest{}, err
+	}
+
 	// MarshalDimensionsForUpdate preserves zero values for field clearing in updates
 	// This is where struct tag parsing happens and values are validated
 	dimensions, extraData, err := MarshalDimensionsForUpdate(data)
 	if err != nil {
 		return nanostore.UpdateRequest{}, fmt.Errorf("failed to marshal dimensions: %w", err)
 	}
+	ts.omitComputed(dimensions)
 
 	// Store extra data in dimensions with the "_data." prefix
 	// This preserves fields that don't have dimension tags but need to be stored
@@ -876,7 +898,7 @@ func (ts *Store[T]) DeleteByDimension(filters map[string]interface{}) (int, erro
 // Returns the number of documents deleted.
 func (ts *Store[T]) DeleteWhere(whereClause string, args ...interface{}) (int, error) {
 	// Pass through to underlying store which implements the secure WHERE clause evaluation
-	return ts.store.DeleteWhere(whereClause, args...)
+	return ts.store.DeleteWhere(whereClause, filterValues(args)...)
 }
 
 // UpdateByDimension updates all documents matching the given dimension filters.
@@ -894,7 +916,11 @@ func (ts *Store[T]) UpdateByDimension(filters map[string]interface{}, data *T) (
 		return 0, err
 	}
 
-	return ts.store.UpdateByDimension(filters, req)
+	converted := make(map[string]interface{}, len(filters))
+	for name, value := range filters {
+		converted[name] = filterValue(value)
+	}
+	return ts.store.UpdateByDimension(converted, req)
 }
 
 // UpdateWhere updates all documents matching a custom WHERE clause.
@@ -929,7 +955,7 @@ func (ts *Store[T]) UpdateWhere(whereClause string, data *T, args ...interface{}
 		return 0, err
 	}
 
-	return ts.store.UpdateWhere(whereClause, req, args...)
+	return ts.store.UpdateWhere(whereClause, req, filterValues(args)...)
 }
 
 // UpdateByUUIDs updates multiple documents by their UUIDs in a single operation.
@@ -992,6 +1018,33 @@ func (ts *Store[T]) ResolveUUID(simpleID string) (string, error) {
 	return ts.store.ResolveUUID(simpleID)
 }
 
+// ResolveUUIDInView converts a simple ID listed through a lens (see Query.Lens)
+// to a UUID
+func (ts *Store[T]) ResolveUUIDInView(simpleID string, view *types.CanonicalView) (string, error) {
+	return ts.store.ResolveUUIDInView(simpleID, view)
+}
+
+// Compact renumbers SimpleID positions from 1 in every partition, closing
+// the gaps left by deleted documents. It only applies to stores whose ID
+// scheme keeps stable positions (`ids:"positions=stable"`); other stores
+// number positions this way already.
+func (ts *Store[T]) Compact() error {
+	return ts.store.Compact()
+}
+
+// CanonicalView returns a copy of the store's canonical view: the dimension
+// values omitted from SimpleIDs. Set it with `canonical` struct tags; by
+// default it holds each dimension's default value.
+//
+//	type Task struct {
+//	    nanostore.Document
+//	    Status string `values:"pending,active,done" default:"pending" canonical:"active" prefix:"pending=p,done=d"`
+//	}
+func (ts *Store[T]) CanonicalView() *types.CanonicalView {
+	view := ts.config.GetCanonicalView()
+	return types.NewCanonicalView(append([]types.CanonicalFilter(nil), view.Filters...)...)
+}
+
 // List returns documents based on the provided ListOptions, converted to typed structs
 // This provides direct access to the underlying store's List functionality while maintaining type safety
 func (ts *Store[T]) List(opts types.ListOptions) ([]T, error) {
@@ -1050,8 +1103,8 @@ func (ts *Store[T]) validateAndTransformListOptions(opts types.ListOptions) (typ
 					return types.ListOptions{}, fmt.Errorf("invalid field in OrderBy: %w", err)
 				}
 
-				// Transform to snake_case for storage
-				snakeFieldName := normalizeFieldName(fieldName)
+				// Transform to the stored name
+				snakeFieldName := dataFieldKey(typ, fieldName)
 				transformedOrderBy[i].Column = "_data." + snakeFieldName
 			}
 			// Note: Non-data fields (like "created_at", "title") are passed through unchanged
@@ -1074,8 +1127,8 @@ func (ts *Store[T]) validateAndTransformListOptions(opts types.ListOptions) (typ
 					return types.ListOptions{}, fmt.Errorf("invalid field in Filters: %w", err)
 				}
 
-				// Transform to snake_case for storage
-				snakeFieldName := normalizeFieldName(fieldName)
+				// Transform to the stored name
+				snakeFieldName := dataFieldKey(typ, fieldName)
 				transformedFilters["_data."+snakeFieldName] = value
 			} else {
 				// Non-data filters are passed through unchanged
@@ -1089,10 +1142,19 @@ func (ts *Store[T]) validateAndTransformListOptions(opts types.ListOptions) (typ
 	return transformedOpts, nil
 }
 
-// validateDataFieldName checks if a field name (either snake_case or PascalCase) exists in the struct
+// validateDataFieldName checks if a field name (either snake_case or PascalCase) exists in the struct.
+// Dot paths are checked segment by segment through nested structs, slices and maps.
 func (ts *Store[T]) validateDataFieldName(typ reflect.Type, fieldName string) error {
+	// Dot paths such as "address.city" reach into nested data fields
+	fieldName, path, isPath := strings.Cut(fieldName, ".")
+
 	// Try to find the field by name (supports both conventions)
-	if _, found := findFieldByName(typ, fieldName); found {
+	if field, found := findFieldByName(typ, fieldName); found {
+		if isPath {
+			if err := validateDataPath(field.Type, path); err != nil {
+				return fmt.Errorf("invalid path in field '%s': %w", fieldName, err)
+			}
+		}
 		return nil
 	}
 
@@ -1106,28 +1168,21 @@ func (ts *Store[T]) validateDataFieldName(typ reflect.Type, fieldName string) er
 func (ts *Store[T]) getAvailableDataFields(typ reflect.Type) []string {
 	var fields []string
 
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-
-		// Skip unexported fields
-		if !field.IsExported() {
-			continue
-		}
-
+	for _, field := range storedFields(typ) {
 		// Skip embedded Document field
 		if field.Anonymous && field.Type == reflect.TypeOf(nanostore.Document{}) {
 			continue
 		}
 
-		// Skip dimension fields (fields with dimension or values tags)
+		// Skip dimension fields (fields with dimension, values or multi tags)
 		dimTag := field.Tag.Get("dimension")
 		valuesTag := field.Tag.Get("values")
-		if dimTag != "" || valuesTag != "" {
+		if dimTag != "" || valuesTag != "" || isSetField(field) {
 			continue
 		}
 
-		// Add both snake_case and PascalCase versions for clarity
-		snakeName := normalizeFieldName(field.Name)
+		// Add both the stored name and PascalCase versions for clarity
+		snakeName := dataFieldName(field)
 		fields = append(fields, snakeName, field.Name)
 	}
 
@@ -1199,7 +1254,7 @@ func (ts *Store[T]) AddRaw(title string, dimensions map[string]interface{}) (str
 	for key, value := range dimensions {
 		if strings.HasPrefix(key, "_data.") {
 			fieldName := strings.TrimPrefix(key, "_data.")
-			normalizedFieldName := normalizeFieldName(fieldName)
+			normalizedFieldName := dataFieldKey(ts.typ, fieldName)
 			normalizedDimensions["_data."+normalizedFieldName] = value
 		} else {
 			normalizedDimensions[key] = value
@@ -1481,6 +1536,9 @@ func (ts *Store[T]) SetTimeFunc(timeFunc func() time.Time) error {
 	// Delegate to underlying store's SetTimeFunc
 	// The underlying store handles the actual time function replacement
 	testStore.SetTimeFunc(timeFunc)
+
+	// Relative date expressions in queries ("today", "-7d") use the same clock
+	ts.now = timeFunc
 	return nil
 }
 
@@ -1815,7 +1873,7 @@ func (ts *Store[T]) GetStoreStats() (*StoreStats, error) {
 
 	// Initialize dimension distribution maps
 	for _, dim := range config.Dimensions {
-		if dim.Type == nanostore.Enumerated {
+		if dim.Type == nanostore.Enumerated || dim.Type == nanostore.Set {
 			stats.DimensionDistribution[dim.Name] = make(map[string]int)
 		}
 	}
@@ -1826,13 +1884,17 @@ func (ts *Store[T]) GetStoreStats() (*StoreStats, error) {
 
 	// Analyze each document
 	for _, doc := range allDocs {
-		// Analyze enumerated dimensions
+		// Analyze enumerated dimensions, and set dimensions per member
 		for _, dim := range config.Dimensions {
 			if dim.Type == nanostore.Enumerated {
 				if value, exists := doc.Dimensions[dim.Name]; exists {
 					valueStr := fmt.Sprintf("%v", value)
 					stats.DimensionDistribution[dim.Name][valueStr]++
 				}
+			} else if dim.Type == nanostore.Set {
+				for _, member := range types.SetMembers(doc.Dimensions[dim.Name]) {
+					stats.DimensionDistribution[dim.Name][member]++
+				}
 			}
 		}
 
@@ -1942,6 +2004,24 @@ func (ts *Store[T]) ValidateStoreIntegrity() (*IntegrityReport, error) {
 		Warnings:       []IntegrityWarning{},
 	}
 
+	// Check that transition rules only name known values
+	for _, dim := range config.Dimensions {
+		known := make(map[string]bool, len(dim.Values))
+		for _, value := range dim.Values {
+			known[value] = true
+		}
+		for _, t := range dim.Transitions {
+			for _, value := range []string{t.From, t.To} {
+				if value != types.AnyValue && !known[value] {
+					report.Errors = append(report.Errors, IntegrityError{
+						Type:    "INVALID_TRANSITION_RULE",
+						Message: fmt.Sprintf("dimension '%s': transition '%s' uses unknown value '%s' (allowed: %v)", dim.Name, t, value, dim.Values),
+					})
+				}
+			}
+		}
+	}
+
 	// Track UUIDs for uniqueness validation
 	seenUUIDs := make(map[string]bool)
 
@@ -1992,6 +2072,52 @@ func (ts *Store[T]) ValidateStoreIntegrity() (*IntegrityReport, error) {
 			}
 		}
 
+		// Check the change times of tracked dimensions
+		for _, dim := range config.Dimensions {
+			if !dim.TrackChanges {
+				continue
+			}
+			key := types.ChangedAtKey(dim.Name)
+			raw, exists := doc.Dimensions[key]
+			if !exists || raw == nil {
+				report.Warnings = append(report.Warnings, IntegrityWarning{
+					Type:       "MISSING_TRANSITION_TIMESTAMP",
+					DocumentID: doc.UUID,
+					Message:    fmt.Sprintf("Document %d: dimension '%s' has no change time in '%s'", i, dim.Name, key),
+				})
+				continue
+			}
+			changedAt, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", raw))
+			if err != nil {
+				report.Errors = append(report.Errors, IntegrityError{
+					Type:       "TRANSITION_TIMESTAMP_INCONSISTENT",
+					DocumentID: doc.UUID,
+					Message:    fmt.Sprintf("Document %d: '%s' is not a valid time: %v", i, key, raw),
+				})
+				continue
+			}
+			// Stamps are stored with second precision
+			if changedAt.Before(doc.CreatedAt.Truncate(time.Second)) || changedAt.After(doc.UpdatedAt) {
+				report.Errors = append(report.Errors, IntegrityError{
+					Type:       "TRANSITION_TIMESTAMP_INCONSISTENT",
+					DocumentID: doc.UUID,
+					Message:    fmt.Sprintf("Document %d: dimension '%s' changed at %v, outside its lifetime (%v to %v)", i, dim.Name, changedAt, doc.CreatedAt, doc.UpdatedAt),
+				})
+			}
+		}
+
+		// Check data fields against their validate tags
+		var validationErr *ValidationError
+		if err := ts.validateDocument(doc); errors.As(err, &validationErr) {
+			for _, fieldErr := range validationErr.Fields {
+				report.Errors = append(report.Errors, IntegrityError{
+					Type:       "INVALID_DATA_FIELD",
+					DocumentID: doc.UUID,
+					Message:    fmt.Sprintf("Document %d: field '%s' %s", i, fieldErr.Field, fieldErr.Message),
+				})
+			}
+		}
+
 		// Check for missing required metadata
 		if doc.UUID == "" {
 			report.Errors = append(report.Errors, IntegrityError{
@@ -2328,6 +2454,17 @@ func (ts *Store[T]) GetFieldUsageStats() (*FieldUsageStats, error) {
 
 		// Analyze dimensions
 		for dimName, dimUsage := range stats.DimensionUsage {
+			// Set dimensions count each of their members
+			if dimUsage.Type == nanostore.Set.String() {
+				if members := types.SetMembers(doc.Dimensions[dimName]); len(members) > 0 {
+					dimUsage.NonEmptyCount++
+					for _, member := range members {
+						dimUsage.ValueCounts[member]++
+					}
+					stats.DimensionUsage[dimName] = dimUsage
+				}
+				continue
+			}
 			if value, exists := doc.Dimensions[dimName]; exists && value != nil {
 				valueStr := fmt.Sprintf("%v", value)
 				if valueStr != "" {
@@ -2447,16 +2584,14 @@ func (ts *Store[T]) GetTypeSchema() (*TypeSchema, error) {
 	}
 
 	// Analyze each field
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-
+	for _, field := range storedFields(typ) {
 		// Skip embedded Document field
 		if field.Anonymous && field.Type == reflect.TypeOf(nanostore.Document{}) {
 			continue
 		}
 
 		// Check if this is a dimension field
-		fieldLowerName := strings.ToLower(field.Name)
+		fieldLowerName := dimensionFieldName(field)
 		if dimConfig, isDimension := dimensionMap[fieldLowerName]; isDimension {
 			// This is a dimension field
 			dimField := DimensionFieldInfo{
@@ -2495,6 +2630,8 @@ type Query[T any] struct {
 	store      store.Store       // Underlying store for query execution
 	typedStore *Store[T]         // Parent Store for validation
 	options    types.ListOptions // Accumulated query options
+	predicates []func(T) bool    // In-process filters added with Filter()
+	selected   map[string]bool   // Projected fields (snake_case), nil for all fields
 }
 
 // getDimensionConfig returns the dimension configuration for type T
@@ -2558,10 +2695,10 @@ func (tq *Query[T]) validateDataFieldReferences() error {
 		}
 
 		if isDataField {
-			normalizedFieldName, err := validateDataFieldName(fieldName, validFields)
-			if err != nil {
+			if _, err := validateDataFieldName(fieldName, validFields); err != nil {
 				return fmt.Errorf("invalid filter field: %w", err)
 			}
+			normalizedFieldName := dataFieldKey(tq.typedStore.typ, fieldName)
 
 			// If the field name was normalized, update the filter key
 			if normalizedFieldName != fieldName {
@@ -2576,10 +2713,10 @@ func (tq *Query[T]) validateDataFieldReferences() error {
 	for i, orderClause := range tq.options.OrderBy {
 		if strings.HasPrefix(orderClause.Column, "_data.") {
 			fieldName := strings.TrimPrefix(orderClause.Column, "_data.")
-			correctFieldName, err := validateDataFieldName(fieldName, validFields)
-			if err != nil {
+			if _, err := validateDataFieldName(fieldName, validFields); err != nil {
 				return fmt.Errorf("invalid order by field: %w", err)
 			}
+			correctFieldName := dataFieldKey(tq.typedStore.typ, fieldName)
 
 			// If the field name case was corrected, update the order clause
 			if correctFieldName != fieldName {
@@ -2918,9 +3055,9 @@ func (tq *Query[T]) Data(field string, value interface{}) *Query[T] {
 		return tq
 	}
 
-	// Transform to snake_case for storage
-	snakeField := normalizeFieldName(field)
-	tq.options.Filters["_data."+snakeField] = value
+	// Transform to the stored name
+	snakeField := dataFieldKey(typ, field)
+	tq.options.Filters["_data."+snakeField] = filterValue(value)
 	return tq
 }
 
@@ -2952,9 +3089,9 @@ func (tq *Query[T]) DataIn(field string, values ...interface{}) *Query[T] {
 		return tq
 	}
 
-	// Transform to snake_case for storage
-	snakeField := normalizeFieldName(field)
-	tq.options.Filters["_data."+snakeField] = values
+	// Transform to the stored name
+	snakeField := dataFieldKey(typ, field)
+	tq.options.Filters["_data."+snakeField] = filterValue(values)
 	return tq
 }
 
@@ -2989,9 +3126,9 @@ func (tq *Query[T]) DataNot(field string, value interface{}) *Query[T] {
 		return tq
 	}
 
-	// Transform to snake_case for storage - use snake_case in special filter key
-	snakeField := normalizeFieldName(field)
-	tq.options.Filters["__data_not__"+snakeField] = value
+	// Transform to the stored name, used in the special filter key
+	snakeField := dataFieldKey(typ, field)
+	tq.options.Filters["__data_not__"+snakeField] = filterValue(value)
 	return tq
 }
 
@@ -3019,9 +3156,9 @@ func (tq *Query[T]) DataNotIn(field string, values ...interface{}) *Query[T] {
 		return tq
 	}
 
-	// Transform to snake_case for storage - use snake_case in special filter key
-	snakeField := normalizeFieldName(field)
-	tq.options.Filters["__data_not_in__"+snakeField] = values
+	// Transform to the stored name, used in the special filter key
+	snakeField := dataFieldKey(typ, field)
+	tq.options.Filters["__data_not_in__"+snakeField] = filterValue(values)
 	return tq
 }
 
@@ -3033,9 +3170,11 @@ func (tq *Query[T]) DataNotIn(field string, values ...interface{}) *Query[T] {
 //
 // The whereClause should NOT include the "WHERE" keyword itself.
 // Use SQL column names that match the underlying schema:
-// - Document fields: uuid, simple_id, title, body, created_at, updated_at
-// - Dimension fields: Use dimension names directly (status, priority, etc.)
-// - Data fields: Use _data.field_name format
+//   - Document fields: uuid, simple_id, title, body, created_at, updated_at
+//   - Dimension fields: Use dimension names directly (status, priority, etc.)
+//   - Data fields: Use _data.field_name format
+//   - Virtual fields: depth, child_count, descendant_count, has_children, is_root
+//     and parent.<field> (e.g. parent.status), computed from the hierarchy
 //
 // Performance Note: This may be slower than dimension-based filtering since
 // it requires post-processing of all matching documents from other filters.
@@ -3047,6 +3186,11 @@ func (tq *Query[T]) DataNotIn(field string, values ...interface{}) *Query[T] {
 //	    Where("created_at > ?", time.Now().AddDate(0, 0, -7)).
 //	    Find()
 //
+//	// The same with a relative date expression (see store.ParseDateExpression)
+//	results, err := store.Query().
+//	    Where("created_at > ?", "-7d").
+//	    Find()
+//
 //	// Find documents with title containing text (case-insensitive)
 //	results, err := store.Query().
 //	    Where("LOWER(title) LIKE ?", "%important%").
@@ -3076,13 +3220,126 @@ func (tq *Query[T]) DataNotIn(field string, values ...interface{}) *Query[T] {
 // user parameters are considered. Parameter substitution happens safely after parsing.
 func (tq *Query[T]) Where(whereClause string, args ...interface{}) *Query[T] {
 	// Use special filter key to mark for post-processing
-	tq.options.Filters["__where_clause__"] = map[string]interface{}{
+	tq.options.Filters[store.WhereFilterKey] = map[string]interface{}{
 		"clause": whereClause,
-		"args":   args,
+		"args":   filterValues(args),
+	}
+	return tq
+}
+
+// timeCondition compares a time field with a date expression
+type timeCondition struct {
+	Field    string `json:"field"` // created_at, updated_at or _data.<field>
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// timeConditionsOf returns the time conditions recorded in a filter value,
+// which is a []interface{} when the query was loaded from a saved view
+func timeConditionsOf(value interface{}) []timeCondition {
+	if conditions, ok := value.([]timeCondition); ok {
+		return conditions
+	}
+	var conditions []timeCondition
+	if data, err := json.Marshal(value); err == nil {
+		_ = json.Unmarshal(data, &conditions)
+	}
+	return conditions
+}
+
+// TimeBefore filters for documents whose time field is strictly before when.
+//
+// field is created_at, updated_at or a time.Time data field, by Go or
+// snake_case name. when is a time.Time or a date expression, resolved when
+// the query runs against the store clock (see SetTimeFunc): "now", "today",
+// "tomorrow", "start_of_week", "-7d", "+2w", "today-3d", ISO dates and
+// RFC 3339 timestamps. See store.ParseDateExpression for the full syntax.
+// Documents without a value for the field do not match.
+//
+// Examples:
+//
+//	// Tasks that were due before today
+//	overdue, err := store.Query().TimeBefore("DueDate", "today").Find()
+//
+//	// Documents created before a fixed point in time
+//	old, err := store.Query().TimeBefore("created_at", cutoff).Find()
+func (tq *Query[T]) TimeBefore(field string, when interface{}) *Query[T] {
+	return tq.timeCondition("TimeBefore", field, "<", when)
+}
+
+// TimeAfter filters for documents whose time field is strictly after when. It
+// takes the same fields and date expressions as TimeBefore.
+//
+// Examples:
+//
+//	// Tasks due from tomorrow onwards
+//	upcoming, err := store.Query().TimeAfter("due_date", "tomorrow").Find()
+func (tq *Query[T]) TimeAfter(field string, when interface{}) *Query[T] {
+	return tq.timeCondition("TimeAfter", field, ">", when)
+}
+
+// TimeWithin filters for documents whose time field lies between from and to,
+// both included. It takes the same fields and date expressions as TimeBefore.
+//
+// Examples:
+//
+//	// Documents updated in the last 7 days
+//	recent, err := store.Query().TimeWithin("UpdatedAt", "-7d", "now").Find()
+//
+//	// Tasks due this week
+//	thisWeek, err := store.Query().TimeWithin("DueDate", "start_of_week", "end_of_week").Find()
+func (tq *Query[T]) TimeWithin(field string, from, to interface{}) *Query[T] {
+	return tq.timeCondition("TimeWithin", field, ">=", from).timeCondition("TimeWithin", field, "<=", to)
+}
+
+// timeCondition validates a time field comparison and records it for
+// post-processing
+func (tq *Query[T]) timeCondition(method, field, operator string, when interface{}) *Query[T] {
+	column, err := tq.typedStore.timeField(field)
+	if err != nil {
+		tq.options.Filters["__validation_error__"] = fmt.Errorf("%s field validation: %w", method, err)
+		return tq
+	}
+
+	var value string
+	switch v := when.(type) {
+	case time.Time:
+		value = v.Format(time.RFC3339Nano)
+	case string:
+		if _, err := store.ParseDateExpression(v, time.Now()); err != nil {
+			tq.options.Filters["__validation_error__"] = fmt.Errorf("%s: %w", method, err)
+			return tq
+		}
+		value = v
+	default:
+		tq.options.Filters["__validation_error__"] = fmt.Errorf("%s: expected a time.Time or date expression, got %T", method, when)
+		return tq
 	}
+
+	conditions := timeConditionsOf(tq.options.Filters["__time_conditions__"])
+	tq.options.Filters["__time_conditions__"] = append(conditions, timeCondition{column, operator, value})
 	return tq
 }
 
+// timeField resolves a field name given to the time helpers to the document
+// field holding it
+func (ts *Store[T]) timeField(name string) (string, error) {
+	switch normalized := normalizeFieldName(name); normalized {
+	case "created_at", "updated_at":
+		return normalized, nil
+	}
+
+	field, found := findFieldByName(ts.typ, name)
+	if !found || field.Anonymous {
+		return "", fmt.Errorf("unknown field %q, available data fields: %v", name, ts.getAvailableDataFields(ts.typ))
+	}
+	timeType := reflect.TypeOf(time.Time{})
+	if field.Type != timeType && field.Type != reflect.PointerTo(timeType) {
+		return "", fmt.Errorf("field %q is %s, not a time.Time", name, field.Type)
+	}
+	return "_data." + dataFieldName(field), nil
+}
+
 // ParentID filters by parent ID, with automatic SimpleID resolution.
 //
 // This method demonstrates the power of Smart ID resolution in queries:
@@ -3142,7 +3399,21 @@ func (tq *Query[T]) ParentIDStartsWith(prefix string) *Query[T] {
 // - **Fields Searched**: Document Title and Body fields
 // - **Search Type**: Full-text search with partial matching
 // - **Case Sensitivity**: Typically case-insensitive (store-dependent)
-// - **Multiple Terms**: Space-separated terms are typically treated as AND conditions
+// - **Multiple Terms**: Space-separated terms are treated as AND conditions
+//
+// # Query Syntax
+//
+// The text uses the same query language as the search package (search.Query):
+//
+//	"quarterly budget"       exact phrase
+//	title:groceries          word scoped to the title (or body:...)
+//	status:done              dimension value
+//	assignee:alice           custom data field (also _data.assignee:alice)
+//	-archived                exclude documents containing a word
+//	budget OR forecast       either word
+//	(budget OR cost) -draft  grouping
+//
+// Unscoped words and phrases match within the Title and Body.
 //
 // # Usage Examples
 //
@@ -3174,6 +3445,11 @@ func (tq *Query[T]) ParentIDStartsWith(prefix string) *Query[T] {
 //	    Search("project alpha").
 //	    Exists()
 //
+//	// Power-user syntax
+//	groceries, err := store.Query().
+//	    Search(`title:groceries "whole milk" -done OR urgent`).
+//	    Find()
+//
 // # Performance Considerations
 //
 // - **Text Search**: May be slower than dimensional filtering
@@ -3210,13 +3486,32 @@ func (tq *Query[T]) ParentIDStartsWith(prefix string) *Query[T] {
 //
 // # Note on Data Fields
 //
-// Search() only searches Title and Body fields. To search custom data fields
-// (like Assignee, Description, etc.), use Data() or Where() methods instead.
+// Unscoped terms only search Title and Body fields. To search custom data
+// fields (like Assignee, Description, etc.), scope the term to the field
+// (assignee:alice) or use Data() or Where() methods instead.
 func (tq *Query[T]) Search(text string) *Query[T] {
 	tq.options.FilterBySearch = text
 	return tq
 }
 
+// Lens lists the results through an alternate canonical view. The SimpleIDs
+// of the results omit the prefixes of the lens's values instead of the
+// store's, so a screen showing only done tasks can show "1" rather than "d1".
+// Resolve those IDs with Store.ResolveUUIDInView and the same lens.
+//
+// Filtering is unchanged: the lens only decides how IDs are written.
+//
+// # Usage Examples
+//
+//	lens := store.CanonicalView().With("status", "done")
+//	done, err := store.Query().Status("done").Lens(lens).Find()
+//	// done[0].SimpleID == "1"
+//	uuid, err := store.ResolveUUIDInView("1", lens)
+func (tq *Query[T]) Lens(view *types.CanonicalView) *Query[T] {
+	tq.options.CanonicalView = view
+	return tq
+}
+
 // OrderBy adds ordering
 func (tq *Query[T]) OrderBy(column string) *Query[T] {
 	tq.options.OrderBy = append(tq.options.OrderBy, types.OrderClause{
@@ -3273,8 +3568,8 @@ func (tq *Query[T]) OrderByData(field string) *Query[T] {
 		return tq
 	}
 
-	// Transform to snake_case for storage
-	snakeField := normalizeFieldName(field)
+	// Transform to the stored name
+	snakeField := dataFieldKey(typ, field)
 	tq.options.OrderBy = append(tq.options.OrderBy, types.OrderClause{
 		Column:     "_data." + snakeField,
 		Descending: false,
@@ -3309,8 +3604,8 @@ func (tq *Query[T]) OrderByDataDesc(field string) *Query[T] {
 		return tq
 	}
 
-	// Transform to snake_case for storage
-	snakeField := normalizeFieldName(field)
+	// Transform to the stored name
+	snakeField := dataFieldKey(typ, field)
 	tq.options.OrderBy = append(tq.options.OrderBy, types.OrderClause{
 		Column:     "_data." + snakeField,
 		Descending: true,
@@ -3318,6 +3613,54 @@ func (tq *Query[T]) OrderByDataDesc(field string) *Query[T] {
 	return tq
 }
 
+// NullsFirst places documents without a value for the most recently added
+// ordering column before all other documents, regardless of direction.
+//
+// By default missing values sort as the largest value: last for ascending
+// orderings and first for descending ones.
+//
+//	// Unassigned tasks first, then by assignee
+//	results, err := store.Query().OrderByData("assignee").NullsFirst().Find()
+func (tq *Query[T]) NullsFirst() *Query[T] {
+	return tq.modifyLastOrder("NullsFirst", func(clause *types.OrderClause) {
+		clause.Nulls = types.NullsFirst
+	})
+}
+
+// NullsLast places documents without a value for the most recently added
+// ordering column after all other documents, regardless of direction.
+//
+//	// Highest estimates first, unestimated tasks at the end
+//	results, err := store.Query().OrderByDataDesc("estimate").NullsLast().Find()
+func (tq *Query[T]) NullsLast() *Query[T] {
+	return tq.modifyLastOrder("NullsLast", func(clause *types.OrderClause) {
+		clause.Nulls = types.NullsLast
+	})
+}
+
+// Collate sets how text values of the most recently added ordering column are
+// compared. Use types.CollationCaseInsensitive to ignore letter case, or
+// types.CollationNatural to also compare embedded numbers by value so that
+// "Task 2" sorts before "Task 10".
+//
+//	results, err := store.Query().OrderBy("title").Collate(types.CollationNatural).Find()
+func (tq *Query[T]) Collate(collation types.Collation) *Query[T] {
+	return tq.modifyLastOrder("Collate", func(clause *types.OrderClause) {
+		clause.Collation = collation
+	})
+}
+
+// modifyLastOrder applies fn to the last order clause, recording a validation
+// error when no ordering has been added yet
+func (tq *Query[T]) modifyLastOrder(method string, fn func(clause *types.OrderClause)) *Query[T] {
+	if len(tq.options.OrderBy) == 0 {
+		tq.options.Filters["__validation_error__"] = fmt.Errorf("%s must follow an OrderBy method", method)
+		return tq
+	}
+	fn(&tq.options.OrderBy[len(tq.options.OrderBy)-1])
+	return tq
+}
+
 // Limit sets the maximum number of results to return from the query.
 //
 // This method implements result limiting for pagination and performance optimization.
@@ -3524,6 +3867,79 @@ func (tq *Query[T]) Offset(n int) *Query[T] {
 	return tq
 }
 
+// Filter adds an in-process predicate written in Go.
+//
+// Predicates run after all store-level filters (dimensions, Data(), Where(), ...)
+// and before Limit(), Offset() and cursor pages are cut, so a limited query
+// still returns up to N matching documents. Multiple Filter() calls are
+// combined with AND. Predicates always receive fully populated values,
+// including the body, even when Select() narrows the results.
+//
+// # Usage Examples
+//
+//	overdue, err := store.Query().
+//	    Status("active").
+//	    Filter(func(t Task) bool {
+//	        return t.DueDate.Before(time.Now()) && len(t.Description) > 0
+//	    }).
+//	    OrderBy("title").
+//	    Limit(20).
+//	    Find()
+//
+// # Performance Note
+//
+// Every candidate document is unmarshaled to evaluate predicates, and
+// pagination happens in-process. Prefer store-level filters to narrow the
+// candidates first.
+func (tq *Query[T]) Filter(predicate func(T) bool) *Query[T] {
+	tq.predicates = append(tq.predicates, predicate)
+	return tq
+}
+
+// Select restricts the fields populated in query results.
+//
+// Field names are the Go struct field names (case-insensitive, snake_case
+// also accepted). Document metadata (UUID, SimpleID, Title, CreatedAt,
+// UpdatedAt) is always populated; the Body is only loaded when "Body" is
+// selected, which keeps listings fast on stores with large or external
+// bodies. Fields that are not selected keep their zero values.
+//
+// # Usage Examples
+//
+//	// Titles and assignees only, without reading bodies
+//	tasks, err := store.Query().Select("Assignee").Find()
+//
+//	// Include the body
+//	notes, err := store.Query().Select("Body", "Tags").Find()
+func (tq *Query[T]) Select(fields ...string) *Query[T] {
+	if tq.selected == nil {
+		tq.selected = make(map[string]bool)
+	}
+
+	valid := make(map[string]bool)
+	for _, name := range []string{"uuid", "simple_id", "title", "body", "created_at", "updated_at"} {
+		valid[name] = true
+	}
+	for _, field := range storedFields(tq.typedStore.typ) {
+		if !field.Anonymous {
+			valid[normalizeFieldName(field.Name)] = true
+		}
+	}
+
+	for _, field := range fields {
+		name := normalizeFieldName(field)
+		if match, found := findFieldByName(tq.typedStore.typ, field); found && !match.Anonymous {
+			name = normalizeFieldName(match.Name)
+		}
+		if !valid[name] {
+			tq.options.Filters["__validation_error__"] = fmt.Errorf("select: unknown field %q", field)
+			return tq
+		}
+		tq.selected[name] = true
+	}
+	return tq
+}
+
 // Find executes the query and returns typed results.
 //
 // This is the primary terminal method for query execution. It performs several steps:
@@ -3586,45 +4002,154 @@ func (tq *Query[T]) Offset(n int) *Query[T] {
 //	    Limit(10).
 //	    Find()
 func (tq *Query[T]) Find() ([]T, error) {
+	docs, _, err := tq.fetch(false)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]T, 0, len(docs))
+	for _, doc := range docs {
+		typed, err := tq.decode(doc)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, typed)
+	}
+
+	return results, nil
+}
+
+// decode unmarshals a result document, honouring the Select() projection
+func (tq *Query[T]) decode(doc types.Document) (T, error) {
+	var typed T
+	if err := unmarshalDocument(doc, &typed, tq.selected); err != nil {
+		return typed, fmt.Errorf("failed to unmarshal document: %w", err)
+	}
+	return typed, nil
+}
+
+// postFilters holds the query conditions that are evaluated client-side,
+// after the store has returned its results.
+type postFilters struct {
+	parentNotExists bool
+	dataNot         []struct {
+		field string
+		value interface{}
+	}
+	dataNotIn []struct {
+		field  string
+		values []interface{}
+	}
+	where *store.WhereEvaluator
+	times []timeFilter
+}
+
+// timeFilter is a time field comparison added by TimeBefore, TimeAfter or
+// TimeWithin
+type timeFilter struct {
+	field string
+	where *store.WhereEvaluator
+}
+
+// active reports whether any client-side filter is set
+func (f *postFilters) active() bool {
+	return f.parentNotExists || len(f.dataNot) > 0 || len(f.dataNotIn) > 0 || f.where != nil || len(f.times) > 0
+}
+
+// matches reports whether a document passes all post-processing filters
+func (f *postFilters) matches(doc types.Document) (bool, error) {
+	if f.parentNotExists {
+		// Check if parent_id exists in dimensions
+		if _, hasParent := doc.Dimensions["parent_id"]; hasParent {
+			return false, nil // Skip documents with parent
+		}
+	}
+
+	// Apply data NOT filters
+	for _, filter := range f.dataNot {
+		if dataValue, exists := doc.Dimensions["_data."+filter.field]; exists {
+			if dataValue == filter.value {
+				return false, nil
+			}
+		}
+	}
+
+	// Apply data NOT IN filters
+	for _, filter := range f.dataNotIn {
+		if dataValue, exists := doc.Dimensions["_data."+filter.field]; exists {
+			for _, excludeValue := range filter.values {
+				if dataValue == excludeValue {
+					return false, nil
+				}
+			}
+		}
+	}
+
+	// Apply WHERE clause filter
+	if f.where != nil {
+		// Use the secure WhereEvaluator to safely evaluate the WHERE clause
+		matches, err := f.where.EvaluateDocument(&doc)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate WHERE clause: %w", err)
+		}
+		if !matches {
+			return false, nil // Skip documents that don't match the WHERE clause
+		}
+	}
+
+	// Apply time field filters; unset and zero times never match
+	for _, filter := range f.times {
+		if strings.HasPrefix(filter.field, "_data.") {
+			text, _ := doc.Dimensions[filter.field].(string)
+			if t, err := time.Parse(time.RFC3339, text); err != nil || t.IsZero() {
+				return false, nil
+			}
+		}
+		matches, err := filter.where.EvaluateDocument(&doc)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate time filter: %w", err)
+		}
+		if !matches {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// fetch validates the query, runs it against the store and applies the
+// client-side filters and predicates. When any client-side filtering is
+// needed, Limit and Offset are applied here rather than by the store so they
+// count matching documents only. hasMore reports whether matches remain
+// beyond the returned page. needBody loads bodies even when the Select()
+// projection leaves them out.
+func (tq *Query[T]) fetch(needBody bool) (docs []types.Document, hasMore bool, err error) {
 	// Check for validation errors first
 	if validationErr, ok := tq.options.Filters["__validation_error__"]; ok {
 		delete(tq.options.Filters, "__validation_error__")
 		if err, isErr := validationErr.(error); isErr {
-			return nil, err
+			return nil, false, err
 		}
 	}
 
 	// Validate data field references before executing the query
 	if err := tq.validateDataFieldReferences(); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
+	filters := &postFilters{}
+
 	// Check for special filters and extract them for post-processing
-	parentNotExists := false
 	if _, ok := tq.options.Filters["__parent_not_exists__"]; ok {
-		parentNotExists = true
+		filters.parentNotExists = true
 		delete(tq.options.Filters, "__parent_not_exists__")
 	}
 
 	// Extract special filters for post-processing
-	var dataNotFilters []struct {
-		field string
-		value interface{}
-	}
-	var dataNotInFilters []struct {
-		field  string
-		values []interface{}
-	}
-	var whereClause struct {
-		clause string
-		args   []interface{}
-		active bool
-	}
-
 	for key, value := range tq.options.Filters {
 		if strings.HasPrefix(key, "__data_not__") {
 			field := strings.TrimPrefix(key, "__data_not__")
-			dataNotFilters = append(dataNotFilters, struct {
+			filters.dataNot = append(filters.dataNot, struct {
 				field string
 				value interface{}
 			}{field, value})
@@ -3632,94 +4157,214 @@ func (tq *Query[T]) Find() ([]T, error) {
 		} else if strings.HasPrefix(key, "__data_not_in__") {
 			field := strings.TrimPrefix(key, "__data_not_in__")
 			if values, ok := value.([]interface{}); ok {
-				dataNotInFilters = append(dataNotInFilters, struct {
+				filters.dataNotIn = append(filters.dataNotIn, struct {
 					field  string
 					values []interface{}
 				}{field, values})
 			}
 			delete(tq.options.Filters, key)
-		} else if key == "__where_clause__" {
+		} else if key == store.WhereFilterKey {
 			if whereMap, ok := value.(map[string]interface{}); ok {
 				if clause, ok := whereMap["clause"].(string); ok {
-					whereClause.clause = clause
-					whereClause.active = true
-					if args, ok := whereMap["args"].([]interface{}); ok {
-						whereClause.args = args
-					}
+					args, _ := whereMap["args"].([]interface{})
+					filters.where = store.NewWhereEvaluator(clause, args...).WithClock(tq.typedStore.now)
 				}
 			}
 			delete(tq.options.Filters, key)
+		} else if key == "__time_conditions__" {
+			for _, condition := range timeConditionsOf(value) {
+				clause := fmt.Sprintf("%s %s ?", condition.Field, condition.Operator)
+				filters.times = append(filters.times, timeFilter{
+					field: condition.Field,
+					where: store.NewWhereEvaluator(clause, condition.Value).WithClock(tq.typedStore.now),
+				})
+			}
+			delete(tq.options.Filters, key)
 		}
 	}
 
-	docs, err := tq.store.List(tq.options)
+	// Virtual fields in the WHERE clause are computed from the whole store
+	if filters.where != nil && filters.where.UsesVirtualFields() {
+		all, err := tq.store.List(types.ListOptions{})
+		if err != nil {
+			return nil, false, err
+		}
+		filters.where.WithVirtualFields(query.NewVirtualFields(all, tq.typedStore.config.GetDimensionSet()))
+	}
+
+	clientSide := filters.active() || len(tq.predicates) > 0
+
+	storeOptions := tq.options
+	if clientSide {
+		// Paginate after client-side filtering so pages are counted correctly
+		storeOptions.Limit = nil
+		storeOptions.Offset = nil
+	} else if tq.selected != nil && !tq.selected["body"] && !needBody {
+		// Nothing client-side needs the body, so the store can skip it
+		storeOptions.ExcludeBody = true
+	}
+
+	candidates, err := tq.store.List(storeOptions)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	results := make([]T, 0, len(docs))
-	for _, doc := range docs {
-		// Apply post-processing filters
-		if parentNotExists {
-			// Check if parent_id exists in dimensions
-			if _, hasParent := doc.Dimensions["parent_id"]; hasParent {
-				continue // Skip documents with parent
-			}
-		}
+	if !clientSide {
+		limit := tq.options.Limit
+		return candidates, limit != nil && *limit > 0 && len(candidates) == *limit, nil
+	}
 
-		// Apply data NOT filters
-		skip := false
-		for _, filter := range dataNotFilters {
-			if dataValue, exists := doc.Dimensions["_data."+filter.field]; exists {
-				if dataValue == filter.value {
-					skip = true
-					break
-				}
-			}
+	matched := make([]types.Document, 0, len(candidates))
+	for _, doc := range candidates {
+		// Apply post-processing filters
+		matches, err := filters.matches(doc)
+		if err != nil {
+			return nil, false, err
 		}
-		if skip {
+		if !matches {
 			continue
 		}
 
-		// Apply data NOT IN filters
-		for _, filter := range dataNotInFilters {
-			if dataValue, exists := doc.Dimensions["_data."+filter.field]; exists {
-				for _, excludeValue := range filter.values {
-					if dataValue == excludeValue {
-						skip = true
-						break
-					}
-				}
-				if skip {
-					break
-				}
+		// Apply in-process predicates to the fully populated value
+		if len(tq.predicates) > 0 {
+			var typed T
+			if err := UnmarshalDimensions(doc, &typed); err != nil {
+				return nil, false, fmt.Errorf("failed to unmarshal document: %w", err)
+			}
+			if !tq.matchesPredicates(typed) {
+				continue
 			}
 		}
-		if skip {
-			continue
+
+		matched = append(matched, doc)
+	}
+
+	// Apply pagination
+	if tq.options.Offset != nil && *tq.options.Offset > 0 {
+		if *tq.options.Offset >= len(matched) {
+			matched = matched[:0]
+		} else {
+			matched = matched[*tq.options.Offset:]
 		}
+	}
+	if tq.options.Limit != nil && *tq.options.Limit > 0 && *tq.options.Limit < len(matched) {
+		return matched[:*tq.options.Limit], true, nil
+	}
 
-		// Apply WHERE clause filter
-		if whereClause.active {
-			// Use the secure WhereEvaluator to safely evaluate the WHERE clause
-			evaluator := store.NewWhereEvaluator(whereClause.clause, whereClause.args...)
-			matches, err := evaluator.EvaluateDocument(&doc)
-			if err != nil {
-				return nil, fmt.Errorf("failed to evaluate WHERE clause: %w", err)
-			}
-			if !matches {
-				continue // Skip documents that don't match the WHERE clause
-			}
+	return matched, false, nil
+}
+
+// matchesPredicates reports whether a value satisfies every Filter() predicate
+func (tq *Query[T]) matchesPredicates(item T) bool {
+	for _, predicate := range tq.predicates {
+		if !predicate(item) {
+			return false
 		}
+	}
+	return true
+}
+
+// After resumes a paginated query after the position encoded in cursor.
+//
+// Cursors are returned by FindPage() and identify the last document of the
+// previous page. Unlike Offset(), a cursor keeps pointing at the same place
+// when documents are created or deleted between requests, so pages never skip
+// or repeat documents.
+//
+// The query must use the same ordering as the one that produced the cursor;
+// otherwise execution fails with query.ErrInvalidCursor. Without any OrderBy
+// clause, pages follow creation order. An empty cursor starts from the
+// beginning, which makes the first and subsequent requests look the same.
+//
+// # Usage Examples
+//
+//	cursor := ""
+//	for {
+//	    tasks, next, err := store.Query().
+//	        Status("active").
+//	        OrderBy("title").
+//	        After(cursor).
+//	        Limit(50).
+//	        FindPage()
+//	    if err != nil {
+//	        return err
+//	    }
+//	    process(tasks)
+//	    if next == "" {
+//	        break
+//	    }
+//	    cursor = next
+//	}
+func (tq *Query[T]) After(cursor string) *Query[T] {
+	tq.options.Cursor = cursor
+	return tq
+}
+
+// FindPage executes the query and returns one page of results together with
+// the cursor for the next page.
+//
+// The page size is set with Limit(). The returned cursor is empty when there
+// are no more results; otherwise pass it to After() on an identically ordered
+// query to fetch the following page. Without Limit() the whole result set is
+// returned as a single page.
+func (tq *Query[T]) FindPage() ([]T, string, error) {
+	if len(tq.options.OrderBy) == 0 {
+		// Make the first page use the same creation order as cursor pages
+		tq.options.OrderBy = []types.OrderClause{{Column: "created_at"}}
+	}
 
-		var typed T
-		if err := UnmarshalDimensions(doc, &typed); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal document: %w", err)
+	docs, hasMore, err := tq.fetch(false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	results := make([]T, 0, len(docs))
+	for _, doc := range docs {
+		typed, err := tq.decode(doc)
+		if err != nil {
+			return nil, "", err
 		}
 		results = append(results, typed)
 	}
 
-	return results, nil
+	next := ""
+	if hasMore && len(docs) > 0 {
+		next = query.EncodeCursor(docs[len(docs)-1], tq.options.OrderBy)
+	}
+
+	return results, next, nil
+}
+
+// All executes the query and returns an iterator over the typed results.
+//
+// Documents are unmarshaled one at a time as the caller ranges over the
+// sequence, so breaking out of the loop early skips the remaining conversion
+// work. Errors are yielded alongside a zero value and end the iteration.
+//
+// # Usage Examples
+//
+//	for task, err := range store.Query().Status("active").All() {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(task.Title)
+//	}
+func (tq *Query[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		docs, _, err := tq.fetch(false)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+
+		for _, doc := range docs {
+			typed, err := tq.decode(doc)
+			if !yield(typed, err) || err != nil {
+				return
+			}
+		}
+	}
 }
 
 // First returns the first matching document or an error if no documents are found.
@@ -4202,6 +4847,23 @@ func embedsDocument(typ reflect.Type) bool {
 // - Prefixes: {"done": "d"} (done→d, others no prefix)
 // - Default: "pending"
 //
+// An optional `canonical:"active"` tag picks the value omitted from SimpleIDs
+// when it differs from the default ("*" keeps every prefix). Dimensions
+// without the tag keep their default value as canonical.
+//
+// An optional `transitions:"pending->active,active->done,*->archived"` tag
+// restricts how the value may change ("*" matches any value), and
+// `track_changes:"true"` records the time of each change in the
+// "<dimension>_changed_at" data field.
+//
+// ## Unique Data Fields
+//
+//	Slug string `unique:"true"`
+//
+// This adds a unique constraint on the data field. Any other value than
+// "true" names a group, and fields with the same group form a compound
+// constraint. The ",parent" option scopes the constraint to siblings.
+//
 // ## Hierarchical Dimensions
 //
 //	ParentID string `dimension:"parent_id,ref"`
@@ -4211,11 +4873,22 @@ func embedsDocument(typ reflect.Type) bool {
 // - Type: Hierarchical
 // - RefField: "parent_id" (the actual reference field name)
 //
+// ## Stored Names
+//
+//	Owner   string `nanostore:"assignee"`   // stored as _data.assignee
+//	Notes   string `nanostore:",omitempty"` // kept by updates when empty
+//	Summary string `nanostore:"-"`          // not stored
+//
+// A nanostore tag name overrides the stored name of data fields and of
+// values or multi dimensions, so Go fields can be renamed without orphaning
+// data. Embedded structs other than Document are flattened: their fields are
+// stored as if declared on the model. Stored names must be distinct.
+//
 // # Configuration Generation Strategy
 //
 // The function makes several automatic decisions:
 //
-// - **Dimension Names**: Derived from struct field names (lowercased)
+// - **Dimension Names**: Derived from struct field names (lowercased) or nanostore tags
 // - **Type Inference**: Enumerated vs Hierarchical based on tag patterns
 // - **Validation**: Ensures prefixes don't conflict, defaults are valid
 // - **Error Reporting**: Provides specific errors with field context
@@ -4234,24 +4907,40 @@ func embedsDocument(typ reflect.Type) bool {
 // Potential improvements to tag processing:
 //
 // - **Advanced Validation**: Cross-field constraint validation
-// - **Custom Naming**: Override field-to-dimension name mapping
 // - **Inheritance**: Support for dimension inheritance across struct hierarchies
 // - **Plugin System**: Custom tag processors for domain-specific needs
 func generateConfigFromType(typ reflect.Type) (nanostore.Config, error) {
 	var config nanostore.Config
+	canonical := make(map[string]string)
 
 	if typ.Kind() == reflect.Ptr {
 		typ = typ.Elem()
 	}
 
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-
-		// Skip embedded Document field
+	storedBy := make(map[string]string) // Stored names and the fields using them
+	for _, field := range storedFields(typ) {
+		// Skip embedded Document field, which may carry the ID scheme
 		if field.Anonymous && field.Type == reflect.TypeOf(nanostore.Document{}) {
+			if schemeTag, schemeExists := field.Tag.Lookup("ids"); schemeExists {
+				scheme, err := parseIDSchemeTag(schemeTag)
+				if err != nil {
+					return config, fmt.Errorf("field '%s': %w", field.Name, err)
+				}
+				config.IDScheme = &scheme
+			}
 			continue
 		}
 
+		// Names chosen with nanostore tags must be usable and distinct
+		storedName, err := storedFieldKey(field)
+		if err != nil {
+			return config, fmt.Errorf("field '%s': %w", field.Name, err)
+		}
+		if other, taken := storedBy[storedName]; taken && storedName != "" {
+			return config, fmt.Errorf("field '%s': stored name '%s' is already used by field '%s'", field.Name, strings.TrimPrefix(storedName, "_data."), other)
+		}
+		storedBy[storedName] = field.Name
+
 		// Check for pointer fields and validate supported types
 		if field.Type.Kind() == reflect.Ptr {
 			// Check if the underlying type is supported
@@ -4260,21 +4949,47 @@ func generateConfigFromType(typ reflect.Type) (nanostore.Config, error) {
 			case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
 				// Basic pointer types are supported
 			default:
-				// Check for time.Time specifically
-				if elemType == reflect.TypeOf(time.Time{}) {
-					// *time.Time is supported
+				// Check for time.Time and custom types specifically
+				if elemType == reflect.TypeOf(time.Time{}) || isCustomType(elemType) {
+					// *time.Time, *url.URL and pointers to marshalers are supported
 				} else {
-					return config, fmt.Errorf("field %s: pointer type %s is not supported (supported: *string, *bool, *int, *int64, *float64, *time.Time)", field.Name, field.Type)
+					return config, fmt.Errorf("field %s: pointer type %s is not supported (supported: *string, *bool, *int, *int64, *float64, *time.Time, and pointers to time.Duration, url.URL and text or JSON marshalers)", field.Name, field.Type)
 				}
 			}
 		}
 
+		// Multi tags declare set dimensions holding lists of values
+		if multiTag, multiExists := field.Tag.Lookup("multi"); multiExists {
+			multi, err := strconv.ParseBool(multiTag)
+			if err != nil {
+				return config, fmt.Errorf("field '%s': multi tag '%s' must be true or false", field.Name, multiTag)
+			}
+			if multi {
+				dimConfig, err := setDimensionConfig(field)
+				if err != nil {
+					return config, fmt.Errorf("field '%s': %w", field.Name, err)
+				}
+				config.Dimensions = append(config.Dimensions, dimConfig)
+				continue
+			}
+		}
+
+		// Unique tags group data fields into unique constraints
+		if uniqueTag, uniqueExists := field.Tag.Lookup("unique"); uniqueExists {
+			if field.Tag.Get("values") != "" || field.Tag.Get("dimension") != "" {
+				return config, fmt.Errorf("field '%s': unique tags are only supported on data fields", field.Name)
+			}
+			if err := parseUniqueTag(uniqueTag, dataFieldName(field), &config); err != nil {
+				return config, fmt.Errorf("field '%s': %w", field.Name, err)
+			}
+		}
+
 		// Look for field tags in different formats
 		if tagValue, tagExists := field.Tag.Lookup("values"); tagExists {
 			// Parse enumerated dimension from tags like:
 			// `values:"pending,active,done" prefix:"done=d" default:"pending"`
 			dimConfig := nanostore.DimensionConfig{
-				Name: strings.ToLower(field.Name),
+				Name: dimensionFieldName(field),
 				Type: nanostore.Enumerated,
 			}
 
@@ -4297,6 +5012,29 @@ func generateConfigFromType(typ reflect.Type) (nanostore.Config, error) {
 				}
 			}
 
+			// Parse and validate canonical tag
+			if canonicalVal, canonicalExists := field.Tag.Lookup("canonical"); canonicalExists {
+				value, err := parseCanonicalTag(canonicalVal, &dimConfig)
+				if err != nil {
+					return config, fmt.Errorf("field '%s': %w", field.Name, err)
+				}
+				canonical[dimConfig.Name] = value
+			}
+
+			// Parse and validate transitions and track_changes tags
+			if transitionsTag, transitionsExist := field.Tag.Lookup("transitions"); transitionsExist {
+				if err := parseTransitionsTag(transitionsTag, &dimConfig); err != nil {
+					return config, fmt.Errorf("field '%s': %w", field.Name, err)
+				}
+			}
+			if trackTag, trackExists := field.Tag.Lookup("track_changes"); trackExists {
+				track, err := strconv.ParseBool(trackTag)
+				if err != nil {
+					return config, fmt.Errorf("field '%s': track_changes tag '%s' must be true or false", field.Name, trackTag)
+				}
+				dimConfig.TrackChanges = track
+			}
+
 			config.Dimensions = append(config.Dimensions, dimConfig)
 		} else if dimTag := field.Tag.Get("dimension"); dimTag != "" {
 			// Parse dimension tag like: `dimension:"parent_id,ref"`
@@ -4334,9 +5072,121 @@ func generateConfigFromType(typ reflect.Type) (nanostore.Config, error) {
 		return config, fmt.Errorf("struct tag validation failed: %w", err)
 	}
 
+	// Canonical tags override the canonical values derived from defaults
+	if len(canonical) > 0 {
+		view := types.DefaultCanonicalView(types.DimensionSetFromConfig(config))
+		for _, dim := range config.Dimensions {
+			if value, exists := canonical[dim.Name]; exists {
+				view = view.With(dim.Name, value)
+			}
+		}
+		config.CanonicalView = view
+	}
+
 	return config, nil
 }
 
+// parseIDSchemeTag parses the "ids" tag of the embedded Document, a comma
+// separated list of key=value settings:
+//
+//	nanostore.Document `ids:"separator=/,numbering=base36,padding=3,prefix=after"`
+//
+// Keys are separator (".", "/", "-"), numbering (decimal, letters, base36),
+// padding (minimum position width), prefix (before, after) and positions
+// (computed, or stable to keep positions across deletions). Whether the
+// scheme is usable with the dimensions is checked when the store is created.
+func parseIDSchemeTag(tagValue string) (types.IDScheme, error) {
+	scheme := types.DefaultIDScheme()
+	for _, part := range strings.Split(tagValue, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return scheme, fmt.Errorf("ids tag entry '%s' must be key=value", part)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case "separator":
+			scheme.Separator = value
+		case "numbering":
+			if err := scheme.Numbering.UnmarshalJSON([]byte(strconv.Quote(value))); err != nil {
+				return scheme, err
+			}
+		case "padding":
+			padding, err := strconv.Atoi(value)
+			if err != nil {
+				return scheme, fmt.Errorf("ids tag padding '%s' is not a number", value)
+			}
+			scheme.Padding = padding
+		case "prefix":
+			if err := scheme.PrefixPlacement.UnmarshalJSON([]byte(strconv.Quote(value))); err != nil {
+				return scheme, err
+			}
+		case "positions":
+			switch value {
+			case "computed":
+				scheme.StablePositions = false
+			case "stable":
+				scheme.StablePositions = true
+			default:
+				return scheme, fmt.Errorf("ids tag positions '%s' must be 'computed' or 'stable'", value)
+			}
+		default:
+			return scheme, fmt.Errorf("unknown ids tag key '%s' (valid keys: separator, numbering, padding, prefix, positions)", key)
+		}
+	}
+	return scheme, nil
+}
+
+// parseCanonicalTag parses and validates the "canonical" struct tag, which
+// names the value omitted from SimpleIDs when it differs from the default,
+// or "*" to keep the prefixes of every value
+func parseCanonicalTag(tagValue string, dimConfig *nanostore.DimensionConfig) (string, error) {
+	value := strings.TrimSpace(tagValue)
+	if value == "" {
+		return "", fmt.Errorf("canonical tag cannot be empty")
+	}
+	if value == "*" {
+		return value, nil
+	}
+	for _, v := range dimConfig.Values {
+		if v == value {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("canonical value '%s' is not in values list %v", value, dimConfig.Values)
+}
+
+// parseTransitionsTag parses and validates the "transitions" struct tag, a
+// comma separated list of allowed changes such as
+// "pending->active,active->done,*->archived"
+func parseTransitionsTag(tagValue string, dimConfig *nanostore.DimensionConfig) error {
+	transitions, err := types.ParseTransitions(tagValue)
+	if err != nil {
+		return err
+	}
+	if len(transitions) == 0 {
+		return fmt.Errorf("transitions tag cannot be empty")
+	}
+
+	valueSet := make(map[string]bool, len(dimConfig.Values))
+	for _, v := range dimConfig.Values {
+		valueSet[v] = true
+	}
+	for _, t := range transitions {
+		for _, value := range []string{t.From, t.To} {
+			if value != types.AnyValue && !valueSet[value] {
+				return fmt.Errorf("transition '%s' uses value '%s' not in values list %v", t, value, dimConfig.Values)
+			}
+		}
+	}
+	dimConfig.Transitions = transitions
+	return nil
+}
+
 // parseValuesTag parses and validates the "values" struct tag
 func parseValuesTag(tagValue string, dimConfig *nanostore.DimensionConfig, fieldName string) error {
 	if strings.TrimSpace(tagValue) == "" {
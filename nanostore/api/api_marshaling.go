@@ -26,9 +26,8 @@ func MarshalDimensions(v interface{}) (dimensions map[string]interface{}, data m
 	dimensions = make(map[string]interface{})
 	data = make(map[string]interface{})
 
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-		fieldVal := val.Field(i)
+	for _, field := range storedFields(typ) {
+		fieldVal := val.FieldByIndex(field.Index)
 
 		// Skip unexported fields
 		if !fieldVal.CanInterface() {
@@ -62,6 +61,14 @@ func MarshalDimensions(v interface{}) (dimensions map[string]interface{}, data m
 			}
 		}
 
+		// Set dimensions hold lists of values, and are left out when empty
+		if isSetField(field) {
+			if members := setFieldMembers(fieldVal); len(members) > 0 {
+				dimensions[dimensionFieldName(field)] = members
+			}
+			continue
+		}
+
 		// Check for dimension tag
 		dimTag := field.Tag.Get("dimension")
 		isDimension := false
@@ -70,7 +77,7 @@ func MarshalDimensions(v interface{}) (dimensions map[string]interface{}, data m
 			isDimension = true
 		} else if field.Tag.Get("values") != "" {
 			// Also check for values tag (declarative API style)
-			dimTag = strings.ToLower(field.Name)
+			dimTag = dimensionFieldName(field)
 			isDimension = true
 		}
 
@@ -106,11 +113,6 @@ func MarshalDimensions(v interface{}) (dimensions map[string]interface{}, data m
 				return nil, nil, err
 			}
 
-			// For values tag style, use lowercase field name as dimension name
-			if field.Tag.Get("values") != "" && dimTag == strings.ToLower(field.Name) {
-				dimName = strings.ToLower(field.Name)
-			}
-
 			// Validate enumerated dimension values against their allowed values
 			if valuesTag := field.Tag.Get("values"); valuesTag != "" {
 				if err = validateEnumeratedValue(value, valuesTag, field.Name); err != nil {
@@ -118,14 +120,17 @@ func MarshalDimensions(v interface{}) (dimensions map[string]interface{}, data m
 				}
 			}
 
-			dimensions[dimName] = value
+			// Named types such as `type Status string` are stored as their underlying type
+			dimensions[dimName] = basicValue(value)
 		} else {
 			// Non-dimension field - store in data map
 			// Skip zero values to avoid storing empty data
 			if !isZeroValue(fieldVal) {
 				// Store all non-dimension fields in data map using snake_case
-				snakeFieldName := normalizeFieldName(field.Name)
-				data[snakeFieldName] = value
+				snakeFieldName := dataFieldName(field)
+				if data[snakeFieldName], err = dataValue(value); err != nil {
+					return nil, nil, fmt.Errorf("field %s: %w", field.Name, err)
+				}
 			}
 		}
 	}
@@ -171,9 +176,8 @@ func MarshalDimensionsForUpdate(v interface{}) (dimensions map[string]interface{
 	dimensions = make(map[string]interface{})
 	data = make(map[string]interface{})
 
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-		fieldVal := val.Field(i)
+	for _, field := range storedFields(typ) {
+		fieldVal := val.FieldByIndex(field.Index)
 
 		// Skip unexported fields
 		if !fieldVal.CanInterface() {
@@ -207,6 +211,12 @@ func MarshalDimensionsForUpdate(v interface{}) (dimensions map[string]interface{
 			}
 		}
 
+		// Set dimensions are replaced as a whole, so an empty set clears them
+		if isSetField(field) {
+			dimensions[dimensionFieldName(field)] = setFieldMembers(fieldVal)
+			continue
+		}
+
 		// Check for dimension tag
 		dimTag := field.Tag.Get("dimension")
 		isDimension := false
@@ -215,7 +225,7 @@ func MarshalDimensionsForUpdate(v interface{}) (dimensions map[string]interface{
 			isDimension = true
 		} else if field.Tag.Get("values") != "" {
 			// Also check for values tag (declarative API style)
-			dimTag = strings.ToLower(field.Name)
+			dimTag = dimensionFieldName(field)
 			isDimension = true
 		}
 
@@ -232,8 +242,9 @@ func MarshalDimensionsForUpdate(v interface{}) (dimensions map[string]interface{
 			// For dimension fields, we need to be careful about zero values
 			// Skip zero values for enumerated dimensions to avoid validation errors
 			// But allow zero values for non-enumerated dimensions (like refs)
-			if isZeroValue(fieldVal) && field.Tag.Get("values") != "" {
+			if isZeroValue(fieldVal) && (field.Tag.Get("values") != "" || isOmitEmpty(field)) {
 				// Skip zero values for enumerated dimensions (they would fail validation)
+				// and for fields tagged omitempty
 				continue
 			}
 
@@ -242,11 +253,6 @@ func MarshalDimensionsForUpdate(v interface{}) (dimensions map[string]interface{
 				return nil, nil, err
 			}
 
-			// For values tag style, use lowercase field name as dimension name
-			if field.Tag.Get("values") != "" && dimTag == strings.ToLower(field.Name) {
-				dimName = strings.ToLower(field.Name)
-			}
-
 			// Validate enumerated dimension values against their allowed values
 			if valuesTag := field.Tag.Get("values"); valuesTag != "" {
 				if err = validateEnumeratedValue(value, valuesTag, field.Name); err != nil {
@@ -254,13 +260,20 @@ func MarshalDimensionsForUpdate(v interface{}) (dimensions map[string]interface{
 				}
 			}
 
-			dimensions[dimName] = value
+			// Named types such as `type Status string` are stored as their underlying type
+			dimensions[dimName] = basicValue(value)
 		} else {
 			// Non-dimension field - store in data map
-			// For updates, preserve zero values to allow field clearing
-			// Store all non-dimension fields in data map using snake_case
-			snakeFieldName := normalizeFieldName(field.Name)
-			data[snakeFieldName] = value
+			// For updates, preserve zero values to allow field clearing,
+			// unless the field is tagged omitempty
+			if isOmitEmpty(field) && isZeroValue(fieldVal) {
+				continue
+			}
+			// Store all non-dimension fields in data map using their stored name
+			snakeFieldName := dataFieldName(field)
+			if data[snakeFieldName], err = dataValue(value); err != nil {
+				return nil, nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
 		}
 	}
 
@@ -269,6 +282,14 @@ func MarshalDimensionsForUpdate(v interface{}) (dimensions map[string]interface{
 
 // UnmarshalDimensions populates a struct from a Document, mapping dimensions to tagged fields
 func UnmarshalDimensions(doc nanostore.Document, v interface{}) error {
+	return unmarshalDocument(doc, v, nil)
+}
+
+// unmarshalDocument populates v from doc. When selected is not nil, only the
+// struct fields whose snake_case names are in selected are populated, and the
+// body is left empty unless "body" is selected; the remaining Document
+// metadata is always set.
+func unmarshalDocument(doc nanostore.Document, v interface{}, selected map[string]bool) error {
 	val := reflect.ValueOf(v)
 	if val.Kind() != reflect.Ptr {
 		return fmt.Errorf("expected pointer to struct, got %s", val.Kind())
@@ -290,6 +311,10 @@ func UnmarshalDimensions(doc nanostore.Document, v interface{}) error {
 		}
 	}
 
+	if selected != nil && !selected["body"] {
+		doc.Body = ""
+	}
+
 	// First, populate the embedded Document fields if present
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
@@ -300,9 +325,8 @@ func UnmarshalDimensions(doc nanostore.Document, v interface{}) error {
 	}
 
 	// Then populate dimension fields
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-		fieldVal := val.Field(i)
+	for _, field := range storedFields(typ) {
+		fieldVal := val.FieldByIndex(field.Index)
 
 		// Skip unexported fields
 		if !fieldVal.CanSet() {
@@ -314,6 +338,17 @@ func UnmarshalDimensions(doc nanostore.Document, v interface{}) error {
 			continue
 		}
 
+		// Skip fields outside the projection
+		if selected != nil && !selected[normalizeFieldName(field.Name)] {
+			continue
+		}
+
+		// Set dimensions are stored as lists of values
+		if isSetField(field) {
+			setSetField(fieldVal, doc.Dimensions[dimensionFieldName(field)])
+			continue
+		}
+
 		// Check for dimension tag
 		dimTag := field.Tag.Get("dimension")
 		var dimName string
@@ -324,7 +359,7 @@ func UnmarshalDimensions(doc nanostore.Document, v interface{}) error {
 			isDimension = true
 		} else if field.Tag.Get("values") != "" {
 			// Check for values tag (declarative API style)
-			dimName = strings.ToLower(field.Name)
+			dimName = dimensionFieldName(field)
 			defaultValue = field.Tag.Get("default")
 			isDimension = true
 		}
@@ -367,13 +402,13 @@ func UnmarshalDimensions(doc nanostore.Document, v interface{}) error {
 			}
 		} else {
 			// Non-dimension field - check our extracted data map
-			// Try both snake_case and PascalCase versions
-			snakeFieldName := normalizeFieldName(field.Name)
+			// Try both the stored name and PascalCase versions
+			snakeFieldName := dataFieldName(field)
 
 			var dataValue interface{}
 			var exists bool
 
-			// First try snake_case (canonical storage format)
+			// First try the stored name (snake_case unless renamed by a nanostore tag)
 			if dataValue, exists = dataMap[snakeFieldName]; !exists {
 				// Then try original PascalCase (for backward compatibility)
 				dataValue, exists = dataMap[field.Name]
@@ -425,23 +460,30 @@ func normalizeFieldName(fieldName string) string {
 	return toSnakeCase(fieldName)
 }
 
-// findFieldByName attempts to find a struct field by either snake_case or PascalCase name
+// findFieldByName attempts to find a stored struct field by its Go name, its
+// stored name, or either converted between snake_case and PascalCase. Fields
+// of flattened embedded structs are found by their own names.
 func findFieldByName(typ reflect.Type, fieldName string) (reflect.StructField, bool) {
+	fields := storedFields(typ)
+
 	// First try exact match
-	if field, ok := typ.FieldByName(fieldName); ok {
-		return field, true
+	for _, field := range fields {
+		if field.Name == fieldName || parseFieldTag(field).name == fieldName {
+			return field, true
+		}
 	}
 
 	// Try converting snake_case to PascalCase
 	pascalName := fromSnakeCase(fieldName)
-	if field, ok := typ.FieldByName(pascalName); ok {
-		return field, true
+	for _, field := range fields {
+		if field.Name == pascalName {
+			return field, true
+		}
 	}
 
 	// Try converting PascalCase to snake_case and looking for that
 	snakeName := toSnakeCase(fieldName)
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
+	for _, field := range fields {
 		if toSnakeCase(field.Name) == snakeName {
 			return field, true
 		}
@@ -465,6 +507,8 @@ func isZeroValue(v reflect.Value) bool {
 		return v.String() == ""
 	case reflect.Interface, reflect.Ptr, reflect.Slice, reflect.Map:
 		return v.IsNil()
+	case reflect.Array:
+		return v.IsZero()
 	default:
 		return false
 	}
@@ -549,6 +593,25 @@ func setFieldFromInterface(field reflect.Value, value interface{}) error {
 		return nil
 	}
 
+	// Custom types restore themselves from their stored form
+	if handled, err := setCustomValue(field, value); handled {
+		return err
+	}
+
+	// Nested data is stored in its JSON form
+	if isNestedType(field.Type()) {
+		return setNestedValue(field, value)
+	}
+
+	// Numbers decoded from JSON are float64, which may not print as integers
+	if f, ok := value.(float64); ok && f == float64(int64(f)) {
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.SetInt(int64(f))
+			return nil
+		}
+	}
+
 	// Check if the value is a complex type that we can't convert
 	switch valReflect.Kind() {
 	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
@@ -603,14 +666,7 @@ func getValidDataFields(v interface{}) ([]string, error) {
 	typ := val.Type()
 	var dataFields []string
 
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-
-		// Skip unexported fields
-		if !field.IsExported() {
-			continue
-		}
-
+	for _, field := range storedFields(typ) {
 		// Skip embedded Document field
 		if field.Anonymous && field.Type == reflect.TypeOf(nanostore.Document{}) {
 			continue
@@ -618,13 +674,13 @@ func getValidDataFields(v interface{}) ([]string, error) {
 
 		// Check if this is a dimension field
 		dimTag := field.Tag.Get("dimension")
-		isEnumeratedDimension := field.Tag.Get("values") != ""
+		isEnumeratedDimension := field.Tag.Get("values") != "" || isSetField(field)
 
 		// If it's not a dimension field, it's a data field
 		if dimTag == "" && !isEnumeratedDimension {
-			// Add both PascalCase (Go field name) and snake_case (storage format)
+			// Add both PascalCase (Go field name) and the stored name
 			dataFields = append(dataFields, field.Name)
-			snakeFieldName := normalizeFieldName(field.Name)
+			snakeFieldName := dataFieldName(field)
 			if snakeFieldName != field.Name {
 				dataFields = append(dataFields, snakeFieldName)
 			}
@@ -650,6 +706,15 @@ func getValidDataFields(v interface{}) ([]string, error) {
 //
 // Returns the correct case field name if valid, or an error with suggestions if invalid
 func validateDataFieldName(fieldName string, validFields []string) (string, error) {
+	// Dot paths into nested data validate their top-level field
+	if head, path, isPath := strings.Cut(fieldName, "."); isPath {
+		normalized, err := validateDataFieldName(head, validFields)
+		if err != nil {
+			return "", err
+		}
+		return normalized + "." + path, nil
+	}
+
 	// Convert field names to lowercase for case-insensitive comparison
 	fieldNameLower := strings.ToLower(fieldName)
 
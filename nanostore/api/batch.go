@@ -0,0 +1,195 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// NewDoc describes a document to create with CreateDocument or CreateMany
+type NewDoc[T any] struct {
+	// Title and Body default to those of Data's embedded Document
+	Title string
+	Body  string
+	Data  *T
+
+	// CreatedAt, when set, is recorded as the creation time instead of the
+	// current time, as imports need
+	CreatedAt time.Time
+
+	// Parent is the SimpleID or UUID of an existing parent document
+	Parent string
+
+	// ParentIndex, when set, is the index in the batch of the parent
+	// document, which must come before its children
+	ParentIndex *int
+}
+
+// CreateResult holds the outcome of creating one document with CreateMany:
+// its IDs, or the error that kept it from being created
+type CreateResult struct {
+	UUID     string
+	SimpleID string
+	Error    error
+}
+
+// CreateDocument creates a document with its title, body, data, parent and
+// creation time in one operation, returning its UUID:
+//
+//	id, err := store.CreateDocument(api.NewDoc[Note]{
+//	    Title:     "Meeting notes",
+//	    Body:      body,
+//	    Data:      &Note{Status: "active"},
+//	    Parent:    "1",
+//	    CreatedAt: imported.CreatedAt,
+//	})
+//
+// The document is written once, with its body: hybrid stores keep large
+// bodies in files from the start.
+func (ts *Store[T]) CreateDocument(doc NewDoc[T]) (string, error) {
+	if doc.ParentIndex != nil {
+		return "", fmt.Errorf("parent index is only valid with CreateMany")
+	}
+	prepared, err := ts.newDocument(doc)
+	if err != nil {
+		return "", err
+	}
+	return ts.store.AddDocument(prepared)
+}
+
+// CreateManyOption configures CreateMany
+type CreateManyOption func(*createManyOptions)
+
+type createManyOptions struct {
+	bestEffort bool
+}
+
+// BestEffort makes CreateMany create the valid documents of a batch and
+// report the others in their results, instead of creating none
+func BestEffort() CreateManyOption {
+	return func(o *createManyOptions) {
+		o.bestEffort = true
+	}
+}
+
+// CreateMany creates a batch of documents, validating them all first and
+// saving the store once, which makes importing many documents far cheaper
+// than calling Create for each:
+//
+//	release := 0
+//	results, err := store.CreateMany([]api.NewDoc[Task]{
+//	    {Title: "Release", Data: &Task{Status: "pending"}},
+//	    {Title: "Write notes", Data: &Task{}, ParentIndex: &release},
+//	    {Title: "Fix login", Data: &Task{}, Parent: "2"},
+//	})
+//
+// Results follow the order of docs. By default the batch is all or nothing:
+// when any document is invalid, none is created and the error names the
+// first failure. With BestEffort the valid documents are created and the
+// error is only set when saving fails. Children of a document that is not
+// created fail too.
+func (ts *Store[T]) CreateMany(docs []NewDoc[T], opts ...CreateManyOption) ([]CreateResult, error) {
+	var options createManyOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	results := make([]CreateResult, len(docs))
+	batch := make([]types.NewDocument, 0, len(docs))
+	positions := make(map[int]int, len(docs)) // batch positions of valid docs
+	for i, doc := range docs {
+		prepared, err := ts.newDocument(doc)
+		if err == nil && doc.ParentIndex != nil {
+			if position, valid := positions[*doc.ParentIndex]; valid {
+				prepared.ParentIndex = &position
+			} else if *doc.ParentIndex >= 0 && *doc.ParentIndex < i {
+				err = fmt.Errorf("parent document %d is invalid", *doc.ParentIndex)
+			} else {
+				err = fmt.Errorf("parent index %d must refer to an earlier document of the batch", *doc.ParentIndex)
+			}
+		}
+		if err != nil {
+			results[i].Error = err
+			if !options.bestEffort {
+				return failCreate(results, fmt.Errorf("document %d: %w", i, err))
+			}
+			continue
+		}
+		positions[i] = len(batch)
+		batch = append(batch, prepared)
+	}
+	if len(batch) == 0 {
+		return results, nil
+	}
+
+	added, err := ts.store.AddMany(batch, !options.bestEffort)
+	for i, position := range positions {
+		results[i].UUID = added[position].UUID
+		results[i].Error = added[position].Error
+	}
+	if err != nil {
+		return failCreate(results, err)
+	}
+
+	// SimpleIDs depend on the whole store, so they are read once all
+	// documents are in
+	simpleIDs := make(map[string]string, len(batch))
+	all, err := ts.store.List(types.NewListOptions())
+	if err != nil {
+		return results, fmt.Errorf("failed to read simple ids: %w", err)
+	}
+	for _, doc := range all {
+		simpleIDs[doc.UUID] = doc.SimpleID
+	}
+	for i := range results {
+		results[i].SimpleID = simpleIDs[results[i].UUID]
+	}
+	return results, nil
+}
+
+// newDocument converts a typed batch document to the store's form, with
+// its title and body resolved as Create does
+func (ts *Store[T]) newDocument(doc NewDoc[T]) (types.NewDocument, error) {
+	data := doc.Data
+	if data == nil {
+		data = new(T)
+	}
+	if err := ts.validateData(data); err != nil {
+		return types.NewDocument{}, err
+	}
+	dimensions, err := ts.createDimensions(data)
+	if err != nil {
+		return types.NewDocument{}, err
+	}
+
+	if doc.Parent != "" {
+		hierarchical := ts.config.GetDimensionSet().Hierarchical()
+		if len(hierarchical) == 0 {
+			return types.NewDocument{}, fmt.Errorf("parent requires a hierarchical dimension")
+		}
+		dimensions[hierarchical[0].RefField] = doc.Parent
+	}
+
+	title, body := doc.Title, doc.Body
+	if structTitle, structBody, found := extractDocumentFields(data); found {
+		if title == "" {
+			title = structTitle
+		}
+		if body == "" {
+			body = structBody
+		}
+	}
+	return types.NewDocument{Title: title, Body: body, Dimensions: dimensions, CreatedAt: doc.CreatedAt}, nil
+}
+
+// failCreate marks every document of a batch as not created
+func failCreate(results []CreateResult, err error) ([]CreateResult, error) {
+	for i := range results {
+		results[i].UUID = ""
+		if results[i].Error == nil {
+			results[i].Error = err
+		}
+	}
+	return results, err
+}
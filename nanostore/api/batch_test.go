@@ -0,0 +1,188 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+)
+
+type Milestone struct {
+	nanostore.Document
+	Status   string `values:"open,closed" default:"open"`
+	ParentID string `dimension:"parent_id,ref"`
+	Owner    string `validate:"required"`
+	Slug     string `unique:"true"`
+}
+
+func TestCreateMany(t *testing.T) {
+	store, err := api.New[Milestone](t.TempDir() + "/store.json")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	existing, err := store.Create("Existing", &Milestone{Owner: "alice", Slug: "existing"})
+	if err != nil {
+		t.Fatalf("failed to create milestone: %v", err)
+	}
+	count := func(t *testing.T) int {
+		t.Helper()
+		n, err := store.Query().Count()
+		if err != nil {
+			t.Fatalf("failed to count milestones: %v", err)
+		}
+		return n
+	}
+
+	t.Run("CreatesBatch", func(t *testing.T) {
+		first := 0
+		results, err := store.CreateMany([]api.NewDoc[Milestone]{
+			{Title: "Release", Body: "notes", Data: &Milestone{Owner: "alice", Slug: "release"}},
+			{Data: &Milestone{Document: nanostore.Document{Title: "Docs", Body: "from data"}, Owner: "bob"}, ParentIndex: &first},
+			{Title: "Follow-up", Data: &Milestone{Owner: "carol", Status: "closed"}, Parent: "1"},
+		})
+		if err != nil {
+			t.Fatalf("failed to create batch: %v", err)
+		}
+		for i, result := range results {
+			if result.Error != nil || result.UUID == "" || result.SimpleID == "" {
+				t.Errorf("document %d: unexpected result %+v", i, result)
+			}
+		}
+		if results[1].SimpleID != results[0].SimpleID+".1" {
+			t.Errorf("expected docs to be a child of %s, got %s", results[0].SimpleID, results[1].SimpleID)
+		}
+
+		docs, err := store.Get(results[1].UUID)
+		if err != nil || docs.Title != "Docs" || docs.Body != "from data" || docs.ParentID != results[0].UUID {
+			t.Errorf("unexpected document %+v (%v)", docs, err)
+		}
+		release, err := store.Get(results[0].SimpleID)
+		if err != nil || release.Body != "notes" {
+			t.Errorf("expected the release body, got %+v (%v)", release, err)
+		}
+		followUp, err := store.Get(results[2].UUID)
+		if err != nil || followUp.ParentID != existing {
+			t.Errorf("expected a child of the existing milestone, got %+v (%v)", followUp, err)
+		}
+	})
+
+	t.Run("AllOrNothing", func(t *testing.T) {
+		before := count(t)
+		results, err := store.CreateMany([]api.NewDoc[Milestone]{
+			{Title: "Valid", Data: &Milestone{Owner: "dave"}},
+			{Title: "Duplicate", Data: &Milestone{Owner: "dave", Slug: "release"}},
+		})
+		if err == nil {
+			t.Fatal("expected a unique conflict")
+		}
+		for i, result := range results {
+			if result.Error == nil || result.UUID != "" {
+				t.Errorf("document %d: expected a failure, got %+v", i, result)
+			}
+		}
+		if _, err := store.CreateMany([]api.NewDoc[Milestone]{{Title: "No owner", Data: &Milestone{}}}); err == nil {
+			t.Error("expected a validation error")
+		}
+		if after := count(t); after != before {
+			t.Errorf("expected no documents to be created, got %d more", after-before)
+		}
+	})
+
+	t.Run("BestEffort", func(t *testing.T) {
+		before := count(t)
+		invalid, valid := 0, 1
+		results, err := store.CreateMany([]api.NewDoc[Milestone]{
+			{Title: "No owner", Data: &Milestone{}},
+			{Title: "Orphan", Data: &Milestone{Owner: "erin"}, ParentIndex: &invalid},
+			{Title: "Duplicate", Data: &Milestone{Owner: "erin", Slug: "existing"}},
+			{Title: "Valid", Data: &Milestone{Owner: "erin"}},
+			{Title: "Late", Data: &Milestone{Owner: "erin"}, ParentIndex: &valid},
+			{Title: "Child", Data: &Milestone{Owner: "erin"}, ParentIndex: &[]int{3}[0]},
+		}, api.BestEffort())
+		if err != nil {
+			t.Fatalf("unexpected batch error: %v", err)
+		}
+		for i, failed := range []bool{true, true, true, false, true, false} {
+			if got := results[i].Error != nil; got != failed {
+				t.Errorf("document %d: expected failure %v, got %+v", i, failed, results[i])
+			}
+		}
+		if results[5].SimpleID != results[3].SimpleID+".1" {
+			t.Errorf("expected child of %s, got %s", results[3].SimpleID, results[5].SimpleID)
+		}
+		if after := count(t); after != before+2 {
+			t.Errorf("expected 2 documents to be created, got %d", after-before)
+		}
+	})
+}
+
+func TestCreateDocument(t *testing.T) {
+	store, err := api.New[Milestone](t.TempDir() + "/store.json")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	parent, err := store.Create("Parent", &Milestone{Owner: "alice"})
+	if err != nil {
+		t.Fatalf("failed to create milestone: %v", err)
+	}
+
+	created := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	id, err := store.CreateDocument(api.NewDoc[Milestone]{
+		Title:     "Imported",
+		Body:      "imported body",
+		Data:      &Milestone{Owner: "bob", Status: "closed"},
+		Parent:    "1",
+		CreatedAt: created,
+	})
+	if err != nil {
+		t.Fatalf("failed to create document: %v", err)
+	}
+	imported, err := store.Get(id)
+	if err != nil {
+		t.Fatalf("failed to get milestone: %v", err)
+	}
+	if imported.Title != "Imported" || imported.Body != "imported body" || imported.ParentID != parent || imported.Status != "closed" {
+		t.Errorf("unexpected milestone %+v", imported)
+	}
+	if !imported.CreatedAt.Equal(created) || !imported.UpdatedAt.Equal(created) {
+		t.Errorf("expected the milestone to be dated %v, got %v and %v", created, imported.CreatedAt, imported.UpdatedAt)
+	}
+
+	t.Run("CreateWritesBodyOnce", func(t *testing.T) {
+		id, err := store.Create("", &Milestone{Document: nanostore.Document{Title: "From data", Body: "body"}, Owner: "carol"})
+		if err != nil {
+			t.Fatalf("failed to create milestone: %v", err)
+		}
+		milestone, err := store.Get(id)
+		if err != nil || milestone.Title != "From data" || milestone.Body != "body" {
+			t.Fatalf("unexpected milestone %+v (%v)", milestone, err)
+		}
+		if !milestone.UpdatedAt.Equal(milestone.CreatedAt) {
+			t.Errorf("expected no follow-up update, got %v after %v", milestone.UpdatedAt, milestone.CreatedAt)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		first := 0
+		if _, err := store.CreateDocument(api.NewDoc[Milestone]{Title: "Child", Data: &Milestone{Owner: "dave"}, ParentIndex: &first}); err == nil {
+			t.Error("expected a parent index to be rejected")
+		}
+		if _, err := store.CreateDocument(api.NewDoc[Milestone]{Title: "No owner"}); err == nil {
+			t.Error("expected a validation error")
+		}
+	})
+}
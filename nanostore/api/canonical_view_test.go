@@ -0,0 +1,99 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+)
+
+type CanonicalTask struct {
+	nanostore.Document
+	Status string `values:"pending,active,done" default:"pending" canonical:"active" prefix:"pending=p,done=d"`
+}
+
+func TestCanonicalViewTagAndLens(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[CanonicalTask](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	for _, task := range []CanonicalTask{{Status: "active"}, {Status: "pending"}, {Status: "done"}, {Status: "done"}} {
+		task := task
+		if _, err := store.Create("Task "+task.Status, &task); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+	}
+
+	simpleIDs := func(query *api.Query[CanonicalTask]) []string {
+		t.Helper()
+		tasks, err := query.OrderBy("simple_id").Find()
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		result := []string{}
+		for _, task := range tasks {
+			result = append(result, task.SimpleID)
+		}
+		return result
+	}
+
+	t.Run("CanonicalTag", func(t *testing.T) {
+		if got, expected := store.CanonicalView().String(), "canonical:status:active"; got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+		if got, expected := simpleIDs(store.Query()), []string{"1", "d1", "d2", "p1"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Lens", func(t *testing.T) {
+		lens := store.CanonicalView().With("status", "done")
+		if got, expected := simpleIDs(store.Query().Status("done").Lens(lens)), []string{"1", "2"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+
+		uuid, err := store.ResolveUUIDInView("2", lens)
+		if err != nil {
+			t.Fatalf("failed to resolve lens ID: %v", err)
+		}
+		expected, err := store.ResolveUUID("d2")
+		if err != nil {
+			t.Fatalf("failed to resolve store ID: %v", err)
+		}
+		if uuid != expected {
+			t.Error("expected lens ID 2 to resolve to the document with store ID d2")
+		}
+	})
+
+	t.Run("InvalidCanonicalTag", func(t *testing.T) {
+		type BadTask struct {
+			nanostore.Document
+			Status string `values:"pending,done" canonical:"archived"`
+		}
+		_, err := api.New[BadTask](tmpfile.Name() + ".bad")
+		if err == nil || !strings.Contains(err.Error(), "canonical value") {
+			t.Errorf("expected a canonical value error, got %v", err)
+		}
+	})
+}
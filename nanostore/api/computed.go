@@ -0,0 +1,66 @@
+package api
+
+import (
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// Compute makes an enumerated dimension computed: its value is derived by fn
+// whenever documents are listed or their SimpleIDs are generated or
+// resolved, and is never written to the store. fn receives the document and
+// every document of the store, so values can depend on related documents
+// such as children. An empty result, or one the dimension's values do not
+// include, stands for the dimension's default.
+//
+// Computed values can be filtered and sorted on and take part in SimpleID
+// partitioning, so a prefix shows up as soon as the value changes. Values of
+// a computed field passed to Create or Update are ignored.
+//
+// Register computations right after creating the store, before using it.
+//
+// # Usage Examples
+//
+//	type Task struct {
+//	    nanostore.Document
+//	    Status   string `values:"pending,done" default:"pending"`
+//	    Overdue  string `values:"no,yes" default:"no" prefix:"yes=o"`
+//	    ParentID string `dimension:"parent_id,ref"`
+//	    DueDate  *time.Time
+//	}
+//
+//	err := store.Compute("overdue", func(task Task, _ []Task) string {
+//	    if task.DueDate != nil && task.DueDate.Before(time.Now()) {
+//	        return "yes"
+//	    }
+//	    return "no"
+//	})
+//
+//	// Overdue tasks are listed as o1, o2, ...
+//	overdue, err := store.Query().Where("overdue = ?", "yes").Find()
+func (ts *Store[T]) Compute(dimension string, fn func(item T, items []T) string) error {
+	return ts.config.GetDimensionSet().SetCompute(dimension, func(docs []types.Document) func(types.Document) string {
+		items := make([]T, len(docs))
+		index := make(map[string]int, len(docs))
+		for i, doc := range docs {
+			// Documents that do not fit T still get a value from their other fields
+			_ = UnmarshalDimensions(doc, &items[i])
+			index[doc.UUID] = i
+		}
+
+		return func(doc types.Document) string {
+			if i, exists := index[doc.UUID]; exists {
+				return fn(items[i], items)
+			}
+			var item T
+			_ = UnmarshalDimensions(doc, &item)
+			return fn(item, items)
+		}
+	})
+}
+
+// omitComputed removes computed dimensions from marshaled values, since
+// their values are derived at query time
+func (ts *Store[T]) omitComputed(dimensions map[string]interface{}) {
+	for _, dim := range ts.config.GetDimensionSet().Computed() {
+		delete(dimensions, dim.Name)
+	}
+}
@@ -0,0 +1,179 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+)
+
+type ComputedTask struct {
+	nanostore.Document
+	Status   string `values:"pending,done" default:"pending" prefix:"done=d"`
+	Overdue  string `values:"no,yes" default:"no" prefix:"yes=o"`
+	Blocked  string `values:"no,yes" default:"no" prefix:"yes=b"`
+	ParentID string `dimension:"parent_id,ref"`
+	DueDate  *time.Time
+}
+
+func TestComputedDimensions(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[ComputedTask](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.Compute("overdue", func(task ComputedTask, _ []ComputedTask) string {
+		if task.Status != "done" && task.DueDate != nil && task.DueDate.Before(now) {
+			return "yes"
+		}
+		return "no"
+	}); err != nil {
+		t.Fatalf("failed to register overdue: %v", err)
+	}
+	blockedCalls := 0
+	if err := store.Compute("blocked", func(task ComputedTask, tasks []ComputedTask) string {
+		blockedCalls++
+		for _, other := range tasks {
+			if other.ParentID == task.UUID && other.Status != "done" {
+				return "yes"
+			}
+		}
+		return "no"
+	}); err != nil {
+		t.Fatalf("failed to register blocked: %v", err)
+	}
+
+	yesterday := now.Add(-24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+	lateUUID, err := store.Create("Late", &ComputedTask{DueDate: &yesterday, Overdue: "no"})
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	parentUUID, err := store.Create("Parent", &ComputedTask{DueDate: &tomorrow})
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	childUUID, err := store.Create("Child", &ComputedTask{ParentID: parentUUID})
+	if err != nil {
+		t.Fatalf("failed to create child: %v", err)
+	}
+
+	t.Run("PrefixesInSimpleIDs", func(t *testing.T) {
+		late, err := store.Get(lateUUID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if late.Overdue != "yes" || late.SimpleID != "o1" {
+			t.Errorf("expected overdue task o1, got overdue=%q id=%q", late.Overdue, late.SimpleID)
+		}
+
+		parent, err := store.Get("b1")
+		if err != nil {
+			t.Fatalf("failed to resolve b1: %v", err)
+		}
+		if parent.Title != "Parent" || parent.Blocked != "yes" {
+			t.Errorf("expected blocked Parent at b1, got %q blocked=%q", parent.Title, parent.Blocked)
+		}
+
+		child, err := store.Get(childUUID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if child.SimpleID != "b1.1" {
+			t.Errorf("expected child b1.1, got %q", child.SimpleID)
+		}
+	})
+
+	t.Run("FiltersAndOrdering", func(t *testing.T) {
+		overdue, err := store.Query().Where("overdue = ?", "yes").Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(overdue) != 1 || overdue[0].Title != "Late" {
+			t.Errorf("expected only Late to be overdue, got %d results", len(overdue))
+		}
+
+		ordered, err := store.Query().OrderByDesc("blocked").OrderBy("title").Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ordered) != 3 || ordered[0].Title != "Parent" {
+			t.Errorf("expected the blocked Parent first, got %v", ordered)
+		}
+	})
+
+	t.Run("ComputedOncePerList", func(t *testing.T) {
+		blockedCalls = 0
+		tasks, err := store.List(nanostore.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if blockedCalls != len(tasks) {
+			t.Errorf("expected one computation per task, got %d for %d tasks", blockedCalls, len(tasks))
+		}
+	})
+
+	t.Run("ValuesFollowTheDocuments", func(t *testing.T) {
+		child, err := store.Get(childUUID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		child.Status = "done"
+		if _, err := store.Update(childUUID, child); err != nil {
+			t.Fatalf("failed to update child: %v", err)
+		}
+
+		parent, err := store.Get(parentUUID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parent.Blocked != "no" || parent.SimpleID != "1" {
+			t.Errorf("expected unblocked parent 1, got blocked=%q id=%q", parent.Blocked, parent.SimpleID)
+		}
+	})
+
+	t.Run("NeverPersisted", func(t *testing.T) {
+		raw, err := store.Store().GetByID(lateUUID)
+		if err != nil || raw == nil {
+			t.Fatalf("failed to get stored document: %v", err)
+		}
+		if _, stored := raw.Dimensions["overdue"]; stored {
+			t.Errorf("computed dimension should not be stored, got %v", raw.Dimensions)
+		}
+
+		if _, err := store.AddRaw("Raw", map[string]interface{}{"overdue": "yes"}); err == nil || !strings.Contains(err.Error(), "computed") {
+			t.Errorf("expected an error setting a computed dimension, got %v", err)
+		}
+	})
+
+	t.Run("InvalidRegistrations", func(t *testing.T) {
+		fn := func(ComputedTask, []ComputedTask) string { return "" }
+		if err := store.Compute("missing", fn); err == nil {
+			t.Error("expected an error for an unknown dimension")
+		}
+		if err := store.Compute("ParentID_hierarchy", fn); err == nil {
+			t.Error("expected an error for a hierarchical dimension")
+		}
+	})
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	urlType             = reflect.TypeOf(url.URL{})
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// implements reports whether t or *t implements iface
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// isCustomType reports whether values of t are stored in a form they define
+// themselves: time.Duration as its nanoseconds, so it filters and orders
+// numerically, url.URL as its string, types implementing json.Marshaler as
+// their JSON and encoding.TextMarshaler as their text. time.Time keeps its
+// own RFC3339 handling.
+func isCustomType(t reflect.Type) bool {
+	switch {
+	case t == timeType:
+		return false
+	case t == durationType, t == urlType:
+		return true
+	}
+	return implements(t, jsonMarshalerType) || implements(t, textMarshalerType)
+}
+
+// customValue converts v, of a custom type, to the form it is stored in.
+// Marshalers are checked in the order encoding/json uses.
+func customValue(v reflect.Value) (interface{}, error) {
+	// Methods with pointer receivers need an addressable value
+	if !v.CanAddr() {
+		addressable := reflect.New(v.Type()).Elem()
+		addressable.Set(v)
+		v = addressable
+	}
+
+	switch {
+	case v.Type() == durationType:
+		return v.Int(), nil
+	case v.Type() == urlType:
+		u := v.Addr().Interface().(*url.URL)
+		return u.String(), nil
+	case implements(v.Type(), jsonMarshalerType):
+		data, err := v.Addr().Interface().(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", v.Type(), err)
+		}
+		var stored interface{}
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", v.Type(), err)
+		}
+		return stored, nil
+	default:
+		text, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", v.Type(), err)
+		}
+		return string(text), nil
+	}
+}
+
+// setCustomValue fills a field of a custom type from its stored form. It
+// reports false when the field's type is not one it handles, leaving the
+// field to the regular conversions.
+func setCustomValue(field reflect.Value, value interface{}) (bool, error) {
+	t := field.Type()
+	if t == timeType || !field.CanAddr() {
+		return false, nil
+	}
+
+	switch {
+	case t == durationType:
+		d, err := durationOf(value)
+		if err != nil {
+			return true, err
+		}
+		field.SetInt(int64(d))
+
+	case t == urlType:
+		text, ok := value.(string)
+		if !ok {
+			return true, fmt.Errorf("cannot convert %T to %s", value, t)
+		}
+		u, err := url.Parse(text)
+		if err != nil {
+			return true, fmt.Errorf("failed to parse url '%s': %w", text, err)
+		}
+		field.Set(reflect.ValueOf(*u))
+
+	case implements(t, jsonMarshalerType) && implements(t, jsonUnmarshalerType):
+		data, err := json.Marshal(value)
+		if err != nil {
+			return true, err
+		}
+		if err := field.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(data); err != nil {
+			return true, fmt.Errorf("failed to unmarshal %s: %w", t, err)
+		}
+
+	case implements(t, textUnmarshalerType):
+		text, ok := value.(string)
+		if !ok {
+			return true, fmt.Errorf("cannot convert %T to %s", value, t)
+		}
+		if err := field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+			return true, fmt.Errorf("failed to unmarshal %s: %w", t, err)
+		}
+
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// durationOf reads a stored duration: a number of nanoseconds, or the
+// string form durations were briefly stored in
+func durationOf(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse duration '%s': %w", v, err)
+		}
+		return d, nil
+	case float64:
+		return time.Duration(v), nil
+	case int:
+		return time.Duration(v), nil
+	case int64:
+		return time.Duration(v), nil
+	}
+	return 0, fmt.Errorf("cannot convert %T to time.Duration", value)
+}
+
+// filterValue converts a value given to a filter to the form matching
+// values are stored in: custom types to their stored form and named basic
+// types, such as enum types declared as `type Status string`, to their
+// underlying type. Lists are converted element by element.
+func filterValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if isCustomType(v.Type()) {
+		if stored, err := customValue(v); err == nil {
+			return stored
+		}
+		return value
+	}
+	if v.Kind() == reflect.Slice && needsConversion(v.Type().Elem()) {
+		converted := make([]interface{}, v.Len())
+		for i := range converted {
+			converted[i] = filterValue(v.Index(i).Interface())
+		}
+		return converted
+	}
+	return basicValue(v.Interface())
+}
+
+// filterValues converts the arguments of a WHERE clause with filterValue
+func filterValues(args []interface{}) []interface{} {
+	converted := make([]interface{}, len(args))
+	for i, arg := range args {
+		converted[i] = filterValue(arg)
+	}
+	return converted
+}
+
+// needsConversion reports whether filter values of t may be stored in
+// another form
+func needsConversion(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface || isCustomType(t) || (t.PkgPath() != "" && t.Kind() != reflect.Struct)
+}
+
+// basicValue returns a value of a named basic type as its underlying type,
+// so a Status("active") is stored and compared as the string "active"
+func basicValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type().PkgPath() == "" {
+			return value
+		}
+		return v.Convert(reflect.TypeOf(int64(0))).Interface()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Type().PkgPath() == "" {
+			return value
+		}
+		return v.Convert(reflect.TypeOf(uint64(0))).Interface()
+	case reflect.Float32, reflect.Float64:
+		if v.Type().PkgPath() == "" {
+			return value
+		}
+		return v.Float()
+	}
+	return value
+}
@@ -0,0 +1,219 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+	"github.com/google/uuid"
+)
+
+type Stage string
+
+const (
+	StageDraft     Stage = "draft"
+	StageReview    Stage = "review"
+	StagePublished Stage = "published"
+)
+
+// Version is stored as its text, e.g. "1.2"
+type Version struct {
+	Major, Minor int
+}
+
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%d", v.Major, v.Minor)), nil
+}
+
+func (v *Version) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d.%d", &v.Major, &v.Minor)
+	return err
+}
+
+// Money is stored as its JSON, a number of cents
+type Money struct {
+	Cents int64
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Cents)
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &m.Cents)
+}
+
+type Release struct {
+	nanostore.Document
+	Stage    Stage `values:"draft,review,published" default:"draft"`
+	Version  Version
+	Budget   Money
+	Timeout  time.Duration
+	Build    uuid.UUID
+	Homepage url.URL
+	Mirror   *url.URL
+}
+
+func TestCustomTypes(t *testing.T) {
+	path := t.TempDir() + "/store.json"
+	store, err := api.New[Release](path)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	build := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	homepage, _ := url.Parse("https://example.com/releases?tab=notes")
+	mirror, _ := url.Parse("https://mirror.example.com/")
+	stable, err := store.Create("Stable", &Release{
+		Stage:    StagePublished,
+		Version:  Version{Major: 1, Minor: 2},
+		Budget:   Money{Cents: 1250},
+		Timeout:  90 * time.Second,
+		Build:    build,
+		Homepage: *homepage,
+		Mirror:   mirror,
+	})
+	if err != nil {
+		t.Fatalf("failed to create release: %v", err)
+	}
+	if _, err := store.Create("Nightly", &Release{Version: Version{Major: 2}, Timeout: time.Hour}); err != nil {
+		t.Fatalf("failed to create release: %v", err)
+	}
+
+	check := func(t *testing.T, store *api.Store[Release]) {
+		t.Run("RoundTrip", func(t *testing.T) {
+			release, err := store.Get(stable)
+			if err != nil {
+				t.Fatalf("failed to get release: %v", err)
+			}
+			if release.Stage != StagePublished || release.Version != (Version{Major: 1, Minor: 2}) || release.Budget.Cents != 1250 {
+				t.Errorf("unexpected values: %+v", release)
+			}
+			if release.Timeout != 90*time.Second || release.Build != build {
+				t.Errorf("unexpected values: %+v", release)
+			}
+			if release.Homepage.String() != homepage.String() || release.Mirror == nil || release.Mirror.Host != "mirror.example.com" {
+				t.Errorf("unexpected urls: %v, %v", release.Homepage, release.Mirror)
+			}
+		})
+
+		t.Run("NamedEnumDefault", func(t *testing.T) {
+			nightly, err := store.Query().Data("Timeout", time.Hour).First()
+			if err != nil || nightly.Stage != StageDraft || nightly.Mirror != nil {
+				t.Errorf("expected a draft without mirror, got %+v (%v)", nightly, err)
+			}
+		})
+
+		t.Run("Filters", func(t *testing.T) {
+			for name, query := range map[string]*api.Query[Release]{
+				"Version":  store.Query().Data("Version", Version{Major: 1, Minor: 2}),
+				"Budget":   store.Query().Data("Budget", Money{Cents: 1250}),
+				"Timeout":  store.Query().DataIn("Timeout", time.Minute, 90*time.Second),
+				"Build":    store.Query().Data("Build", build),
+				"Homepage": store.Query().Data("Homepage", homepage),
+				"Stage":    store.Query().Where("stage = ?", StagePublished),
+				"Where":    store.Query().Where("_data.build = ? AND _data.timeout != ?", build, time.Hour),
+			} {
+				releases, err := query.Find()
+				if err != nil || len(releases) != 1 || releases[0].Title != "Stable" {
+					t.Errorf("%s: expected Stable, got %d releases (%v)", name, len(releases), err)
+				}
+			}
+		})
+	}
+
+	t.Run("InMemory", func(t *testing.T) { check(t, store) })
+
+	t.Run("Update", func(t *testing.T) {
+		release, err := store.Get(stable)
+		if err != nil {
+			t.Fatalf("failed to get release: %v", err)
+		}
+		release.Stage = StageReview
+		release.Timeout = 2 * time.Minute
+		if _, err := store.Update(stable, release); err != nil {
+			t.Fatalf("failed to update release: %v", err)
+		}
+		count, err := store.Query().Where("stage = ? AND _data.timeout = ?", StageReview, 2*time.Minute).Count()
+		if err != nil || count != 1 {
+			t.Errorf("expected the updated release, got %d (%v)", count, err)
+		}
+		release.Timeout = 90 * time.Second
+		release.Stage = StagePublished
+		if _, err := store.Update(stable, release); err != nil {
+			t.Fatalf("failed to update release: %v", err)
+		}
+	})
+	_ = store.Close()
+
+	reopened, err := api.New[Release](path)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	defer func() { _ = reopened.Close() }()
+	t.Run("Reloaded", func(t *testing.T) { check(t, reopened) })
+}
+
+type Job struct {
+	nanostore.Document
+	Stage   Stage `values:"draft,review,published" default:"draft"`
+	Timeout time.Duration
+}
+
+func TestDurationRangesAndOrdering(t *testing.T) {
+	path := t.TempDir() + "/store.json"
+	store, err := api.New[Job](path)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	for title, timeout := range map[string]time.Duration{"Short": 30 * time.Second, "Medium": 2 * time.Minute, "Long": 10 * time.Minute} {
+		if _, err := store.Create(title, &Job{Timeout: timeout}); err != nil {
+			t.Fatalf("failed to create job: %v", err)
+		}
+	}
+
+	check := func(t *testing.T, store *api.Store[Job]) {
+		longer, err := store.Query().Where("_data.timeout > ?", time.Minute).OrderBy("title").Find()
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if len(longer) != 2 || longer[0].Title != "Long" || longer[1].Title != "Medium" {
+			t.Errorf("expected Long and Medium to run over a minute, got %+v", longer)
+		}
+
+		ordered, err := store.Query().OrderBy("timeout").Find()
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		var titles []string
+		for _, job := range ordered {
+			titles = append(titles, job.Title)
+		}
+		if fmt.Sprint(titles) != "[Short Medium Long]" {
+			t.Errorf("expected jobs ordered by duration, got %v", titles)
+		}
+	}
+
+	t.Run("InMemory", func(t *testing.T) { check(t, store) })
+	_ = store.Close()
+
+	reopened, err := api.New[Job](path)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	defer func() { _ = reopened.Close() }()
+	t.Run("Reloaded", func(t *testing.T) { check(t, reopened) })
+}
@@ -0,0 +1,309 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/store"
+)
+
+// FieldError describes a data field value that breaks one of the rules of
+// its validate tag
+type FieldError struct {
+	Field   string // snake_case name of the data field, as stored
+	Rule    string // the rule that failed, such as "required" or "max"
+	Message string // what the value must satisfy, such as "is required"
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// ValidationError aggregates every FieldError of a document, so that all
+// invalid fields are reported at once
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// validationRule is a parsed rule of a validate tag
+type validationRule struct {
+	name    string
+	limit   float64
+	pattern *regexp.Regexp
+	options []string
+}
+
+// fieldValidation holds the rules of one data field of T
+type fieldValidation struct {
+	index []int
+	name  string
+	rules []validationRule
+}
+
+// parseValidationRules collects the validate tags of the data fields of typ:
+//
+//	AssignedTo  string `validate:"required,max=50"`
+//	Description string `validate:"min=1,max=200"`
+//	Code        string `validate:"regex=^[a-z]+$"`
+//	Size        string `validate:"oneof=s m l"`
+//
+// Rules are required, min and max (length of strings, value of numbers),
+// regex (strings) and oneof (space separated values). Apart from required,
+// rules skip unset values: empty strings and nil pointers. A regex may
+// contain commas only as the last rule of the tag.
+func parseValidationRules(typ reflect.Type) ([]fieldValidation, error) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	var validations []fieldValidation
+	for _, field := range storedFields(typ) {
+		tag, exists := field.Tag.Lookup("validate")
+		if !exists {
+			continue
+		}
+		if field.Tag.Get("values") != "" || field.Tag.Get("dimension") != "" || isSetField(field) {
+			return nil, fmt.Errorf("field '%s': validate tags are only supported on data fields", field.Name)
+		}
+
+		rules, err := parseValidateTag(tag, field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", field.Name, err)
+		}
+		validations = append(validations, fieldValidation{
+			index: field.Index,
+			name:  dataFieldName(field),
+			rules: rules,
+		})
+	}
+	return validations, nil
+}
+
+// parseValidateTag parses the rules of a validate tag and checks that they
+// apply to the field's type
+func parseValidateTag(tag string, fieldType reflect.Type) ([]validationRule, error) {
+	kind := fieldType.Kind()
+	if kind == reflect.Ptr {
+		kind = fieldType.Elem().Kind()
+	}
+	isString := kind == reflect.String
+	isNumber := (kind >= reflect.Int && kind <= reflect.Uint64) || kind == reflect.Float32 || kind == reflect.Float64
+
+	var rules []validationRule
+	parts := strings.Split(tag, ",")
+	for i := 0; i < len(parts); i++ {
+		part := strings.TrimSpace(parts[i])
+		if part == "" {
+			continue
+		}
+		name, arg, _ := strings.Cut(part, "=")
+		rule := validationRule{name: name}
+
+		switch name {
+		case "required":
+		case "min", "max":
+			if !isString && !isNumber {
+				return nil, fmt.Errorf("validate rule '%s' requires a string or number field", name)
+			}
+			limit, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return nil, fmt.Errorf("validate rule '%s' needs a number, got '%s'", name, arg)
+			}
+			rule.limit = limit
+		case "regex":
+			if !isString {
+				return nil, fmt.Errorf("validate rule 'regex' requires a string field")
+			}
+			// The pattern runs to the end of the tag when it contains commas
+			if i < len(parts)-1 {
+				arg = strings.Join(append([]string{arg}, parts[i+1:]...), ",")
+				i = len(parts)
+			}
+			pattern, err := regexp.Compile(arg)
+			if err != nil {
+				return nil, fmt.Errorf("validate rule 'regex': %w", err)
+			}
+			rule.pattern = pattern
+		case "oneof":
+			rule.options = strings.Fields(arg)
+			if len(rule.options) == 0 {
+				return nil, fmt.Errorf("validate rule 'oneof' needs at least one value")
+			}
+		default:
+			return nil, fmt.Errorf("unknown validate rule '%s' (valid rules: required, min, max, regex, oneof)", name)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// check returns the FieldErrors of a field value
+func (fv fieldValidation) check(value reflect.Value) []FieldError {
+	var errs []FieldError
+	fail := func(rule, format string, args ...interface{}) {
+		errs = append(errs, FieldError{Field: fv.name, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// A set pointer satisfies required even when it points to a zero value
+	missing, unset := false, false
+	if value.Kind() == reflect.Ptr {
+		missing, unset = value.IsNil(), value.IsNil()
+		if !unset {
+			value = value.Elem()
+		}
+	} else if value.Kind() == reflect.String {
+		unset = value.String() == ""
+		missing = strings.TrimSpace(value.String()) == ""
+	} else {
+		missing = isZeroValue(value)
+	}
+
+	for _, rule := range fv.rules {
+		if rule.name == "required" {
+			if missing {
+				fail("required", "is required")
+			}
+			continue
+		}
+		if unset {
+			continue
+		}
+
+		switch rule.name {
+		case "min", "max":
+			if value.Kind() == reflect.String {
+				length := float64(utf8.RuneCountInString(value.String()))
+				if rule.name == "min" && length < rule.limit {
+					fail("min", "must be at least %v characters", rule.limit)
+				} else if rule.name == "max" && length > rule.limit {
+					fail("max", "must be at most %v characters", rule.limit)
+				}
+				continue
+			}
+			number := numberOf(value)
+			if rule.name == "min" && number < rule.limit {
+				fail("min", "must be at least %v", rule.limit)
+			} else if rule.name == "max" && number > rule.limit {
+				fail("max", "must be at most %v", rule.limit)
+			}
+		case "regex":
+			if !rule.pattern.MatchString(value.String()) {
+				fail("regex", "must match %s", rule.pattern)
+			}
+		case "oneof":
+			text := fmt.Sprintf("%v", value.Interface())
+			found := false
+			for _, option := range rule.options {
+				if option == text {
+					found = true
+					break
+				}
+			}
+			if !found {
+				fail("oneof", "must be one of: %s", strings.Join(rule.options, ", "))
+			}
+		}
+	}
+	return errs
+}
+
+// numberOf returns the value of a numeric field as a float64
+func numberOf(value reflect.Value) float64 {
+	switch {
+	case value.CanInt():
+		return float64(value.Int())
+	case value.CanUint():
+		return float64(value.Uint())
+	case value.CanFloat():
+		return value.Float()
+	}
+	return 0
+}
+
+// validateData checks the data fields of item against their validate tags,
+// returning a *ValidationError listing every invalid field
+func (ts *Store[T]) validateData(item *T) error {
+	return ts.validateFields(item, nil)
+}
+
+// validateFields checks the data fields of item whose stored names are in
+// only, or all of them when only is nil, as validateData does
+func (ts *Store[T]) validateFields(item *T, only map[string]bool) error {
+	if item == nil || len(ts.validations) == 0 {
+		return nil
+	}
+
+	val := reflect.ValueOf(item).Elem()
+	var errs []FieldError
+	for _, fv := range ts.validations {
+		if only != nil && !only[fv.name] {
+			continue
+		}
+		errs = append(errs, fv.check(val.FieldByIndex(fv.index))...)
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+// validateDocument checks the data fields of a stored or imported document
+func (ts *Store[T]) validateDocument(doc nanostore.Document) error {
+	var item T
+	if err := UnmarshalDimensions(doc, &item); err != nil {
+		return err
+	}
+	return ts.validateData(&item)
+}
+
+// validatingStore checks the data fields of documents added through it, so
+// that imports follow the validate tags of T
+type validatingStore[T any] struct {
+	store.Store
+	typed *Store[T]
+}
+
+// Add validates the data fields before adding the document
+func (vs *validatingStore[T]) Add(title string, dimensions map[string]interface{}) (string, error) {
+	if err := vs.typed.validateDocument(nanostore.Document{Title: title, Dimensions: dimensions}); err != nil {
+		return "", err
+	}
+	return vs.Store.Add(title, dimensions)
+}
+
+// AddDocument validates the data fields before adding the document
+func (vs *validatingStore[T]) AddDocument(doc nanostore.NewDocument) (string, error) {
+	if err := vs.typed.validateDocument(nanostore.Document{Title: doc.Title, Body: doc.Body, Dimensions: doc.Dimensions}); err != nil {
+		return "", err
+	}
+	return vs.Store.AddDocument(doc)
+}
+
+// Import imports documents from a directory or zip file, as
+// nanostore.ImportFromPath does, checking each document's data fields
+// against the validate tags of T. Documents that fail validation are
+// listed in the result's Failed documents and the import continues.
+func (ts *Store[T]) Import(path string, options nanostore.ImportOptions) (*nanostore.ImportResult, error) {
+	return nanostore.ImportFromPath(&validatingStore[T]{Store: ts.store, typed: ts}, path, options)
+}
+
+// ImportData imports documents from an ImportData structure, as
+// nanostore.ProcessImportData does, with the checks of Import
+func (ts *Store[T]) ImportData(data nanostore.ImportData, options nanostore.ImportOptions) (*nanostore.ImportResult, error) {
+	return nanostore.ProcessImportData(&validatingStore[T]{Store: ts.store, typed: ts}, data, options)
+}
@@ -0,0 +1,186 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+)
+
+type ValidatedTask struct {
+	nanostore.Document
+	Status   string `values:"pending,done" default:"pending"`
+	Assignee string `validate:"required,max=10"`
+	Code     string `validate:"regex=^[a-z]{2,4}$"`
+	Size     string `validate:"oneof=s m l"`
+	Estimate *int   `validate:"min=1,max=100"`
+}
+
+func validationFields(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var validationErr *api.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	fields := make(map[string]string)
+	for _, field := range validationErr.Fields {
+		fields[field.Field] = field.Rule
+	}
+	return fields
+}
+
+func TestDataFieldValidation(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[ValidatedTask](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	estimate := 5
+	id, err := store.Create("Valid", &ValidatedTask{Assignee: "alice", Code: "ab", Size: "m", Estimate: &estimate})
+	if err != nil {
+		t.Fatalf("expected a valid task to be created: %v", err)
+	}
+
+	t.Run("CreateReportsEveryField", func(t *testing.T) {
+		tooBig := 500
+		_, err := store.Create("Invalid", &ValidatedTask{Assignee: "  ", Code: "ABC", Size: "xl", Estimate: &tooBig})
+		fields := validationFields(t, err)
+		expected := map[string]string{"assignee": "required", "code": "regex", "size": "oneof", "estimate": "max"}
+		for field, rule := range expected {
+			if fields[field] != rule {
+				t.Errorf("expected %s to fail %s, got %v", field, rule, fields)
+			}
+		}
+		if !strings.HasPrefix(err.Error(), "validation failed: ") {
+			t.Errorf("unexpected message: %v", err)
+		}
+	})
+
+	t.Run("UnsetValuesOnlyFailRequired", func(t *testing.T) {
+		if _, err := store.Create("Minimal", &ValidatedTask{Assignee: "bob"}); err != nil {
+			t.Errorf("expected unset optional fields to pass, got %v", err)
+		}
+	})
+
+	t.Run("Updates", func(t *testing.T) {
+		task, err := store.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		task.Assignee = "a very long assignee name"
+		if _, err := store.Update(id, task); validationFields(t, err)["assignee"] != "max" {
+			t.Errorf("expected Update to fail max, got %v", err)
+		}
+
+		if _, err := store.UpdateWhere("status = ?", &ValidatedTask{Size: "m"}, "pending"); validationFields(t, err)["assignee"] != "required" {
+			t.Errorf("expected UpdateWhere to fail required, got %v", err)
+		}
+
+		unchanged, err := store.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if unchanged.Assignee != "alice" {
+			t.Errorf("expected failed updates to change nothing, got assignee %q", unchanged.Assignee)
+		}
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		createdAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+		result, err := store.ImportData(nanostore.ImportData{Documents: []nanostore.ImportDocument{
+			{Title: "Imported", Body: "Imported body", CreatedAt: &createdAt, Dimensions: map[string]interface{}{"_data.assignee": "carol"}},
+			{Title: "Unassigned", Dimensions: map[string]interface{}{"_data.size": "m"}},
+		}}, nanostore.DefaultImportOptions())
+		if err != nil {
+			t.Fatalf("import failed: %v", err)
+		}
+		if len(result.Imported) != 1 || len(result.Failed) != 1 || result.Failed[0].Title != "Unassigned" {
+			t.Fatalf("expected only the unassigned document to fail, got %+v", result)
+		}
+
+		// Imported documents keep their body and creation time
+		imported, err := store.Get(result.Imported[0].NewUUID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if imported.Body != "Imported body" || !imported.CreatedAt.Equal(createdAt) {
+			t.Errorf("expected the imported body and creation time, got %q and %v", imported.Body, imported.CreatedAt)
+		}
+	})
+
+	t.Run("IntegrityReport", func(t *testing.T) {
+		report, err := store.ValidateStoreIntegrity()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.IsValid {
+			t.Errorf("expected a valid store, got %+v", report.Errors)
+		}
+
+		// Documents written without the typed API can break the rules
+		rawUUID, err := store.AddRaw("Raw", map[string]interface{}{"_data.code": "NOPE"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		report, err = store.ValidateStoreIntegrity()
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for _, e := range report.Errors {
+			if e.Type == "INVALID_DATA_FIELD" && e.DocumentID == rawUUID {
+				count++
+			}
+		}
+		if count != 2 {
+			t.Errorf("expected assignee and code errors for the raw document, got %+v", report.Errors)
+		}
+	})
+}
+
+func TestValidateTagErrors(t *testing.T) {
+	type UnknownRule struct {
+		nanostore.Document
+		Name string `validate:"required,email"`
+	}
+	if _, err := api.New[UnknownRule]("unused.json"); err == nil || !strings.Contains(err.Error(), "unknown validate rule 'email'") {
+		t.Errorf("expected an unknown rule error, got %v", err)
+	}
+
+	type RegexOnNumber struct {
+		nanostore.Document
+		Count int `validate:"regex=^1$"`
+	}
+	if _, err := api.New[RegexOnNumber]("unused.json"); err == nil {
+		t.Error("expected an error for regex on a number field")
+	}
+
+	type OnDimension struct {
+		nanostore.Document
+		Status string `values:"a,b" default:"a" validate:"required"`
+	}
+	if _, err := api.New[OnDimension]("unused.json"); err == nil {
+		t.Error("expected an error for validate on a dimension")
+	}
+}
@@ -105,16 +105,14 @@ func convertToJSONSchema(typ reflect.Type, config nanostore.Config) (JSONStoreCo
 	}
 
 	// Add data fields (non-dimension fields)
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-
+	for _, field := range storedFields(typ) {
 		// Skip embedded Document field
 		if field.Anonymous && field.Type == reflect.TypeOf(nanostore.Document{}) {
 			continue
 		}
 
 		// Skip dimension fields
-		fieldNameLower := strings.ToLower(field.Name)
+		fieldNameLower := dimensionFieldName(field)
 		if dimensionFields[fieldNameLower] || dimensionFields[field.Name] {
 			continue
 		}
@@ -137,16 +135,14 @@ func convertToJSONSchema(typ reflect.Type, config nanostore.Config) (JSONStoreCo
 
 // findFieldForDimension finds the original struct field that corresponds to a dimension
 func findFieldForDimension(typ reflect.Type, dim types.DimensionConfig) (string, string, bool) {
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-
+	for _, field := range storedFields(typ) {
 		// Skip embedded Document field
 		if field.Anonymous && field.Type == reflect.TypeOf(nanostore.Document{}) {
 			continue
 		}
 
 		// Check if this field matches the dimension
-		fieldNameLower := strings.ToLower(field.Name)
+		fieldNameLower := dimensionFieldName(field)
 
 		// For enumerated dimensions, check if field name matches dimension name
 		if dim.Type == types.Enumerated && fieldNameLower == dim.Name {
@@ -182,7 +178,7 @@ func findFieldForDimension(typ reflect.Type, dim types.DimensionConfig) (string,
 func hasAnyDimensionTag(field reflect.StructField) bool {
 	_, hasValues := field.Tag.Lookup("values")
 	_, hasDimension := field.Tag.Lookup("dimension")
-	return hasValues || hasDimension
+	return hasValues || hasDimension || isSetField(field)
 }
 
 // getFieldTypeString converts Go reflect.Type to JSON schema type string
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+)
+
+var documentType = reflect.TypeOf(nanostore.Document{})
+
+// fieldTag holds the options of a nanostore struct tag:
+//
+//	Owner    string `nanostore:"assignee"`       // stored as "assignee"
+//	Note     string `nanostore:"note,omitempty"` // left as is by updates when empty
+//	Progress int    `nanostore:",omitempty"`     // default name, omitempty
+//	Summary  string `nanostore:"-"`              // never stored
+type fieldTag struct {
+	name      string
+	omitEmpty bool
+	skip      bool
+}
+
+// parseFieldTag parses the nanostore tag of a field
+func parseFieldTag(field reflect.StructField) fieldTag {
+	tag, exists := field.Tag.Lookup("nanostore")
+	if !exists {
+		return fieldTag{}
+	}
+	if tag == "-" {
+		return fieldTag{skip: true}
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	parsed := fieldTag{name: strings.TrimSpace(name)}
+	for _, option := range strings.Split(options, ",") {
+		if strings.TrimSpace(option) == "omitempty" {
+			parsed.omitEmpty = true
+		}
+	}
+	return parsed
+}
+
+// isFlattened reports whether an embedded field has its fields stored as
+// if they were declared on the embedding struct. Embedded structs other than
+// Document are flattened, unless their nanostore tag gives them a name.
+func isFlattened(field reflect.StructField) bool {
+	return field.Anonymous && field.IsExported() &&
+		field.Type.Kind() == reflect.Struct && field.Type != documentType &&
+		!isCustomType(field.Type) && parseFieldTag(field).name == ""
+}
+
+// storedFields returns the fields of a model struct that are stored, in
+// declaration order: exported fields not tagged `nanostore:"-"`, with the
+// fields of flattened embedded structs in place of the struct. The embedded
+// Document is included. Index holds the path to use with FieldByIndex.
+//
+//	type Audit struct {
+//	    CreatedBy string
+//	    UpdatedBy string
+//	}
+//
+//	type Task struct {
+//	    nanostore.Document
+//	    Audit              // stored as created_by and updated_by
+//	}
+func storedFields(typ reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if parseFieldTag(field).skip {
+			continue
+		}
+		if isFlattened(field) {
+			for _, embedded := range storedFields(field.Type) {
+				embedded.Index = append([]int{i}, embedded.Index...)
+				fields = append(fields, embedded)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// dataFieldName returns the name a data field is stored under: its nanostore
+// tag name, or its snake_case Go name
+func dataFieldName(field reflect.StructField) string {
+	if name := parseFieldTag(field).name; name != "" {
+		return name
+	}
+	return normalizeFieldName(field.Name)
+}
+
+// dimensionFieldName returns the name of the dimension declared by a field
+// with a values or multi tag: its nanostore tag name, or its lowercase Go name
+func dimensionFieldName(field reflect.StructField) string {
+	if name := parseFieldTag(field).name; name != "" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+// storedFieldKey returns the key a field is stored under in a document's
+// dimensions, "" for fields that are not stored, and rejects nanostore tags
+// whose names cannot be used
+func storedFieldKey(field reflect.StructField) (string, error) {
+	tag := parseFieldTag(field)
+	if strings.ContainsAny(tag.name, ". ") || strings.HasPrefix(tag.name, "_") {
+		return "", fmt.Errorf("nanostore tag name '%s' cannot contain dots or spaces or start with '_'", tag.name)
+	}
+	if dimTag := field.Tag.Get("dimension"); dimTag != "" {
+		if tag.name != "" {
+			return "", fmt.Errorf("nanostore tag name cannot be combined with a dimension tag, which names the dimension")
+		}
+		if name, _, _ := strings.Cut(dimTag, ","); name != "-" {
+			return name, nil
+		}
+		return "", nil
+	}
+	if field.Tag.Get("values") != "" || isSetField(field) {
+		return dimensionFieldName(field), nil
+	}
+	return "_data." + dataFieldName(field), nil
+}
+
+// isOmitEmpty reports whether updates leave a field as it is when it holds
+// its zero value
+func isOmitEmpty(field reflect.StructField) bool {
+	return parseFieldTag(field).omitEmpty
+}
+
+// dataFieldKey returns the name a data field given by Go, snake_case or
+// stored name is stored under, followed by the stored form of any dot path
+// into it. Unknown fields are returned in snake_case.
+func dataFieldKey(typ reflect.Type, name string) string {
+	head, path, isPath := strings.Cut(name, ".")
+	field, found := findFieldByName(typ, head)
+	if !found {
+		return normalizeFieldName(name)
+	}
+	key := dataFieldName(field)
+	if isPath {
+		key += "." + dataPathKey(field.Type, path)
+	}
+	return key
+}
+
+// dataPathKey returns the stored form of a dot path into a value of type t,
+// resolving struct fields to their stored names. Map keys, and everything
+// below a value of interface type, are stored as they are.
+func dataPathKey(t reflect.Type, path string) string {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			t = t.Elem()
+		}
+		if _, err := strconv.Atoi(segment); err == nil {
+			continue
+		}
+		switch {
+		case t.Kind() == reflect.Interface:
+			return strings.Join(segments, ".")
+		case t.Kind() == reflect.Map:
+			t = t.Elem()
+		case t.Kind() == reflect.Struct && t != timeType:
+			if field, found := findFieldByName(t, segment); found {
+				segments[i] = dataFieldName(field)
+				t = field.Type
+				continue
+			}
+			segments[i] = normalizeFieldName(segment)
+		default:
+			segments[i] = normalizeFieldName(segment)
+		}
+	}
+	return strings.Join(segments, ".")
+}
@@ -0,0 +1,193 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+)
+
+type Audit struct {
+	CreatedBy string
+	UpdatedBy string `nanostore:"editor"`
+}
+
+type Ticket struct {
+	nanostore.Document
+	Audit
+	Phase   string `values:"open,closed" default:"open" nanostore:"state"`
+	Owner   string `nanostore:"assignee"`
+	Notes   string `nanostore:",omitempty"`
+	Summary string `nanostore:"-"`
+}
+
+// RenamedTicket reads the tickets stored by Ticket with renamed Go fields
+type RenamedTicket struct {
+	nanostore.Document
+	Status      string `values:"open,closed" default:"open" nanostore:"state"`
+	Responsible string `nanostore:"assignee"`
+	Author      string `nanostore:"created_by"`
+}
+
+func TestFieldTags(t *testing.T) {
+	path := t.TempDir() + "/store.json"
+	store, err := api.New[Ticket](path)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	id, err := store.Create("Login fails", &Ticket{
+		Audit:   Audit{CreatedBy: "alice", UpdatedBy: "bob"},
+		Phase:   "closed",
+		Owner:   "carol",
+		Notes:   "needs logs",
+		Summary: "computed, never stored",
+	})
+	if err != nil {
+		t.Fatalf("failed to create ticket: %v", err)
+	}
+	if _, err := store.Create("Slow search", &Ticket{Audit: Audit{CreatedBy: "dave"}, Owner: "alice"}); err != nil {
+		t.Fatalf("failed to create ticket: %v", err)
+	}
+
+	t.Run("StoredNames", func(t *testing.T) {
+		raw, err := store.GetRaw(id)
+		if err != nil {
+			t.Fatalf("failed to get raw document: %v", err)
+		}
+		want := map[string]interface{}{
+			"state":            "closed",
+			"_data.assignee":   "carol",
+			"_data.created_by": "alice",
+			"_data.editor":     "bob",
+			"_data.notes":      "needs logs",
+		}
+		for key, value := range want {
+			if raw.Dimensions[key] != value {
+				t.Errorf("expected %s to be %v, got %v", key, value, raw.Dimensions[key])
+			}
+		}
+		for _, key := range []string{"phase", "_data.owner", "_data.summary", "_data.updated_by", "_data.audit"} {
+			if _, exists := raw.Dimensions[key]; exists {
+				t.Errorf("expected no %s, got %v", key, raw.Dimensions[key])
+			}
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		ticket, err := store.Get(id)
+		if err != nil {
+			t.Fatalf("failed to get ticket: %v", err)
+		}
+		if ticket.CreatedBy != "alice" || ticket.UpdatedBy != "bob" || ticket.Phase != "closed" || ticket.Owner != "carol" || ticket.Summary != "" {
+			t.Errorf("unexpected ticket: %+v", ticket)
+		}
+	})
+
+	t.Run("Queries", func(t *testing.T) {
+		for name, query := range map[string]*api.Query[Ticket]{
+			"GoName":       store.Query().Data("Owner", "carol"),
+			"StoredName":   store.Query().Data("assignee", "carol"),
+			"Embedded":     store.Query().Data("CreatedBy", "alice"),
+			"EmbeddedTag":  store.Query().DataIn("UpdatedBy", "bob", "erin"),
+			"Where":        store.Query().Where("state = ? AND _data.editor = ?", "closed", "bob"),
+			"OrderByFirst": store.Query().OrderByData("CreatedBy").Limit(1),
+		} {
+			tickets, err := query.Find()
+			if err != nil || len(tickets) != 1 || tickets[0].Title != "Login fails" {
+				t.Errorf("%s: expected Login fails, got %d tickets (%v)", name, len(tickets), err)
+			}
+		}
+		if _, err := store.Query().Data("Summary", "computed, never stored").Find(); err == nil {
+			t.Error("expected an error filtering on an omitted field")
+		}
+	})
+
+	t.Run("OmitEmptyUpdates", func(t *testing.T) {
+		if _, err := store.Update(id, &Ticket{Phase: "open"}); err != nil {
+			t.Fatalf("failed to update ticket: %v", err)
+		}
+		ticket, err := store.Get(id)
+		if err != nil {
+			t.Fatalf("failed to get ticket: %v", err)
+		}
+		if ticket.Notes != "needs logs" {
+			t.Errorf("expected omitempty notes to be kept, got %q", ticket.Notes)
+		}
+		if ticket.Owner != "" || ticket.CreatedBy != "" {
+			t.Errorf("expected other data fields to be cleared, got %+v", ticket)
+		}
+	})
+	_ = store.Close()
+
+	t.Run("RenamedGoFields", func(t *testing.T) {
+		renamed, err := api.New[RenamedTicket](path)
+		if err != nil {
+			t.Fatalf("failed to open store: %v", err)
+		}
+		defer func() { _ = renamed.Close() }()
+
+		tickets, err := renamed.Query().Data("Author", "dave").Find()
+		if err != nil || len(tickets) != 1 {
+			t.Fatalf("expected one ticket, got %d (%v)", len(tickets), err)
+		}
+		if got := tickets[0]; got.Responsible != "alice" || got.Status != "open" {
+			t.Errorf("unexpected ticket: %+v", got)
+		}
+	})
+}
+
+func TestFieldTagsConfiguration(t *testing.T) {
+	type duplicate struct {
+		nanostore.Document
+		Owner    string `nanostore:"assignee"`
+		Assignee string
+	}
+	type dotted struct {
+		nanostore.Document
+		Owner string `nanostore:"owner.name"`
+	}
+	type withDimension struct {
+		nanostore.Document
+		ParentID string `dimension:"parent_id,ref" nanostore:"parent"`
+	}
+
+	for name, create := range map[string]func() error{
+		"Duplicate":     func() error { _, err := api.New[duplicate](t.TempDir() + "/store.json"); return err },
+		"Dotted":        func() error { _, err := api.New[dotted](t.TempDir() + "/store.json"); return err },
+		"WithDimension": func() error { _, err := api.New[withDimension](t.TempDir() + "/store.json"); return err },
+	} {
+		if err := create(); err == nil {
+			t.Errorf("%s: expected a configuration error", name)
+		}
+	}
+
+	schema, err := func() (*api.TypeSchema, error) {
+		store, err := api.New[Ticket](t.TempDir() + "/store.json")
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = store.Close() }()
+		return store.GetTypeSchema()
+	}()
+	if err != nil {
+		t.Fatalf("failed to get schema: %v", err)
+	}
+	var dataFields []string
+	for _, field := range schema.DataFields {
+		dataFields = append(dataFields, field.Name)
+	}
+	if want := []string{"CreatedBy", "UpdatedBy", "Owner", "Notes"}; !reflect.DeepEqual(dataFields, want) {
+		t.Errorf("expected data fields %v, got %v", want, dataFields)
+	}
+}
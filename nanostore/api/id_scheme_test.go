@@ -0,0 +1,132 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+)
+
+type SchemeTask struct {
+	nanostore.Document `ids:"separator=/,padding=3,prefix=after"`
+	Status             string `values:"pending,done" default:"pending" prefix:"done=d"`
+	ParentID           string `dimension:"parent_id,ref"`
+}
+
+func TestIDSchemeTag(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[SchemeTask](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	parentUUID, err := store.Create("Parent", &SchemeTask{})
+	if err != nil {
+		t.Fatalf("failed to create parent: %v", err)
+	}
+	if _, err := store.Create("Child", &SchemeTask{Status: "done", ParentID: parentUUID}); err != nil {
+		t.Fatalf("failed to create child: %v", err)
+	}
+
+	parent, err := store.Get(parentUUID)
+	if err != nil {
+		t.Fatalf("failed to get parent: %v", err)
+	}
+	if parent.SimpleID != "001" {
+		t.Errorf("expected parent ID 001, got %q", parent.SimpleID)
+	}
+
+	child, err := store.Get("001/001d")
+	if err != nil {
+		t.Fatalf("failed to get child by its SimpleID: %v", err)
+	}
+	if child.Title != "Child" || child.SimpleID != "001/001d" {
+		t.Errorf("expected Child with ID 001/001d, got %q with ID %q", child.Title, child.SimpleID)
+	}
+
+	t.Run("InvalidTags", func(t *testing.T) {
+		type UnknownKey struct {
+			nanostore.Document `ids:"style=fancy"`
+			Status             string `values:"pending,done"`
+		}
+		if _, err := api.New[UnknownKey](tmpfile.Name() + ".bad"); err == nil || !strings.Contains(err.Error(), "unknown ids tag key") {
+			t.Errorf("expected an unknown key error, got %v", err)
+		}
+
+		type Ambiguous struct {
+			nanostore.Document `ids:"numbering=letters"`
+			Status             string `values:"pending,done" prefix:"done=d"`
+		}
+		if _, err := api.New[Ambiguous](tmpfile.Name() + ".bad"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+			t.Errorf("expected an ambiguity error, got %v", err)
+		}
+	})
+}
+
+type StableTask struct {
+	nanostore.Document `ids:"positions=stable"`
+	Status             string `values:"pending,done" default:"pending"`
+}
+
+func TestStablePositionsTag(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[StableTask](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	for _, title := range []string{"One", "Two", "Three"} {
+		if _, err := store.Create(title, &StableTask{}); err != nil {
+			t.Fatalf("failed to create %s: %v", title, err)
+		}
+	}
+	if err := store.Delete("2", false); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+
+	third, err := store.Get("3")
+	if err != nil || third.Title != "Three" {
+		t.Fatalf("expected Three to keep ID 3, got %v (%v)", third, err)
+	}
+
+	if err := store.Compact(); err != nil {
+		t.Fatalf("compact failed: %v", err)
+	}
+	third, err = store.Get("2")
+	if err != nil || third.Title != "Three" {
+		t.Errorf("expected Three at 2 after compaction, got %v (%v)", third, err)
+	}
+
+	type BadPositions struct {
+		nanostore.Document `ids:"positions=sticky"`
+		Status             string `values:"pending,done"`
+	}
+	if _, err := api.New[BadPositions](tmpfile.Name() + ".bad"); err == nil || !strings.Contains(err.Error(), "positions") {
+		t.Errorf("expected a positions error, got %v", err)
+	}
+}
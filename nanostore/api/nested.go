@@ -0,0 +1,203 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// isNestedType reports whether values of t are stored as nested data: structs
+// other than time.Time, slices and maps. Nested structs honour nanostore tags
+// and flatten their embedded structs like models do.
+func isNestedType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != timeType
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// dataValue converts a data field value to the JSON form documents are
+// persisted in, so it reads the same before and after a reload and can be
+// addressed with dot paths: custom types take their own stored form (see
+// isCustomType), named basic types their underlying type, structs become
+// maps keyed by snake_case field names, slices become []interface{} and
+// times RFC3339 strings. Other values are returned unchanged.
+func dataValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t := reflect.TypeOf(value)
+	if !isCustomType(t) && !isNestedType(t) {
+		return basicValue(value), nil
+	}
+	return storableValue(reflect.ValueOf(value))
+}
+
+// storableValue converts v and everything it holds to its JSON form
+func storableValue(v reflect.Value) (interface{}, error) {
+	if v.IsValid() && isCustomType(v.Type()) {
+		return customValue(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return storableValue(v.Elem())
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v.Interface().(time.Time).Format(time.RFC3339), nil
+		}
+		result := make(map[string]interface{})
+		for _, field := range storedFields(v.Type()) {
+			fieldVal := v.FieldByIndex(field.Index)
+			if isOmitEmpty(field) && (isZeroValue(fieldVal) || fieldVal.IsZero()) {
+				continue
+			}
+			item, err := storableValue(fieldVal)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.Name, err)
+			}
+			result[dataFieldName(field)] = item
+		}
+		return result, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		result := make([]interface{}, v.Len())
+		for i := range result {
+			item, err := storableValue(v.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			result[i] = item
+		}
+		return result, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		result := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			item, err := storableValue(iter.Value())
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", iter.Key().Interface(), err)
+			}
+			result[fmt.Sprintf("%v", iter.Key().Interface())] = item
+		}
+		return result, nil
+	default:
+		return basicValue(v.Interface()), nil
+	}
+}
+
+// setNestedValue fills a struct, slice or map field from its stored JSON form
+func setNestedValue(field reflect.Value, value interface{}) error {
+	switch field.Kind() {
+	case reflect.Struct:
+		stored, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("cannot convert %T to %s", value, field.Type())
+		}
+		for _, structField := range storedFields(field.Type()) {
+			item, exists := stored[dataFieldName(structField)]
+			if !exists {
+				item, exists = stored[structField.Name]
+			}
+			if exists {
+				if err := setFieldFromInterface(field.FieldByIndex(structField.Index), item); err != nil {
+					return fmt.Errorf("%s: %w", structField.Name, err)
+				}
+			}
+		}
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		stored := reflect.ValueOf(value)
+		if stored.Kind() != reflect.Slice && stored.Kind() != reflect.Array {
+			return fmt.Errorf("cannot convert %T to %s", value, field.Type())
+		}
+		target := field
+		if field.Kind() == reflect.Slice {
+			target = reflect.MakeSlice(field.Type(), stored.Len(), stored.Len())
+		}
+		for i := 0; i < stored.Len() && i < target.Len(); i++ {
+			if err := setFieldFromInterface(target.Index(i), stored.Index(i).Interface()); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		field.Set(target)
+		return nil
+
+	case reflect.Map:
+		stored, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("cannot convert %T to %s", value, field.Type())
+		}
+		target := reflect.MakeMapWithSize(field.Type(), len(stored))
+		for key, item := range stored {
+			mapKey := reflect.New(field.Type().Key()).Elem()
+			if err := setFieldValue(mapKey, key); err != nil {
+				return fmt.Errorf("key %q: %w", key, err)
+			}
+			elem := reflect.New(field.Type().Elem()).Elem()
+			if err := setFieldFromInterface(elem, item); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			target.SetMapIndex(mapKey, elem)
+		}
+		field.Set(target)
+		return nil
+	}
+	return fmt.Errorf("cannot convert %T to %s", value, field.Type())
+}
+
+// validateDataPath checks the segments of a dot path after the top-level
+// data field of type t: struct fields by snake_case or Go name, any key of a
+// map, and numeric indexes or element fields of slices
+func validateDataPath(t reflect.Type, path string) error {
+	for _, segment := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		// Paths through lists address the fields of their elements
+		for t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			t = t.Elem()
+			if _, err := strconv.Atoi(segment); err == nil {
+				segment = ""
+			}
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+		}
+		if segment == "" {
+			continue
+		}
+
+		switch {
+		case t.Kind() == reflect.Map || t.Kind() == reflect.Interface:
+			return nil // Keys are not known in advance
+		case t.Kind() == reflect.Struct && t != timeType:
+			field, found := findFieldByName(t, segment)
+			if !found {
+				return fmt.Errorf("field '%s' not found in %s", segment, t.Name())
+			}
+			t = field.Type
+		default:
+			return fmt.Errorf("cannot address '%s' inside %s", segment, t)
+		}
+	}
+	return nil
+}
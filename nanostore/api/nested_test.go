@@ -0,0 +1,148 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+)
+
+type Address struct {
+	Street string
+	City   string
+}
+
+type Subtask struct {
+	Name     string
+	Estimate int
+	Due      time.Time
+}
+
+type Project struct {
+	nanostore.Document
+	Status   string `values:"active,done" default:"active"`
+	Address  Address
+	Subtasks []Subtask
+	Labels   map[string]string
+	Owners   []string
+}
+
+func titlesOf(projects []Project) []string {
+	titles := make([]string, len(projects))
+	for i, p := range projects {
+		titles[i] = p.Title
+	}
+	return titles
+}
+
+func TestNestedDataFields(t *testing.T) {
+	path := t.TempDir() + "/store.json"
+	store, err := api.New[Project](path)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	due := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	paris, err := store.Create("Paris", &Project{
+		Address:  Address{Street: "Rue de Rivoli", City: "Paris"},
+		Subtasks: []Subtask{{Name: "design", Estimate: 2, Due: due}, {Name: "build", Estimate: 5}},
+		Labels:   map[string]string{"team": "core", "homeCity": "Paris"},
+		Owners:   []string{"alice", "bob"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	if _, err := store.Create("Lyon", &Project{
+		Address:  Address{City: "Lyon"},
+		Subtasks: []Subtask{{Name: "plan", Estimate: 1}},
+		Owners:   []string{"carol"},
+	}); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	check := func(t *testing.T, store *api.Store[Project]) {
+		t.Run("RoundTrip", func(t *testing.T) {
+			project, err := store.Get(paris)
+			if err != nil {
+				t.Fatalf("failed to get project: %v", err)
+			}
+			if project.Address.City != "Paris" || len(project.Subtasks) != 2 || project.Subtasks[1].Estimate != 5 {
+				t.Errorf("unexpected nested values: %+v", project)
+			}
+			if !project.Subtasks[0].Due.Equal(due) || project.Labels["team"] != "core" || !reflect.DeepEqual(project.Owners, []string{"alice", "bob"}) {
+				t.Errorf("unexpected nested values: %+v", project)
+			}
+		})
+
+		t.Run("DataFilters", func(t *testing.T) {
+			projects, err := store.Query().Data("address.city", "Lyon").Find()
+			if err != nil || !reflect.DeepEqual(titlesOf(projects), []string{"Lyon"}) {
+				t.Errorf("expected Lyon, got %v (%v)", titlesOf(projects), err)
+			}
+			projects, err = store.Query().Data("Owners", "bob").Find()
+			if err != nil || !reflect.DeepEqual(titlesOf(projects), []string{"Paris"}) {
+				t.Errorf("expected any owner to match, got %v (%v)", titlesOf(projects), err)
+			}
+			projects, err = store.Query().Data("labels.team", "core").Find()
+			if err != nil || !reflect.DeepEqual(titlesOf(projects), []string{"Paris"}) {
+				t.Errorf("expected Paris, got %v (%v)", titlesOf(projects), err)
+			}
+			projects, err = store.Query().Data("Labels.homeCity", "Paris").Find()
+			if err != nil || !reflect.DeepEqual(titlesOf(projects), []string{"Paris"}) {
+				t.Errorf("expected map keys to be matched as they are, got %v (%v)", titlesOf(projects), err)
+			}
+		})
+
+		t.Run("WhereAnySemantics", func(t *testing.T) {
+			projects, err := store.Query().Where("_data.subtasks.estimate > ?", 3).Find()
+			if err != nil || !reflect.DeepEqual(titlesOf(projects), []string{"Paris"}) {
+				t.Errorf("expected any subtask estimate to match, got %v (%v)", titlesOf(projects), err)
+			}
+			projects, err = store.Query().Where("_data.subtasks.name != ?", "design").Find()
+			if err != nil || !reflect.DeepEqual(titlesOf(projects), []string{"Lyon"}) {
+				t.Errorf("expected projects without a design subtask, got %v (%v)", titlesOf(projects), err)
+			}
+			projects, err = store.Query().Where("_data.subtasks.0.name = ?", "plan").Find()
+			if err != nil || !reflect.DeepEqual(titlesOf(projects), []string{"Lyon"}) {
+				t.Errorf("expected an index to select one subtask, got %v (%v)", titlesOf(projects), err)
+			}
+		})
+
+		t.Run("OrderByPath", func(t *testing.T) {
+			projects, err := store.Query().OrderByDataDesc("Address.City").Find()
+			if err != nil || !reflect.DeepEqual(titlesOf(projects), []string{"Paris", "Lyon"}) {
+				t.Errorf("expected Paris before Lyon, got %v (%v)", titlesOf(projects), err)
+			}
+		})
+
+		t.Run("InvalidPaths", func(t *testing.T) {
+			if _, err := store.Query().Data("address.zip", "75001").Find(); err == nil {
+				t.Error("expected an error for an unknown nested field")
+			}
+			if _, err := store.Query().OrderByData("subtasks.owner").Find(); err == nil {
+				t.Error("expected an error for an unknown field of list elements")
+			}
+		})
+	}
+
+	t.Run("InMemory", func(t *testing.T) { check(t, store) })
+	_ = store.Close()
+
+	reopened, err := api.New[Project](path)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	defer func() { _ = reopened.Close() }()
+	t.Run("Reloaded", func(t *testing.T) { check(t, reopened) })
+}
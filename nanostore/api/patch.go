@@ -0,0 +1,215 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+)
+
+// Patch updates only the named fields of a document, leaving all others as
+// they are. Fields are given by Go, snake_case or stored name; "Title" and
+// "Body" set the document's title and body. Unlike Update, zero values are
+// written, so false, 0 and "" can be stored:
+//
+//	store.Patch("1", map[string]any{"Done": false, "Priority": 0})
+//
+// A nil value sets a field to its zero value, and an enumerated field to its
+// default value. Returns (1, nil) on success.
+func (ts *Store[T]) Patch(id string, fields map[string]interface{}) (int, error) {
+	data := new(T)
+	val := reflect.ValueOf(data).Elem()
+	names := make([]string, 0, len(fields))
+	for name, value := range fields {
+		names = append(names, name)
+
+		var target reflect.Value
+		if docField, ok := documentField(name); ok {
+			if target = val.FieldByName("Document"); target.IsValid() {
+				target = target.FieldByName(docField)
+			}
+		} else if field, found := findFieldByName(ts.typ, name); found {
+			target = val.FieldByIndex(field.Index)
+		}
+		if !target.IsValid() || value == nil {
+			// Unknown fields are reported by UpdateFields
+			continue
+		}
+		if err := setFieldFromInterface(target, value); err != nil {
+			return 0, fmt.Errorf("failed to set field %s: %w", name, err)
+		}
+	}
+	sort.Strings(names)
+	return ts.UpdateFields(id, data, names...)
+}
+
+// UpdateFields updates a document with the values data holds for the given
+// fields only, leaving all others as they are:
+//
+//	task.Status = "done"
+//	task.DueDate = nil
+//	store.UpdateFields("1", task, "Status", "DueDate")
+//
+// Fields are given by Go, snake_case or stored name; "Title" and "Body" set
+// the document's title and body. Zero values are written, so a field can be
+// set to false, 0 or "", except enumerated fields, whose zero value is their
+// default value. Only the validate tags of the named fields are checked. The
+// document is looked up and updated in one locked operation. Returns (1, nil)
+// on success.
+func (ts *Store[T]) UpdateFields(id string, data *T, fields ...string) (int, error) {
+	req, err := ts.buildMaskedRequest(data, fields)
+	if err != nil {
+		return 0, err
+	}
+
+	err = ts.store.Modify(id, func(nanostore.Document) (nanostore.UpdateRequest, error) {
+		return req, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Modify applies fn to the current version of a document and saves the
+// result, holding the store's write lock throughout, so that concurrent
+// read-modify-write cycles never lose each other's changes:
+//
+//	err := store.Modify("1", func(task *Task) error {
+//	    task.Votes++
+//	    return nil
+//	})
+//
+// When fn returns an error the document is left unchanged and the error is
+// returned. fn must not call other methods of the store, which would wait
+// for the lock Modify holds.
+func (ts *Store[T]) Modify(id string, fn func(item *T) error) error {
+	return ts.store.Modify(id, func(doc nanostore.Document) (nanostore.UpdateRequest, error) {
+		var item T
+		if err := UnmarshalDimensions(doc, &item); err != nil {
+			return nanostore.UpdateRequest{}, fmt.Errorf("failed to unmarshal document: %w", err)
+		}
+		if err := fn(&item); err != nil {
+			return nanostore.UpdateRequest{}, err
+		}
+		req, err := ts.buildUpdateRequest(&item)
+		if err != nil {
+			return nanostore.UpdateRequest{}, err
+		}
+
+		// Unlike Update, an emptied title or body is written as well
+		req.Title, req.Body = nil, nil
+		if title, body, found := extractDocumentFields(&item); found {
+			if title != doc.Title {
+				req.Title = &title
+			}
+			if body != doc.Body {
+				req.Body = &body
+			}
+		}
+		return req, nil
+	})
+}
+
+// buildMaskedRequest creates an UpdateRequest holding the given fields of
+// data, including zero values
+func (ts *Store[T]) buildMaskedRequest(data *T, fields []string) (nanostore.UpdateRequest, error) {
+	if data == nil {
+		return nanostore.UpdateRequest{}, fmt.Errorf("data cannot be nil")
+	}
+	if len(fields) == 0 {
+		return nanostore.UpdateRequest{}, fmt.Errorf("no fields to update")
+	}
+
+	dimensions, extraData, err := MarshalDimensionsForUpdate(data)
+	if err != nil {
+		return nanostore.UpdateRequest{}, fmt.Errorf("failed to marshal dimensions: %w", err)
+	}
+	for key, value := range extraData {
+		dimensions["_data."+key] = value
+	}
+
+	req := nanostore.UpdateRequest{Dimensions: make(map[string]interface{})}
+	val := reflect.ValueOf(data).Elem()
+	validated := make(map[string]bool)
+	for _, name := range fields {
+		if docField, ok := documentField(name); ok {
+			docVal := val.FieldByName("Document")
+			if !docVal.IsValid() {
+				return nanostore.UpdateRequest{}, fmt.Errorf("field %s requires an embedded nanostore.Document", name)
+			}
+			value := docVal.FieldByName(docField).String()
+			if docField == "Title" {
+				req.Title = &value
+			} else {
+				req.Body = &value
+			}
+			continue
+		}
+
+		field, found := findFieldByName(ts.typ, name)
+		if !found || field.Anonymous {
+			return nanostore.UpdateRequest{}, fmt.Errorf("unknown field '%s' for type %s", name, ts.typ.Name())
+		}
+		key, err := storedFieldKey(field)
+		if err != nil {
+			return nanostore.UpdateRequest{}, err
+		}
+		if key == "" {
+			return nanostore.UpdateRequest{}, fmt.Errorf("field %s is not stored", name)
+		}
+		dim, isDimension := ts.config.GetDimensionSet().Get(key)
+		if isDimension && dim.IsComputed() {
+			return nanostore.UpdateRequest{}, fmt.Errorf("field %s is computed and cannot be updated", name)
+		}
+
+		value, exists := dimensions[key]
+		switch {
+		case exists:
+		case isDimension && dim.Type == nanostore.Enumerated:
+			// An enumerated dimension cannot be empty, so its zero value
+			// stands for its default
+			if dim.DefaultValue == "" {
+				return nanostore.UpdateRequest{}, fmt.Errorf("field %s must be one of %v", name, dim.Values)
+			}
+			value = dim.DefaultValue
+		default:
+			// Zero values of omitempty fields are left out by
+			// MarshalDimensionsForUpdate, but are written when named
+			if value, err = maskedZeroValue(field, val.FieldByIndex(field.Index)); err != nil {
+				return nanostore.UpdateRequest{}, err
+			}
+		}
+		req.Dimensions[key] = value
+		validated[dataFieldName(field)] = true
+	}
+
+	if err := ts.validateFields(data, validated); err != nil {
+		return nanostore.UpdateRequest{}, err
+	}
+	return req, nil
+}
+
+// maskedZeroValue returns the stored form of a zero value left out of
+// marshaled updates
+func maskedZeroValue(field reflect.StructField, fieldVal reflect.Value) (interface{}, error) {
+	if fieldVal.Kind() == reflect.Ptr && fieldVal.IsNil() {
+		return nil, nil
+	}
+	if field.Tag.Get("dimension") != "" || field.Tag.Get("values") != "" {
+		return basicValue(fieldVal.Interface()), nil
+	}
+	return dataValue(fieldVal.Interface())
+}
+
+// documentField returns the Document field a field name refers to, if any
+func documentField(name string) (string, bool) {
+	switch normalizeFieldName(name) {
+	case "title":
+		return "Title", true
+	case "body":
+		return "Body", true
+	}
+	return "", false
+}
@@ -0,0 +1,175 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+)
+
+type Chore struct {
+	nanostore.Document
+	Status   string `values:"todo,doing,done" default:"todo"`
+	Owner    string `validate:"required"`
+	Votes    int
+	Archived bool
+	Note     string `nanostore:",omitempty"`
+	DueDate  *time.Time
+}
+
+func TestPatchAndUpdateFields(t *testing.T) {
+	store, err := api.New[Chore](t.TempDir() + "/store.json")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	due := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	id, err := store.Create("Water plants", &Chore{Status: "doing", Owner: "alice", Votes: 3, Archived: true, Note: "twice", DueDate: &due})
+	if err != nil {
+		t.Fatalf("failed to create chore: %v", err)
+	}
+
+	t.Run("PatchZeroValues", func(t *testing.T) {
+		if _, err := store.Patch(id, map[string]interface{}{"Archived": false, "votes": 0, "Note": "", "DueDate": nil}); err != nil {
+			t.Fatalf("failed to patch chore: %v", err)
+		}
+		chore, err := store.Get(id)
+		if err != nil {
+			t.Fatalf("failed to get chore: %v", err)
+		}
+		if chore.Archived || chore.Votes != 0 || chore.Note != "" || chore.DueDate != nil {
+			t.Errorf("expected cleared fields, got %+v", chore)
+		}
+		if chore.Status != "doing" || chore.Owner != "alice" || chore.Title != "Water plants" {
+			t.Errorf("expected other fields to be kept, got %+v", chore)
+		}
+	})
+
+	t.Run("UpdateFieldsMask", func(t *testing.T) {
+		update := &Chore{Document: nanostore.Document{Title: "Water all plants"}, Status: "done", Votes: 7, DueDate: &due}
+		if _, err := store.UpdateFields(id, update, "Status", "Title", "DueDate"); err != nil {
+			t.Fatalf("failed to update fields: %v", err)
+		}
+		chore, err := store.Get(id)
+		if err != nil {
+			t.Fatalf("failed to get chore: %v", err)
+		}
+		if chore.Status != "done" || chore.Title != "Water all plants" || chore.DueDate == nil || !chore.DueDate.Equal(due) {
+			t.Errorf("expected masked fields to be updated, got %+v", chore)
+		}
+		if chore.Votes != 0 || chore.Owner != "alice" {
+			t.Errorf("expected unmasked fields to be kept, got %+v", chore)
+		}
+	})
+
+	t.Run("PatchNilEnumerated", func(t *testing.T) {
+		if _, err := store.Patch(id, map[string]interface{}{"Status": nil}); err != nil {
+			t.Fatalf("failed to patch chore: %v", err)
+		}
+		chore, err := store.Get(id)
+		if err != nil {
+			t.Fatalf("failed to get chore: %v", err)
+		}
+		if chore.Status != "todo" {
+			t.Errorf("expected the default status, got %q", chore.Status)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := store.UpdateFields(id, &Chore{}, "Owner"); err == nil {
+			t.Error("expected a validation error for the masked owner")
+		}
+		if _, err := store.Patch(id, map[string]interface{}{"Unknown": 1}); err == nil {
+			t.Error("expected an error for an unknown field")
+		}
+		if _, err := store.UpdateFields(id, &Chore{}); err == nil {
+			t.Error("expected an error without fields")
+		}
+		if count, err := store.Patch("99", map[string]interface{}{"Votes": 1}); err == nil || count != 0 {
+			t.Errorf("expected an error for a missing chore, got %d", count)
+		}
+	})
+}
+
+func TestModify(t *testing.T) {
+	store, err := api.New[Chore](t.TempDir() + "/store.json")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	id, err := store.Create("Sweep", &Chore{Owner: "bob"})
+	if err != nil {
+		t.Fatalf("failed to create chore: %v", err)
+	}
+
+	t.Run("ConcurrentIncrements", func(t *testing.T) {
+		const workers, increments = 8, 10
+		var wg sync.WaitGroup
+		errs := make(chan error, workers*increments)
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < increments; i++ {
+					errs <- store.Modify(id, func(chore *Chore) error {
+						chore.Votes++
+						return nil
+					})
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("failed to modify chore: %v", err)
+			}
+		}
+
+		chore, err := store.Get(id)
+		if err != nil {
+			t.Fatalf("failed to get chore: %v", err)
+		}
+		if chore.Votes != workers*increments || chore.Owner != "bob" || chore.Title != "Sweep" {
+			t.Errorf("expected %d votes, got %+v", workers*increments, chore)
+		}
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		abort := errors.New("abort")
+		err := store.Modify(id, func(chore *Chore) error {
+			chore.Votes = 0
+			return abort
+		})
+		if !errors.Is(err, abort) {
+			t.Fatalf("expected the abort error, got %v", err)
+		}
+		chore, err := store.Get(id)
+		if err != nil || chore.Votes == 0 {
+			t.Errorf("expected the chore to be unchanged, got %+v (%v)", chore, err)
+		}
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		if err := store.Modify(id, func(chore *Chore) error { chore.Owner = ""; return nil }); err == nil {
+			t.Error("expected a validation error")
+		}
+		if err := store.Modify("99", func(chore *Chore) error { return nil }); err == nil {
+			t.Error("expected an error for a missing chore")
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+)
+
+func TestQueryFilterAndSelect(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[TodoItem](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	for i := 0; i < 10; i++ {
+		item := &TodoItem{
+			Document: nanostore.Document{Body: fmt.Sprintf("Body of task %d", i)},
+			Status:   "pending",
+			Assignee: "alice",
+			Estimate: i,
+		}
+		if _, err := store.Create(fmt.Sprintf("Task %02d", i), item); err != nil {
+			t.Fatalf("failed to create task %d: %v", i, err)
+		}
+	}
+
+	titles := func(items []TodoItem) []string {
+		var result []string
+		for _, item := range items {
+			result = append(result, item.Title)
+		}
+		return result
+	}
+
+	t.Run("FilterRunsBeforeLimit", func(t *testing.T) {
+		results, err := store.Query().
+			Filter(func(item TodoItem) bool { return item.Estimate%3 == 0 }).
+			OrderBy("title").
+			Limit(2).
+			Find()
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+
+		expected := []string{"Task 00", "Task 03"}
+		if fmt.Sprint(titles(results)) != fmt.Sprint(expected) {
+			t.Errorf("expected %v, got %v", expected, titles(results))
+		}
+	})
+
+	t.Run("FilterWithOffset", func(t *testing.T) {
+		results, err := store.Query().
+			Filter(func(item TodoItem) bool { return item.Estimate%3 == 0 }).
+			OrderBy("title").
+			Offset(1).
+			Limit(2).
+			Find()
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+
+		expected := []string{"Task 03", "Task 06"}
+		if fmt.Sprint(titles(results)) != fmt.Sprint(expected) {
+			t.Errorf("expected %v, got %v", expected, titles(results))
+		}
+	})
+
+	t.Run("FiltersCombineWithStoreFilters", func(t *testing.T) {
+		count, err := store.Query().
+			Where("_data.estimate > ?", 4).
+			Filter(func(item TodoItem) bool { return strings.Contains(item.Body, "task") }).
+			Filter(func(item TodoItem) bool { return item.Estimate%2 == 0 }).
+			Count()
+		if err != nil {
+			t.Fatalf("count failed: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("expected 2 matches (6 and 8), got %d", count)
+		}
+	})
+
+	t.Run("FilterWithFindPage", func(t *testing.T) {
+		var seen []string
+		cursor := ""
+		for pages := 0; pages < 10; pages++ {
+			results, next, err := store.Query().
+				Filter(func(item TodoItem) bool { return item.Estimate%2 == 1 }).
+				OrderBy("title").
+				After(cursor).
+				Limit(2).
+				FindPage()
+			if err != nil {
+				t.Fatalf("page failed: %v", err)
+			}
+			seen = append(seen, titles(results)...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+
+		expected := []string{"Task 01", "Task 03", "Task 05", "Task 07", "Task 09"}
+		if fmt.Sprint(seen) != fmt.Sprint(expected) {
+			t.Errorf("expected %v, got %v", expected, seen)
+		}
+	})
+
+	t.Run("SelectOmitsBodyAndFields", func(t *testing.T) {
+		results, err := store.Query().Select("Assignee").OrderBy("title").Limit(1).Find()
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("expected 1 result, got %d", len(results))
+		}
+
+		item := results[0]
+		if item.Title != "Task 00" || item.UUID == "" || item.SimpleID == "" {
+			t.Errorf("expected metadata to be populated, got %+v", item.Document)
+		}
+		if item.Assignee != "alice" {
+			t.Errorf("expected selected field to be populated, got %q", item.Assignee)
+		}
+		if item.Body != "" {
+			t.Errorf("expected body to be omitted, got %q", item.Body)
+		}
+		if item.Status != "" {
+			t.Errorf("expected unselected field to be zero, got %q", item.Status)
+		}
+	})
+
+	t.Run("SelectBody", func(t *testing.T) {
+		results, err := store.Query().Select("body", "estimate").OrderBy("title").Limit(1).Find()
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if len(results) != 1 || results[0].Body != "Body of task 0" {
+			t.Errorf("expected body to be selected, got %+v", results)
+		}
+	})
+
+	t.Run("SelectWithFilter", func(t *testing.T) {
+		// Predicates see the full value even when the projection drops fields
+		results, err := store.Query().
+			Select("Assignee").
+			Filter(func(item TodoItem) bool { return item.Estimate == 7 && item.Body != "" }).
+			Find()
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if len(results) != 1 || results[0].Title != "Task 07" {
+			t.Fatalf("expected Task 07, got %v", titles(results))
+		}
+		if results[0].Estimate != 0 || results[0].Body != "" {
+			t.Errorf("expected projection to apply, got %+v", results[0])
+		}
+	})
+
+	t.Run("SelectUnknownField", func(t *testing.T) {
+		_, err := store.Query().Select("NoSuchField").Find()
+		if err == nil || !strings.Contains(err.Error(), "NoSuchField") {
+			t.Errorf("expected unknown field error, got %v", err)
+		}
+	})
+}
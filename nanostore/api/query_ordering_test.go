@@ -0,0 +1,102 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore/api"
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestTypedQueryTypeAwareOrdering(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[TodoItem](tmpfile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = store.Close() }()
+
+	items := []struct {
+		title    string
+		priority string
+		estimate int
+		assignee string
+	}{
+		{"task 10", "low", 10, "bob"},
+		{"Task 9", "high", 9, ""},
+		{"task 2", "medium", 2, "alice"},
+	}
+	for _, item := range items {
+		_, err := store.Create(item.title, &TodoItem{
+			Priority: item.priority,
+			Estimate: item.estimate,
+			Assignee: item.assignee,
+		})
+		if err != nil {
+			t.Fatalf("failed to create %q: %v", item.title, err)
+		}
+	}
+
+	// titles renders query results (or the query error) for comparison
+	titles := func(results []TodoItem, err error) string {
+		if err != nil {
+			return "error: " + err.Error()
+		}
+		out := make([]string, len(results))
+		for i, r := range results {
+			out[i] = r.Title
+		}
+		return fmt.Sprint(out)
+	}
+
+	t.Run("NumericDataField", func(t *testing.T) {
+		got := titles(store.Query().OrderByData("Estimate").Find())
+		if got != "[task 2 Task 9 task 10]" {
+			t.Errorf("unexpected order %s", got)
+		}
+	})
+
+	t.Run("DeclaredEnumOrder", func(t *testing.T) {
+		got := titles(store.Query().OrderByDesc("priority").Find())
+		if got != "[Task 9 task 2 task 10]" {
+			t.Errorf("unexpected order %s", got)
+		}
+	})
+
+	t.Run("NaturalCollation", func(t *testing.T) {
+		got := titles(store.Query().OrderBy("title").Collate(types.CollationNatural).Find())
+		if got != "[task 2 Task 9 task 10]" {
+			t.Errorf("unexpected order %s", got)
+		}
+	})
+
+	t.Run("CaseInsensitiveCollation", func(t *testing.T) {
+		got := titles(store.Query().OrderBy("title").Collate(types.CollationCaseInsensitive).Find())
+		if got != "[task 10 task 2 Task 9]" {
+			t.Errorf("unexpected order %s", got)
+		}
+	})
+
+	t.Run("ModifierWithoutOrdering", func(t *testing.T) {
+		_, err := store.Query().NullsFirst().Find()
+		if err == nil {
+			t.Error("expected error when NullsFirst is used without OrderBy")
+		}
+	})
+}
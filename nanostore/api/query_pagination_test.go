@@ -0,0 +1,151 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore/api"
+	"github.com/arthur-debert/nanostore/nanostore/query"
+)
+
+func TestQueryPagination(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[TodoItem](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	for i := 0; i < 10; i++ {
+		status := "pending"
+		if i%2 == 0 {
+			status = "active"
+		}
+		_, err := store.Create(fmt.Sprintf("Task %02d", i), &TodoItem{Status: status, Estimate: i})
+		if err != nil {
+			t.Fatalf("failed to create task %d: %v", i, err)
+		}
+	}
+
+	t.Run("AllIteratesResults", func(t *testing.T) {
+		var titles []string
+		for item, err := range store.Query().Status("active").OrderBy("title").All() {
+			if err != nil {
+				t.Fatalf("iteration failed: %v", err)
+			}
+			titles = append(titles, item.Title)
+		}
+
+		expected := []string{"Task 00", "Task 02", "Task 04", "Task 06", "Task 08"}
+		if fmt.Sprint(titles) != fmt.Sprint(expected) {
+			t.Errorf("expected %v, got %v", expected, titles)
+		}
+	})
+
+	t.Run("AllStopsEarly", func(t *testing.T) {
+		count := 0
+		for _, err := range store.Query().All() {
+			if err != nil {
+				t.Fatalf("iteration failed: %v", err)
+			}
+			count++
+			if count == 3 {
+				break
+			}
+		}
+		if count != 3 {
+			t.Errorf("expected to stop after 3 items, got %d", count)
+		}
+	})
+
+	t.Run("AllYieldsErrors", func(t *testing.T) {
+		var gotErr error
+		for _, err := range store.Query().Data("no_such_field", "x").All() {
+			gotErr = err
+		}
+		if gotErr == nil {
+			t.Error("expected validation error from iterator")
+		}
+	})
+
+	t.Run("FindPageWalksAllPages", func(t *testing.T) {
+		var titles []string
+		cursor := ""
+		pages := 0
+		for {
+			items, next, err := store.Query().OrderByDesc("title").After(cursor).Limit(4).FindPage()
+			if err != nil {
+				t.Fatalf("failed to fetch page: %v", err)
+			}
+			pages++
+			for _, item := range items {
+				titles = append(titles, item.Title)
+			}
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+
+		if pages != 3 {
+			t.Errorf("expected 3 pages, got %d", pages)
+		}
+		if len(titles) != 10 || titles[0] != "Task 09" || titles[9] != "Task 00" {
+			t.Errorf("unexpected page contents: %v", titles)
+		}
+	})
+
+	t.Run("FindPageDefaultOrderSurvivesInserts", func(t *testing.T) {
+		first, cursor, err := store.Query().Limit(5).FindPage()
+		if err != nil {
+			t.Fatalf("failed to fetch first page: %v", err)
+		}
+		if len(first) != 5 || cursor == "" {
+			t.Fatalf("expected a full first page with a cursor, got %d items", len(first))
+		}
+
+		if _, err := store.Create("Task 10", &TodoItem{}); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+
+		rest, next, err := store.Query().After(cursor).Limit(10).FindPage()
+		if err != nil {
+			t.Fatalf("failed to fetch next page: %v", err)
+		}
+		if next != "" {
+			t.Errorf("expected no further pages, got cursor %q", next)
+		}
+		if len(rest) != 6 || rest[len(rest)-1].Title != "Task 10" {
+			t.Errorf("expected remaining 5 tasks plus the new one, got %d items", len(rest))
+		}
+	})
+
+	t.Run("MismatchedOrdering", func(t *testing.T) {
+		_, cursor, err := store.Query().OrderBy("title").Limit(2).FindPage()
+		if err != nil {
+			t.Fatalf("failed to fetch page: %v", err)
+		}
+
+		_, err = store.Query().OrderBy("status").After(cursor).Find()
+		if !errors.Is(err, query.ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+)
+
+type DeadlineItem struct {
+	nanostore.Document
+	Status  string `values:"pending,done" default:"pending"`
+	DueDate time.Time
+	Owner   string
+}
+
+func TestQueryTimeHelpers(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[DeadlineItem](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	// Wednesday 17 January 2024, 15:30 UTC
+	now := time.Date(2024, 1, 17, 15, 30, 0, 0, time.UTC)
+	clock := now
+	if err := store.SetTimeFunc(func() time.Time { return clock }); err != nil {
+		t.Fatalf("failed to set time func: %v", err)
+	}
+
+	items := []struct {
+		title   string
+		created time.Time
+		due     time.Time
+	}{
+		{"Overdue", now.AddDate(0, 0, -20), now.AddDate(0, 0, -2)},
+		{"Due today", now.AddDate(0, 0, -5), now.Add(2 * time.Hour)},
+		{"Due this week", now.AddDate(0, 0, -1), now.AddDate(0, 0, 3)},
+		{"Due next month", now.Add(-time.Hour), now.AddDate(0, 1, 0)},
+	}
+	for _, item := range items {
+		clock = item.created
+		if _, err := store.Create(item.title, &DeadlineItem{DueDate: item.due}); err != nil {
+			t.Fatalf("failed to create %q: %v", item.title, err)
+		}
+	}
+	if _, err := store.Create("No due date", &DeadlineItem{Owner: "alice"}); err != nil {
+		t.Fatalf("failed to create item without due date: %v", err)
+	}
+	clock = now
+
+	titles := func(items []DeadlineItem) []string {
+		result := []string{}
+		for _, item := range items {
+			result = append(result, item.Title)
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		query    *api.Query[DeadlineItem]
+		expected []string
+	}{
+		{"DueBeforeToday", store.Query().TimeBefore("DueDate", "today"), []string{"Overdue"}},
+		{"DueAfterTomorrow", store.Query().TimeAfter("due_date", "tomorrow"), []string{"Due next month", "Due this week"}},
+		{"DueThisWeek", store.Query().TimeWithin("DueDate", "start_of_week", "end_of_week"), []string{"Due this week", "Due today", "Overdue"}},
+		{"CreatedInLastWeek", store.Query().TimeWithin("CreatedAt", "-7d", "now"), []string{"Due next month", "Due this week", "Due today", "No due date"}},
+		{"CreatedBeforeTime", store.Query().TimeBefore("created_at", now.AddDate(0, 0, -2)), []string{"Due today", "Overdue"}},
+		{"CombinedWithWhere", store.Query().TimeBefore("DueDate", "+7d").Where("created_at > ?", "-7d"), []string{"Due this week", "Due today"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := tt.query.Find()
+			if err != nil {
+				t.Fatalf("query failed: %v", err)
+			}
+			if got := titles(results); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("ExpressionsFollowTheClock", func(t *testing.T) {
+		clock = now.AddDate(0, 0, 4)
+		defer func() { clock = now }()
+
+		results, err := store.Query().TimeBefore("DueDate", "today").Find()
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		expected := []string{"Due this week", "Due today", "Overdue"}
+		if got := titles(results); !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		errorCases := []struct {
+			name    string
+			query   *api.Query[DeadlineItem]
+			message string
+		}{
+			{"UnknownField", store.Query().TimeBefore("Deadline", "today"), "unknown field"},
+			{"NotATimeField", store.Query().TimeAfter("Owner", "today"), "not a time.Time"},
+			{"BadExpression", store.Query().TimeWithin("DueDate", "soon", "now"), "invalid date expression"},
+			{"BadValueType", store.Query().TimeBefore("DueDate", 42), "expected a time.Time"},
+		}
+		for _, tc := range errorCases {
+			_, err := tc.query.Find()
+			if err == nil || !strings.Contains(err.Error(), tc.message) {
+				t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.message, err)
+			}
+		}
+	})
+}
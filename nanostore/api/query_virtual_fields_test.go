@@ -0,0 +1,87 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"os"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore/api"
+)
+
+func TestTypedQueryVirtualFields(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[TodoItem](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	create := func(title string, item *TodoItem) string {
+		t.Helper()
+		id, err := store.Create(title, item)
+		if err != nil {
+			t.Fatalf("failed to create %q: %v", title, err)
+		}
+		return id
+	}
+
+	project := create("Project", &TodoItem{Status: "done"})
+	feature := create("Feature", &TodoItem{ParentID: project})
+	create("Subtask", &TodoItem{ParentID: feature, Status: "pending"})
+	create("Docs", &TodoItem{ParentID: project, Status: "pending"})
+	create("Standalone", &TodoItem{Status: "pending"})
+
+	titles := func(results []TodoItem, err error) []string {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		out := make([]string, len(results))
+		for i, r := range results {
+			out[i] = r.Title
+		}
+		return out
+	}
+
+	t.Run("ChildrenOfDoneParents", func(t *testing.T) {
+		got := titles(store.Query().Where("parent.status = ?", "done").OrderBy("title").Find())
+		if len(got) != 2 || got[0] != "Docs" || got[1] != "Feature" {
+			t.Errorf("expected [Docs Feature], got %v", got)
+		}
+	})
+
+	t.Run("PendingLeaves", func(t *testing.T) {
+		got := titles(store.Query().Status("pending").Where("has_children = ?", false).OrderBy("title").Find())
+		if len(got) != 3 || got[0] != "Docs" || got[1] != "Standalone" || got[2] != "Subtask" {
+			t.Errorf("expected [Docs Standalone Subtask], got %v", got)
+		}
+	})
+
+	t.Run("RootsWithDescendants", func(t *testing.T) {
+		got := titles(store.Query().Where("is_root = ? AND descendant_count > ?", true, 0).Find())
+		if len(got) != 1 || got[0] != "Project" {
+			t.Errorf("expected [Project], got %v", got)
+		}
+	})
+
+	t.Run("OrderByDepth", func(t *testing.T) {
+		got := titles(store.Query().OrderByDesc("depth").OrderBy("title").Find())
+		if len(got) != 5 || got[0] != "Subtask" || got[1] != "Docs" || got[2] != "Feature" {
+			t.Errorf("unexpected depth order %v", got)
+		}
+	})
+}
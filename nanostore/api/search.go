@@ -0,0 +1,171 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arthur-debert/nanostore/search"
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// SearchHit is a ranked, typed search result
+type SearchHit[T any] struct {
+	// Item is the matching document
+	Item T
+
+	// Score is the relevance of the match (0.0 to 1.0, higher is better)
+	Score float64
+
+	// MatchType describes the best match found
+	MatchType search.MatchType
+
+	// MatchedFields lists the fields that matched: "title", "body", dimension
+	// names and data field names in snake_case (e.g. "assignee")
+	MatchedFields []string
+
+	// Highlights holds each matched field's text with the matches wrapped in
+	// the highlight markers. Only populated if EnableHighlight is true.
+	Highlights map[string]string
+
+	// Snippets holds excerpts around the matches of each matched field. Only
+	// populated if SnippetContext is positive.
+	Snippets map[string][]string
+}
+
+// Search runs a ranked full-text search over the whole store.
+//
+// It is shorthand for Query().SearchHits(opts); use that form to restrict the
+// search with query filters.
+//
+// # Usage Examples
+//
+//	hits, err := store.Search(search.SearchOptions{
+//	    Query:           `budget "next quarter" -draft`,
+//	    EnableHighlight: true,
+//	    SnippetContext:  40,
+//	})
+//	for _, hit := range hits {
+//	    fmt.Println(hit.Item.Title, hit.Score, hit.Snippets["body"])
+//	}
+func (ts *Store[T]) Search(opts search.SearchOptions) ([]SearchHit[T], error) {
+	return ts.Query().SearchHits(opts)
+}
+
+// SearchHits runs a ranked full-text search over the documents matching the
+// query and returns typed hits, best first.
+//
+// opts.Query uses the search query language (phrases, field scopes,
+// -exclusions and OR; see search.Query), and the other options select fuzzy
+// matching, highlighting and snippets. opts.Fields accepts struct field names
+// in either Go or snake_case form as well as "title" and "body".
+//
+// All query filters apply before searching, including Search(), Where(),
+// Filter() and Select(). Limit() and Offset() apply to the ranked hits and
+// OrderBy() is ignored, since hits are ordered by relevance.
+//
+// # Usage Examples
+//
+//	// Search only within pending tasks of project 2
+//	hits, err := store.Query().
+//	    Status("pending").
+//	    ParentID("2").
+//	    Limit(10).
+//	    SearchHits(search.SearchOptions{Query: "invoice", MaxEdits: 1})
+func (tq *Query[T]) SearchHits(opts search.SearchOptions) ([]SearchHit[T], error) {
+	fields := make([]string, 0, len(opts.Fields))
+	for _, field := range opts.Fields {
+		key, err := tq.typedStore.searchField(field)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, key)
+	}
+	opts.Fields = fields
+
+	// Pagination applies to the ranked hits, not to the candidates
+	limit, offset := tq.options.Limit, tq.options.Offset
+	tq.options.Limit, tq.options.Offset = nil, nil
+	docs, _, err := tq.fetch(true)
+	tq.options.Limit, tq.options.Offset = limit, offset
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := search.NewEngine(documentList(docs)).Search(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset != nil && *offset > 0 {
+		if *offset >= len(results) {
+			results = nil
+		} else {
+			results = results[*offset:]
+		}
+	}
+	if limit != nil && *limit > 0 && *limit < len(results) {
+		results = results[:*limit]
+	}
+
+	hits := make([]SearchHit[T], 0, len(results))
+	for _, result := range results {
+		item, err := tq.decode(result.Document)
+		if err != nil {
+			return nil, err
+		}
+
+		hit := SearchHit[T]{
+			Item:          item,
+			Score:         result.Score,
+			MatchType:     result.MatchType,
+			MatchedFields: make([]string, 0, len(result.MatchedFields)),
+		}
+		for _, field := range result.MatchedFields {
+			hit.MatchedFields = append(hit.MatchedFields, strings.TrimPrefix(field, "_data."))
+		}
+		if result.Highlights != nil {
+			hit.Highlights = make(map[string]string, len(result.Highlights))
+			for field, text := range result.Highlights {
+				hit.Highlights[strings.TrimPrefix(field, "_data.")] = text
+			}
+		}
+		if result.Snippets != nil {
+			hit.Snippets = make(map[string][]string, len(result.Snippets))
+			for field, snippets := range result.Snippets {
+				hit.Snippets[strings.TrimPrefix(field, "_data.")] = snippets
+			}
+		}
+		hits = append(hits, hit)
+	}
+
+	return hits, nil
+}
+
+// searchField maps a field name given to SearchHits to the document field
+// searched by the engine
+func (ts *Store[T]) searchField(name string) (string, error) {
+	if name == "title" || name == "body" || strings.HasPrefix(name, "_data.") {
+		return name, nil
+	}
+
+	normalized := normalizeFieldName(name)
+	if normalized == "title" || normalized == "body" {
+		return normalized, nil
+	}
+	if _, ok := ts.config.GetDimensionSet().Get(normalized); ok {
+		return normalized, nil
+	}
+	if field, found := findFieldByName(ts.typ, name); found {
+		return "_data." + dataFieldName(field), nil
+	}
+
+	return "", fmt.Errorf("search: unknown field %q, available data fields: %v", name, ts.getAvailableDataFields(ts.typ))
+}
+
+// documentList serves already fetched documents to the search engine
+type documentList []types.Document
+
+// GetDocuments implements search.DocumentProvider
+func (d documentList) GetDocuments(filters map[string]interface{}) ([]types.Document, error) {
+	return d, nil
+}
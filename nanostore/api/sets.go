@@ -0,0 +1,161 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// isSetField reports whether a struct field declares a set dimension:
+//
+//	Labels []string `values:"bug,feature,docs" multi:"true"` // members from a list
+//	Tags   []string `multi:"true"`                            // any members
+func isSetField(field reflect.StructField) bool {
+	multi, err := strconv.ParseBool(field.Tag.Get("multi"))
+	return err == nil && multi
+}
+
+// setDimensionConfig builds the set dimension declared by a field with a
+// multi tag. Sets are stored under the lowercase field name.
+func setDimensionConfig(field reflect.StructField) (nanostore.DimensionConfig, error) {
+	dimConfig := nanostore.DimensionConfig{
+		Name: dimensionFieldName(field),
+		Type: nanostore.Set,
+	}
+
+	if field.Type.Kind() != reflect.Slice || field.Type.Elem().Kind() != reflect.String {
+		return dimConfig, fmt.Errorf("multi fields must be []string, got %s", field.Type)
+	}
+	if valuesTag, valuesExist := field.Tag.Lookup("values"); valuesExist {
+		if err := parseValuesTag(valuesTag, &dimConfig, field.Name); err != nil {
+			return dimConfig, err
+		}
+	}
+	for _, tag := range []string{"default", "prefix", "canonical", "transitions", "track_changes", "dimension", "unique"} {
+		if _, exists := field.Tag.Lookup(tag); exists {
+			return dimConfig, fmt.Errorf("%s tag is not supported on multi fields", tag)
+		}
+	}
+	return dimConfig, nil
+}
+
+// setFieldMembers returns the members held by a set field, nil when empty
+func setFieldMembers(fieldVal reflect.Value) []string {
+	if fieldVal.Len() == 0 {
+		return nil
+	}
+	members := make([]string, fieldVal.Len())
+	for i := range members {
+		members[i] = fieldVal.Index(i).String()
+	}
+	return types.NormalizeSet(members)
+}
+
+// setSetField fills a set field from a stored set value
+func setSetField(fieldVal reflect.Value, value interface{}) {
+	members := types.SetMembers(value)
+	slice := reflect.MakeSlice(fieldVal.Type(), len(members), len(members))
+	for i, member := range members {
+		slice.Index(i).SetString(member)
+	}
+	fieldVal.Set(slice)
+}
+
+// setDimension resolves a field given to the set helpers, by Go or
+// dimension name, to its set dimension
+func (ts *Store[T]) setDimension(field string) (string, error) {
+	if match, found := findFieldByName(ts.typ, field); found && isSetField(match) {
+		return dimensionFieldName(match), nil
+	}
+
+	var names []string
+	for _, dim := range ts.config.GetDimensionSet().Sets() {
+		if dim.Name == strings.ToLower(field) || dim.Name == strings.ReplaceAll(normalizeFieldName(field), "_", "") {
+			return dim.Name, nil
+		}
+		names = append(names, dim.Name)
+	}
+	return "", fmt.Errorf("unknown set field '%s', available set fields: %v", field, names)
+}
+
+// AddToSet adds values to a set field of a document, without reading the
+// document first. Values already present are left as they are.
+//
+//	err := store.AddToSet("1", "Tags", "urgent", "backend")
+func (ts *Store[T]) AddToSet(id string, field string, values ...string) error {
+	return ts.updateSet(id, field, types.AddToSet(values...))
+}
+
+// RemoveFromSet removes values from a set field of a document, without
+// reading the document first. Values that are not present are ignored.
+//
+//	err := store.RemoveFromSet("1", "Tags", "urgent")
+func (ts *Store[T]) RemoveFromSet(id string, field string, values ...string) error {
+	return ts.updateSet(id, field, types.RemoveFromSet(values...))
+}
+
+// updateSet applies a SetUpdate to a set field of a document
+func (ts *Store[T]) updateSet(id string, field string, update types.SetUpdate) error {
+	dimension, err := ts.setDimension(field)
+	if err != nil {
+		return err
+	}
+	return ts.store.Update(id, types.UpdateRequest{
+		Dimensions: map[string]interface{}{dimension: update},
+	})
+}
+
+// Contains filters for documents whose set field holds value.
+//
+// Examples:
+//
+//	// Notes tagged "work"
+//	work, err := store.Query().Contains("Tags", "work").Find()
+//
+//	// Calls chain: notes tagged both "work" and "urgent"
+//	both, err := store.Query().Contains("Tags", "work").Contains("Tags", "urgent").Find()
+func (tq *Query[T]) Contains(field string, value string) *Query[T] {
+	return tq.setCondition("Contains", field, types.Contains(value))
+}
+
+// ContainsAny filters for documents whose set field holds at least one of
+// values.
+//
+// Examples:
+//
+//	// Bugs and regressions alike
+//	issues, err := store.Query().ContainsAny("Labels", "bug", "regression").Find()
+func (tq *Query[T]) ContainsAny(field string, values ...string) *Query[T] {
+	return tq.setCondition("ContainsAny", field, types.ContainsAny(values...))
+}
+
+// ContainsAll filters for documents whose set field holds every one of
+// values.
+//
+// Examples:
+//
+//	// Documentation bugs
+//	docBugs, err := store.Query().ContainsAll("Labels", "bug", "docs").Find()
+func (tq *Query[T]) ContainsAll(field string, values ...string) *Query[T] {
+	return tq.setCondition("ContainsAll", field, types.ContainsAll(values...))
+}
+
+// setCondition validates a set field and records its filter. Conditions
+// requiring all of their values combine with the ones already on the field.
+func (tq *Query[T]) setCondition(method, field string, filter types.SetFilter) *Query[T] {
+	dimension, err := tq.typedStore.setDimension(field)
+	if err != nil {
+		tq.options.Filters["__validation_error__"] = fmt.Errorf("%s field validation: %w", method, err)
+		return tq
+	}
+
+	if existing, ok := types.SetFilterOf(tq.options.Filters[dimension]); ok && existing.Match == types.MatchAll && filter.Match == types.MatchAll {
+		filter = types.ContainsAll(append(append([]string{}, existing.Values...), filter.Values...)...)
+	}
+	tq.options.Filters[dimension] = filter
+	return tq
+}
@@ -0,0 +1,157 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+)
+
+type LabeledIssue struct {
+	nanostore.Document
+	Status   string   `values:"open,closed" default:"open"`
+	Labels   []string `values:"bug,feature,docs" multi:"true"`
+	Tags     []string `multi:"true"`
+	Assignee string
+}
+
+func TestSetFields(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[LabeledIssue](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	crash, err := store.Create("Crash", &LabeledIssue{Labels: []string{"bug", "docs"}, Tags: []string{"urgent"}})
+	if err != nil {
+		t.Fatalf("failed to create issue: %v", err)
+	}
+	if _, err := store.Create("Login", &LabeledIssue{Labels: []string{"feature"}, Assignee: "alice"}); err != nil {
+		t.Fatalf("failed to create issue: %v", err)
+	}
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		issue, err := store.Get(crash)
+		if err != nil {
+			t.Fatalf("failed to get issue: %v", err)
+		}
+		if !reflect.DeepEqual(issue.Labels, []string{"bug", "docs"}) || !reflect.DeepEqual(issue.Tags, []string{"urgent"}) {
+			t.Errorf("expected labels [bug docs] and tags [urgent], got %v and %v", issue.Labels, issue.Tags)
+		}
+	})
+
+	t.Run("InvalidMember", func(t *testing.T) {
+		if _, err := store.Create("Bad", &LabeledIssue{Labels: []string{"chore"}}); err == nil {
+			t.Error("expected an error for a label outside the values")
+		}
+	})
+
+	t.Run("QueryContains", func(t *testing.T) {
+		issues, err := store.Query().Contains("Labels", "bug").Find()
+		if err != nil || len(issues) != 1 || issues[0].Title != "Crash" {
+			t.Errorf("expected Crash, got %v (%v)", issues, err)
+		}
+
+		count, err := store.Query().ContainsAny("labels", "feature", "docs").Count()
+		if err != nil || count != 2 {
+			t.Errorf("expected 2 issues, got %d (%v)", count, err)
+		}
+
+		count, err = store.Query().Contains("Labels", "bug").Contains("Labels", "feature").Count()
+		if err != nil || count != 0 {
+			t.Errorf("expected chained Contains to require both labels, got %d (%v)", count, err)
+		}
+
+		if _, err := store.Query().ContainsAll("Assignee", "alice").Find(); err == nil {
+			t.Error("expected an error for a field that is not a set")
+		}
+	})
+
+	t.Run("AddAndRemove", func(t *testing.T) {
+		if err := store.AddToSet(crash, "Tags", "backend", "urgent"); err != nil {
+			t.Fatalf("failed to add tags: %v", err)
+		}
+		if err := store.RemoveFromSet(crash, "tags", "urgent"); err != nil {
+			t.Fatalf("failed to remove tags: %v", err)
+		}
+		issue, _ := store.Get(crash)
+		if !reflect.DeepEqual(issue.Tags, []string{"backend"}) {
+			t.Errorf("expected tags [backend], got %v", issue.Tags)
+		}
+
+		if err := store.AddToSet(crash, "Status", "closed"); err == nil {
+			t.Error("expected an error for a field that is not a set")
+		}
+	})
+
+	t.Run("UpdateReplaces", func(t *testing.T) {
+		issue, _ := store.Get(crash)
+		issue.Labels = []string{"feature"}
+		issue.Tags = nil
+		if _, err := store.Update(crash, issue); err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+		issue, _ = store.Get(crash)
+		if !reflect.DeepEqual(issue.Labels, []string{"feature"}) || len(issue.Tags) != 0 {
+			t.Errorf("expected labels [feature] and no tags, got %v and %v", issue.Labels, issue.Tags)
+		}
+	})
+
+	t.Run("StatsCountMembers", func(t *testing.T) {
+		if err := store.AddToSet(crash, "Labels", "bug"); err != nil {
+			t.Fatalf("failed to add label: %v", err)
+		}
+		stats, err := store.GetStoreStats()
+		if err != nil {
+			t.Fatalf("failed to get stats: %v", err)
+		}
+		expected := map[string]int{"bug": 1, "feature": 2}
+		if !reflect.DeepEqual(stats.DimensionDistribution["labels"], expected) {
+			t.Errorf("expected %v, got %v", expected, stats.DimensionDistribution["labels"])
+		}
+
+		usage, err := store.GetFieldUsageStats()
+		if err != nil {
+			t.Fatalf("failed to get usage stats: %v", err)
+		}
+		if labels := usage.DimensionUsage["labels"]; labels.NonEmptyCount != 2 || labels.ValueCounts["feature"] != 2 {
+			t.Errorf("expected labels on 2 issues with feature twice, got %+v", labels)
+		}
+	})
+}
+
+func TestSetFieldTags(t *testing.T) {
+	type NotSlice struct {
+		nanostore.Document
+		Tags string `multi:"true"`
+	}
+	if _, err := api.New[NotSlice](t.TempDir() + "/store.json"); err == nil {
+		t.Error("expected an error for a multi field that is not []string")
+	}
+
+	type WithDefault struct {
+		nanostore.Document
+		Tags []string `multi:"true" default:"a"`
+	}
+	if _, err := api.New[WithDefault](t.TempDir() + "/store.json"); err == nil {
+		t.Error("expected an error for a multi field with a default")
+	}
+}
@@ -0,0 +1,177 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+	"github.com/arthur-debert/nanostore/search"
+)
+
+func TestStoreSearch(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[TodoItem](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	tasks := []struct {
+		title    string
+		body     string
+		status   string
+		assignee string
+	}{
+		{"Prepare budget", "Draft the quarterly budget and send it to finance", "pending", "alice"},
+		{"Budget review", "Review spending with the team", "done", "bob"},
+		{"Team lunch", "Book a table, the budget is tight this month", "pending", "carol"},
+		{"Write report", "Summarize the results for the board", "pending", "alice"},
+	}
+	for _, task := range tasks {
+		item := &TodoItem{
+			Document: nanostore.Document{Body: task.body},
+			Status:   task.status,
+			Assignee: task.assignee,
+		}
+		if _, err := store.Create(task.title, item); err != nil {
+			t.Fatalf("failed to create %q: %v", task.title, err)
+		}
+	}
+
+	hitTitles := func(hits []api.SearchHit[TodoItem]) []string {
+		var result []string
+		for _, hit := range hits {
+			result = append(result, hit.Item.Title)
+		}
+		return result
+	}
+
+	t.Run("RanksTypedHits", func(t *testing.T) {
+		hits, err := store.Search(search.SearchOptions{Query: "budget"})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if len(hits) != 3 {
+			t.Fatalf("expected 3 hits, got %v", hitTitles(hits))
+		}
+		if hits[0].Score != 1.0 {
+			t.Errorf("expected best hit to score 1.0, got %f", hits[0].Score)
+		}
+		for i := 1; i < len(hits); i++ {
+			if hits[i].Score > hits[i-1].Score {
+				t.Errorf("hits are not ordered by score: %v", hitTitles(hits))
+			}
+		}
+		if hits[0].Item.Assignee == "" || hits[0].Item.Status == "" {
+			t.Errorf("expected hits to carry decoded items, got %+v", hits[0].Item)
+		}
+	})
+
+	t.Run("ComposesWithQueryFilters", func(t *testing.T) {
+		hits, err := store.Query().
+			Status("pending").
+			Data("Assignee", "alice").
+			SearchHits(search.SearchOptions{Query: "budget"})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		expected := []string{"Prepare budget"}
+		if !reflect.DeepEqual(hitTitles(hits), expected) {
+			t.Errorf("expected %v, got %v", expected, hitTitles(hits))
+		}
+	})
+
+	t.Run("HighlightsAndSnippets", func(t *testing.T) {
+		hits, err := store.Query().
+			Status("pending").
+			Data("Assignee", "carol").
+			SearchHits(search.SearchOptions{
+				Query:           "budget",
+				EnableHighlight: true,
+				SnippetContext:  10,
+			})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if len(hits) != 1 {
+			t.Fatalf("expected 1 hit, got %v", hitTitles(hits))
+		}
+
+		hit := hits[0]
+		if !reflect.DeepEqual(hit.MatchedFields, []string{"body"}) {
+			t.Errorf("expected body to match, got %v", hit.MatchedFields)
+		}
+		if !strings.Contains(hit.Highlights["body"], "**budget**") {
+			t.Errorf("expected highlighted body, got %q", hit.Highlights["body"])
+		}
+		expected := []string{"...the **budget** is tight..."}
+		if !reflect.DeepEqual(hit.Snippets["body"], expected) {
+			t.Errorf("expected snippets %q, got %q", expected, hit.Snippets["body"])
+		}
+	})
+
+	t.Run("DataFieldScope", func(t *testing.T) {
+		hits, err := store.Search(search.SearchOptions{Query: "alice", Fields: []string{"Assignee"}})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if len(hits) != 2 {
+			t.Fatalf("expected 2 hits, got %v", hitTitles(hits))
+		}
+		if !reflect.DeepEqual(hits[0].MatchedFields, []string{"assignee"}) {
+			t.Errorf("expected assignee to match, got %v", hits[0].MatchedFields)
+		}
+
+		if _, err := store.Search(search.SearchOptions{Query: "alice", Fields: []string{"Owner"}}); err == nil {
+			t.Error("expected an error for an unknown field")
+		}
+	})
+
+	t.Run("LimitAppliesToHits", func(t *testing.T) {
+		all, err := store.Search(search.SearchOptions{Query: "budget"})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+
+		hits, err := store.Query().Offset(1).Limit(1).SearchHits(search.SearchOptions{Query: "budget"})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if len(hits) != 1 || hits[0].Item.UUID != all[1].Item.UUID {
+			t.Errorf("expected the second ranked hit %q, got %v", all[1].Item.Title, hitTitles(hits))
+		}
+	})
+
+	t.Run("SelectStillSearchesBody", func(t *testing.T) {
+		hits, err := store.Query().
+			Select("title").
+			SearchHits(search.SearchOptions{Query: "finance"})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if len(hits) != 1 || hits[0].Item.Title != "Prepare budget" {
+			t.Fatalf("expected the body match, got %v", hitTitles(hits))
+		}
+		if hits[0].Item.Body != "" {
+			t.Errorf("expected body to be projected out, got %q", hits[0].Item.Body)
+		}
+	})
+}
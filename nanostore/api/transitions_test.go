@@ -0,0 +1,161 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+	"github.com/arthur-debert/nanostore/types"
+)
+
+type TransitionTask struct {
+	nanostore.Document
+	Status          string `values:"pending,active,done,archived" default:"pending" transitions:"pending->active,active->done,*->archived" track_changes:"true"`
+	Priority        string `values:"low,high" default:"low"`
+	StatusChangedAt *time.Time
+}
+
+func TestTransitionTags(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[TransitionTask](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	if err := store.SetTimeFunc(func() time.Time { return created }); err != nil {
+		t.Fatal(err)
+	}
+	id, err := store.Create("Task", &TransitionTask{})
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+
+	t.Run("DisallowedTransitionsFail", func(t *testing.T) {
+		task, err := store.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		task.Status = "done"
+		_, err = store.Update(id, task)
+		var transitionErr *types.TransitionError
+		if !errors.As(err, &transitionErr) {
+			t.Fatalf("expected a TransitionError, got %v", err)
+		}
+		if transitionErr.Dimension != "status" || transitionErr.From != "pending" || transitionErr.To != "done" {
+			t.Errorf("unexpected error details: %+v", transitionErr)
+		}
+
+		if _, err := store.UpdateWhere("status = ?", &TransitionTask{Status: "pending"}, "archived"); err != nil {
+			t.Errorf("expected an update matching nothing to succeed, got %v", err)
+		}
+	})
+
+	t.Run("AllowedTransitionsRecordChangeTime", func(t *testing.T) {
+		task, err := store.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.StatusChangedAt == nil || !task.StatusChangedAt.Equal(created) {
+			t.Errorf("expected the creation time as change time, got %v", task.StatusChangedAt)
+		}
+
+		changed := created.Add(time.Hour)
+		_ = store.SetTimeFunc(func() time.Time { return changed })
+		task.Status = "active"
+		if _, err := store.Update(id, task); err != nil {
+			t.Fatalf("expected pending->active to be allowed: %v", err)
+		}
+
+		task, err = store.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.Status != "active" || task.StatusChangedAt == nil || !task.StatusChangedAt.Equal(changed) {
+			t.Errorf("expected an active task changed at %v, got %q at %v", changed, task.Status, task.StatusChangedAt)
+		}
+
+		// Other fields change without touching the change time
+		_ = store.SetTimeFunc(func() time.Time { return changed.Add(time.Hour) })
+		task.Priority = "high"
+		if _, err := store.Update(id, task); err != nil {
+			t.Fatal(err)
+		}
+		task, _ = store.Get(id)
+		if task.StatusChangedAt == nil || !task.StatusChangedAt.Equal(changed) {
+			t.Errorf("expected the change time to stay %v, got %v", changed, task.StatusChangedAt)
+		}
+	})
+
+	t.Run("IntegrityReport", func(t *testing.T) {
+		report, err := store.ValidateStoreIntegrity()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.IsValid || report.WarningCount != 0 {
+			t.Errorf("expected a valid store, got %+v", report)
+		}
+
+		// A change time edited behind the store's back can fall outside the
+		// document's lifetime
+		rawUUID, err := store.Store().Add("Raw", map[string]interface{}{"status": "pending"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		future := created.Add(48 * time.Hour).Format(time.RFC3339)
+		if err := store.Store().Update(rawUUID, types.UpdateRequest{Dimensions: map[string]interface{}{"_data.status_changed_at": future}}); err != nil {
+			t.Fatal(err)
+		}
+
+		report, err = store.ValidateStoreIntegrity()
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, e := range report.Errors {
+			if e.Type == "TRANSITION_TIMESTAMP_INCONSISTENT" && e.DocumentID == rawUUID {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected a TRANSITION_TIMESTAMP_INCONSISTENT error, got %+v", report.Errors)
+		}
+	})
+}
+
+func TestTransitionTagValidation(t *testing.T) {
+	type BadRule struct {
+		nanostore.Document
+		Status string `values:"pending,done" default:"pending" transitions:"pending->reopened"`
+	}
+	if _, err := api.New[BadRule]("unused.json"); err == nil {
+		t.Error("expected an error for a rule naming an unknown value")
+	}
+
+	type BadTrack struct {
+		nanostore.Document
+		Status string `values:"pending,done" default:"pending" track_changes:"sometimes"`
+	}
+	if _, err := api.New[BadTrack]("unused.json"); err == nil {
+		t.Error("expected an error for an invalid track_changes tag")
+	}
+}
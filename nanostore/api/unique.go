@@ -0,0 +1,174 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// parseUniqueTag adds a data field to the unique constraints of config:
+//
+//	Slug    string `unique:"true"`         // constraint "slug"
+//	JiraKey string `unique:"external"`     // fields naming the same group
+//	Source  string `unique:"external"`     // form one compound constraint
+//	Name    string `unique:"true,parent"`  // unique among siblings only
+//
+// A group is scoped per parent when any of its fields has the parent option.
+// storedName is the name the data field is stored under.
+func parseUniqueTag(tagValue string, storedName string, config *nanostore.Config) error {
+	parts := strings.Split(tagValue, ",")
+	group := strings.TrimSpace(parts[0])
+	perParent := false
+	for _, option := range parts[1:] {
+		switch strings.TrimSpace(option) {
+		case "parent":
+			perParent = true
+		default:
+			return fmt.Errorf("unknown unique tag option '%s' (valid options: parent)", strings.TrimSpace(option))
+		}
+	}
+
+	switch group {
+	case "":
+		return fmt.Errorf("unique tag cannot be empty")
+	case "false":
+		return nil
+	case "true":
+		group = storedName
+	}
+
+	key := "_data." + storedName
+	for i := range config.Unique {
+		if config.Unique[i].Name == group {
+			config.Unique[i].Fields = append(config.Unique[i].Fields, key)
+			config.Unique[i].PerParent = config.Unique[i].PerParent || perParent
+			return nil
+		}
+	}
+	config.Unique = append(config.Unique, types.UniqueConstraint{
+		Name:      group,
+		Fields:    []string{key},
+		PerParent: perParent,
+	})
+	return nil
+}
+
+// uniqueConstraint returns the unique constraint named key, accepting the Go
+// field name of single field constraints as well
+func (ts *Store[T]) uniqueConstraint(key string) (types.UniqueConstraint, error) {
+	names := make([]string, len(ts.config.Unique))
+	for i, constraint := range ts.config.Unique {
+		if constraint.Name == key || constraint.Name == dataFieldKey(ts.typ, key) {
+			return constraint, nil
+		}
+		names[i] = constraint.Name
+	}
+	return types.UniqueConstraint{}, fmt.Errorf("unknown unique key '%s', available keys: %v", key, names)
+}
+
+// GetBy retrieves the document whose field has the given value, typically
+// a natural key such as a slug or an external ID kept in a unique data field:
+//
+//	task, err := store.GetBy("jira_key", "PROJ-123")
+//
+// The field is a data field (snake_case or Go name), an enumerated dimension
+// or a hierarchical reference field. It is an error when no document or more
+// than one document matches.
+func (ts *Store[T]) GetBy(field string, value interface{}) (*T, error) {
+	key, err := ts.storedKey(field)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := ts.store.List(types.ListOptions{
+		Filters: map[string]interface{}{key: value},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no document with %s '%v' found", field, value)
+	}
+	if len(docs) > 1 {
+		return nil, fmt.Errorf("multiple documents found with %s '%v'", field, value)
+	}
+
+	var result T
+	if err := UnmarshalDimensions(docs[0], &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal document: %w", err)
+	}
+	return &result, nil
+}
+
+// storedKey returns the key under which documents store a field
+func (ts *Store[T]) storedKey(field string) (string, error) {
+	for _, dim := range ts.config.Dimensions {
+		if dim.Type == nanostore.Enumerated && dim.Name == field {
+			return field, nil
+		}
+		if dim.Type == nanostore.Hierarchical && dim.RefField == field {
+			return field, nil
+		}
+	}
+	if err := ts.validateDataFieldName(ts.typ, field); err != nil {
+		return "", err
+	}
+	return "_data." + dataFieldKey(ts.typ, field), nil
+}
+
+// Upsert creates or updates the document identified by the values data has
+// for the fields of a unique constraint, in one locked operation, so
+// concurrent upserts of the same natural key never create duplicates:
+//
+//	type Issue struct {
+//	    nanostore.Document
+//	    JiraKey string `unique:"true"`
+//	    Summary string
+//	}
+//
+//	id, created, err := store.Upsert("jira_key", &Issue{JiraKey: "PROJ-123", Summary: "..."})
+//
+// key names the constraint: the group name of a compound constraint or the
+// field of a single field one. Constraints scoped per parent match the
+// document with the same parent. An existing document is updated as by
+// Update, so zero data fields clear their values; a new one takes its title
+// from the embedded Document. Returns the document's UUID and whether it
+// was created.
+func (ts *Store[T]) Upsert(key string, data *T) (string, bool, error) {
+	constraint, err := ts.uniqueConstraint(key)
+	if err != nil {
+		return "", false, err
+	}
+
+	// Validates the data fields for both paths
+	update, err := ts.buildUpdateRequest(data)
+	if err != nil {
+		return "", false, err
+	}
+	create, err := ts.createDimensions(data)
+	if err != nil {
+		return "", false, err
+	}
+
+	match := make(map[string]interface{}, len(constraint.Fields)+1)
+	for _, field := range constraint.Fields {
+		value, exists := create[field]
+		if !exists || value == nil || fmt.Sprintf("%v", value) == "" {
+			return "", false, fmt.Errorf("upsert by '%s' needs a value for %s", constraint.Name, strings.TrimPrefix(field, "_data."))
+		}
+		match[field] = value
+	}
+	if constraint.PerParent {
+		for _, dim := range ts.config.Dimensions {
+			if dim.Type == nanostore.Hierarchical {
+				match[dim.RefField] = create[dim.RefField]
+				break
+			}
+		}
+	}
+
+	title, body, _ := extractDocumentFields(data)
+	return ts.store.Upsert(match, types.NewDocument{Title: title, Body: body, Dimensions: create}, update)
+}
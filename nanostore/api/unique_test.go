@@ -0,0 +1,170 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+	"github.com/arthur-debert/nanostore/types"
+)
+
+type UniqueTask struct {
+	nanostore.Document
+	Status   string `values:"pending,done" default:"pending"`
+	ParentID string `dimension:"parent_id,ref"`
+	Slug     string `unique:"true"`
+	Source   string `unique:"external"`
+	Key      string `unique:"external"`
+	Name     string `unique:"true,parent"`
+	Summary  string
+}
+
+func TestUniqueTags(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	store, err := api.New[UniqueTask](tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	first, err := store.Create("First", &UniqueTask{Slug: "first", Source: "jira", Key: "P-1"})
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+
+	t.Run("CreateAndUpdateConflicts", func(t *testing.T) {
+		_, err := store.Create("Copy", &UniqueTask{Slug: "first"})
+		var conflict *types.UniqueConflictError
+		if !errors.As(err, &conflict) || conflict.Constraint != "slug" || conflict.ExistingUUID != first {
+			t.Fatalf("expected a slug conflict with %s, got %v", first, err)
+		}
+
+		if _, err := store.Create("Other source", &UniqueTask{Source: "github", Key: "P-1"}); err != nil {
+			t.Errorf("expected a different compound key to be accepted: %v", err)
+		}
+		second, err := store.Create("Second", &UniqueTask{Slug: "second"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Update(second, &UniqueTask{Slug: "first"}); !errors.As(err, &conflict) {
+			t.Errorf("expected Update to conflict, got %v", err)
+		}
+	})
+
+	t.Run("PerParent", func(t *testing.T) {
+		if _, err := store.Create("Child", &UniqueTask{ParentID: first, Name: "notes"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Create("Root notes", &UniqueTask{Name: "notes"}); err != nil {
+			t.Errorf("expected the name to be free at the root: %v", err)
+		}
+		if _, err := store.Create("Sibling", &UniqueTask{ParentID: first, Name: "notes"}); err == nil {
+			t.Error("expected siblings to conflict")
+		}
+	})
+
+	t.Run("GetBy", func(t *testing.T) {
+		task, err := store.GetBy("slug", "first")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.UUID != first {
+			t.Errorf("expected %s, got %s", first, task.UUID)
+		}
+		if _, err := store.GetBy("Slug", "missing"); err == nil {
+			t.Error("expected an error for an unknown slug")
+		}
+		if _, err := store.GetBy("nonexistent", "x"); err == nil {
+			t.Error("expected an error for an unknown field")
+		}
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		data := &UniqueTask{Document: nanostore.Document{Title: "Imported", Body: "From jira"}, Source: "jira", Key: "P-2", Summary: "v1"}
+		uuid, created, err := store.Upsert("external", data)
+		if err != nil || !created {
+			t.Fatalf("expected a new task, got %v, %v", created, err)
+		}
+		task, err := store.Get(uuid)
+		if err != nil || task.Body != "From jira" || !task.UpdatedAt.Equal(task.CreatedAt) {
+			t.Fatalf("expected the task to be created with its body, got %+v (%v)", task, err)
+		}
+
+		data.Summary = "v2"
+		again, created, err := store.Upsert("external", data)
+		if err != nil || created || again != uuid {
+			t.Fatalf("expected %s to be updated, got %s, %v, %v", uuid, again, created, err)
+		}
+
+		task, err = store.Get(uuid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.Summary != "v2" || task.Title != "Imported" || task.Body != "From jira" {
+			t.Errorf("unexpected task after upsert: %+v", task)
+		}
+
+		if _, _, err := store.Upsert("external", &UniqueTask{Source: "jira"}); err == nil {
+			t.Error("expected an error for an incomplete key")
+		}
+		if _, _, err := store.Upsert("unknown", data); err == nil {
+			t.Error("expected an error for an unknown key")
+		}
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		result, err := store.ImportData(nanostore.ImportData{Documents: []nanostore.ImportDocument{
+			{Title: "Fresh", Dimensions: map[string]interface{}{"_data.slug": "fresh"}},
+			{Title: "Duplicate", Dimensions: map[string]interface{}{"_data.slug": "first"}},
+		}}, nanostore.DefaultImportOptions())
+		if err != nil {
+			t.Fatalf("import failed: %v", err)
+		}
+		if len(result.Imported) != 1 || len(result.Failed) != 1 || result.Failed[0].Title != "Duplicate" {
+			t.Errorf("expected only the duplicate to fail, got %+v", result)
+		}
+	})
+}
+
+func TestUniqueTagErrors(t *testing.T) {
+	type OnDimension struct {
+		nanostore.Document
+		Status string `values:"a,b" default:"a" unique:"true"`
+	}
+	if _, err := api.New[OnDimension]("unused.json"); err == nil {
+		t.Error("expected an error for unique on a dimension")
+	}
+
+	type UnknownOption struct {
+		nanostore.Document
+		Slug string `unique:"true,global"`
+	}
+	if _, err := api.New[UnknownOption]("unused.json"); err == nil {
+		t.Error("expected an error for an unknown unique option")
+	}
+
+	type NoHierarchy struct {
+		nanostore.Document
+		Name string `unique:"true,parent"`
+	}
+	if _, err := api.New[NoHierarchy]("unused.json"); err == nil {
+		t.Error("expected an error for a per parent constraint without a parent field")
+	}
+}
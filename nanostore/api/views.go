@@ -0,0 +1,78 @@
+package api
+
+import (
+	"fmt"
+)
+
+// Save stores the query as a named view in the store's metadata, replacing
+// any view with that name. The view keeps the filters, WHERE clause, search,
+// ordering, limit and offset, and is loaded back with Store.View.
+//
+// Filter() predicates are Go functions and cannot be saved, so queries using
+// them return an error. Select() projections are not part of a view.
+//
+// Views that only use dimension filters, Where(), Search() and ordering can
+// also be run by the underlying store with RunView; views using other
+// conditions, such as DataNot() or TimeBefore(), need Store.View.
+//
+// # Usage Examples
+//
+//	err := store.Query().
+//	    Status("pending").
+//	    Priority("high").
+//	    OrderBy("created_at").
+//	    Save("urgent")
+//
+//	// Later, possibly from another process
+//	query, err := store.View("urgent")
+//	tasks, err := query.Find()
+func (tq *Query[T]) Save(name string) error {
+	if validationErr, ok := tq.options.Filters["__validation_error__"]; ok {
+		if err, isErr := validationErr.(error); isErr {
+			return err
+		}
+	}
+	if len(tq.predicates) > 0 {
+		return fmt.Errorf("cannot save view %q: Filter() predicates cannot be saved", name)
+	}
+	if err := tq.validateDataFieldReferences(); err != nil {
+		return err
+	}
+
+	return tq.store.SaveView(name, tq.options)
+}
+
+// View loads a saved view as a query. The returned query can be refined with
+// more conditions before running it; doing so does not change the view.
+//
+// Returns an error wrapping store.ErrViewNotFound if no view has the name.
+//
+// # Usage Examples
+//
+//	// Run a view as saved
+//	query, err := store.View("urgent")
+//	tasks, err := query.Find()
+//
+//	// Narrow a view down further
+//	query, err = store.View("urgent")
+//	mine, err := query.Data("assignee", "alice").Limit(5).Find()
+func (ts *Store[T]) View(name string) (*Query[T], error) {
+	options, err := ts.store.GetView(name)
+	if err != nil {
+		return nil, err
+	}
+
+	query := ts.Query()
+	query.options = options
+	return query, nil
+}
+
+// Views returns the names of the saved views, sorted
+func (ts *Store[T]) Views() ([]string, error) {
+	return ts.store.ListViews()
+}
+
+// DeleteView removes a saved view
+func (ts *Store[T]) DeleteView(name string) error {
+	return ts.store.DeleteView(name)
+}
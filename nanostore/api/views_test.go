@@ -0,0 +1,164 @@
+package api_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/nanostore/api"
+	"github.com/arthur-debert/nanostore/nanostore/store"
+)
+
+func TestQuerySavedViews(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	now := time.Date(2024, 1, 17, 15, 30, 0, 0, time.UTC)
+	open := func() *api.Store[DeadlineItem] {
+		s, err := api.New[DeadlineItem](tmpfile.Name())
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+		if err := s.SetTimeFunc(func() time.Time { return now }); err != nil {
+			t.Fatalf("failed to set time func: %v", err)
+		}
+		return s
+	}
+
+	ts := open()
+	items := map[string]DeadlineItem{
+		"Pay rent":     {Owner: "alice", DueDate: now.AddDate(0, 0, -1)},
+		"Book flights": {Owner: "alice", DueDate: now.AddDate(0, 0, 10)},
+		"Call bank":    {Owner: "bob", DueDate: now.AddDate(0, 0, -3)},
+		"Archive mail": {Owner: "alice", Status: "done", DueDate: now.AddDate(0, 0, -5)},
+	}
+	for title, item := range items {
+		if _, err := ts.Create(title, &item); err != nil {
+			t.Fatalf("failed to create %q: %v", title, err)
+		}
+	}
+
+	if err := ts.Query().Status("pending").OrderBy("title").Save("open"); err != nil {
+		t.Fatalf("failed to save view: %v", err)
+	}
+	if err := ts.Query().Status("pending").TimeBefore("DueDate", "today").OrderByDesc("title").Limit(1).Save("overdue"); err != nil {
+		t.Fatalf("failed to save view: %v", err)
+	}
+	if err := ts.Query().Where("_data.owner = ?", "alice").Save("alice"); err != nil {
+		t.Fatalf("failed to save view: %v", err)
+	}
+	_ = ts.Close()
+
+	// Views are loaded back from the file
+	ts = open()
+	defer func() { _ = ts.Close() }()
+
+	titles := func(items []DeadlineItem) []string {
+		result := []string{}
+		for _, item := range items {
+			result = append(result, item.Title)
+		}
+		return result
+	}
+	run := func(t *testing.T, query *api.Query[DeadlineItem], expected []string) {
+		t.Helper()
+		results, err := query.Find()
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if got := titles(results); !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	}
+
+	t.Run("RunView", func(t *testing.T) {
+		query, err := ts.View("open")
+		if err != nil {
+			t.Fatal(err)
+		}
+		run(t, query, []string{"Book flights", "Call bank", "Pay rent"})
+	})
+
+	t.Run("TimeConditionsAndLimit", func(t *testing.T) {
+		query, err := ts.View("overdue")
+		if err != nil {
+			t.Fatal(err)
+		}
+		run(t, query, []string{"Pay rent"})
+	})
+
+	t.Run("RefineView", func(t *testing.T) {
+		query, err := ts.View("alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		run(t, query.Status("done"), []string{"Archive mail"})
+
+		// Refining does not change the saved view
+		query, err = ts.View("alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		count, err := query.Count()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 3 {
+			t.Errorf("expected 3 items in the saved view, got %d", count)
+		}
+	})
+
+	t.Run("Views", func(t *testing.T) {
+		names, err := ts.Views()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := []string{"alice", "open", "overdue"}; !reflect.DeepEqual(names, expected) {
+			t.Errorf("expected %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("DeleteView", func(t *testing.T) {
+		if err := ts.DeleteView("open"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ts.View("open"); !errors.Is(err, store.ErrViewNotFound) {
+			t.Errorf("expected ErrViewNotFound, got %v", err)
+		}
+	})
+
+	t.Run("UnsavableQueries", func(t *testing.T) {
+		err := ts.Query().Filter(func(item DeadlineItem) bool { return item.Owner != "" }).Save("predicate")
+		if err == nil || !strings.Contains(err.Error(), "Filter() predicates") {
+			t.Errorf("expected a predicate error, got %v", err)
+		}
+
+		err = ts.Query().TimeBefore("Deadline", "today").Save("invalid")
+		if err == nil || !strings.Contains(err.Error(), "unknown field") {
+			t.Errorf("expected a validation error, got %v", err)
+		}
+
+		names, _ := ts.Views()
+		for _, name := range names {
+			if name == "predicate" || name == "invalid" {
+				t.Errorf("expected view %q not to be saved", name)
+			}
+		}
+	})
+}
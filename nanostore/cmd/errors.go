@@ -1,8 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/arthur-debert/nanostore/nanostore/api"
 )
 
 // CLIError represents a user-friendly CLI error with context and suggestions
@@ -136,6 +139,7 @@ func NewFilterError(operation, filter, issue string) *CLIError {
 	suggestions := []string{
 		"Use format: --field=value or --field__operator=value",
 		"Available operators: eq, ne, gt, lt, gte, lte, contains, startswith, endswith",
+		"Date values accept expressions such as today, -7d or start_of_week",
 		"Use --or to combine conditions with OR logic",
 		"Check field names match your document schema",
 	}
@@ -164,6 +168,26 @@ func WrapError(operation string, err error, suggestions ...string) error {
 	return NewStoreError(operation, err, suggestions...)
 }
 
+// FormatError renders an error for the terminal. Data field validation
+// errors are listed one field per line.
+func FormatError(err error) string {
+	var validationErr *api.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err.Error()
+	}
+
+	// Keep the context wrapped around the validation error
+	var msg strings.Builder
+	if full := err.Error(); strings.HasSuffix(full, validationErr.Error()) {
+		msg.WriteString(strings.TrimSuffix(full, validationErr.Error()))
+	}
+	msg.WriteString("validation failed:")
+	for _, field := range validationErr.Fields {
+		msg.WriteString(fmt.Sprintf("\n  %s: %s", field.Field, field.Message))
+	}
+	return msg.String()
+}
+
 // Common error messages and suggestions
 var (
 	CommonSuggestions = struct {
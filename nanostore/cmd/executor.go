@@ -1,11 +1,14 @@
 package main
 
 import (
+	"encoding"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -65,6 +68,21 @@ func (me *MethodExecutor) ExecuteCommand(cmd *Command, cobraCmd *cobra.Command,
 
 		return me.outputResult(result, format)
 
+	case "view":
+		sort, _ := cobraCmd.Flags().GetString("sort")
+		limit, _ := cobraCmd.Flags().GetInt("limit")
+		name := ""
+		if len(args) > 1 {
+			name = args[1]
+		}
+
+		result, err := reflectionExec.ExecuteView(typeName, dbPath, args[0], name, query, sort, limit)
+		if err != nil {
+			return fmt.Errorf("failed to execute view %s: %w", args[0], err)
+		}
+
+		return me.outputResult(result, format)
+
 	case "create":
 		if len(args) == 0 {
 			return fmt.Errorf("create command requires a title argument")
@@ -223,6 +241,17 @@ func (me *MethodExecutor) ExecuteCommand(cmd *Command, cobraCmd *cobra.Command,
 
 		return me.outputResult(result, format)
 
+	case "renumber":
+		if err := reflectionExec.ExecuteRenumber(typeName, dbPath); err != nil {
+			return fmt.Errorf("failed to execute renumber: %w", err)
+		}
+
+		result := map[string]interface{}{
+			"message": "Positions renumbered successfully",
+		}
+
+		return me.outputResult(result, format)
+
 	case "stats":
 		// Log the SQL query that would be generated (placeholder for now)
 		logSQLQuery("stats", "SELECT COUNT(*), ... FROM documents", []interface{}{dbPath})
@@ -685,45 +714,101 @@ func (me *MethodExecutor) UpdateCommandWithTypeFlags(cmd *Command, typeName stri
 	return nil
 }
 
-// ParseFlagValue parses a flag value according to its expected type
+// ParseFlagValue parses a flag value according to its expected type.
+//
+// Named types such as `type Status string` parse as their underlying kind and
+// are returned as the expected type. time.Duration values use Go duration
+// syntax ("90s", "1h30m"), url.URL values are parsed with url.Parse, and types
+// implementing encoding.TextUnmarshaler (uuid.UUID among them) parse
+// themselves.
 func (me *MethodExecutor) ParseFlagValue(value string, expectedType reflect.Type) (interface{}, error) {
+	return parseFlagValue(value, expectedType)
+}
+
+// parseFlagValue implements ParseFlagValue; the reflection executor shares it
+// to fill document fields from create and update flags
+func parseFlagValue(value string, expectedType reflect.Type) (interface{}, error) {
 	if value == "" {
 		return nil, nil
 	}
 
+	switch {
+	case expectedType == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse value %q as %s: %w", value, expectedType.String(), err)
+		}
+		return d, nil
+
+	case expectedType == reflect.TypeOf(url.URL{}):
+		u, err := url.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse value %q as %s: %w", value, expectedType.String(), err)
+		}
+		return *u, nil
+
+	case expectedType.Kind() != reflect.Ptr && reflect.PointerTo(expectedType).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()):
+		parsed := reflect.New(expectedType)
+		if err := parsed.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+			return nil, fmt.Errorf("unable to parse value %q as %s: %w", value, expectedType.String(), err)
+		}
+		return parsed.Elem().Interface(), nil
+	}
+
+	var parsed interface{}
+	var err error
 	switch expectedType.Kind() {
 	case reflect.String:
-		return value, nil
+		parsed = value
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err = strconv.ParseInt(value, 10, expectedType.Bits())
 
-	case reflect.Int, reflect.Int64:
-		return strconv.ParseInt(value, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err = strconv.ParseUint(value, 10, expectedType.Bits())
 
-	case reflect.Float64:
-		return strconv.ParseFloat(value, 64)
+	case reflect.Float32, reflect.Float64:
+		parsed, err = strconv.ParseFloat(value, expectedType.Bits())
 
 	case reflect.Bool:
-		return strconv.ParseBool(value)
+		parsed, err = strconv.ParseBool(value)
 
 	case reflect.Slice:
 		if expectedType.Elem().Kind() == reflect.String {
-			return strings.Split(value, ","), nil
+			parts := strings.Split(value, ",")
+			slice := reflect.MakeSlice(expectedType, len(parts), len(parts))
+			for i, part := range parts {
+				slice.Index(i).SetString(part)
+			}
+			return slice.Interface(), nil
 		}
 
 	case reflect.Ptr:
 		// Handle pointer types by parsing the underlying type
 		elemType := expectedType.Elem()
-		parsed, err := me.ParseFlagValue(value, elemType)
+		elem, err := parseFlagValue(value, elemType)
 		if err != nil {
 			return nil, err
 		}
 
 		// Create pointer to the parsed value
 		ptrValue := reflect.New(elemType)
-		if parsed != nil {
-			ptrValue.Elem().Set(reflect.ValueOf(parsed))
+		if elem != nil {
+			elemValue := reflect.ValueOf(elem)
+			if !elemValue.Type().ConvertibleTo(elemType) {
+				return nil, fmt.Errorf("unable to parse value %q as %s", value, expectedType.String())
+			}
+			ptrValue.Elem().Set(elemValue.Convert(elemType))
 		}
 		return ptrValue.Interface(), nil
 	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse value %q as %s: %w", value, expectedType.String(), err)
+	}
+	if parsed != nil {
+		// Return basic values as the expected, possibly named, type
+		return reflect.ValueOf(parsed).Convert(expectedType).Interface(), nil
+	}
 
 	// For complex types, try JSON parsing
 	var result interface{}
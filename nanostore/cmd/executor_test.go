@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+type flagStage string
+
+func TestParseFlagValue(t *testing.T) {
+	executor := NewMethodExecutor(nil)
+	stage := flagStage("review")
+	homepage, _ := url.Parse("https://example.com/docs?page=2")
+
+	testCases := []struct {
+		name     string
+		value    string
+		typ      reflect.Type
+		expected interface{}
+	}{
+		{name: "String", value: "alice", typ: reflect.TypeOf(""), expected: "alice"},
+		{name: "Int", value: "42", typ: reflect.TypeOf(0), expected: 42},
+		{name: "Int64", value: "42", typ: reflect.TypeOf(int64(0)), expected: int64(42)},
+		{name: "Uint", value: "7", typ: reflect.TypeOf(uint(0)), expected: uint(7)},
+		{name: "Float", value: "1.5", typ: reflect.TypeOf(0.0), expected: 1.5},
+		{name: "Bool", value: "true", typ: reflect.TypeOf(false), expected: true},
+		{name: "StringSlice", value: "a,b", typ: reflect.TypeOf([]string{}), expected: []string{"a", "b"}},
+		{name: "NamedString", value: "review", typ: reflect.TypeOf(flagStage("")), expected: stage},
+		{name: "NamedStringPointer", value: "review", typ: reflect.TypeOf(&stage), expected: &stage},
+		{name: "Duration", value: "1h30m", typ: reflect.TypeOf(time.Duration(0)), expected: 90 * time.Minute},
+		{name: "URL", value: homepage.String(), typ: reflect.TypeOf(url.URL{}), expected: *homepage},
+		{name: "URLPointer", value: homepage.String(), typ: reflect.TypeOf(homepage), expected: homepage},
+		{name: "UUID", value: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", typ: reflect.TypeOf(uuid.UUID{}), expected: uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")},
+		{name: "Empty", value: "", typ: reflect.TypeOf(0), expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := executor.ParseFlagValue(tc.value, tc.typ)
+			if err != nil {
+				t.Fatalf("failed to parse %q: %v", tc.value, err)
+			}
+			if diff := cmp.Diff(tc.expected, parsed); diff != "" {
+				t.Errorf("ParseFlagValue() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	for _, typ := range []reflect.Type{reflect.TypeOf(0), reflect.TypeOf(time.Duration(0)), reflect.TypeOf(uuid.UUID{})} {
+		if _, err := executor.ParseFlagValue("not-a-value", typ); err == nil {
+			t.Errorf("expected an error parsing an invalid %s", typ)
+		}
+	}
+}
@@ -191,12 +191,27 @@ func (cg *CommandGenerator) GenerateCommands() []Command {
 			Method:      "List",
 			Description: "List documents with optional filtering and sorting",
 			Flags: []FlagSpec{
-				{Name: "sort", Type: reflect.TypeOf(""), Description: "Sort field", Default: ""},
+				{Name: "sort", Type: reflect.TypeOf(""), Description: "Sort fields, comma separated (-field or field:desc for descending; simple_id and tree for ID display order)", Default: ""},
 				{Name: "limit", Type: reflect.TypeOf(0), Description: "Limit number of results", Default: 0},
 			},
 			Returns:  ReturnSpec{Type: nil, Description: "List of matching documents", IsList: true},
 			Category: CategoryQuery,
 		},
+		{
+			Name:        "view",
+			Method:      "View",
+			Description: "Save, run, list or delete named views (saved list queries)",
+			Args: []ArgSpec{
+				{Name: "action", Type: reflect.TypeOf(""), Description: "One of save, run, list or delete", Required: true},
+				{Name: "name", Type: reflect.TypeOf(""), Description: "View name (not needed for list)", Required: false},
+			},
+			Flags: []FlagSpec{
+				{Name: "sort", Type: reflect.TypeOf(""), Description: "Sort fields saved with the view, same format as list --sort", Default: ""},
+				{Name: "limit", Type: reflect.TypeOf(0), Description: "Limit saved with the view", Default: 0},
+			},
+			Returns:  ReturnSpec{Type: nil, Description: "Matching documents for run, view names for list", IsList: true},
+			Category: CategoryQuery,
+		},
 
 		// Bulk Operations
 		{
@@ -391,6 +406,13 @@ func (cg *CommandGenerator) GenerateCommands() []Command {
 			Returns:  ReturnSpec{Type: reflect.TypeOf(nil), Description: "Success confirmation"},
 			Category: CategoryAdmin,
 		},
+		{
+			Name:        "renumber",
+			Method:      "Compact",
+			Description: "Re-pack stable SimpleID positions so each partition counts from 1 (IDs may change)",
+			Returns:     ReturnSpec{Type: reflect.TypeOf(nil), Description: "Success confirmation"},
+			Category:    CategoryAdmin,
+		},
 	}
 
 	commands = append(commands, apiMethods...)
@@ -476,14 +498,14 @@ func (cmd *Command) GenerateLongDescription() string {
 // GenerateRunFunc creates the execution function for the command
 func (cmd *Command) GenerateRunFunc(generator *CommandGenerator) func(*cobra.Command, []string) error {
 	return func(cobraCmd *cobra.Command, args []string) error {
-		// Validate arguments
-		if len(args) < len(cmd.Args) {
-			var requiredArgs []string
-			for _, arg := range cmd.Args {
-				if arg.Required {
-					requiredArgs = append(requiredArgs, arg.Name)
-				}
+		// Validate arguments; optional ones may be left out
+		var requiredArgs []string
+		for _, arg := range cmd.Args {
+			if arg.Required {
+				requiredArgs = append(requiredArgs, arg.Name)
 			}
+		}
+		if len(args) < len(requiredArgs) {
 			return fmt.Errorf("missing required arguments: %v", requiredArgs)
 		}
 
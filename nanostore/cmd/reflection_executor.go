@@ -9,6 +9,7 @@ import (
 
 	"github.com/arthur-debert/nanostore/nanostore"
 	"github.com/arthur-debert/nanostore/nanostore/api"
+	"github.com/arthur-debert/nanostore/nanostore/store"
 )
 
 // ReflectionExecutor handles actual Store method invocation using reflection
@@ -53,23 +54,25 @@ func (re *ReflectionExecutor) createNoteStore(dbPath string) (*api.Store[NoteDoc
 	return api.New[NoteDocument](dbPath)
 }
 
-// TaskDocument represents a Task document type for actual store operations
+// TaskDocument represents a Task document type for actual store operations.
+// Built-in types keep stable positions, so IDs used in scripts survive
+// deletions until `nano-db renumber` re-packs them.
 type TaskDocument struct {
-	nanostore.Document
-	Status      string `values:"pending,active,done" default:"pending"`
-	Priority    string `values:"low,medium,high" default:"medium"`
-	ParentID    string `dimension:"parent_id,ref"`
-	Description string
-	Assignee    string
-	DueDate     *time.Time
+	nanostore.Document `ids:"positions=stable"`
+	Status             string `values:"pending,active,done" default:"pending"`
+	Priority           string `values:"low,medium,high" default:"medium"`
+	ParentID           string `dimension:"parent_id,ref"`
+	Description        string
+	Assignee           string
+	DueDate            *time.Time
 }
 
 // NoteDocument represents a Note document type for actual store operations
 type NoteDocument struct {
-	nanostore.Document
-	Category string `values:"personal,work,idea,reference" default:"personal"`
-	Tags     string
-	Content  string
+	nanostore.Document `ids:"positions=stable"`
+	Category           string `values:"personal,work,idea,reference" default:"personal"`
+	Tags               string
+	Content            string
 }
 
 // ExecuteMethod executes the specified method on a Store instance
@@ -251,7 +254,9 @@ func (re *ReflectionExecutor) ExecuteCreate(typeName, dbPath, title string, data
 
 		task := &TaskDocument{}
 		task.Title = title
-		re.populateDocumentFromMap(task, data)
+		if err := re.populateDocumentFromMap(task, data); err != nil {
+			return nil, err
+		}
 
 		return store.Create(title, task)
 
@@ -264,7 +269,9 @@ func (re *ReflectionExecutor) ExecuteCreate(typeName, dbPath, title string, data
 
 		note := &NoteDocument{}
 		note.Title = title
-		re.populateDocumentFromMap(note, data)
+		if err := re.populateDocumentFromMap(note, data); err != nil {
+			return nil, err
+		}
 
 		return store.Create(title, note)
 
@@ -400,7 +407,9 @@ func (re *ReflectionExecutor) ExecuteUpdate(typeName, dbPath, id string, data ma
 		defer func() { _ = store.Close() }()
 
 		task := &TaskDocument{}
-		re.populateDocumentFromMap(task, data)
+		if err := re.populateDocumentFromMap(task, data); err != nil {
+			return nil, err
+		}
 
 		return store.Update(id, task)
 
@@ -412,7 +421,9 @@ func (re *ReflectionExecutor) ExecuteUpdate(typeName, dbPath, id string, data ma
 		defer func() { _ = store.Close() }()
 
 		note := &NoteDocument{}
-		re.populateDocumentFromMap(note, data)
+		if err := re.populateDocumentFromMap(note, data); err != nil {
+			return nil, err
+		}
 
 		return store.Update(id, note)
 
@@ -461,6 +472,34 @@ func (re *ReflectionExecutor) ExecuteUpdateByUUIDs(typeName, dbPath string, uuid
 	return re.ExecuteMethod(typeName, "UpdateByUUIDs", args)
 }
 
+// ExecuteRenumber re-packs the stable SimpleID positions of a store
+func (re *ReflectionExecutor) ExecuteRenumber(typeName, dbPath string) error {
+	logOperation("renumber", fmt.Sprintf("COMPACT %s positions", typeName), nil)
+
+	switch typeName {
+	case "Task":
+		store, err := re.createTaskStore(dbPath)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = store.Close() }()
+
+		return store.Compact()
+
+	case "Note":
+		store, err := re.createNoteStore(dbPath)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = store.Close() }()
+
+		return store.Compact()
+
+	default:
+		return NewTypeError("renumber", typeName, []string{"Task", "Note"})
+	}
+}
+
 // ExecuteDeleteByDimension executes DeleteByDimension on the store
 func (re *ReflectionExecutor) ExecuteDeleteByDimension(typeName, dbPath string, filters map[string]interface{}) (interface{}, error) {
 	// Log the delete operation
@@ -598,6 +637,29 @@ func (re *ReflectionExecutor) BuildWhereFromQuery(query *Query) (string, []inter
 	return finalClause.String(), finalArgs
 }
 
+// qualifyDataFields returns a copy of query whose conditions on data fields of
+// the type use the _data. prefix of WHERE clauses, so that filters such as
+// --due_date__lt=today compare against the stored value
+func (re *ReflectionExecutor) qualifyDataFields(typeName string, query *Query) *Query {
+	typeDef, exists := re.registry.GetTypeDefinition(typeName)
+	if query == nil || !exists {
+		return query
+	}
+
+	qualified := &Query{Operators: query.Operators, Groups: make([]FilterGroup, len(query.Groups))}
+	for i, group := range query.Groups {
+		conditions := make([]FilterCondition, len(group.Conditions))
+		for j, condition := range group.Conditions {
+			if _, isField := typeDef.Schema.Fields[condition.Field]; isField {
+				condition.Field = "_data." + condition.Field
+			}
+			conditions[j] = condition
+		}
+		qualified.Groups[i] = FilterGroup{Conditions: conditions}
+	}
+	return qualified
+}
+
 // A simple map to translate from our DSL operators to SQL-like operators
 var operatorMap = map[string]string{
 	"eq":         "=",
@@ -614,7 +676,7 @@ var operatorMap = map[string]string{
 
 // ExecuteList now uses the Query object from the context.
 func (re *ReflectionExecutor) ExecuteList(typeName, dbPath string, query *Query, sort string, limit, offset int) (interface{}, error) {
-	whereClause, whereArgs := re.BuildWhereFromQuery(query)
+	whereClause, whereArgs := re.BuildWhereFromQuery(re.qualifyDataFields(typeName, query))
 
 	// Log the generated SQL query
 	if whereClause != "" {
@@ -643,7 +705,7 @@ func (re *ReflectionExecutor) ExecuteQuery(typeName, dbPath, whereClause string,
 
 		// Add ORDER BY if provided
 		if orderBy != "" {
-			query = query.OrderBy(orderBy)
+			query = applySort(query, orderBy)
 		}
 
 		// Add LIMIT if provided
@@ -674,7 +736,7 @@ func (re *ReflectionExecutor) ExecuteQuery(typeName, dbPath, whereClause string,
 
 		// Add ORDER BY if provided
 		if orderBy != "" {
-			query = query.OrderBy(orderBy)
+			query = applySort(query, orderBy)
 		}
 
 		// Add LIMIT if provided
@@ -694,8 +756,106 @@ func (re *ReflectionExecutor) ExecuteQuery(typeName, dbPath, whereClause string,
 	}
 }
 
+// ExecuteView saves, runs, lists or deletes named views. Saving stores the
+// filters, sort and limit given on the command line under name.
+func (re *ReflectionExecutor) ExecuteView(typeName, dbPath, action, name string, query *Query, sort string, limit int) (interface{}, error) {
+	whereClause, whereArgs := re.BuildWhereFromQuery(re.qualifyDataFields(typeName, query))
+
+	switch typeName {
+	case "Task":
+		store, err := re.createTaskStore(dbPath)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = store.Close() }()
+		return executeView(store, action, name, whereClause, whereArgs, sort, limit)
+
+	case "Note":
+		store, err := re.createNoteStore(dbPath)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = store.Close() }()
+		return executeView(store, action, name, whereClause, whereArgs, sort, limit)
+
+	default:
+		return nil, NewTypeError("view", typeName, []string{"Task", "Note"})
+	}
+}
+
+// executeView runs a view action against a typed store
+func executeView[T any](store *api.Store[T], action, name, whereClause string, whereArgs []interface{}, sort string, limit int) (interface{}, error) {
+	if action != "list" && name == "" {
+		return nil, fmt.Errorf("view %s requires a view name", action)
+	}
+
+	switch action {
+	case "save":
+		query := store.Query()
+		if whereClause != "" {
+			query = query.Where(whereClause, whereArgs...)
+		}
+		if sort != "" {
+			query = applySort(query, sort)
+		}
+		if limit > 0 {
+			query = query.Limit(limit)
+		}
+		if err := query.Save(name); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"message": "View saved successfully", "view": name}, nil
+
+	case "run":
+		query, err := store.View(name)
+		if err != nil {
+			return nil, err
+		}
+		return query.Find()
+
+	case "list":
+		return store.Views()
+
+	case "delete":
+		if err := store.DeleteView(name); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"message": "View deleted successfully", "view": name}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown view action %q (use save, run, list or delete)", action)
+	}
+}
+
+// applySort adds the order clauses of a --sort value to a query.
+// Fields are comma separated; a leading "-" or a ":desc" suffix sorts that
+// field descending. The special simple_id and tree columns give the display
+// order of IDs (1, 1.1, 1.2, 2, ... 10).
+func applySort[T any](query *api.Query[T], spec string) *api.Query[T] {
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		descending := false
+		if strings.HasPrefix(field, "-") {
+			field, descending = field[1:], true
+		} else if name, direction, found := strings.Cut(field, ":"); found {
+			field, descending = name, strings.EqualFold(direction, "desc")
+		}
+
+		if descending {
+			query = query.OrderByDesc(field)
+		} else {
+			query = query.OrderBy(field)
+		}
+	}
+	return query
+}
+
 // populateDocumentFromMap populates a document struct from a map
-func (re *ReflectionExecutor) populateDocumentFromMap(doc interface{}, data map[string]interface{}) {
+func (re *ReflectionExecutor) populateDocumentFromMap(doc interface{}, data map[string]interface{}) error {
 	docValue := reflect.ValueOf(doc).Elem()
 
 	for key, value := range data {
@@ -710,42 +870,62 @@ func (re *ReflectionExecutor) populateDocumentFromMap(doc interface{}, data map[
 			continue
 		}
 
-		re.setFieldValue(field, value)
+		if err := re.setFieldValue(field, value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
 	}
+	return nil
 }
 
-// setFieldValue sets a field value with proper type conversion
-func (re *ReflectionExecutor) setFieldValue(field reflect.Value, value interface{}) {
+// setFieldValue sets a field value with proper type conversion. Values are
+// parsed like flag values, so durations, URLs and text unmarshalers such as
+// uuid.UUID get their own syntax; dates also accept date expressions.
+func (re *ReflectionExecutor) setFieldValue(field reflect.Value, value interface{}) error {
 	if value == nil {
-		return
+		return nil
 	}
 
 	valueStr := fmt.Sprintf("%v", value)
 	if valueStr == "" {
-		return
+		return nil
 	}
 
-	switch field.Kind() {
-	case reflect.String:
-		field.SetString(valueStr)
-	case reflect.Int, reflect.Int64:
-		if i, err := strconv.ParseInt(valueStr, 10, 64); err == nil {
-			field.SetInt(i)
+	// Handle time.Time and *time.Time, accepting date expressions such as "tomorrow"
+	switch field.Type() {
+	case reflect.TypeOf(time.Time{}):
+		t, err := store.ParseDateExpression(valueStr, time.Now())
+		if err != nil {
+			return err
 		}
-	case reflect.Bool:
-		if b, err := strconv.ParseBool(valueStr); err == nil {
-			field.SetBool(b)
-		}
-	case reflect.Ptr:
-		if field.Type().Elem().Kind() == reflect.Struct {
-			// Handle *time.Time
-			if field.Type() == reflect.TypeOf((*time.Time)(nil)) {
-				if t, err := time.Parse(time.RFC3339, valueStr); err == nil {
-					field.Set(reflect.ValueOf(&t))
-				}
-			}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	case reflect.TypeOf((*time.Time)(nil)):
+		t, err := store.ParseDateExpression(valueStr, time.Now())
+		if err != nil {
+			return err
 		}
+		field.Set(reflect.ValueOf(&t))
+		return nil
+	}
+
+	parsed, err := parseFlagValue(valueStr, field.Type())
+	if err != nil {
+		return err
+	}
+	if parsed == nil {
+		return nil
+	}
+
+	parsedValue := reflect.ValueOf(parsed)
+	switch {
+	case parsedValue.Type().AssignableTo(field.Type()):
+		field.Set(parsedValue)
+	case parsedValue.Type().ConvertibleTo(field.Type()):
+		field.Set(parsedValue.Convert(field.Type()))
+	default:
+		return fmt.Errorf("unable to parse value %q as %s", valueStr, field.Type())
 	}
+	return nil
 }
 
 // toPascalCase converts snake_case to PascalCase
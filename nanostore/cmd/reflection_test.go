@@ -1,9 +1,16 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"testing"
 	"time"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/api"
+	"github.com/google/uuid"
 )
 
 func TestReflectionExecutorIntegration(t *testing.T) {
@@ -211,7 +218,9 @@ func TestTypeConversions(t *testing.T) {
 		"due_date": now.Format(time.RFC3339),
 	}
 
-	executor.populateDocumentFromMap(task, data)
+	if err := executor.populateDocumentFromMap(task, data); err != nil {
+		t.Fatalf("Failed to populate task: %v", err)
+	}
 
 	if task.DueDate == nil {
 		t.Error("Expected DueDate to be set, but it was nil")
@@ -223,3 +232,288 @@ func TestTypeConversions(t *testing.T) {
 		}
 	}
 }
+
+type releaseDocument struct {
+	nanostore.Document
+	Timeout  time.Duration
+	Build    uuid.UUID
+	Homepage url.URL
+	Mirror   *url.URL
+	Retries  int
+}
+
+func TestCustomTypeConversions(t *testing.T) {
+	executor := NewReflectionExecutor(NewEnhancedTypeRegistry())
+
+	release := &releaseDocument{}
+	data := map[string]interface{}{
+		"timeout":  "1h30m",
+		"build":    "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"homepage": "https://example.com/docs",
+		"mirror":   "https://mirror.example.com/",
+		"retries":  3,
+	}
+	if err := executor.populateDocumentFromMap(release, data); err != nil {
+		t.Fatalf("Failed to populate release: %v", err)
+	}
+
+	if release.Timeout != 90*time.Minute {
+		t.Errorf("Expected timeout 1h30m, got %v", release.Timeout)
+	}
+	if release.Build != uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8") {
+		t.Errorf("Expected build to be parsed, got %v", release.Build)
+	}
+	if release.Homepage.String() != "https://example.com/docs" {
+		t.Errorf("Expected homepage to be parsed, got %v", release.Homepage)
+	}
+	if release.Mirror == nil || release.Mirror.Host != "mirror.example.com" {
+		t.Errorf("Expected mirror to be parsed, got %v", release.Mirror)
+	}
+	if release.Retries != 3 {
+		t.Errorf("Expected 3 retries, got %d", release.Retries)
+	}
+
+	for key, value := range map[string]interface{}{
+		"timeout": "90",
+		"build":   "not-a-uuid",
+		"retries": "many",
+	} {
+		if err := executor.populateDocumentFromMap(&releaseDocument{}, map[string]interface{}{key: value}); err == nil {
+			t.Errorf("Expected an error for %s=%v", key, value)
+		}
+	}
+}
+
+func TestReflectionExecutorListSort(t *testing.T) {
+	testDB := "test_sort_reflection.db"
+	defer func() { _ = os.Remove(testDB) }()
+
+	registry := NewEnhancedTypeRegistry()
+	if err := registry.LoadBuiltinTypes(); err != nil {
+		t.Fatalf("Failed to load builtin types: %v", err)
+	}
+	executor := NewReflectionExecutor(registry)
+
+	for i := 1; i <= 11; i++ {
+		if _, err := executor.ExecuteCreate("Task", testDB, fmt.Sprintf("Task %d", i), map[string]interface{}{}); err != nil {
+			t.Fatalf("Failed to create task %d: %v", i, err)
+		}
+		// Distinct creation times keep the SimpleID positions deterministic
+		time.Sleep(time.Millisecond)
+	}
+
+	listIDs := func(sort string) []string {
+		t.Helper()
+		result, err := executor.ExecuteList("Task", testDB, nil, sort, 0, 0)
+		if err != nil {
+			t.Fatalf("Failed to list with sort %q: %v", sort, err)
+		}
+		docs, ok := result.([]TaskDocument)
+		if !ok {
+			t.Fatalf("Expected []TaskDocument, got %T", result)
+		}
+		ids := make([]string, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.SimpleID
+		}
+		return ids
+	}
+
+	ids := listIDs("simple_id")
+	if len(ids) != 11 || ids[8] != "9" || ids[9] != "10" || ids[10] != "11" {
+		t.Errorf("Expected natural SimpleID order, got %v", ids)
+	}
+
+	for _, spec := range []string{"-simple_id", "simple_id:desc", "tree:desc"} {
+		ids = listIDs(spec)
+		if len(ids) != 11 || ids[0] != "11" || ids[10] != "1" {
+			t.Errorf("Expected descending order for %q, got %v", spec, ids)
+		}
+	}
+}
+
+func TestReflectionExecutorListDateFilters(t *testing.T) {
+	testDB := "test_date_filters_reflection.db"
+	defer func() { _ = os.Remove(testDB) }()
+
+	registry := NewEnhancedTypeRegistry()
+	if err := registry.LoadBuiltinTypes(); err != nil {
+		t.Fatalf("Failed to load builtin types: %v", err)
+	}
+	executor := NewReflectionExecutor(registry)
+
+	tasks := map[string]string{
+		"Overdue":  "-3d",
+		"Upcoming": "+2w",
+		"Dated":    "2020-06-01",
+	}
+	for title, due := range tasks {
+		if _, err := executor.ExecuteCreate("Task", testDB, title, map[string]interface{}{"due_date": due}); err != nil {
+			t.Fatalf("Failed to create task %s: %v", title, err)
+		}
+	}
+
+	list := func(filters ...string) []string {
+		t.Helper()
+		result, err := executor.ExecuteList("Task", testDB, parseFilters(filters), "title", 0, 0)
+		if err != nil {
+			t.Fatalf("Failed to list with %v: %v", filters, err)
+		}
+		docs, ok := result.([]TaskDocument)
+		if !ok {
+			t.Fatalf("Expected []TaskDocument, got %T", result)
+		}
+		titles := make([]string, len(docs))
+		for i, doc := range docs {
+			titles[i] = doc.Title
+		}
+		return titles
+	}
+
+	if got := list("--due_date__lt=today"); fmt.Sprint(got) != "[Dated Overdue]" {
+		t.Errorf("Expected tasks due before today, got %v", got)
+	}
+	if got := list("--due_date__gt=start_of_week+1w"); fmt.Sprint(got) != "[Upcoming]" {
+		t.Errorf("Expected tasks due after next week starts, got %v", got)
+	}
+	if got := list("--created_at__gte=today"); len(got) != 3 {
+		t.Errorf("Expected all tasks created today, got %v", got)
+	}
+}
+
+func TestReflectionExecutorViews(t *testing.T) {
+	testDB := "test_views_reflection.db"
+	defer func() { _ = os.Remove(testDB) }()
+
+	registry := NewEnhancedTypeRegistry()
+	if err := registry.LoadBuiltinTypes(); err != nil {
+		t.Fatalf("Failed to load builtin types: %v", err)
+	}
+	executor := NewReflectionExecutor(registry)
+
+	tasks := map[string]map[string]interface{}{
+		"Fix login":    {"priority": "high", "assignee": "alice"},
+		"Update docs":  {"priority": "low", "assignee": "alice"},
+		"Deploy patch": {"priority": "high", "assignee": "bob"},
+	}
+	for title, data := range tasks {
+		if _, err := executor.ExecuteCreate("Task", testDB, title, data); err != nil {
+			t.Fatalf("Failed to create task %s: %v", title, err)
+		}
+	}
+
+	if _, err := executor.ExecuteView("Task", testDB, "save", "urgent", parseFilters([]string{"--priority=high"}), "-title", 0); err != nil {
+		t.Fatalf("Failed to save view: %v", err)
+	}
+	if _, err := executor.ExecuteView("Task", testDB, "save", "alice", parseFilters([]string{"--assignee=alice"}), "title", 1); err != nil {
+		t.Fatalf("Failed to save view: %v", err)
+	}
+
+	run := func(name string) []string {
+		t.Helper()
+		result, err := executor.ExecuteView("Task", testDB, "run", name, &Query{}, "", 0)
+		if err != nil {
+			t.Fatalf("Failed to run view %s: %v", name, err)
+		}
+		docs, ok := result.([]TaskDocument)
+		if !ok {
+			t.Fatalf("Expected []TaskDocument, got %T", result)
+		}
+		titles := make([]string, len(docs))
+		for i, doc := range docs {
+			titles[i] = doc.Title
+		}
+		return titles
+	}
+
+	if got := run("urgent"); fmt.Sprint(got) != "[Fix login Deploy patch]" {
+		t.Errorf("Expected high priority tasks by title descending, got %v", got)
+	}
+	if got := run("alice"); fmt.Sprint(got) != "[Fix login]" {
+		t.Errorf("Expected first task assigned to alice, got %v", got)
+	}
+
+	names, err := executor.ExecuteView("Task", testDB, "list", "", &Query{}, "", 0)
+	if err != nil {
+		t.Fatalf("Failed to list views: %v", err)
+	}
+	if fmt.Sprint(names) != "[alice urgent]" {
+		t.Errorf("Expected views [alice urgent], got %v", names)
+	}
+
+	if _, err := executor.ExecuteView("Task", testDB, "delete", "urgent", &Query{}, "", 0); err != nil {
+		t.Fatalf("Failed to delete view: %v", err)
+	}
+	if _, err := executor.ExecuteView("Task", testDB, "run", "urgent", &Query{}, "", 0); err == nil {
+		t.Error("Expected an error running a deleted view")
+	}
+	if _, err := executor.ExecuteView("Task", testDB, "rename", "alice", &Query{}, "", 0); err == nil {
+		t.Error("Expected an error for an unknown action")
+	}
+}
+
+func TestReflectionExecutorRenumber(t *testing.T) {
+	testDB := "test_renumber_reflection.db"
+	defer func() { _ = os.Remove(testDB) }()
+
+	registry := NewEnhancedTypeRegistry()
+	if err := registry.LoadBuiltinTypes(); err != nil {
+		t.Fatalf("Failed to load builtin types: %v", err)
+	}
+	executor := NewReflectionExecutor(registry)
+
+	for _, title := range []string{"First", "Second", "Third"} {
+		if _, err := executor.ExecuteCreate("Task", testDB, title, map[string]interface{}{}); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		// Distinct creation times keep the SimpleID positions deterministic
+		time.Sleep(time.Millisecond)
+	}
+	titleOf := func(id string) string {
+		t.Helper()
+		result, err := executor.ExecuteGet("Task", testDB, id)
+		if err != nil {
+			return ""
+		}
+		return result.(*TaskDocument).Title
+	}
+
+	// Built-in types keep positions across deletions
+	if err := executor.ExecuteDelete("Task", testDB, "2", false); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	if got := titleOf("3"); got != "Third" {
+		t.Errorf("Expected task 3 to keep its ID after the delete, got %q", got)
+	}
+
+	// Renumbering re-packs them
+	if err := executor.ExecuteRenumber("Task", testDB); err != nil {
+		t.Fatalf("Failed to renumber: %v", err)
+	}
+	if got := titleOf("2"); got != "Third" {
+		t.Errorf("Expected the third task to become 2 after renumbering, got %q", got)
+	}
+	if got := titleOf("3"); got != "" {
+		t.Errorf("Expected no task 3 after renumbering, got %q", got)
+	}
+
+	if err := executor.ExecuteRenumber("Unknown", testDB); err == nil {
+		t.Error("Expected an error for an unknown type")
+	}
+}
+
+func TestFormatValidationError(t *testing.T) {
+	err := fmt.Errorf("failed to execute create: %w", &api.ValidationError{Fields: []api.FieldError{
+		{Field: "assignee", Rule: "required", Message: "is required"},
+		{Field: "description", Rule: "max", Message: "must be at most 200 characters"},
+	}})
+
+	expected := "failed to execute create: validation failed:\n  assignee: is required\n  description: must be at most 200 characters"
+	if got := FormatError(err); got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+
+	if got := FormatError(errors.New("plain")); got != "plain" {
+		t.Errorf("Expected other errors unchanged, got %q", got)
+	}
+}
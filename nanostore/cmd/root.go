@@ -104,8 +104,13 @@ func preParse(args []string) (cobraArgs, filterArgs, positionalArgs []string) {
 			args = args[i+1:]
 			break
 		}
-		// If it's a root flag before the command
+		// If it's a root flag before the command, keep its value with it
 		cobraArgs = append(cobraArgs, args[i])
+		name, _, hasValue := strings.Cut(strings.TrimPrefix(args[i], "--"), "=")
+		if takesValue, ok := commandFlag(name); ok && takesValue && !hasValue && i+1 < len(args) {
+			i++
+			cobraArgs = append(cobraArgs, args[i])
+		}
 	}
 
 	// These commands do not support filter flags
@@ -119,29 +124,72 @@ func preParse(args []string) (cobraArgs, filterArgs, positionalArgs []string) {
 		return
 	}
 
-	// Separate remaining args into flags and positionals
-	for i, arg := range args {
-		if strings.HasPrefix(arg, "-") {
-			// It's a flag. Check if it's a command flag or a filter flag.
-			// Command flags: --x-*, --filter, --args, --sort, --limit, --cascade
-			if strings.HasPrefix(arg, "--x-") || arg == "--filter" || arg == "--args" || arg == "--sort" || arg == "--limit" || arg == "--cascade" {
-				cobraArgs = append(cobraArgs, arg)
-				// If this is --filter, also add the next argument if it exists and doesn't start with -
-				if arg == "--filter" && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-					cobraArgs = append(cobraArgs, args[i+1])
-					// Skip the next argument in the main loop
-					continue
-				}
-			} else {
-				filterArgs = append(filterArgs, arg)
-			}
-		} else {
+	// Separate remaining args into flags and positionals. Command flags go to
+	// cobra with their values, given either as --flag=value or as the next
+	// argument, which may itself start with "-" (e.g. --sort -title).
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
 			positionalArgs = append(positionalArgs, arg)
+			continue
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		takesValue, isCommandFlag := commandFlag(name)
+		if !isCommandFlag || !strings.HasPrefix(arg, "--") {
+			filterArgs = append(filterArgs, arg)
+			continue
+		}
+
+		cobraArgs = append(cobraArgs, arg)
+		if takesValue && !hasValue && i+1 < len(args) {
+			i++
+			cobraArgs = append(cobraArgs, args[i])
 		}
 	}
 	return
 }
 
+// commandFlags lists the command flags handled by cobra rather than parsed as
+// filters, and whether each takes a value
+var commandFlags = map[string]bool{
+	"filter":  true,
+	"args":    true,
+	"sort":    true,
+	"limit":   true,
+	"cascade": false,
+}
+
+// commandFlag reports whether name is a command flag (a universal --x-* flag
+// or one of commandFlags) and whether it takes a value
+func commandFlag(name string) (takesValue, ok bool) {
+	if strings.HasPrefix(name, "x-") {
+		flag := rootCmd.PersistentFlags().Lookup(name)
+		return flag == nil || flag.Value.Type() != "bool", true
+	}
+	takesValue, ok = commandFlags[name]
+	return takesValue, ok
+}
+
+// run executes the CLI with the given arguments, program name first
+func run(args []string) error {
+	cobraArgs, filterArgs, positionalArgs := preParse(args)
+	query := parseFilters(filterArgs)
+
+	// Pass the query object via context
+	ctx := rootCmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	rootCmd.SetContext(withQuery(ctx, query))
+
+	// Reconstruct the arguments for Cobra
+	cobraArgs = append(cobraArgs, positionalArgs...)
+	rootCmd.SetArgs(cobraArgs[1:])
+
+	return rootCmd.Execute()
+}
+
 // Helper functions for environment variables
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -163,21 +211,8 @@ func getEnvBool(key string) bool {
 }
 
 func main() {
-	cobraArgs, filterArgs, positionalArgs := preParse(os.Args)
-	query := parseFilters(filterArgs)
-
-	// Pass the query object via context
-	ctx := rootCmd.Context()
-	if ctx == nil {
-		ctx = context.Background()
-	}
-	rootCmd.SetContext(withQuery(ctx, query))
-
-	// Reconstruct os.Args for Cobra
-	os.Args = append(cobraArgs, positionalArgs...)
-
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if err := run(os.Args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", FormatError(err))
 		os.Exit(1)
 	}
 }
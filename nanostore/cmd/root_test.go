@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// runCLI runs the CLI as main does, with preParse and cobra, and returns what
+// it printed. Command flags and contexts are reset first, since cobra keeps
+// them between runs.
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	for _, cmd := range rootCmd.Commands() {
+		cmd.SetContext(nil)
+		cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+			_ = flag.Value.Set(flag.DefValue)
+			flag.Changed = false
+		})
+	}
+
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = writer
+	runErr := run(append([]string{"nano-db"}, args...))
+	os.Stdout = stdout
+	_ = writer.Close()
+
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output), runErr
+}
+
+// listTitles decodes the titles of a JSON document list printed by the CLI
+func listTitles(t *testing.T, output string) []string {
+	t.Helper()
+	var docs []struct {
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal([]byte(output), &docs); err != nil {
+		t.Fatalf("Failed to decode output %q: %v", output, err)
+	}
+	titles := []string{}
+	for _, doc := range docs {
+		titles = append(titles, doc.Title)
+	}
+	return titles
+}
+
+func TestPreParse(t *testing.T) {
+	testCases := []struct {
+		name       string
+		args       []string
+		cobra      []string
+		filters    []string
+		positional []string
+	}{
+		{
+			name:    "SortWithValue",
+			args:    []string{"nano-db", "list", "--sort", "title", "--status=pending"},
+			cobra:   []string{"nano-db", "list", "--sort", "title"},
+			filters: []string{"--status=pending"},
+		},
+		{
+			name:  "SortWithEquals",
+			args:  []string{"nano-db", "list", "--sort=simple_id", "--limit=2"},
+			cobra: []string{"nano-db", "list", "--sort=simple_id", "--limit=2"},
+		},
+		{
+			name:  "DescendingSortValue",
+			args:  []string{"nano-db", "list", "--sort", "-title", "--limit", "1"},
+			cobra: []string{"nano-db", "list", "--sort", "-title", "--limit", "1"},
+		},
+		{
+			name:       "ViewSave",
+			args:       []string{"nano-db", "view", "save", "hot", "--status=pending", "--sort", "title", "--limit", "1"},
+			cobra:      []string{"nano-db", "view", "--sort", "title", "--limit", "1"},
+			filters:    []string{"--status=pending"},
+			positional: []string{"save", "hot"},
+		},
+		{
+			name:       "UniversalFlags",
+			args:       []string{"nano-db", "--x-type", "Task", "get", "--x-db", "tasks.json", "--x-quiet", "1"},
+			cobra:      []string{"nano-db", "--x-type", "Task", "get", "--x-db", "tasks.json", "--x-quiet"},
+			positional: []string{"1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cobraArgs, filterArgs, positionalArgs := preParse(tc.args)
+			if !reflect.DeepEqual(cobraArgs, tc.cobra) {
+				t.Errorf("Expected cobra args %v, got %v", tc.cobra, cobraArgs)
+			}
+			if len(filterArgs)+len(tc.filters) > 0 && !reflect.DeepEqual(filterArgs, tc.filters) {
+				t.Errorf("Expected filter args %v, got %v", tc.filters, filterArgs)
+			}
+			if len(positionalArgs)+len(tc.positional) > 0 && !reflect.DeepEqual(positionalArgs, tc.positional) {
+				t.Errorf("Expected positional args %v, got %v", tc.positional, positionalArgs)
+			}
+		})
+	}
+}
+
+func TestCLIListSort(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "tasks.json")
+	flags := []string{"--x-type=Task", "--x-db=" + db, "--x-format=json"}
+	for _, title := range []string{"Bravo", "Alpha", "Charlie"} {
+		if _, err := runCLI(t, append([]string{"create", title}, flags...)...); err != nil {
+			t.Fatalf("Failed to create %s: %v", title, err)
+		}
+		// Distinct creation times keep the SimpleID positions deterministic
+		time.Sleep(time.Millisecond)
+	}
+
+	testCases := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"--sort", "title"}, []string{"Alpha", "Bravo", "Charlie"}},
+		{[]string{"--sort=title"}, []string{"Alpha", "Bravo", "Charlie"}},
+		{[]string{"--sort", "-title"}, []string{"Charlie", "Bravo", "Alpha"}},
+		{[]string{"--sort=-title", "--limit=2"}, []string{"Charlie", "Bravo"}},
+		{[]string{"--sort=simple_id", "--limit", "1"}, []string{"Bravo"}},
+	}
+	for _, tc := range testCases {
+		output, err := runCLI(t, append(append([]string{"list"}, tc.args...), flags...)...)
+		if err != nil {
+			t.Fatalf("list %v failed: %v", tc.args, err)
+		}
+		if titles := listTitles(t, output); !reflect.DeepEqual(titles, tc.expected) {
+			t.Errorf("list %v: expected %v, got %v", tc.args, tc.expected, titles)
+		}
+	}
+}
+
+func TestCLIViews(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "tasks.json")
+	flags := []string{"--x-type=Task", "--x-db=" + db, "--x-format=json"}
+	cli := func(args ...string) string {
+		t.Helper()
+		output, err := runCLI(t, append(args, flags...)...)
+		if err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return output
+	}
+
+	cli("create", "Alpha", "--status=pending")
+	cli("create", "Bravo", "--status=done")
+	cli("create", "Charlie", "--status=pending")
+
+	cli("view", "save", "hot", "--status=pending", "--sort=-title")
+	cli("view", "save", "top", "--sort", "title", "--limit", "1")
+
+	var names []string
+	if err := json.Unmarshal([]byte(cli("view", "list")), &names); err != nil {
+		t.Fatalf("Failed to decode view names: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"hot", "top"}) {
+		t.Errorf("Expected views [hot top], got %v", names)
+	}
+
+	if titles := listTitles(t, cli("view", "run", "hot")); !reflect.DeepEqual(titles, []string{"Charlie", "Alpha"}) {
+		t.Errorf("Expected pending tasks by title descending, got %v", titles)
+	}
+	if titles := listTitles(t, cli("view", "run", "top")); !reflect.DeepEqual(titles, []string{"Alpha"}) {
+		t.Errorf("Expected the first task by title, got %v", titles)
+	}
+}
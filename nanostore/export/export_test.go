@@ -43,6 +43,10 @@ func (m *MockStore) Add(title string, dimensions map[string]interface{}) (string
 	return "", fmt.Errorf("not implemented")
 }
 
+func (m *MockStore) AddDocument(doc types.NewDocument) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
 func (m *MockStore) Update(id string, updates types.UpdateRequest) error {
 	return fmt.Errorf("not implemented")
 }
@@ -96,6 +96,27 @@
 //
 //   - ID `1.2.l3` → types.Partition: `parent:1.2,status:active,priority:low|3`
 //
+//     ID Schemes
+//
+// The steps above use the default types.IDScheme. A store can choose another
+// scheme (see NewIDGeneratorWithScheme): "/" or "-" as the separator, letter
+// or base36 positions, zero-padding and prefixes after the position.
+// Letter and base36 positions cannot be told apart from prefixes, so those
+// numberings are rejected for dimensions with prefixes. Every accepted scheme
+// parses its IDs back to the partition they were written from.
+//
+// With StablePositions, positions are not recomputed from the documents
+// present: the store persists each document's position and a counter per
+// partition, and hands them to the generator (see WithPositions). Deleting a
+// document leaves a gap instead of shifting its siblings, until the store
+// is compacted.
+//
+// Examples:
+//
+//   - Separator "/", padding 2, prefixes after → ID: `01/02/03d`
+//
+//   - Letter numbering → ID: `b.ab` (position 28 under position 2)
+//
 //     ID Generation Process
 //
 //     Multi-Pass Assignment
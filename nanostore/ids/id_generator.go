@@ -15,9 +15,20 @@ type IDGenerator struct {
 	dimensionSet  *types.DimensionSet
 	canonicalView *types.CanonicalView
 	transformer   *IDTransformer
+	positions     PositionLookup
+	counters      PositionCounter
 }
 
-// NewIDGenerator creates a new ID generator
+// PositionLookup returns the persisted position of a document, if it has one
+// in the given partition: the key of BuildPartitionForDocument. A document
+// whose partition changed since its position was persisted has none.
+type PositionLookup func(uuid string, partition string) (int, bool)
+
+// PositionCounter returns the last position handed out in a partition, which
+// may belong to a document deleted since
+type PositionCounter func(partition string) int
+
+// NewIDGenerator creates a new ID generator using the default ID scheme
 func NewIDGenerator(dimensionSet *types.DimensionSet, canonicalView *types.CanonicalView) *IDGenerator {
 	return &IDGenerator{
 		dimensionSet:  dimensionSet,
@@ -26,9 +37,60 @@ func NewIDGenerator(dimensionSet *types.DimensionSet, canonicalView *types.Canon
 	}
 }
 
+// NewIDGeneratorWithScheme creates an ID generator writing IDs with the given
+// scheme, rejecting schemes that would not round-trip (see IDScheme.Validate)
+func NewIDGeneratorWithScheme(dimensionSet *types.DimensionSet, canonicalView *types.CanonicalView, scheme types.IDScheme) (*IDGenerator, error) {
+	transformer, err := NewIDTransformerWithScheme(dimensionSet, canonicalView, scheme)
+	if err != nil {
+		return nil, err
+	}
+	return &IDGenerator{
+		dimensionSet:  dimensionSet,
+		canonicalView: canonicalView,
+		transformer:   transformer,
+	}, nil
+}
+
+// Scheme returns the ID scheme used by the generator
+func (g *IDGenerator) Scheme() types.IDScheme {
+	return g.transformer.Scheme()
+}
+
+// WithPositions makes the generator use persisted positions where the lookup
+// has one, instead of numbering documents by creation order. Documents
+// without one are numbered after the counter of their partition. It returns
+// the generator for chaining.
+func (g *IDGenerator) WithPositions(lookup PositionLookup, counter PositionCounter) *IDGenerator {
+	g.positions = lookup
+	g.counters = counter
+	return g
+}
+
+// ForView returns a generator with the same dimensions, ID scheme and positions that
+// omits the values of another canonical view from the IDs
+func (g *IDGenerator) ForView(canonicalView *types.CanonicalView) *IDGenerator {
+	transformer := NewIDTransformer(g.dimensionSet, canonicalView)
+	transformer.scheme = g.transformer.scheme
+	return &IDGenerator{
+		dimensionSet:  g.dimensionSet,
+		canonicalView: canonicalView,
+		transformer:   transformer,
+		positions:     g.positions,
+		counters:      g.counters,
+	}
+}
+
 // GenerateIDs generates SimpleIDs for a list of documents
 // The documents should be in the order they were retrieved from the store
+// Computed dimensions are evaluated first, so they partition like stored ones
 func (g *IDGenerator) GenerateIDs(documents []types.Document) map[string]string {
+	return g.GenerateComputedIDs(types.ComputeDimensions(documents, g.dimensionSet))
+}
+
+// GenerateComputedIDs generates SimpleIDs for documents whose computed
+// dimensions are already filled in by types.ComputeDimensions, so callers
+// that need the computed values themselves evaluate them only once
+func (g *IDGenerator) GenerateComputedIDs(documents []types.Document) map[string]string {
 	idMap := make(map[string]string)          // SimpleID -> UUID
 	uuidToSimpleID := make(map[string]string) // UUID -> SimpleID
 
@@ -112,6 +174,18 @@ func (g *IDGenerator) canAssignID(doc types.Document, uuidToSimpleID map[string]
 
 // assignIDsToDocuments assigns IDs to a set of documents
 func (g *IDGenerator) assignIDsToDocuments(docsToProcess []types.Document, idMap map[string]string, uuidToSimpleID map[string]string, allDocuments []types.Document) {
+	if g.positions != nil {
+		positions := g.persistedPositions(docsToProcess)
+		for _, doc := range docsToProcess {
+			partition := g.getPartitionWithSimpleParentID(doc, uuidToSimpleID)
+			partition.Position = positions[doc.UUID]
+			simpleID := g.transformer.ToShortForm(partition)
+			idMap[simpleID] = doc.UUID
+			uuidToSimpleID[doc.UUID] = simpleID
+		}
+		return
+	}
+
 	// For stable positions, we need to consider ALL documents that have ever been in each partition
 	// This simulates having a position counter per partition that increments but never decreases
 
@@ -170,6 +244,45 @@ func (g *IDGenerator) assignIDsToDocuments(docsToProcess []types.Document, idMap
 	}
 }
 
+// persistedPositions returns the positions of docs, which hold every
+// document of their partitions, from the position lookup. Documents without
+// a persisted position in their partition, such as those a computed
+// dimension moved since the last save, take the positions after the
+// partition's counter in creation order, as the next save will persist them,
+// so they neither share an ID nor reuse a deleted document's.
+func (g *IDGenerator) persistedPositions(docs []types.Document) map[string]int {
+	positions := make(map[string]int, len(docs))
+	last := make(map[string]int) // partition key -> highest position
+	var unplaced []types.Document
+	for _, doc := range docs {
+		key := BuildPartitionForDocument(doc, g.dimensionSet).Key()
+		if position, ok := g.positions(doc.UUID, key); ok {
+			positions[doc.UUID] = position
+			last[key] = max(last[key], position)
+			continue
+		}
+		unplaced = append(unplaced, doc)
+	}
+
+	sort.SliceStable(unplaced, func(i, j int) bool {
+		if !unplaced[i].CreatedAt.Equal(unplaced[j].CreatedAt) {
+			return unplaced[i].CreatedAt.Before(unplaced[j].CreatedAt)
+		}
+		return unplaced[i].UUID < unplaced[j].UUID
+	})
+	seeded := make(map[string]bool)
+	for _, doc := range unplaced {
+		key := BuildPartitionForDocument(doc, g.dimensionSet).Key()
+		if !seeded[key] && g.counters != nil {
+			last[key] = max(last[key], g.counters(key))
+			seeded[key] = true
+		}
+		last[key]++
+		positions[doc.UUID] = last[key]
+	}
+	return positions
+}
+
 // buildHistoricalPartitionMap builds a map of all documents that belong to each partition
 // This includes documents that might have moved to other partitions
 func (g *IDGenerator) buildHistoricalPartitionMap(documents []types.Document, uuidToSimpleID map[string]string) map[string][]types.Document {
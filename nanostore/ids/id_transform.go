@@ -2,7 +2,6 @@ package ids
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/arthur-debert/nanostore/internal/matching"
@@ -21,17 +20,36 @@ type IDTransformer struct {
 	dimensionSet     *types.DimensionSet
 	canonicalView    *types.CanonicalView
 	canonicalMatcher *matching.CanonicalMatcher
+	scheme           types.IDScheme
 }
 
-// NewIDTransformer creates a new ID transformer
+// NewIDTransformer creates a new ID transformer using the default ID scheme
 func NewIDTransformer(dimensionSet *types.DimensionSet, canonicalView *types.CanonicalView) *IDTransformer {
 	return &IDTransformer{
 		dimensionSet:     dimensionSet,
 		canonicalView:    canonicalView,
 		canonicalMatcher: matching.NewCanonicalMatcher(canonicalView, dimensionSet),
+		scheme:           types.DefaultIDScheme(),
 	}
 }
 
+// NewIDTransformerWithScheme creates an ID transformer writing IDs with the
+// given scheme. Schemes whose IDs would not parse back to the same partition
+// with these dimensions are rejected.
+func NewIDTransformerWithScheme(dimensionSet *types.DimensionSet, canonicalView *types.CanonicalView, scheme types.IDScheme) (*IDTransformer, error) {
+	if err := scheme.Validate(dimensionSet); err != nil {
+		return nil, err
+	}
+	transformer := NewIDTransformer(dimensionSet, canonicalView)
+	transformer.scheme = scheme
+	return transformer, nil
+}
+
+// Scheme returns the ID scheme used by the transformer
+func (t *IDTransformer) Scheme() types.IDScheme {
+	return t.scheme
+}
+
 // ToShortForm converts a partition to a user-facing short form ID
 // Example: parent:1,status:pending,priority:medium|3 → 1.3 (with canonical status:pending,priority:medium)
 func (t *IDTransformer) ToShortForm(partition types.Partition) string {
@@ -59,8 +77,8 @@ func (t *IDTransformer) ToShortForm(partition types.Partition) string {
 			// Hierarchical dimensions are always included in the ID structure
 			// regardless of canonical filters
 			if dv.Value != "" && dv.Value != "0" { // Skip empty/zero parent values
-				// Split hierarchical value by dots and add each part
-				parts := strings.Split(dv.Value, ".")
+				// Split hierarchical value by the separator and add each part
+				parts := strings.Split(dv.Value, t.scheme.GetSeparator())
 				for _, part := range parts {
 					if part != "" {
 						segments = append(segments, part)
@@ -80,16 +98,11 @@ func (t *IDTransformer) ToShortForm(partition types.Partition) string {
 		}
 	}
 
-	// Add position to the current segment
-	currentSegment = append(currentSegment, strconv.Itoa(partition.Position))
+	// Add the position and its prefixes as the last segment
+	segments = append(segments, t.scheme.JoinSegment(strings.Join(currentSegment, ""), partition.Position))
 
-	// Combine current segment
-	if len(currentSegment) > 0 {
-		segments = append(segments, strings.Join(currentSegment, ""))
-	}
-
-	// Join all segments with dots
-	return strings.Join(segments, ".")
+	// Join all segments with the separator
+	return strings.Join(segments, t.scheme.GetSeparator())
 }
 
 // FromShortForm parses a short form ID into dimension values
@@ -100,8 +113,8 @@ func (t *IDTransformer) FromShortForm(shortForm string) (types.Partition, error)
 		return types.Partition{}, fmt.Errorf("empty ID")
 	}
 
-	// Split by dots for hierarchical segments
-	segments := strings.Split(shortForm, ".")
+	// Split by the separator for hierarchical segments
+	segments := strings.Split(shortForm, t.scheme.GetSeparator())
 
 	var values []types.DimensionValue
 	var position int
@@ -120,7 +133,7 @@ func (t *IDTransformer) FromShortForm(shortForm string) (types.Partition, error)
 		if len(hierarchical) > 0 {
 			values = append(values, types.DimensionValue{
 				Dimension: hierarchical[0].Name,
-				Value:     strings.Join(hierarchicalPath, "."),
+				Value:     strings.Join(hierarchicalPath, t.scheme.GetSeparator()),
 			})
 		}
 	}
@@ -189,42 +202,11 @@ func (t *IDTransformer) FromShortForm(shortForm string) (types.Partition, error)
 func (t *IDTransformer) extractPrefixesAndPosition(segment string) (map[string]string, int, error) {
 	prefixes := make(map[string]string)
 
-	// Find where the numeric position starts
-	numStart := -1
-	for i := len(segment) - 1; i >= 0; i-- {
-		if segment[i] < '0' || segment[i] > '9' {
-			numStart = i + 1
-			break
-		}
-	}
-
-	// If we didn't find any non-numeric character, entire segment is numeric
-	if numStart == -1 {
-		numStart = 0
+	prefixPart, position, ok := t.scheme.SplitSegment(segment)
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid position in %s", segment)
 	}
 
-	// If entire segment is numeric, it's just a position
-	if numStart == 0 {
-		pos, err := strconv.Atoi(segment)
-		if err != nil {
-			return nil, 0, fmt.Errorf("invalid position: %s", segment)
-		}
-		return prefixes, pos, nil
-	}
-
-	// Extract position
-	if numStart >= len(segment) {
-		return nil, 0, fmt.Errorf("missing position number")
-	}
-
-	position, err := strconv.Atoi(segment[numStart:])
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid position: %s", segment[numStart:])
-	}
-
-	// Extract prefixes
-	prefixPart := segment[:numStart]
-
 	// Build reverse map of prefix -> dimension name
 	prefixToDim := make(map[string]string)
 	for _, dim := range t.dimensionSet.Enumerated() {
@@ -409,3 +409,77 @@ func TestIDTransformer(t *testing.T) {
 		}
 	})
 }
+
+func TestIDTransformerSchemes(t *testing.T) {
+	dimension := func(prefixes map[string]string) []types.Dimension {
+		return []types.Dimension{
+			{Name: "parent", Type: types.Hierarchical, RefField: "parent_uuid", Meta: types.DimensionMetadata{Order: 0}},
+			{Name: "status", Type: types.Enumerated, Values: []string{"pending", "done"}, Prefixes: prefixes, DefaultValue: "pending", Meta: types.DimensionMetadata{Order: 1}},
+		}
+	}
+	cv := types.NewCanonicalView(
+		types.CanonicalFilter{Dimension: "status", Value: "pending"},
+		types.CanonicalFilter{Dimension: "parent", Value: "*"},
+	)
+	partition := func(parent, status string, position int) types.Partition {
+		values := []types.DimensionValue{}
+		if parent != "" {
+			values = append(values, types.DimensionValue{Dimension: "parent", Value: parent})
+		}
+		values = append(values, types.DimensionValue{Dimension: "status", Value: status})
+		return types.Partition{Values: values, Position: position}
+	}
+
+	tests := []struct {
+		name      string
+		scheme    types.IDScheme
+		prefixes  map[string]string
+		partition types.Partition
+		expected  string
+	}{
+		{"SlashSeparator", types.IDScheme{Separator: "/"}, map[string]string{"done": "d"}, partition("1/2", "done", 3), "1/2/d3"},
+		{"DashSeparator", types.IDScheme{Separator: "-"}, map[string]string{"done": "d"}, partition("4", "pending", 12), "4-12"},
+		{"PrefixAfter", types.IDScheme{PrefixPlacement: types.PrefixAfter}, map[string]string{"done": "d"}, partition("1", "done", 3), "1.3d"},
+		{"Padding", types.IDScheme{Padding: 3}, map[string]string{"done": "d"}, partition("001", "done", 7), "001.d007"},
+		{"Letters", types.IDScheme{Numbering: types.NumberingLetters}, nil, partition("b", "pending", 28), "b.ab"},
+		{"Base36", types.IDScheme{Separator: "/", Numbering: types.NumberingBase36, Padding: 2}, nil, partition("0z", "pending", 36), "0z/10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer, err := NewIDTransformerWithScheme(types.NewDimensionSet(dimension(tt.prefixes)), cv, tt.scheme)
+			if err != nil {
+				t.Fatalf("failed to create transformer: %v", err)
+			}
+
+			got := transformer.ToShortForm(tt.partition)
+			if got != tt.expected {
+				t.Fatalf("ToShortForm: got %q, want %q", got, tt.expected)
+			}
+
+			parsed, err := transformer.FromShortForm(got)
+			if err != nil {
+				t.Fatalf("FromShortForm(%q): %v", got, err)
+			}
+			if back := transformer.ToShortForm(parsed); back != got {
+				t.Errorf("round trip: %q parsed back as %q", got, back)
+			}
+			if parsed.Position != tt.partition.Position {
+				t.Errorf("round trip: position %d parsed as %d", tt.partition.Position, parsed.Position)
+			}
+		})
+	}
+
+	t.Run("RejectsAmbiguousSchemes", func(t *testing.T) {
+		ds := types.NewDimensionSet(dimension(map[string]string{"done": "d"}))
+		for _, scheme := range []types.IDScheme{
+			{Numbering: types.NumberingLetters},
+			{Numbering: types.NumberingBase36},
+			{Separator: "_"},
+		} {
+			if _, err := NewIDTransformerWithScheme(ds, cv, scheme); err == nil {
+				t.Errorf("%+v: expected an error", scheme)
+			}
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package imports
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -102,19 +103,6 @@ func processDocument(store types.Store, doc ImportDocument, options ImportOption
 	// Prepare dimensions with defaults
 	dimensions := prepareDimensions(doc.Dimensions, options.DefaultDimensions)
 
-	// Add timestamps to dimensions if provided (as strings for compatibility)
-	if doc.CreatedAt != nil {
-		dimensions["_created_at"] = doc.CreatedAt.Format(time.RFC3339)
-	}
-	if doc.UpdatedAt != nil {
-		dimensions["_updated_at"] = doc.UpdatedAt.Format(time.RFC3339)
-	}
-
-	// Handle body in dimensions
-	if doc.Body != "" {
-		dimensions["_body"] = doc.Body
-	}
-
 	// Dry run - don't actually import
 	if options.DryRun {
 		result.Imported = append(result.Imported, ImportedDocument{
@@ -127,8 +115,16 @@ func processDocument(store types.Store, doc ImportDocument, options ImportOption
 		return nil
 	}
 
-	// Perform the actual import
-	createdUUID, err := store.Add(doc.Title, dimensions)
+	// Perform the actual import, keeping the body and creation time
+	newDoc := types.NewDocument{
+		Title:      doc.Title,
+		Body:       doc.Body,
+		Dimensions: dimensions,
+	}
+	if doc.CreatedAt != nil {
+		newDoc.CreatedAt = *doc.CreatedAt
+	}
+	createdUUID, err := store.AddDocument(newDoc)
 	if err != nil {
 		return fmt.Errorf("failed to add document: %w", err)
 	}
@@ -242,8 +238,12 @@ func getOriginalUUID(doc ImportDocument) string {
 
 // isContinuableError determines if an error allows import to continue
 func isContinuableError(err error) bool {
-	// For now, validation errors and duplicate UUIDs are continuable
-	// Other errors (like store errors) are not
+	// For now, validation errors, duplicate UUIDs and unique conflicts are
+	// continuable. Other errors (like store errors) are not
+	var conflict *types.UniqueConflictError
+	if errors.As(err, &conflict) {
+		return true
+	}
 	errStr := err.Error()
 	return strings.Contains(errStr, "validation failed") ||
 		strings.Contains(errStr, "duplicate UUID") ||
@@ -0,0 +1,238 @@
+package query
+
+import (
+	"cmp"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// valueKind ranks the kinds of values so that mixed-type columns still have a
+// consistent total order: booleans, then numbers, then times, then text
+type valueKind int
+
+const (
+	kindBool valueKind = iota
+	kindNumber
+	kindTime
+	kindText
+)
+
+// compareClause compares two values of the clause column, honouring the
+// clause direction, null placement and collation
+func (p *processor) compareClause(clause types.OrderClause, a, b interface{}) int {
+	aNull, bNull := isNull(a), isNull(b)
+	if aNull || bNull {
+		if aNull && bNull {
+			return 0
+		}
+
+		// By default nulls behave as the largest value, so they follow the direction
+		nullsFirst := clause.Descending
+		switch clause.Nulls {
+		case types.NullsFirst:
+			nullsFirst = true
+		case types.NullsLast:
+			nullsFirst = false
+		}
+
+		if aNull == nullsFirst {
+			return -1
+		}
+		return 1
+	}
+
+	c := p.compareColumnValues(clause.Column, a, b, clause.Collation)
+	if clause.Descending {
+		c = -c
+	}
+	return c
+}
+
+// compareColumnValues compares two non-null values in ascending order.
+// SimpleID and tree columns compare IDs hierarchically; enumerated dimensions
+// follow their declared value order, with values outside the declared list
+// sorting after all declared ones.
+func (p *processor) compareColumnValues(column string, a, b interface{}, collation types.Collation) int {
+	switch {
+	case isSimpleIDColumn(column):
+		return compareSimpleIDs(valueToString(a), valueToString(b), p.idScheme(), false)
+	case column == treeColumn:
+		return compareSimpleIDs(valueToString(a), valueToString(b), p.idScheme(), true)
+	}
+
+	if p.dimensionSet != nil {
+		if dim, ok := p.dimensionSet.Get(column); ok && dim.Type == types.Enumerated && len(dim.Values) > 0 {
+			rankA, rankB := enumRank(dim, a), enumRank(dim, b)
+			if c := cmp.Compare(rankA, rankB); c != 0 {
+				return c
+			}
+			if rankA < len(dim.Values) {
+				return 0 // Same declared value
+			}
+		}
+	}
+	return compareValues(a, b, collation)
+}
+
+// enumRank returns the position of a value in the dimension's declared values
+func enumRank(dim *types.Dimension, value interface{}) int {
+	str := valueToString(value)
+	for i, v := range dim.Values {
+		if v == str {
+			return i
+		}
+	}
+	return len(dim.Values)
+}
+
+// compareValues compares two non-null values in ascending order
+func compareValues(a, b interface{}, collation types.Collation) int {
+	kindA, kindB := kindOf(a), kindOf(b)
+	if kindA != kindB {
+		return cmp.Compare(kindA, kindB)
+	}
+
+	switch kindA {
+	case kindBool:
+		return cmp.Compare(boolRank(a.(bool)), boolRank(b.(bool)))
+	case kindNumber:
+		numA, _ := toNumber(a)
+		numB, _ := toNumber(b)
+		return cmp.Compare(numA, numB)
+	case kindTime:
+		timeA, _ := toTime(a)
+		timeB, _ := toTime(b)
+		return timeA.Compare(timeB)
+	default:
+		return compareText(valueToString(a), valueToString(b), collation)
+	}
+}
+
+// compareText compares two strings using the given collation
+func compareText(a, b string, collation types.Collation) int {
+	switch collation {
+	case types.CollationCaseInsensitive:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	case types.CollationNatural:
+		return compareNatural(strings.ToLower(a), strings.ToLower(b))
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// compareNatural compares strings treating runs of digits as numbers
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		chunkA, restA := nextChunk(a)
+		chunkB, restB := nextChunk(b)
+
+		var c int
+		if isDigit(chunkA[0]) && isDigit(chunkB[0]) {
+			// Compare digit runs by value: ignore leading zeros, then longer is larger
+			numA := strings.TrimLeft(chunkA, "0")
+			numB := strings.TrimLeft(chunkB, "0")
+			c = cmp.Compare(len(numA), len(numB))
+			if c == 0 {
+				c = strings.Compare(numA, numB)
+			}
+		} else {
+			c = strings.Compare(chunkA, chunkB)
+		}
+		if c != 0 {
+			return c
+		}
+		a, b = restA, restB
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// nextChunk splits off the leading run of digits or non-digits
+func nextChunk(s string) (string, string) {
+	digits := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigit(c byte) bool {
+	return c < unicode.MaxASCII && unicode.IsDigit(rune(c))
+}
+
+// isNull reports whether a value should be treated as missing for ordering
+func isNull(value interface{}) bool {
+	return value == nil
+}
+
+// kindOf classifies a value for comparison
+func kindOf(value interface{}) valueKind {
+	if _, ok := value.(bool); ok {
+		return kindBool
+	}
+	if _, ok := toNumber(value); ok {
+		return kindNumber
+	}
+	if _, ok := toTime(value); ok {
+		return kindTime
+	}
+	return kindText
+}
+
+// toNumber converts Go and JSON numeric values to float64
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// toTime converts time values and datetime strings to time.Time
+func toTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		return parseDateTime(v)
+	default:
+		return time.Time{}, false
+	}
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,104 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded or
+// was produced for a different ordering than the one being queried
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorPayload is the decoded content of a pagination cursor
+type cursorPayload struct {
+	// Order is a fingerprint of the order clauses the cursor was created for
+	Order string `json:"o"`
+	// Values holds the sort key of the last document, one entry per clause
+	Values []interface{} `json:"v"`
+}
+
+// EncodeCursor returns an opaque cursor positioned at doc for the given ordering.
+// Passing the cursor back in ListOptions.Cursor (with the same OrderBy) returns
+// the documents that sort strictly after doc.
+func EncodeCursor(doc types.Document, orderBy []types.OrderClause) string {
+	p := &processor{}
+	order := keysetOrder(orderBy)
+
+	payload := cursorPayload{
+		Order:  orderFingerprint(order),
+		Values: make([]interface{}, len(order)),
+	}
+	for i, clause := range order {
+		payload.Values[i] = p.getDocumentValue(doc, clause.Column, nil)
+	}
+
+	// All values are plain JSON types or time.Time, so marshaling cannot fail
+	data, _ := json.Marshal(payload)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor and checks it matches the given ordering
+func decodeCursor(cursor string, order []types.OrderClause) (*cursorPayload, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	if payload.Order != orderFingerprint(order) || len(payload.Values) != len(order) {
+		return nil, fmt.Errorf("%w: cursor was created for a different ordering", ErrInvalidCursor)
+	}
+
+	// Virtual field values depend on the whole document set and are not
+	// captured by cursors
+	for _, clause := range order {
+		if IsVirtualField(clause.Column) {
+			return nil, fmt.Errorf("%w: cannot paginate by virtual field %q", ErrInvalidCursor, clause.Column)
+		}
+	}
+
+	return &payload, nil
+}
+
+// orderFingerprint builds a stable textual representation of order clauses
+func orderFingerprint(order []types.OrderClause) string {
+	parts := make([]string, len(order))
+	for i, clause := range order {
+		direction := "asc"
+		if clause.Descending {
+			direction = "desc"
+		}
+		parts[i] = fmt.Sprintf("%s %s %d %d", clause.Column, direction, clause.Nulls, clause.Collation)
+	}
+	return strings.Join(parts, ",")
+}
+
+// afterCursor returns the suffix of sorted docs that comes strictly after the cursor
+func (p *processor) afterCursor(docs []types.Document, cursor *cursorPayload, order []types.OrderClause) []types.Document {
+	for i, doc := range docs {
+		if p.compareToCursor(doc, cursor, order) > 0 {
+			return docs[i:]
+		}
+	}
+	return []types.Document{}
+}
+
+// compareToCursor compares a document's sort key with the cursor position
+func (p *processor) compareToCursor(doc types.Document, cursor *cursorPayload, order []types.OrderClause) int {
+	for i, clause := range order {
+		c := p.compareClause(clause, p.getDocumentValue(doc, clause.Column, nil), cursor.Values[i])
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
@@ -0,0 +1,165 @@
+package query_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/query"
+	"github.com/arthur-debert/nanostore/nanostore/testutil"
+)
+
+func TestCursorPagination(t *testing.T) {
+	store, _ := testutil.LoadUniverse(t)
+
+	// collectPages walks every page of the given ordering and returns the UUIDs in order
+	collectPages := func(t *testing.T, orderBy []nanostore.OrderClause, pageSize int) []string {
+		t.Helper()
+		var uuids []string
+		cursor := ""
+		for page := 0; page < 1000; page++ {
+			limit := pageSize
+			docs, err := store.List(nanostore.ListOptions{
+				OrderBy: orderBy,
+				Limit:   &limit,
+				Cursor:  cursor,
+			})
+			if err != nil {
+				t.Fatalf("failed to list page %d: %v", page, err)
+			}
+			for _, doc := range docs {
+				uuids = append(uuids, doc.UUID)
+			}
+			if len(docs) < pageSize {
+				return uuids
+			}
+			cursor = query.EncodeCursor(docs[len(docs)-1], orderBy)
+		}
+		t.Fatal("pagination did not terminate")
+		return nil
+	}
+
+	orderings := map[string][]nanostore.OrderClause{
+		"CreatedAt":        {{Column: "created_at"}},
+		"Title":            {{Column: "title"}},
+		"StatusDescending": {{Column: "status", Descending: true}},
+		"PriorityThenTitle": {
+			{Column: "priority"},
+			{Column: "title", Descending: true},
+		},
+	}
+
+	for name, orderBy := range orderings {
+		t.Run(name, func(t *testing.T) {
+			all, err := store.List(nanostore.ListOptions{OrderBy: orderBy})
+			if err != nil {
+				t.Fatalf("failed to list: %v", err)
+			}
+
+			paged := collectPages(t, orderBy, 3)
+			if len(paged) != len(all) {
+				t.Fatalf("expected %d documents across pages, got %d", len(all), len(paged))
+			}
+
+			seen := make(map[string]bool)
+			for _, uuid := range paged {
+				if seen[uuid] {
+					t.Errorf("document %s returned on more than one page", uuid)
+				}
+				seen[uuid] = true
+			}
+
+			for i := range all {
+				if all[i].UUID != paged[i] {
+					t.Errorf("position %d: expected %s, got %s", i, all[i].UUID, paged[i])
+					break
+				}
+			}
+		})
+	}
+
+	t.Run("StableAfterInsert", func(t *testing.T) {
+		orderBy := []nanostore.OrderClause{{Column: "title"}}
+		limit := 2
+		first, err := store.List(nanostore.ListOptions{OrderBy: orderBy, Limit: &limit})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		cursor := query.EncodeCursor(first[len(first)-1], orderBy)
+
+		// A document sorting before the cursor must not shift the next page
+		if _, err := store.Add("!!! sorts first", map[string]interface{}{}); err != nil {
+			t.Fatalf("failed to add: %v", err)
+		}
+
+		next, err := store.List(nanostore.ListOptions{OrderBy: orderBy, Limit: &limit, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("failed to list next page: %v", err)
+		}
+		for _, doc := range next {
+			if doc.Title < first[len(first)-1].Title {
+				t.Errorf("next page contains %q which sorts before the cursor", doc.Title)
+			}
+			testutil.AssertDocumentNotExists(t, first, doc.UUID)
+		}
+	})
+
+	t.Run("DefaultsToCreationOrder", func(t *testing.T) {
+		orderBy := []nanostore.OrderClause{{Column: "created_at"}}
+		limit := 4
+		first, err := store.List(nanostore.ListOptions{OrderBy: orderBy, Limit: &limit})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+
+		// A cursor without OrderBy continues in creation order
+		cursor := query.EncodeCursor(first[len(first)-1], nil)
+		withoutOrder, err := store.List(nanostore.ListOptions{Limit: &limit, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("failed to list without ordering: %v", err)
+		}
+		withOrder, err := store.List(nanostore.ListOptions{OrderBy: orderBy, Limit: &limit, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("failed to list with ordering: %v", err)
+		}
+
+		testutil.AssertDocumentCount(t, withoutOrder, len(withOrder))
+		for i := range withOrder {
+			if withOrder[i].UUID != withoutOrder[i].UUID {
+				t.Errorf("position %d differs between implicit and explicit creation order", i)
+			}
+		}
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		_, err := store.List(nanostore.ListOptions{Cursor: "not a cursor!"})
+		if !errors.Is(err, query.ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor, got %v", err)
+		}
+	})
+
+	t.Run("CursorForDifferentOrdering", func(t *testing.T) {
+		docs, err := store.List(nanostore.ListOptions{OrderBy: []nanostore.OrderClause{{Column: "title"}}})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		cursor := query.EncodeCursor(docs[0], []nanostore.OrderClause{{Column: "title"}})
+
+		_, err = store.List(nanostore.ListOptions{
+			OrderBy: []nanostore.OrderClause{{Column: "status"}},
+			Cursor:  cursor,
+		})
+		if !errors.Is(err, query.ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor, got %v", err)
+		}
+	})
+}
@@ -2,21 +2,31 @@ package query
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/arthur-debert/nanostore/types"
 )
 
 // matchesFilters checks if a document matches all the provided filters
-func (p *processor) matchesFilters(doc types.Document, filters map[string]interface{}) bool {
+// Virtual fields are resolved through vf, which may be nil when no filter needs them
+func (p *processor) matchesFilters(doc types.Document, filters map[string]interface{}, vf *VirtualFields) bool {
 	if len(filters) == 0 {
 		return true // No filters means match all
 	}
 
 	for filterKey, filterValue := range filters {
-		// Handle special filter for UUID
+		// Handle special filter for UUID, which also accepts a list of UUIDs
 		if filterKey == "uuid" {
-			if doc.UUID != fmt.Sprintf("%v", filterValue) {
+			if !matchesUUID(doc.UUID, filterValue) {
+				return false
+			}
+			continue
+		}
+
+		// Set dimensions match on their members rather than on equality
+		if p.isSetDimension(filterKey) {
+			if !setFilterFor(filterValue).Matches(types.SetMembers(doc.Dimensions[filterKey])) {
 				return false
 			}
 			continue
@@ -54,71 +64,135 @@ func (p *processor) matchesFilters(doc types.Document, filters map[string]interf
 							}
 						}
 					}
+					if !found && vf != nil && IsVirtualField(filterKey) {
+						// Computed from the document hierarchy
+						docValue = vf.Value(doc, filterKey)
+						found = true
+					}
 					if !found {
-						return false
+						// Dot paths into nested data match when any value they reach matches
+						values, isPath := dataPathValues(doc, filterKey)
+						if !isPath || !matchesAnyValue(values, filterValue) {
+							return false
+						}
+						continue
 					}
 				}
 			}
 		}
 
-		// Convert values to comparable strings
-		docStr := valueToString(docValue)
-
-		// Handle slice values (for "IN" style filtering)
-		switch fv := filterValue.(type) {
-		case []string:
-			// Filter value is a slice, check if document value is in the slice
-			found := false
-			for _, v := range fv {
-				if docStr == v {
-					found = true
-					break
-				}
-			}
-			if !found {
-				return false
-			}
-		case []interface{}:
-			// Filter value is a slice, check if document value is in the slice
-			found := false
-			for _, v := range fv {
-				if docStr == valueToString(v) {
-					found = true
-					break
-				}
-			}
-			if !found {
-				return false
-			}
-		default:
-			// Simple equality check
-			filterStr := valueToString(filterValue)
-			if docStr != filterStr {
+		// Lists of data values match when any of their values matches
+		if list, isList := docValue.([]interface{}); isList {
+			if !matchesAnyValue(list, filterValue) {
 				return false
 			}
+			continue
+		}
+		if !matchesValue(docValue, filterValue) {
+			return false
 		}
 	}
 
 	return true
 }
 
-// matchesSearch checks if a document matches the search text
-func (p *processor) matchesSearch(doc types.Document, searchText string) bool {
-	// Simple case-insensitive substring search in title and body
-	searchLower := strings.ToLower(searchText)
+// matchesValue checks a document value against a filter value, which is
+// either a single value or a slice of accepted values ("IN" style filtering)
+func matchesValue(docValue, filterValue interface{}) bool {
+	// Convert values to comparable strings
+	docStr := valueToString(docValue)
 
-	if strings.Contains(strings.ToLower(doc.Title), searchLower) {
-		return true
+	switch fv := filterValue.(type) {
+	case []string:
+		for _, v := range fv {
+			if docStr == v {
+				return true
+			}
+		}
+		return false
+	case []interface{}:
+		for _, v := range fv {
+			if docStr == valueToString(v) {
+				return true
+			}
+		}
+		return false
+	default:
+		// Simple equality check
+		return docStr == valueToString(filterValue)
 	}
+}
 
-	if strings.Contains(strings.ToLower(doc.Body), searchLower) {
-		return true
+// matchesUUID checks a document UUID against a uuid filter: a single UUID, a
+// list of them, or the set Execute turns a list into
+func matchesUUID(uuid string, filterValue interface{}) bool {
+	switch fv := filterValue.(type) {
+	case map[string]bool:
+		return fv[uuid]
+	case []string:
+		return slices.Contains(fv, uuid)
+	case []interface{}:
+		for _, v := range fv {
+			if fmt.Sprintf("%v", v) == uuid {
+				return true
+			}
+		}
+		return false
+	default:
+		return fmt.Sprintf("%v", filterValue) == uuid
 	}
+}
 
+// matchesAnyValue checks whether any of the document values matches the filter value
+func matchesAnyValue(docValues []interface{}, filterValue interface{}) bool {
+	for _, docValue := range docValues {
+		if matchesValue(docValue, filterValue) {
+			return true
+		}
+	}
 	return false
 }
 
+// dataPathValues returns the values a dot path such as "address.city" or
+// "_data.subtasks.estimate" reaches in the nested data of a document. Virtual
+// fields, which also contain dots, are never treated as paths.
+func dataPathValues(doc types.Document, path string) ([]interface{}, bool) {
+	if !strings.Contains(path, ".") || IsVirtualField(path) {
+		return nil, false
+	}
+	if values, found := types.PathValues(doc.Dimensions, path); found {
+		return values, true
+	}
+	return types.PathValues(doc.Dimensions, "_data."+path)
+}
+
+// isSetDimension reports whether name is a set dimension
+func (p *processor) isSetDimension(name string) bool {
+	if p.dimensionSet == nil {
+		return false
+	}
+	dim, ok := p.dimensionSet.Get(name)
+	return ok && dim.Type == types.Set
+}
+
+// setFilterFor returns the SetFilter a filter value stands for on a set
+// dimension: a plain value must be a member, and a slice matches sets holding
+// any of its values
+func setFilterFor(filterValue interface{}) types.SetFilter {
+	if filter, ok := types.SetFilterOf(filterValue); ok {
+		return filter
+	}
+	switch fv := filterValue.(type) {
+	case []string:
+		return types.ContainsAny(fv...)
+	case []interface{}:
+		return types.ContainsAny(types.SetMembers(fv)...)
+	}
+	return types.Contains(fmt.Sprintf("%v", filterValue))
+}
+
 // MatchesFilters implements the Processor interface method
+// Without the rest of the document set, filters on virtual fields never match
 func (p *processor) MatchesFilters(doc types.Document, filters map[string]interface{}) bool {
-	return p.matchesFilters(doc, filters)
+	return p.matchesFilters(doc, filters, nil)
 }
@@ -120,6 +120,27 @@ func TestFilteringMigrated(t *testing.T) {
 		}
 	})
 
+	t.Run("FilterByUUIDList", func(t *testing.T) {
+		uuids := []string{universe.BuyGroceries.UUID, universe.UnicodeEmoji.UUID}
+		docs, err := store.List(nanostore.ListOptions{
+			Filters: map[string]interface{}{
+				"uuid": uuids,
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+
+		if len(docs) != 2 {
+			t.Fatalf("expected 2 documents, got %d", len(docs))
+		}
+		for _, doc := range docs {
+			if doc.UUID != uuids[0] && doc.UUID != uuids[1] {
+				t.Errorf("unexpected document %s", doc.Title)
+			}
+		}
+	})
+
 	t.Run("FilterCombination", func(t *testing.T) {
 		// Filter by parent and status
 		docs, err := store.List(nanostore.ListOptions{
@@ -163,6 +184,18 @@ func TestFilteringMigrated(t *testing.T) {
 			t.Log("Note: search found documents but 'important' not in body - might be in title")
 		}
 	})
+
+	t.Run("SearchQueryLanguage", func(t *testing.T) {
+		// PackForTrip (pending) and PackLunch (done) are the only "pack" documents
+		testutil.AssertSearchFinds(t, store, "pack status:done", 1)
+		testutil.AssertSearchFinds(t, store, `"pack lunch"`, 1)
+		testutil.AssertSearchFinds(t, store, `"lunch pack"`, 0)
+		testutil.AssertSearchFinds(t, store, "pack -lunch", 1)
+		testutil.AssertSearchFinds(t, store, "title:pack", 2)
+		testutil.AssertSearchFinds(t, store, "body:vacation", 1)
+		testutil.AssertSearchFinds(t, store, "pack OR groceries", 3)
+		testutil.AssertSearchFinds(t, store, "(trip OR lunch) category:personal -tomorrow", 1)
+	})
 }
 
 func TestEmptyFiltersMigrated(t *testing.T) {
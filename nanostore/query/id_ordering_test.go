@@ -0,0 +1,128 @@
+package query_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/store"
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestSimpleIDAndTreeOrdering(t *testing.T) {
+	// Fresh store: the scenario needs more than nine siblings and
+	// deterministic creation times
+	tmpfile, err := os.CreateTemp("", "test*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	_ = tmpfile.Close()
+
+	config := types.Config{
+		Dimensions: []types.DimensionConfig{
+			{
+				Name:         "status",
+				Type:         types.Enumerated,
+				Values:       []string{"pending", "done"},
+				Prefixes:     map[string]string{"done": "d"},
+				DefaultValue: "pending",
+			},
+			{
+				Name:     "parent_id",
+				Type:     types.Hierarchical,
+				RefField: "parent_id",
+			},
+		},
+	}
+
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s, err := store.NewWithOptions(tmpfile.Name(), &config, store.WithTimeFunc(func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	add := func(title string, dims map[string]interface{}) string {
+		t.Helper()
+		id, err := s.Add(title, dims)
+		if err != nil {
+			t.Fatalf("failed to add %q: %v", title, err)
+		}
+		return id
+	}
+
+	var roots []string
+	for i := 1; i <= 11; i++ {
+		roots = append(roots, add(fmt.Sprintf("root %d", i), map[string]interface{}{}))
+	}
+	add("done root", map[string]interface{}{"status": "done"})
+	add("child 1.1", map[string]interface{}{"parent_id": roots[0]})
+	add("child 1.2", map[string]interface{}{"parent_id": roots[0]})
+	add("child 10.1", map[string]interface{}{"parent_id": roots[9]})
+
+	listIDs := func(t *testing.T, orderBy ...nanostore.OrderClause) string {
+		t.Helper()
+		docs, err := s.List(nanostore.ListOptions{OrderBy: orderBy})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		ids := make([]string, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.SimpleID
+		}
+		return strings.Join(ids, " ")
+	}
+
+	t.Run("SimpleIDNatural", func(t *testing.T) {
+		got := listIDs(t, nanostore.OrderClause{Column: "simple_id"})
+		expected := "1 1.1 1.2 2 3 4 5 6 7 8 9 10 10.1 11 d1"
+		if got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("SimpleIDDescending", func(t *testing.T) {
+		got := listIDs(t, nanostore.OrderClause{Column: "simple_id", Descending: true})
+		expected := "d1 11 10.1 10 9 8 7 6 5 4 3 2 1.2 1.1 1"
+		if got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("TreeDepthFirst", func(t *testing.T) {
+		got := listIDs(t, nanostore.OrderClause{Column: "tree"})
+		expected := "1 1.1 1.2 d1 2 3 4 5 6 7 8 9 10 10.1 11"
+		if got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("TreeWithFilter", func(t *testing.T) {
+		docs, err := s.List(nanostore.ListOptions{
+			Filters: map[string]interface{}{"parent_id": roots[0]},
+			OrderBy: []nanostore.OrderClause{{Column: "tree", Descending: true}},
+		})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		if len(docs) != 2 || docs[0].SimpleID != "1.2" || docs[1].SimpleID != "1.1" {
+			t.Errorf("unexpected children order: %v", docs)
+		}
+	})
+}
@@ -17,6 +17,12 @@ import (
 	"github.com/arthur-debert/nanostore/nanostore/testutil"
 )
 
+// Declared value order of the universe enumerated dimensions
+var (
+	statusRank   = map[string]int{"pending": 0, "active": 1, "done": 2}
+	priorityRank = map[string]int{"low": 0, "medium": 1, "high": 2}
+)
+
 func TestOrderingMigrated(t *testing.T) {
 	store, universe := testutil.LoadUniverse(t)
 
@@ -59,7 +65,7 @@ func TestOrderingMigrated(t *testing.T) {
 	})
 
 	t.Run("OrderByDimension", func(t *testing.T) {
-		// Order by status (alphabetical: active, done, pending)
+		// Order by status (declared order: pending, active, done)
 		docs, err := store.List(nanostore.ListOptions{
 			OrderBy: []nanostore.OrderClause{
 				{Column: "status", Descending: false},
@@ -73,7 +79,7 @@ func TestOrderingMigrated(t *testing.T) {
 		var lastStatus string
 		for i, doc := range docs {
 			status := doc.Dimensions["status"].(string)
-			if i > 0 && lastStatus > status {
+			if i > 0 && statusRank[lastStatus] > statusRank[status] {
 				t.Errorf("status not in ascending order: %q > %q", lastStatus, status)
 			}
 			lastStatus = status
@@ -98,7 +104,7 @@ func TestOrderingMigrated(t *testing.T) {
 			status := doc.Dimensions["status"].(string)
 			priority := doc.Dimensions["priority"].(string)
 
-			if status == lastStatus && priority < lastPriority {
+			if status == lastStatus && priorityRank[priority] < priorityRank[lastPriority] {
 				t.Errorf("within status %q, priority not ordered: %q < %q",
 					status, priority, lastPriority)
 			}
@@ -3,7 +3,10 @@
 package query
 
 import (
+	"maps"
+
 	"github.com/arthur-debert/nanostore/nanostore/ids"
+	"github.com/arthur-debert/nanostore/search"
 	"github.com/arthur-debert/nanostore/types"
 )
 
@@ -30,20 +33,74 @@ func NewProcessor(dimensionSet *types.DimensionSet, idGenerator *ids.IDGenerator
 	}
 }
 
+// idScheme returns the scheme the SimpleIDs of the results are written in
+func (p *processor) idScheme() types.IDScheme {
+	if p.idGenerator == nil {
+		return types.DefaultIDScheme()
+	}
+	return p.idGenerator.Scheme()
+}
+
 // Execute runs the query and returns filtered, sorted, and paginated results
 func (p *processor) Execute(docs []types.Document, opts types.ListOptions) ([]types.Document, error) {
+	// Computed dimensions are filled in so they can be filtered and sorted on
+	docs = types.ComputeDimensions(docs, p.dimensionSet)
+
+	// An alternate canonical view changes which prefixes the SimpleIDs omit
+	idGenerator := p.idGenerator
+	if opts.CanonicalView != nil {
+		if err := opts.CanonicalView.Validate(p.dimensionSet); err != nil {
+			return nil, err
+		}
+		idGenerator = p.idGenerator.ForView(opts.CanonicalView)
+	}
+
+	// Generate SimpleIDs using the ID generator
+	// We need ALL documents for proper ID generation (not just filtered ones)
+	idMap := idGenerator.GenerateComputedIDs(docs)
+
+	// Create reverse mapping (UUID -> SimpleID)
+	uuidToID := make(map[string]string)
+	for simpleID, uuid := range idMap {
+		uuidToID[uuid] = simpleID
+	}
+
+	// Virtual fields are computed once per query, only when referenced
+	var vf *VirtualFields
+	if referencesVirtualFields(opts) {
+		vf = NewVirtualFields(docs, p.dimensionSet)
+		vf.simpleIDs = uuidToID
+	}
+
+	// The search text is parsed once for all documents
+	var searchQuery *search.Query
+	if opts.FilterBySearch != "" {
+		searchQuery = search.ParseQuery(opts.FilterBySearch)
+	}
+
+	// A list of UUIDs, as searches pass, is looked up in a set
+	filters := opts.Filters
+	if uuids, ok := filters["uuid"].([]string); ok {
+		set := make(map[string]bool, len(uuids))
+		for _, uuid := range uuids {
+			set[uuid] = true
+		}
+		filters = maps.Clone(filters)
+		filters["uuid"] = set
+	}
+
 	// Start with all documents
 	result := make([]types.Document, 0, len(docs))
 
 	// Apply filters
 	for _, doc := range docs {
 		// Check dimension filters
-		if !p.matchesFilters(doc, opts.Filters) {
+		if !p.matchesFilters(doc, filters, vf) {
 			continue
 		}
 
 		// Check text search filter
-		if opts.FilterBySearch != "" && !p.matchesSearch(doc, opts.FilterBySearch) {
+		if searchQuery != nil && !searchQuery.Matches(doc) {
 			continue
 		}
 
@@ -52,21 +109,6 @@ func (p *processor) Execute(docs []types.Document, opts types.ListOptions) ([]ty
 		result = append(result, docCopy)
 	}
 
-	// Apply ordering
-	if len(opts.OrderBy) > 0 {
-		p.sortDocuments(result, opts.OrderBy)
-	}
-
-	// Generate SimpleIDs using the ID generator
-	// We need ALL documents for proper ID generation (not just filtered ones)
-	idMap := p.idGenerator.GenerateIDs(docs)
-
-	// Create reverse mapping (UUID -> SimpleID)
-	uuidToID := make(map[string]string)
-	for simpleID, uuid := range idMap {
-		uuidToID[uuid] = simpleID
-	}
-
 	// Assign SimpleIDs to results
 	for i := range result {
 		if simpleID, exists := uuidToID[result[i].UUID]; exists {
@@ -77,6 +119,22 @@ func (p *processor) Execute(docs []types.Document, opts types.ListOptions) ([]ty
 		}
 	}
 
+	// Apply ordering
+	// SimpleIDs are assigned first so the simple_id and tree columns can be sorted
+	// A uuid tiebreaker keeps the order deterministic, which cursors rely on
+	if len(opts.OrderBy) > 0 || opts.Cursor != "" {
+		order := keysetOrder(opts.OrderBy)
+		p.sortDocuments(result, order, vf)
+
+		if opts.Cursor != "" {
+			cursor, err := decodeCursor(opts.Cursor, order)
+			if err != nil {
+				return nil, err
+			}
+			result = p.afterCursor(result, cursor, order)
+		}
+	}
+
 	// Apply pagination
 	if opts.Offset != nil && *opts.Offset > 0 {
 		if *opts.Offset >= len(result) {
@@ -92,5 +150,11 @@ func (p *processor) Execute(docs []types.Document, opts types.ListOptions) ([]ty
 		}
 	}
 
+	if opts.ExcludeBody {
+		for i := range result {
+			result[i].Body = ""
+		}
+	}
+
 	return result, nil
 }
@@ -0,0 +1,72 @@
+package query
+
+import (
+	"cmp"
+	"strings"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// Special order columns based on the SimpleIDs assigned to documents
+const (
+	// simpleIDColumn orders by SimpleID segment by segment, comparing positions
+	// numerically and grouping segments with the same prefix together
+	// (1, 2, 10, d1, d2, h1)
+	simpleIDColumn = "simple_id"
+
+	// treeColumn orders depth-first: every document follows its parent and
+	// siblings are ordered by position, with prefixes only breaking ties
+	// (1, 1.1, d1, 2, h2)
+	treeColumn = "tree"
+)
+
+// isSimpleIDColumn reports whether the column refers to the SimpleID
+func isSimpleIDColumn(column string) bool {
+	return column == simpleIDColumn || column == "simpleid"
+}
+
+// compareSimpleIDs compares two SimpleIDs hierarchically. Each segment
+// between the scheme's separators is split into its prefix letters and
+// position; when positionFirst is set positions are compared before
+// prefixes. Segments that do not follow the scheme (such as UUID fallbacks)
+// sort after well-formed ones.
+func compareSimpleIDs(a, b string, scheme types.IDScheme, positionFirst bool) int {
+	segmentsA := strings.Split(a, scheme.GetSeparator())
+	segmentsB := strings.Split(b, scheme.GetSeparator())
+
+	for i := 0; i < len(segmentsA) && i < len(segmentsB); i++ {
+		if c := compareIDSegments(segmentsA[i], segmentsB[i], scheme, positionFirst); c != 0 {
+			return c
+		}
+	}
+
+	// A parent sorts before its children
+	return cmp.Compare(len(segmentsA), len(segmentsB))
+}
+
+// compareIDSegments compares a single SimpleID segment such as "3" or "dh12"
+func compareIDSegments(a, b string, scheme types.IDScheme, positionFirst bool) int {
+	prefixA, posA, okA := scheme.SplitSegment(a)
+	prefixB, posB, okB := scheme.SplitSegment(b)
+
+	if !okA || !okB {
+		if okA != okB {
+			if okA {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	}
+
+	// Unprefixed segments (canonical values) come before prefixed ones
+	prefixOrder := cmp.Compare(boolRank(prefixA != ""), boolRank(prefixB != ""))
+	if prefixOrder == 0 {
+		prefixOrder = strings.Compare(prefixA, prefixB)
+	}
+
+	if positionFirst {
+		return cmp.Or(cmp.Compare(posA, posB), prefixOrder)
+	}
+	return cmp.Or(prefixOrder, cmp.Compare(posA, posB))
+}
@@ -7,35 +7,51 @@ import (
 )
 
 // sortDocuments sorts documents according to the order clauses
-func (p *processor) sortDocuments(docs []types.Document, orderBy []types.OrderClause) {
-	sort.Slice(docs, func(i, j int) bool {
-		for _, clause := range orderBy {
-			// Get values for comparison
-			valI := p.getDocumentValue(docs[i], clause.Column)
-			valJ := p.getDocumentValue(docs[j], clause.Column)
+func (p *processor) sortDocuments(docs []types.Document, orderBy []types.OrderClause, vf *VirtualFields) {
+	sort.SliceStable(docs, func(i, j int) bool {
+		return p.compareDocuments(docs[i], docs[j], orderBy, vf) < 0
+	})
+}
 
-			// Convert to comparable strings
-			strI := valueToString(valI)
-			strJ := valueToString(valJ)
+// compareDocuments compares two documents according to the order clauses.
+// It returns a negative number when a sorts before b, a positive number when
+// a sorts after b, and zero when they are equal for every clause.
+func (p *processor) compareDocuments(a, b types.Document, orderBy []types.OrderClause, vf *VirtualFields) int {
+	for _, clause := range orderBy {
+		c := p.compareClause(clause, p.getDocumentValue(a, clause.Column, vf), p.getDocumentValue(b, clause.Column, vf))
+		if c != 0 {
+			return c
+		}
+		// If equal, continue to next order clause
+	}
+	return 0 // All equal
+}
 
-			// Compare
-			if strI < strJ {
-				return !clause.Descending
-			} else if strI > strJ {
-				return clause.Descending
-			}
-			// If equal, continue to next order clause
+// keysetOrder returns the order clauses extended with a uuid tiebreaker so that
+// every document has a unique position. With no explicit ordering, documents
+// are ordered by creation time.
+func keysetOrder(orderBy []types.OrderClause) []types.OrderClause {
+	order := make([]types.OrderClause, 0, len(orderBy)+2)
+	order = append(order, orderBy...)
+	if len(order) == 0 {
+		order = append(order, types.OrderClause{Column: "created_at"})
+	}
+	for _, clause := range order {
+		if clause.Column == "uuid" {
+			return order
 		}
-		return false // All equal
-	})
+	}
+	return append(order, types.OrderClause{Column: "uuid"})
 }
 
 // getDocumentValue retrieves a value from a document by field name
-func (p *processor) getDocumentValue(doc types.Document, column string) interface{} {
+// Virtual fields are resolved through vf when it is not nil
+func (p *processor) getDocumentValue(doc types.Document, column string, vf *VirtualFields) interface{} {
 	switch column {
 	case "uuid":
 		return doc.UUID
-	case "simple_id", "simpleid":
+	case simpleIDColumn, "simpleid", treeColumn:
+		// Tree order is derived from the hierarchical SimpleID path
 		return doc.SimpleID
 	case "title":
 		return doc.Title
@@ -54,7 +70,15 @@ func (p *processor) getDocumentValue(doc types.Document, column string) interfac
 		if val, exists := doc.Dimensions["_data."+column]; exists {
 			return val
 		}
-		// Return empty string for non-existent fields
-		return ""
+		// Computed fields derived from the document hierarchy
+		if vf != nil && IsVirtualField(column) {
+			return vf.Value(doc, column)
+		}
+		// Dot paths into nested data sort by the first value they reach
+		if values, found := dataPathValues(doc, column); found {
+			return values[0]
+		}
+		// Non-existent fields have no value
+		return nil
 	}
 }
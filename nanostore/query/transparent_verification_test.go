@@ -95,12 +95,12 @@ func TestTransparentFilteringVerificationMigrated(t *testing.T) {
 			priority := doc.Dimensions["priority"].(string)
 
 			if i > 0 {
-				// Check status ordering (alphabetical)
-				if status < lastStatus {
+				// Check status ordering (declared value order)
+				if statusRank[status] < statusRank[lastStatus] {
 					t.Errorf("status not in ascending order: %s < %s", status, lastStatus)
 				}
 				// Within same status, check priority ordering (desc)
-				if status == lastStatus && priority > lastPriority {
+				if status == lastStatus && priorityRank[priority] > priorityRank[lastPriority] {
 					t.Errorf("priority not in descending order within status %s: %s > %s",
 						status, priority, lastPriority)
 				}
@@ -0,0 +1,123 @@
+package query_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/testutil"
+)
+
+func TestTypeAwareOrdering(t *testing.T) {
+	store, _ := testutil.LoadUniverse(t)
+
+	// Extra documents carrying data fields of different types, tagged so
+	// they can be listed separately from the fixture documents
+	extras := []struct {
+		title string
+		data  map[string]interface{}
+	}{
+		{"item 10", map[string]interface{}{"_data.estimate": 10, "_data.due": "2024-03-01T09:00:00Z"}},
+		{"Item 9", map[string]interface{}{"_data.estimate": 9, "_data.due": "2024-03-01T08:30:00.5Z"}},
+		{"item 2", map[string]interface{}{"_data.estimate": 2.5}},
+		{"ITEM 1", map[string]interface{}{"_data.due": "2024-01-15"}},
+	}
+	for _, extra := range extras {
+		extra.data["_data.batch"] = "ordering"
+		if _, err := store.Add(extra.title, extra.data); err != nil {
+			t.Fatalf("failed to add %q: %v", extra.title, err)
+		}
+	}
+
+	list := func(t *testing.T, orderBy ...nanostore.OrderClause) []string {
+		t.Helper()
+		docs, err := store.List(nanostore.ListOptions{
+			Filters: map[string]interface{}{"_data.batch": "ordering"},
+			OrderBy: orderBy,
+		})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		titles := make([]string, len(docs))
+		for i, doc := range docs {
+			titles[i] = doc.Title
+		}
+		return titles
+	}
+
+	assertOrder := func(t *testing.T, got []string, expected ...string) {
+		t.Helper()
+		if len(got) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Fatalf("expected %v, got %v", expected, got)
+			}
+		}
+	}
+
+	t.Run("NumbersCompareNumerically", func(t *testing.T) {
+		got := list(t, nanostore.OrderClause{Column: "estimate"})
+		assertOrder(t, got, "item 2", "Item 9", "item 10", "ITEM 1")
+	})
+
+	t.Run("NullsFollowDirectionByDefault", func(t *testing.T) {
+		got := list(t, nanostore.OrderClause{Column: "estimate", Descending: true})
+		assertOrder(t, got, "ITEM 1", "item 10", "Item 9", "item 2")
+	})
+
+	t.Run("ExplicitNullPlacement", func(t *testing.T) {
+		got := list(t, nanostore.OrderClause{Column: "estimate", Nulls: nanostore.NullsFirst})
+		assertOrder(t, got, "ITEM 1", "item 2", "Item 9", "item 10")
+
+		got = list(t, nanostore.OrderClause{Column: "estimate", Descending: true, Nulls: nanostore.NullsLast})
+		assertOrder(t, got, "item 10", "Item 9", "item 2", "ITEM 1")
+	})
+
+	t.Run("TimesCompareChronologically", func(t *testing.T) {
+		got := list(t, nanostore.OrderClause{Column: "due"})
+		assertOrder(t, got, "ITEM 1", "Item 9", "item 10", "item 2")
+	})
+
+	t.Run("BinaryCollation", func(t *testing.T) {
+		got := list(t, nanostore.OrderClause{Column: "title"})
+		assertOrder(t, got, "ITEM 1", "Item 9", "item 10", "item 2")
+	})
+
+	t.Run("CaseInsensitiveCollation", func(t *testing.T) {
+		got := list(t, nanostore.OrderClause{Column: "title", Collation: nanostore.CollationCaseInsensitive})
+		assertOrder(t, got, "ITEM 1", "item 10", "item 2", "Item 9")
+	})
+
+	t.Run("NaturalCollation", func(t *testing.T) {
+		got := list(t, nanostore.OrderClause{Column: "title", Collation: nanostore.CollationNatural})
+		assertOrder(t, got, "ITEM 1", "item 2", "Item 9", "item 10")
+	})
+
+	t.Run("EnumeratedDimensionsUseDeclaredOrder", func(t *testing.T) {
+		docs, err := store.List(nanostore.ListOptions{
+			OrderBy: []nanostore.OrderClause{{Column: "priority", Descending: true}},
+		})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+
+		rank := map[string]int{"high": 0, "medium": 1, "low": 2}
+		for i := 1; i < len(docs); i++ {
+			prev := docs[i-1].Dimensions["priority"].(string)
+			cur := docs[i].Dimensions["priority"].(string)
+			if rank[prev] > rank[cur] {
+				t.Errorf("priority not in declared descending order: %q before %q", prev, cur)
+			}
+		}
+	})
+}
@@ -2,11 +2,33 @@ package query
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 )
 
+// dateTimeFormats lists the layouts recognized when a string holds a datetime
+var dateTimeFormats = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseDateTime parses a string in one of the recognized datetime formats
+func parseDateTime(s string) (time.Time, bool) {
+	for _, format := range dateTimeFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
 // valueToString converts any value to a string for comparison
-// Special handling for time.Time values to use RFC3339Nano format
+// Special handling for time.Time values to use RFC3339Nano format, and for
+// floats, which numbers loaded from JSON are, so large whole numbers such as
+// durations in nanoseconds compare equal to the integers they were saved as
 func valueToString(value interface{}) string {
 	switch v := value.(type) {
 	case time.Time:
@@ -14,20 +36,13 @@ func valueToString(value interface{}) string {
 		return v.Format(time.RFC3339Nano)
 	case string:
 		// Check if it's a datetime string and normalize it
-		// Try various datetime formats
-		for _, format := range []string{
-			time.RFC3339Nano,
-			time.RFC3339,
-			"2006-01-02T15:04:05Z",
-			"2006-01-02 15:04:05",
-			"2006-01-02",
-		} {
-			if t, err := time.Parse(format, v); err == nil {
-				return t.Format(time.RFC3339Nano)
-			}
+		if t, ok := parseDateTime(v); ok {
+			return t.Format(time.RFC3339Nano)
 		}
 		// Not a datetime, return as-is
 		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
 	default:
 		return fmt.Sprintf("%v", value)
 	}
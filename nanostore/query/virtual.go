@@ -0,0 +1,207 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// Names of the virtual fields computed from the document hierarchy.
+// Virtual fields are never stored; they are derived once per query from the
+// full set of documents and can be used in filters, WHERE clauses and ordering.
+const (
+	// VirtualDepth is the number of ancestors of a document (0 for roots)
+	VirtualDepth = "depth"
+	// VirtualChildCount is the number of direct children of a document
+	VirtualChildCount = "child_count"
+	// VirtualDescendantCount is the number of documents below a document
+	VirtualDescendantCount = "descendant_count"
+	// VirtualHasChildren reports whether a document has at least one child
+	VirtualHasChildren = "has_children"
+	// VirtualIsRoot reports whether a document has no parent
+	VirtualIsRoot = "is_root"
+	// VirtualParentPrefix traverses to the parent document: "parent.status",
+	// "parent.title" or "parent.parent.depth". Roots have no parent, so their
+	// parent fields are null.
+	VirtualParentPrefix = "parent."
+)
+
+// IsVirtualField reports whether a field name refers to a virtual field
+func IsVirtualField(field string) bool {
+	switch field {
+	case VirtualDepth, VirtualChildCount, VirtualDescendantCount, VirtualHasChildren, VirtualIsRoot:
+		return true
+	default:
+		return strings.HasPrefix(field, VirtualParentPrefix) && len(field) > len(VirtualParentPrefix)
+	}
+}
+
+// VirtualFields computes hierarchy-derived field values for a set of documents.
+// Counts and depths are memoized, so a single instance should be shared by all
+// evaluations within one query.
+type VirtualFields struct {
+	dimensionSet *types.DimensionSet
+	byUUID       map[string]*types.Document
+	children     map[string][]string // parent UUID -> child UUIDs
+	simpleIDs    map[string]string   // UUID -> SimpleID, when known
+
+	depths      map[string]int
+	descendants map[string]int
+}
+
+// NewVirtualFields indexes the hierarchy of docs. The slice must contain every
+// document of the store, not only the ones being evaluated.
+func NewVirtualFields(docs []types.Document, dimensionSet *types.DimensionSet) *VirtualFields {
+	v := &VirtualFields{
+		dimensionSet: dimensionSet,
+		byUUID:       make(map[string]*types.Document, len(docs)),
+		children:     make(map[string][]string),
+		depths:       make(map[string]int),
+		descendants:  make(map[string]int),
+	}
+
+	for i := range docs {
+		doc := &docs[i]
+		v.byUUID[doc.UUID] = doc
+	}
+	for i := range docs {
+		if parentUUID := v.parentUUID(docs[i]); parentUUID != "" {
+			v.children[parentUUID] = append(v.children[parentUUID], docs[i].UUID)
+		}
+	}
+
+	return v
+}
+
+// Value returns the value of a virtual field for doc, or nil when the field
+// has no value (such as parent fields of a root document)
+func (v *VirtualFields) Value(doc types.Document, field string) interface{} {
+	switch field {
+	case VirtualDepth:
+		return v.depth(doc.UUID, make(map[string]bool))
+	case VirtualChildCount:
+		return len(v.children[doc.UUID])
+	case VirtualDescendantCount:
+		return v.descendantCount(doc.UUID, make(map[string]bool))
+	case VirtualHasChildren:
+		return len(v.children[doc.UUID]) > 0
+	case VirtualIsRoot:
+		return v.parentUUID(doc) == ""
+	}
+
+	if rest, ok := strings.CutPrefix(field, VirtualParentPrefix); ok {
+		parent, exists := v.byUUID[v.parentUUID(doc)]
+		if !exists {
+			return nil
+		}
+		return v.fieldValue(*parent, rest)
+	}
+
+	return nil
+}
+
+// fieldValue resolves a regular or virtual field of a document reached by
+// parent traversal
+func (v *VirtualFields) fieldValue(doc types.Document, field string) interface{} {
+	if IsVirtualField(field) {
+		return v.Value(doc, field)
+	}
+
+	switch field {
+	case "uuid":
+		return doc.UUID
+	case simpleIDColumn, "simpleid":
+		if simpleID, ok := v.simpleIDs[doc.UUID]; ok {
+			return simpleID
+		}
+		return doc.SimpleID
+	case "title":
+		return doc.Title
+	case "body":
+		return doc.Body
+	case "created_at":
+		return doc.CreatedAt
+	case "updated_at":
+		return doc.UpdatedAt
+	}
+
+	if val, exists := doc.Dimensions[field]; exists {
+		return val
+	}
+	if val, exists := doc.Dimensions["_data."+field]; exists {
+		return val
+	}
+	return nil
+}
+
+// parentUUID returns the UUID referenced by the document's hierarchical dimension
+func (v *VirtualFields) parentUUID(doc types.Document) string {
+	if v.dimensionSet == nil {
+		return ""
+	}
+	for _, dim := range v.dimensionSet.Hierarchical() {
+		if parent, exists := doc.Dimensions[dim.RefField]; exists && parent != nil && parent != "" {
+			return fmt.Sprintf("%v", parent)
+		}
+	}
+	return ""
+}
+
+// depth counts the ancestors of a document; visited guards against cycles
+func (v *VirtualFields) depth(uuid string, visited map[string]bool) int {
+	if d, ok := v.depths[uuid]; ok {
+		return d
+	}
+
+	d := 0
+	doc, exists := v.byUUID[uuid]
+	if exists && !visited[uuid] {
+		visited[uuid] = true
+		if parentUUID := v.parentUUID(*doc); parentUUID != "" {
+			if _, parentExists := v.byUUID[parentUUID]; parentExists {
+				d = v.depth(parentUUID, visited) + 1
+			} else {
+				// The parent is gone, but the document is still not a root
+				d = 1
+			}
+		}
+	}
+
+	v.depths[uuid] = d
+	return d
+}
+
+// descendantCount counts all documents below uuid; visited guards against cycles
+func (v *VirtualFields) descendantCount(uuid string, visited map[string]bool) int {
+	if n, ok := v.descendants[uuid]; ok {
+		return n
+	}
+	if visited[uuid] {
+		return 0
+	}
+	visited[uuid] = true
+
+	n := 0
+	for _, child := range v.children[uuid] {
+		n += 1 + v.descendantCount(child, visited)
+	}
+
+	v.descendants[uuid] = n
+	return n
+}
+
+// referencesVirtualFields reports whether any filter key or order column is a virtual field
+func referencesVirtualFields(opts types.ListOptions) bool {
+	for key := range opts.Filters {
+		if IsVirtualField(key) {
+			return true
+		}
+	}
+	for _, clause := range opts.OrderBy {
+		if IsVirtualField(clause.Column) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package query_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// Key principles:
+// 1. Use testutil.LoadUniverse() for standard test setup
+// 2. Leverage fixture data instead of creating test data
+// 3. Use assertion helpers for cleaner test code
+// 4. Only create fresh stores for specific scenarios (see model_test.go)
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/nanostore"
+	"github.com/arthur-debert/nanostore/nanostore/query"
+	"github.com/arthur-debert/nanostore/nanostore/testutil"
+)
+
+func TestVirtualFields(t *testing.T) {
+	store, universe := testutil.LoadUniverse(t)
+
+	list := func(t *testing.T, opts nanostore.ListOptions) []nanostore.Document {
+		t.Helper()
+		docs, err := store.List(opts)
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		return docs
+	}
+
+	// Index the store by actual UUID (universe.ByUUID is keyed by fixture IDs)
+	all := list(t, nanostore.ListOptions{})
+	byUUID := make(map[string]nanostore.Document)
+	childCount := make(map[string]int)
+	for _, doc := range all {
+		byUUID[doc.UUID] = doc
+		if parent, ok := doc.Dimensions["parent_id"].(string); ok {
+			childCount[parent]++
+		}
+	}
+
+	t.Run("IsRoot", func(t *testing.T) {
+		docs := list(t, nanostore.ListOptions{Filters: map[string]interface{}{"is_root": true}})
+		testutil.AssertDocumentCount(t, docs, len(universe.GetRootDocuments()))
+		for _, doc := range docs {
+			if _, hasParent := doc.Dimensions["parent_id"]; hasParent {
+				t.Errorf("document %q has a parent", doc.Title)
+			}
+		}
+	})
+
+	t.Run("ChildCount", func(t *testing.T) {
+		docs := list(t, nanostore.ListOptions{Filters: map[string]interface{}{"child_count": 3}})
+		testutil.AssertDocumentExists(t, docs, universe.MixedParent.UUID)
+		for _, doc := range docs {
+			if n := childCount[doc.UUID]; n != 3 {
+				t.Errorf("document %q has %d children, expected 3", doc.Title, n)
+			}
+		}
+	})
+
+	t.Run("LeavesOnly", func(t *testing.T) {
+		docs := list(t, nanostore.ListOptions{Filters: map[string]interface{}{"has_children": false}})
+		testutil.AssertDocumentExists(t, docs, universe.Level5Task.UUID)
+		testutil.AssertDocumentNotExists(t, docs, universe.MixedParent.UUID)
+		for _, doc := range docs {
+			if childCount[doc.UUID] > 0 {
+				t.Errorf("document %q has children", doc.Title)
+			}
+		}
+	})
+
+	t.Run("Depth", func(t *testing.T) {
+		docs := list(t, nanostore.ListOptions{Filters: map[string]interface{}{"depth": 5}})
+		testutil.AssertDocumentCount(t, docs, 1)
+		testutil.AssertDocumentExists(t, docs, universe.Level5Task.UUID)
+	})
+
+	t.Run("ParentField", func(t *testing.T) {
+		docs := list(t, nanostore.ListOptions{Filters: map[string]interface{}{"parent.activity": "deleted"}})
+		testutil.AssertDocumentExists(t, docs, universe.OrphanChild.UUID)
+		for _, doc := range docs {
+			parent := byUUID[doc.Dimensions["parent_id"].(string)]
+			if parent.Dimensions["activity"] != "deleted" {
+				t.Errorf("document %q has a parent that is not deleted", doc.Title)
+			}
+		}
+	})
+
+	t.Run("ParentTraversal", func(t *testing.T) {
+		docs := list(t, nanostore.ListOptions{Filters: map[string]interface{}{
+			"parent.parent.title": universe.TeamMeeting.Title,
+		}})
+		testutil.AssertDocumentExists(t, docs, universe.Level3Task.UUID)
+	})
+
+	t.Run("OrderByDescendantCount", func(t *testing.T) {
+		docs := list(t, nanostore.ListOptions{
+			Filters: map[string]interface{}{"is_root": true},
+			OrderBy: []nanostore.OrderClause{{Column: "descendant_count", Descending: true}},
+		})
+		if len(docs) == 0 || docs[0].UUID != universe.TeamMeeting.Dimensions["parent_id"] {
+			t.Errorf("expected the work root with the deepest hierarchy first")
+		}
+	})
+
+	t.Run("OrderByDepth", func(t *testing.T) {
+		docs := list(t, nanostore.ListOptions{
+			OrderBy: []nanostore.OrderClause{{Column: "depth", Descending: true}},
+		})
+		if docs[0].UUID != universe.Level5Task.UUID {
+			t.Errorf("expected deepest document first, got %q", docs[0].Title)
+		}
+	})
+
+	t.Run("CursorRejected", func(t *testing.T) {
+		orderBy := []nanostore.OrderClause{{Column: "depth"}}
+		docs := list(t, nanostore.ListOptions{OrderBy: orderBy})
+		_, err := store.List(nanostore.ListOptions{
+			OrderBy: orderBy,
+			Cursor:  query.EncodeCursor(docs[0], orderBy),
+		})
+		if !errors.Is(err, query.ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor, got %v", err)
+		}
+	})
+
+	t.Run("WhereClause", func(t *testing.T) {
+		// Archive every leaf below a deleted parent
+		count, err := store.UpdateWhere("parent.activity = ? AND has_children = ?", nanostore.UpdateRequest{
+			Dimensions: map[string]interface{}{"activity": "archived"},
+		}, "deleted", false)
+		if err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+		if count == 0 {
+			t.Fatal("expected at least one matching document")
+		}
+
+		updated, err := store.GetByID(universe.OrphanChild.UUID)
+		if err != nil {
+			t.Fatalf("failed to get orphan child: %v", err)
+		}
+		if updated.Dimensions["activity"] != "archived" {
+			t.Errorf("expected orphan child to be archived, got %v", updated.Dimensions["activity"])
+		}
+
+		deleted, err := store.DeleteWhere("parent.activity = ? AND has_children = ?", "deleted", false)
+		if err != nil {
+			t.Fatalf("failed to delete: %v", err)
+		}
+		if deleted != count {
+			t.Errorf("expected the %d archived leaves to be deleted, got %d", count, deleted)
+		}
+		if doc, err := store.GetByID(universe.OrphanChild.UUID); err != nil || doc != nil {
+			t.Errorf("expected orphan child to be deleted, got %v (%v)", doc, err)
+		}
+	})
+}
@@ -20,6 +20,30 @@ type Metadata struct {
 	Version   string    `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Views holds the saved queries of the store, by name
+	Views map[string]types.ListOptions `json:"views,omitempty"`
+
+	// Positions holds the persisted SimpleID positions of stores using
+	// stable positions (types.IDScheme.StablePositions)
+	Positions *PositionTable `json:"positions,omitempty"`
+}
+
+// PositionTable records the position of every document in its partition, so
+// SimpleIDs survive deletions and restarts, and the last position handed out
+// in each partition, so positions are never reused.
+type PositionTable struct {
+	// Counters holds the last position assigned in each partition, by partition key
+	Counters map[string]int `json:"counters"`
+
+	// Documents holds the partition and position of each document, by UUID
+	Documents map[string]DocumentPosition `json:"documents"`
+}
+
+// DocumentPosition is the position of a document within a partition
+type DocumentPosition struct {
+	Partition string `json:"partition"`
+	Position  int    `json:"position"`
 }
 
 // Storage defines the low-level interface for batch persistence.
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// prepareBatch turns the documents of a batch into preprocessed add
+// commands. A document that cannot be added gets an error in results and a
+// nil command.
+func prepareBatch(dimensionSet *types.DimensionSet, docs []types.NewDocument, preprocess func(interface{}) error) ([]*AddCommand, []types.AddResult) {
+	commands := make([]*AddCommand, len(docs))
+	results := make([]types.AddResult, len(docs))
+	for i, doc := range docs {
+		if doc.ParentIndex != nil {
+			if index := *doc.ParentIndex; index < 0 || index >= i {
+				results[i].Error = fmt.Errorf("parent index %d must refer to an earlier document of the batch", index)
+				continue
+			}
+			if parentKey(dimensionSet) == "" {
+				results[i].Error = fmt.Errorf("parent index requires a hierarchical dimension")
+				continue
+			}
+		}
+		if err := rejectComputed(dimensionSet, doc.Dimensions); err != nil {
+			results[i].Error = err
+			continue
+		}
+
+		// Commands are changed while adding, the caller's maps are not
+		dimensions := maps.Clone(doc.Dimensions)
+		if dimensions == nil {
+			dimensions = make(map[string]interface{})
+		}
+		cmd := &AddCommand{Title: doc.Title, Body: doc.Body, Dimensions: dimensions, CreatedAt: doc.CreatedAt}
+		if err := preprocess(cmd); err != nil {
+			results[i].Error = fmt.Errorf("preprocessing failed: %w", err)
+			continue
+		}
+		commands[i] = cmd
+	}
+	return commands, results
+}
+
+// prepareDocument turns a single document into a preprocessed add command
+func prepareDocument(dimensionSet *types.DimensionSet, doc types.NewDocument, preprocess func(interface{}) error) (*AddCommand, error) {
+	if doc.ParentIndex != nil {
+		return nil, fmt.Errorf("parent index is only valid in a batch")
+	}
+	commands, results := prepareBatch(dimensionSet, []types.NewDocument{doc}, preprocess)
+	return commands[0], results[0].Error
+}
+
+// setBatchParent points cmd at the UUID the batch document it is a child of
+// was added with
+func setBatchParent(dimensionSet *types.DimensionSet, doc types.NewDocument, cmd *AddCommand, results []types.AddResult) error {
+	if doc.ParentIndex == nil {
+		return nil
+	}
+	parent := results[*doc.ParentIndex].UUID
+	if parent == "" {
+		return fmt.Errorf("parent document %d was not added", *doc.ParentIndex)
+	}
+	cmd.Dimensions[parentKey(dimensionSet)] = parent
+	return nil
+}
+
+// batchError returns the first error of a batch's results
+func batchError(results []types.AddResult) error {
+	for i, result := range results {
+		if result.Error != nil {
+			return fmt.Errorf("document %d: %w", i, result.Error)
+		}
+	}
+	return nil
+}
+
+// failBatch marks every document of a batch that was not added
+func failBatch(results []types.AddResult, err error) {
+	for i := range results {
+		results[i].UUID = ""
+		if results[i].Error == nil {
+			results[i].Error = err
+		}
+	}
+}
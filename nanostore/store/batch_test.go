@@ -0,0 +1,236 @@
+package store
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// countingFileSystem counts the files written through it
+type countingFileSystem struct {
+	*MockFileSystem
+	writes int
+}
+
+func (c *countingFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	c.writes++
+	return c.MockFileSystem.WriteFile(name, data, perm)
+}
+
+func batchConfig() *types.Config {
+	config := uniqueConfig()
+	config.Unique = config.Unique[:1] // slug
+	return config
+}
+
+func TestAddMany(t *testing.T) {
+	fsys := &countingFileSystem{MockFileSystem: NewMockFileSystem()}
+	st, err := NewWithOptions("test.json", batchConfig(), WithFileSystem(fsys), WithFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	first := 0
+	t.Run("SavesOnce", func(t *testing.T) {
+		writes := fsys.writes
+		results, err := st.AddMany([]types.NewDocument{
+			{Title: "Parent", Body: "body", Dimensions: map[string]interface{}{"status": "done", "_data.slug": "parent"}},
+			{Title: "Child", ParentIndex: &first},
+		}, true)
+		if err != nil {
+			t.Fatalf("failed to add batch: %v", err)
+		}
+		if fsys.writes != writes+1 {
+			t.Errorf("expected one write, got %d", fsys.writes-writes)
+		}
+
+		docs, err := st.List(types.ListOptions{})
+		if err != nil || len(docs) != 2 {
+			t.Fatalf("expected two documents, got %d (%v)", len(docs), err)
+		}
+		if docs[0].UUID != results[0].UUID || docs[0].Body != "body" || docs[0].Dimensions["status"] != "done" {
+			t.Errorf("unexpected parent %+v", docs[0])
+		}
+		if docs[1].Dimensions["parent_uuid"] != results[0].UUID || docs[1].SimpleID != docs[0].SimpleID+".1" {
+			t.Errorf("expected a child of the parent, got %+v", docs[1])
+		}
+	})
+
+	t.Run("Atomic", func(t *testing.T) {
+		writes := fsys.writes
+		results, err := st.AddMany([]types.NewDocument{
+			{Title: "Valid"},
+			{Title: "Duplicate", Dimensions: map[string]interface{}{"_data.slug": "parent"}},
+		}, true)
+		var conflict *types.UniqueConflictError
+		if !errors.As(err, &conflict) {
+			t.Fatalf("expected a unique conflict, got %v", err)
+		}
+		if results[0].UUID != "" || results[0].Error == nil || !errors.As(results[1].Error, &conflict) {
+			t.Errorf("expected every document to fail, got %+v", results)
+		}
+		if fsys.writes != writes {
+			t.Errorf("expected no write, got %d", fsys.writes-writes)
+		}
+	})
+
+	t.Run("BestEffort", func(t *testing.T) {
+		results, err := st.AddMany([]types.NewDocument{
+			{Title: "Invalid", Dimensions: map[string]interface{}{"status": "unknown"}},
+			{Title: "Orphan", ParentIndex: &first},
+			{Title: "Valid", Dimensions: map[string]interface{}{"parent_uuid": "1"}},
+			{Title: "Self", ParentIndex: &[]int{3}[0]},
+		}, false)
+		if err != nil {
+			t.Fatalf("unexpected batch error: %v", err)
+		}
+		for i, failed := range []bool{true, true, false, true} {
+			if got := results[i].Error != nil; got != failed {
+				t.Errorf("document %d: expected failure %v, got %+v", i, failed, results[i])
+			}
+		}
+		docs, err := st.List(types.ListOptions{})
+		if err != nil || len(docs) != 3 {
+			t.Errorf("expected one more document, got %d (%v)", len(docs), err)
+		}
+	})
+
+	t.Run("SaveFailure", func(t *testing.T) {
+		fsys.WriteFileError = errors.New("disk full")
+		defer func() { fsys.WriteFileError = nil }()
+		results, err := st.AddMany([]types.NewDocument{{Title: "Lost"}}, false)
+		if err == nil || results[0].Error == nil || results[0].UUID != "" {
+			t.Fatalf("expected the save to fail, got %+v (%v)", results, err)
+		}
+		fsys.WriteFileError = nil
+		docs, err := st.List(types.ListOptions{})
+		if err != nil || len(docs) != 3 {
+			t.Errorf("expected the batch to be rolled back, got %d documents (%v)", len(docs), err)
+		}
+	})
+}
+
+func TestHybridAddMany(t *testing.T) {
+	fsys := NewMockFileSystemExt()
+	st, err := NewHybridWithOptions("/test/store.json", batchConfig(), WithFileSystemExt(fsys), WithHybridFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	first := 0
+	results, err := st.AddMany([]types.NewDocument{
+		{Title: "Parent", Body: "parent body", Dimensions: map[string]interface{}{"_data.slug": "parent"}},
+		{Title: "Child", Body: "child body", ParentIndex: &first},
+	}, true)
+	if err != nil {
+		t.Fatalf("failed to add batch: %v", err)
+	}
+	child, err := st.GetByID(results[1].UUID)
+	if err != nil || child.Body != "child body" || child.Dimensions["parent_uuid"] != results[0].UUID {
+		t.Errorf("unexpected child %+v (%v)", child, err)
+	}
+
+	_, err = st.AddMany([]types.NewDocument{
+		{Title: "Valid", Body: "kept only if the batch is"},
+		{Title: "Duplicate", Dimensions: map[string]interface{}{"_data.slug": "parent"}},
+	}, true)
+	if err == nil {
+		t.Fatal("expected a unique conflict")
+	}
+	docs, err := st.List(types.ListOptions{})
+	if err != nil || len(docs) != 2 {
+		t.Errorf("expected the batch to be rolled back, got %d documents (%v)", len(docs), err)
+	}
+}
+
+func TestAddDocument(t *testing.T) {
+	fsys := &countingFileSystem{MockFileSystem: NewMockFileSystem()}
+	st, err := NewWithOptions("test.json", batchConfig(), WithFileSystem(fsys), WithFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	parent, err := st.Add("Parent", nil)
+	if err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	writes := fsys.writes
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	id, err := st.AddDocument(types.NewDocument{
+		Title:      "Imported",
+		Body:       "imported body",
+		Dimensions: map[string]interface{}{"parent_uuid": "1", "_data.slug": "imported"},
+		CreatedAt:  created,
+	})
+	if err != nil {
+		t.Fatalf("failed to add document: %v", err)
+	}
+	if fsys.writes != writes+1 {
+		t.Errorf("expected one write, got %d", fsys.writes-writes)
+	}
+
+	doc, err := st.GetByID(id)
+	if err != nil {
+		t.Fatalf("failed to get document: %v", err)
+	}
+	if doc.Body != "imported body" || doc.Dimensions["parent_uuid"] != parent {
+		t.Errorf("unexpected document %+v", doc)
+	}
+	if !doc.CreatedAt.Equal(created) || !doc.UpdatedAt.Equal(created) {
+		t.Errorf("expected the document to be dated %v, got %v and %v", created, doc.CreatedAt, doc.UpdatedAt)
+	}
+
+	first := 0
+	if _, err := st.AddDocument(types.NewDocument{Title: "Child", ParentIndex: &first}); err == nil {
+		t.Error("expected a parent index to be rejected")
+	}
+	_, err = st.AddDocument(types.NewDocument{Title: "Copy", Dimensions: map[string]interface{}{"_data.slug": "imported"}})
+	var conflict *types.UniqueConflictError
+	if !errors.As(err, &conflict) {
+		t.Errorf("expected a unique conflict, got %v", err)
+	}
+}
+
+func TestHybridAddDocument(t *testing.T) {
+	st, err := NewHybridWithOptions("/test/store.json", batchConfig(),
+		WithFileSystemExt(NewMockFileSystemExt()),
+		WithHybridFileLockFactory(NewMockFileLockFactory()),
+		WithEmbedSizeLimit(20),
+	)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	small, err := st.AddDocument(types.NewDocument{Title: "Small", Body: "tiny"})
+	if err != nil {
+		t.Fatalf("failed to add document: %v", err)
+	}
+	largeBody := strings.Repeat("Large body content. ", 10)
+	large, err := st.AddDocument(types.NewDocument{Title: "Large", Body: largeBody})
+	if err != nil {
+		t.Fatalf("failed to add document: %v", err)
+	}
+
+	storage := map[string]BodyStorageType{}
+	for _, doc := range st.(*hybridJSONFileStore).hybridData.Documents {
+		if doc.BodyMeta != nil {
+			storage[doc.UUID] = doc.BodyMeta.Type
+		}
+	}
+	if storage[small] != BodyStorageEmbedded || storage[large] != BodyStorageFile {
+		t.Errorf("expected the large body in a file and the small one embedded, got %v", storage)
+	}
+	doc, err := st.GetByID(large)
+	if err != nil || doc.Body != largeBody {
+		t.Errorf("expected the large body to be read back, got %+v (%v)", doc, err)
+	}
+}
@@ -0,0 +1,128 @@
+package store
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestCanonicalViewConfiguration(t *testing.T) {
+	dimensions := []types.DimensionConfig{
+		{
+			Name:         "status",
+			Type:         types.Enumerated,
+			Values:       []string{"pending", "active", "done"},
+			Prefixes:     map[string]string{"pending": "p", "active": "a", "done": "d"},
+			DefaultValue: "pending",
+		},
+	}
+	newStore := func(t *testing.T, view *types.CanonicalView) Store {
+		t.Helper()
+		s, err := NewWithOptions("test.json", &types.Config{Dimensions: dimensions, CanonicalView: view},
+			WithFileSystem(NewMockFileSystem()),
+			WithFileLockFactory(NewMockFileLockFactory()),
+		)
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+		for _, doc := range []struct{ title, status string }{
+			{"Plan", "pending"},
+			{"Build", "active"},
+			{"Test", "active"},
+			{"Ship", "done"},
+		} {
+			if _, err := s.Add(doc.title, map[string]interface{}{"status": doc.status}); err != nil {
+				t.Fatalf("failed to add %q: %v", doc.title, err)
+			}
+		}
+		return s
+	}
+	ids := func(t *testing.T, s Store, opts types.ListOptions) map[string]string {
+		t.Helper()
+		docs, err := s.List(opts)
+		if err != nil {
+			t.Fatalf("list failed: %v", err)
+		}
+		result := make(map[string]string)
+		for _, doc := range docs {
+			result[doc.Title] = doc.SimpleID
+		}
+		return result
+	}
+
+	t.Run("DefaultsToDimensionDefaults", func(t *testing.T) {
+		s := newStore(t, nil)
+		defer func() { _ = s.Close() }()
+
+		expected := map[string]string{"Plan": "1", "Build": "a1", "Test": "a2", "Ship": "d1"}
+		if got := ids(t, s, types.ListOptions{}); !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("ExplicitCanonicalView", func(t *testing.T) {
+		s := newStore(t, types.NewCanonicalView(types.CanonicalFilter{Dimension: "status", Value: "active"}))
+		defer func() { _ = s.Close() }()
+
+		expected := map[string]string{"Plan": "p1", "Build": "1", "Test": "2", "Ship": "d1"}
+		if got := ids(t, s, types.ListOptions{}); !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+
+		uuid, err := s.ResolveUUID("2")
+		if err != nil {
+			t.Fatalf("failed to resolve: %v", err)
+		}
+		doc, err := s.GetByID(uuid)
+		if err != nil || doc == nil || doc.Title != "Test" {
+			t.Errorf("expected \"2\" to resolve to Test, got %v (%v)", doc, err)
+		}
+	})
+
+	t.Run("Lens", func(t *testing.T) {
+		s := newStore(t, nil)
+		defer func() { _ = s.Close() }()
+
+		lens := types.NewCanonicalView(types.CanonicalFilter{Dimension: "status", Value: "done"})
+		expected := map[string]string{"Plan": "p1", "Build": "a1", "Test": "a2", "Ship": "1"}
+		if got := ids(t, s, types.ListOptions{CanonicalView: lens}); !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+
+		// The lens only changes the IDs of that call
+		if got := ids(t, s, types.ListOptions{}); got["Ship"] != "d1" {
+			t.Errorf("expected store IDs to be unchanged, got %v", got)
+		}
+
+		lensUUID, err := s.ResolveUUIDInView("1", lens)
+		if err != nil {
+			t.Fatalf("failed to resolve lens ID: %v", err)
+		}
+		storeUUID, err := s.ResolveUUID("d1")
+		if err != nil {
+			t.Fatalf("failed to resolve store ID: %v", err)
+		}
+		if lensUUID != storeUUID {
+			t.Errorf("expected lens ID 1 and store ID d1 to resolve to the same document")
+		}
+
+		if _, err := s.ResolveUUIDInView("d1", lens); err == nil {
+			t.Error("expected store IDs not to resolve through the lens")
+		}
+		if _, err := s.List(types.ListOptions{CanonicalView: types.NewCanonicalView(types.CanonicalFilter{Dimension: "status", Value: "archived"})}); err == nil {
+			t.Error("expected an error for an invalid lens")
+		}
+	})
+
+	t.Run("InvalidCanonicalView", func(t *testing.T) {
+		_, err := NewWithOptions("test.json", &types.Config{
+			Dimensions:    dimensions,
+			CanonicalView: types.NewCanonicalView(types.CanonicalFilter{Dimension: "owner", Value: "alice"}),
+		}, WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory()))
+		if err == nil || !strings.Contains(err.Error(), "unknown dimension") {
+			t.Errorf("expected an unknown dimension error, got %v", err)
+		}
+	})
+}
@@ -1,6 +1,10 @@
 package store
 
-import "github.com/arthur-debert/nanostore/types"
+import (
+	"time"
+
+	"github.com/arthur-debert/nanostore/types"
+)
 
 // Command types for preprocessing
 
@@ -8,6 +12,9 @@ import "github.com/arthur-debert/nanostore/types"
 type UpdateCommand struct {
 	ID      string `id:"true"`
 	Request types.UpdateRequest
+
+	// sets holds the changes of set dimensions, taken out of Request
+	sets map[string]setChange
 }
 
 // DeleteCommand represents a delete operation
@@ -21,4 +28,5 @@ type AddCommand struct {
 	Title      string
 	Body       string
 	Dimensions map[string]interface{}
+	CreatedAt  time.Time
 }
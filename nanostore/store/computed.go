@@ -0,0 +1,18 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// rejectComputed returns an error when values are given for computed
+// dimensions, which are derived at query time and never stored
+func rejectComputed(dimensionSet *types.DimensionSet, dimensions map[string]interface{}) error {
+	for _, dim := range dimensionSet.Computed() {
+		if _, exists := dimensions[dim.Name]; exists {
+			return fmt.Errorf("dimension %q is computed and cannot be set", dim.Name)
+		}
+	}
+	return nil
+}
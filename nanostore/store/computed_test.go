@@ -0,0 +1,198 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestComputedDimensions(t *testing.T) {
+	flagged := types.PerDocument(func(doc types.Document) string {
+		if strings.HasPrefix(doc.Title, "!") {
+			return "yes"
+		}
+		return "no"
+	})
+	s, err := NewWithOptions("test.json", &types.Config{
+		Dimensions: []types.DimensionConfig{
+			{Name: "status", Type: types.Enumerated, Values: []string{"pending", "done"}, DefaultValue: "pending"},
+			{Name: "flagged", Type: types.Enumerated, Values: []string{"no", "yes"}, Prefixes: map[string]string{"yes": "f"}, DefaultValue: "no", Compute: flagged},
+		},
+	}, WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	plainUUID, err := s.Add("Plain", nil)
+	if err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	flaggedUUID, err := s.Add("!Urgent", nil)
+	if err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	docs, err := s.List(types.ListOptions{Filters: map[string]interface{}{"flagged": "yes"}})
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(docs) != 1 || docs[0].UUID != flaggedUUID || docs[0].SimpleID != "f1" {
+		t.Fatalf("expected the flagged document as f1, got %+v", docs)
+	}
+	if uuid, err := s.ResolveUUID("f1"); err != nil || uuid != flaggedUUID {
+		t.Errorf("expected f1 to resolve to the flagged document, got %q (%v)", uuid, err)
+	}
+	if uuid, err := s.ResolveUUID("1"); err != nil || uuid != plainUUID {
+		t.Errorf("expected 1 to resolve to the plain document, got %q (%v)", uuid, err)
+	}
+
+	// Values follow the documents they are computed from
+	title := "!Plain"
+	if err := s.Update(plainUUID, types.UpdateRequest{Title: &title}); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if uuid, err := s.ResolveUUID("f1"); err != nil || uuid != plainUUID {
+		t.Errorf("expected the older document to become f1, got %q (%v)", uuid, err)
+	}
+
+	// Computed values are never stored or written
+	stored, err := s.GetByID(plainUUID)
+	if err != nil || stored == nil {
+		t.Fatalf("failed to get document: %v", err)
+	}
+	if _, exists := stored.Dimensions["flagged"]; exists {
+		t.Errorf("computed dimension should not be stored, got %v", stored.Dimensions)
+	}
+	if _, err := s.Add("Forced", map[string]interface{}{"flagged": "yes"}); err == nil {
+		t.Error("expected an error adding a computed value")
+	}
+	if err := s.Update(plainUUID, types.UpdateRequest{Dimensions: map[string]interface{}{"flagged": "no"}}); err == nil {
+		t.Error("expected an error updating a computed value")
+	}
+}
+
+func TestComputedDimensionsInMutationFilters(t *testing.T) {
+	flagged := types.PerDocument(func(doc types.Document) string {
+		if strings.HasPrefix(doc.Title, "!") {
+			return "yes"
+		}
+		return "no"
+	})
+	config := &types.Config{
+		Dimensions: []types.DimensionConfig{
+			{Name: "status", Type: types.Enumerated, Values: []string{"pending", "done"}, DefaultValue: "pending"},
+			{Name: "flagged", Type: types.Enumerated, Values: []string{"no", "yes"}, DefaultValue: "no", Compute: flagged},
+		},
+	}
+	done := types.UpdateRequest{Dimensions: map[string]interface{}{"status": "done"}}
+
+	tests := []struct {
+		name   string
+		mutate func(s Store) (int, error)
+		check  func(t *testing.T, s Store)
+	}{
+		{
+			name: "UpdateWhere",
+			mutate: func(s Store) (int, error) {
+				return s.UpdateWhere("flagged = ?", done, "yes")
+			},
+			check: expectStatusDone,
+		},
+		{
+			name: "UpdateByDimension",
+			mutate: func(s Store) (int, error) {
+				return s.UpdateByDimension(map[string]interface{}{"flagged": "yes"}, done)
+			},
+			check: expectStatusDone,
+		},
+		{
+			name: "DeleteWhere",
+			mutate: func(s Store) (int, error) {
+				return s.DeleteWhere("flagged = ?", "yes")
+			},
+			check: expectPlainLeft,
+		},
+		{
+			name: "DeleteByDimension",
+			mutate: func(s Store) (int, error) {
+				return s.DeleteByDimension(map[string]interface{}{"flagged": "yes"})
+			},
+			check: expectPlainLeft,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewWithOptions("test.json", config, WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory()))
+			if err != nil {
+				t.Fatalf("failed to create store: %v", err)
+			}
+			defer func() { _ = s.Close() }()
+
+			if _, err := s.Add("Plain", nil); err != nil {
+				t.Fatalf("failed to add: %v", err)
+			}
+			if _, err := s.Add("!Urgent", nil); err != nil {
+				t.Fatalf("failed to add: %v", err)
+			}
+
+			count, err := tt.mutate(s)
+			if err != nil {
+				t.Fatalf("mutation failed: %v", err)
+			}
+			if count != 1 {
+				t.Fatalf("expected 1 document to match the computed dimension, got %d", count)
+			}
+			tt.check(t, s)
+		})
+	}
+
+	t.Run("hybrid store", func(t *testing.T) {
+		s, err := NewHybridWithOptions("/test/store.json", config,
+			WithFileSystemExt(NewMockFileSystemExt()),
+			WithHybridFileLockFactory(NewMockFileLockFactory()),
+		)
+		if err != nil {
+			t.Fatalf("failed to create hybrid store: %v", err)
+		}
+		defer func() { _ = s.Close() }()
+
+		for _, title := range []string{"Plain", "!Urgent", "!Later"} {
+			if _, err := s.Add(title, nil); err != nil {
+				t.Fatalf("failed to add: %v", err)
+			}
+		}
+
+		if count, err := s.UpdateWhere("flagged = ? AND title = ?", done, "yes", "!Urgent"); err != nil || count != 1 {
+			t.Fatalf("expected UpdateWhere to update 1 document, got %d (%v)", count, err)
+		}
+		if count, err := s.DeleteWhere("flagged = ?", "yes"); err != nil || count != 2 {
+			t.Fatalf("expected DeleteWhere to delete 2 documents, got %d (%v)", count, err)
+		}
+		expectPlainLeft(t, s)
+	})
+}
+
+func expectStatusDone(t *testing.T, s Store) {
+	t.Helper()
+	docs, err := s.List(types.ListOptions{Filters: map[string]interface{}{"status": "done"}})
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(docs) != 1 || docs[0].Title != "!Urgent" {
+		t.Errorf("expected only the flagged document to be done, got %+v", docs)
+	}
+}
+
+func expectPlainLeft(t *testing.T, s Store) {
+	t.Helper()
+	docs, err := s.List(types.ListOptions{})
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(docs) != 1 || docs[0].Title != "Plain" {
+		t.Errorf("expected only the plain document to remain, got %+v", docs)
+	}
+}
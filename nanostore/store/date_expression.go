@@ -0,0 +1,189 @@
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateAnchors are the named points in time a date expression can start from,
+// resolved against the current time. Weeks start on Monday and end_of_*
+// anchors are the last instant of their period.
+var dateAnchors = []struct {
+	name    string
+	resolve func(now time.Time) time.Time
+}{
+	{"now", func(now time.Time) time.Time { return now }},
+	{"today", startOfDay},
+	{"yesterday", func(now time.Time) time.Time { return startOfDay(now).AddDate(0, 0, -1) }},
+	{"tomorrow", func(now time.Time) time.Time { return startOfDay(now).AddDate(0, 0, 1) }},
+	{"start_of_day", startOfDay},
+	{"end_of_day", func(now time.Time) time.Time { return endOf(startOfDay(now), 0, 0, 1) }},
+	{"start_of_week", startOfWeek},
+	{"end_of_week", func(now time.Time) time.Time { return endOf(startOfWeek(now), 0, 0, 7) }},
+	{"start_of_month", startOfMonth},
+	{"end_of_month", func(now time.Time) time.Time { return endOf(startOfMonth(now), 0, 1, 0) }},
+	{"start_of_year", startOfYear},
+	{"end_of_year", func(now time.Time) time.Time { return endOf(startOfYear(now), 1, 0, 0) }},
+}
+
+// zonedTimeFormats are absolute timestamp layouts carrying their own zone
+var zonedTimeFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999 -0700 MST", // Go's default time format
+}
+
+// localTimeFormats are absolute timestamp layouts read in the clock's zone
+var localTimeFormats = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseDateExpression resolves a date expression to a point in time, relative
+// to now. Expressions are case-insensitive and may be:
+//
+//   - absolute timestamps: RFC 3339 ("2024-03-01T09:00:00Z"), local date
+//     times ("2024-03-01 09:00") and ISO dates ("2024-03-01", at midnight)
+//   - anchors: now, today, yesterday, tomorrow, start_of_day, end_of_day,
+//     start_of_week, end_of_week, start_of_month, end_of_month,
+//     start_of_year and end_of_year
+//   - offsets from now: a signed amount and unit such as "-7d", "+2w" or
+//     "-1h30m"; units are y, mo, w and d (calendar based) and h, m, s and ms
+//   - an anchor or ISO date followed by offsets: "today-7d",
+//     "start_of_month+1w", "2024-03-01+3d"
+//
+// Local timestamps and anchors use now's location, so a clock in UTC gives
+// UTC days.
+func ParseDateExpression(expr string, now time.Time) (time.Time, error) {
+	trimmed := strings.TrimSpace(expr)
+	if trimmed == "" {
+		return time.Time{}, fmt.Errorf("empty date expression")
+	}
+	if t, ok := parseTimestamp(trimmed, now.Location()); ok {
+		return t, nil
+	}
+
+	text := strings.ToLower(trimmed)
+	base, offsets, anchored := now, text, false
+	for _, anchor := range dateAnchors {
+		if rest, found := strings.CutPrefix(text, anchor.name); found && (rest == "" || rest[0] == '+' || rest[0] == '-') {
+			base, offsets, anchored = anchor.resolve(now), rest, true
+			break
+		}
+	}
+	if !anchored && len(text) > len("2006-01-02") {
+		if date, err := time.ParseInLocation("2006-01-02", text[:10], now.Location()); err == nil {
+			base, offsets, anchored = date, text[10:], true
+		}
+	}
+	if anchored && offsets == "" {
+		return base, nil
+	}
+	if anchored && offsets[0] != '+' && offsets[0] != '-' {
+		return time.Time{}, fmt.Errorf("invalid date expression %q", expr)
+	}
+
+	result, err := applyDateOffsets(base, offsets)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date expression %q: %w", expr, err)
+	}
+	return result, nil
+}
+
+// applyDateOffsets adds a sequence of offsets such as "-1w+2d" or "1h30m" to
+// base. An offset without a sign takes the sign of the one before it, and the
+// first defaults to positive.
+func applyDateOffsets(base time.Time, offsets string) (time.Time, error) {
+	sign := 1
+	for offsets != "" {
+		switch offsets[0] {
+		case '+':
+			sign, offsets = 1, offsets[1:]
+		case '-':
+			sign, offsets = -1, offsets[1:]
+		}
+
+		digits := 0
+		for digits < len(offsets) && offsets[digits] >= '0' && offsets[digits] <= '9' {
+			digits++
+		}
+		letters := digits
+		for letters < len(offsets) && offsets[letters] >= 'a' && offsets[letters] <= 'z' {
+			letters++
+		}
+		if digits == 0 || letters == digits {
+			return time.Time{}, fmt.Errorf("expected an amount and unit in %q", offsets)
+		}
+
+		amount, err := strconv.Atoi(offsets[:digits])
+		if err != nil {
+			return time.Time{}, err
+		}
+		amount *= sign
+
+		switch unit := offsets[digits:letters]; unit {
+		case "y":
+			base = base.AddDate(amount, 0, 0)
+		case "mo":
+			base = base.AddDate(0, amount, 0)
+		case "w":
+			base = base.AddDate(0, 0, 7*amount)
+		case "d":
+			base = base.AddDate(0, 0, amount)
+		case "h", "m", "s", "ms":
+			duration, err := time.ParseDuration(strconv.Itoa(amount) + unit)
+			if err != nil {
+				return time.Time{}, err
+			}
+			base = base.Add(duration)
+		default:
+			return time.Time{}, fmt.Errorf("unknown unit %q (use y, mo, w, d, h, m, s or ms)", unit)
+		}
+		offsets = offsets[letters:]
+	}
+	return base, nil
+}
+
+// parseTimestamp parses an absolute timestamp in one of the supported
+// layouts, reading layouts without a zone in loc
+func parseTimestamp(text string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range zonedTimeFormats {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, true
+		}
+	}
+	for _, layout := range localTimeFormats {
+		if t, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func startOfDay(now time.Time) time.Time {
+	year, month, day := now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+}
+
+func startOfWeek(now time.Time) time.Time {
+	sinceMonday := (int(now.Weekday()) + 6) % 7
+	return startOfDay(now).AddDate(0, 0, -sinceMonday)
+}
+
+func startOfMonth(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+}
+
+func startOfYear(now time.Time) time.Time {
+	return time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+}
+
+// endOf returns the last instant of the period of the given length that
+// starts at start
+func endOf(start time.Time, years, months, days int) time.Time {
+	return start.AddDate(years, months, days).Add(-time.Nanosecond)
+}
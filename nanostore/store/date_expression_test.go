@@ -0,0 +1,165 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestParseDateExpression(t *testing.T) {
+	// Wednesday afternoon
+	now := time.Date(2024, 1, 17, 15, 30, 0, 0, time.UTC)
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"now", now},
+		{"today", day(2024, 1, 17)},
+		{"  Today ", day(2024, 1, 17)},
+		{"yesterday", day(2024, 1, 16)},
+		{"tomorrow", day(2024, 1, 18)},
+		{"end_of_day", day(2024, 1, 18).Add(-time.Nanosecond)},
+		{"start_of_week", day(2024, 1, 15)},
+		{"end_of_week", day(2024, 1, 22).Add(-time.Nanosecond)},
+		{"start_of_month", day(2024, 1, 1)},
+		{"end_of_month", day(2024, 2, 1).Add(-time.Nanosecond)},
+		{"start_of_year", day(2024, 1, 1)},
+		{"end_of_year", day(2025, 1, 1).Add(-time.Nanosecond)},
+
+		// Offsets from now
+		{"-7d", now.AddDate(0, 0, -7)},
+		{"+2w", now.AddDate(0, 0, 14)},
+		{"3d", now.AddDate(0, 0, 3)},
+		{"-1mo", now.AddDate(0, -1, 0)},
+		{"+1y", now.AddDate(1, 0, 0)},
+		{"-90m", now.Add(-90 * time.Minute)},
+		{"-1h30m", now.Add(-90 * time.Minute)},
+		{"+1w-2d", now.AddDate(0, 0, 5)},
+
+		// Anchors and dates with offsets
+		{"today-7d", day(2024, 1, 10)},
+		{"start_of_month+1w", day(2024, 1, 8)},
+		{"start_of_week+4h", day(2024, 1, 15).Add(4 * time.Hour)},
+		{"2024-03-01+3d", day(2024, 3, 4)},
+
+		// Absolute timestamps
+		{"2024-03-01", day(2024, 3, 1)},
+		{"2024-03-01 09:15", time.Date(2024, 3, 1, 9, 15, 0, 0, time.UTC)},
+		{"2024-03-01T09:15:30", time.Date(2024, 3, 1, 9, 15, 30, 0, time.UTC)},
+		{"2024-03-01T09:15:30Z", time.Date(2024, 3, 1, 9, 15, 30, 0, time.UTC)},
+		{"2024-03-01T09:15:30+02:00", time.Date(2024, 3, 1, 7, 15, 30, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDateExpression(tt.expr, now)
+		if err != nil {
+			t.Errorf("ParseDateExpression(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("ParseDateExpression(%q) = %v, expected %v", tt.expr, got, tt.expected)
+		}
+	}
+
+	t.Run("LocalTimeUsesClockLocation", func(t *testing.T) {
+		zone := time.FixedZone("UTC-5", -5*60*60)
+		got, err := ParseDateExpression("today", now.In(zone))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := time.Date(2024, 1, 17, 0, 0, 0, 0, zone); !got.Equal(expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, expr := range []string{"", "soon", "7", "-7x", "today7d", "todays", "+", "2024-13-01"} {
+			if _, err := ParseDateExpression(expr, now); err == nil {
+				t.Errorf("expected an error for %q", expr)
+			}
+		}
+	})
+}
+
+func TestWhereEvaluator_DateExpressions(t *testing.T) {
+	now := time.Date(2024, 1, 17, 15, 30, 0, 0, time.UTC)
+	doc := &types.Document{
+		CreatedAt: now.AddDate(0, 0, -3),
+		UpdatedAt: now.Add(-time.Hour),
+		Dimensions: map[string]interface{}{
+			"_data.due_date": now.AddDate(0, 0, 2).Format(time.RFC3339),
+			"_data.title":    "2024 plan",
+		},
+	}
+
+	tests := []struct {
+		clause   string
+		arg      interface{}
+		expected bool
+	}{
+		{"created_at > ?", "-7d", true},
+		{"created_at > ?", "-2d", false},
+		{"created_at < ?", "today", true},
+		{"updated_at >= ?", "today", true},
+		{"updated_at >= ?", "start_of_week", true},
+		{"created_at >= ?", "2024-01-14", true},
+		{"created_at < ?", "2024-01-14", false},
+
+		// Time data fields are stored as RFC 3339 text
+		{"_data.due_date > ?", "tomorrow", true},
+		{"_data.due_date < ?", "end_of_week", true},
+		{"_data.due_date < ?", "today", false},
+		{"_data.due_date > ?", "2024-01-19T12:00:00-05:00", false},
+
+		// Other text keeps comparing as text
+		{"_data.title = ?", "2024 plan", true},
+	}
+
+	for _, tt := range tests {
+		evaluator := NewWhereEvaluator(tt.clause, tt.arg).WithClock(func() time.Time { return now })
+		result, err := evaluator.EvaluateDocument(doc)
+		if err != nil {
+			t.Errorf("evaluating %q with %v: %v", tt.clause, tt.arg, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("evaluating %q with %v: expected %v, got %v", tt.clause, tt.arg, tt.expected, result)
+		}
+	}
+}
+
+func TestWhereEvaluator_DateExpressionsInClockLocation(t *testing.T) {
+	honolulu := time.FixedZone("HST", -10*60*60)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, honolulu)
+	doc := &types.Document{
+		Dimensions: map[string]interface{}{
+			"_data.due": "2024-06-01",
+		},
+	}
+
+	// A date stored without a zone is the same day as the clock's today
+	tests := []struct {
+		clause   string
+		expected bool
+	}{
+		{"_data.due < ?", false},
+		{"_data.due >= ?", true},
+		{"_data.due = ?", true},
+	}
+	for _, tt := range tests {
+		evaluator := NewWhereEvaluator(tt.clause, "today").WithClock(func() time.Time { return now })
+		result, err := evaluator.EvaluateDocument(doc)
+		if err != nil {
+			t.Errorf("evaluating %q: %v", tt.clause, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("evaluating %q: expected %v, got %v", tt.clause, tt.expected, result)
+		}
+	}
+}
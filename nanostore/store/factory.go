@@ -11,6 +11,42 @@ type Config interface {
 	GetDimensionSet() *types.DimensionSet
 }
 
+// canonicalViewConfig is implemented by configs that choose their canonical
+// view, such as *types.Config and *types.ConfigWithCanonicalView
+type canonicalViewConfig interface {
+	GetCanonicalView() *types.CanonicalView
+}
+
+// canonicalViewFor returns the canonical view of a configuration. Configs
+// without one use the view derived from the dimension defaults.
+func canonicalViewFor(config Config) (*types.CanonicalView, error) {
+	var view *types.CanonicalView
+	if c, ok := config.(canonicalViewConfig); ok {
+		view = c.GetCanonicalView()
+	}
+	if view == nil {
+		return types.DefaultCanonicalView(config.GetDimensionSet()), nil
+	}
+	if err := view.Validate(config.GetDimensionSet()); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// idSchemeConfig is implemented by configs that choose how SimpleIDs are
+// written, such as *types.Config
+type idSchemeConfig interface {
+	GetIDScheme() types.IDScheme
+}
+
+// idSchemeFor returns the ID scheme of a configuration, or the default one
+func idSchemeFor(config Config) types.IDScheme {
+	if c, ok := config.(idSchemeConfig); ok {
+		return c.GetIDScheme()
+	}
+	return types.DefaultIDScheme()
+}
+
 // New creates a new Store instance with the specified dimension configuration
 // The store uses a JSON file backend with file locking for concurrent access
 func New(filePath string, config Config) (Store, error) {
@@ -3,6 +3,7 @@ package store
 import (
 	"time"
 
+	"github.com/arthur-debert/nanostore/nanostore/storage"
 	"github.com/arthur-debert/nanostore/types"
 )
 
@@ -64,4 +65,10 @@ type HybridMetadata struct {
 		EmbedSizeLimit int64  `json:"embed_size_limit"`
 		BodiesDir      string `json:"bodies_dir"`
 	} `json:"body_storage,omitempty"`
+
+	// Views holds the saved queries of the store, by name
+	Views map[string]types.ListOptions `json:"views,omitempty"`
+
+	// Positions holds the persisted SimpleID positions (see storage.PositionTable)
+	Positions *storage.PositionTable `json:"positions,omitempty"`
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
@@ -24,6 +25,7 @@ type hybridJSONFileStore struct {
 	config        Config
 	dimensionSet  *types.DimensionSet
 	canonicalView *types.CanonicalView
+	unique        []types.UniqueConstraint
 	idGenerator   *ids.IDGenerator
 	preprocessor  *hybridCommandPreprocessor
 	queryProc     query.Processor
@@ -46,32 +48,27 @@ type hybridJSONFileStore struct {
 
 // newHybridJSONFileStore creates a new hybrid JSON file store
 func newHybridJSONFileStore(filePath string, config Config, opts ...HybridJSONFileStoreOption) (*hybridJSONFileStore, error) {
-	// Create canonical view from config
-	var filters []types.CanonicalFilter
-	for _, dim := range config.GetDimensionSet().Enumerated() {
-		if dim.DefaultValue != "" {
-			filters = append(filters, types.CanonicalFilter{
-				Dimension: dim.Name,
-				Value:     dim.DefaultValue,
-			})
-		}
-	}
-	// Hierarchical dimensions default to "*" (any value)
-	for _, dim := range config.GetDimensionSet().Hierarchical() {
-		filters = append(filters, types.CanonicalFilter{
-			Dimension: dim.Name,
-			Value:     "*",
-		})
+	canonicalView, err := canonicalViewFor(config)
+	if err != nil {
+		return nil, err
 	}
-	canonicalView := types.NewCanonicalView(filters...)
 
-	idGen := ids.NewIDGenerator(config.GetDimensionSet(), canonicalView)
+	idGen, err := ids.NewIDGeneratorWithScheme(config.GetDimensionSet(), canonicalView, idSchemeFor(config))
+	if err != nil {
+		return nil, err
+	}
+
+	unique, err := uniqueConstraintsFor(config)
+	if err != nil {
+		return nil, err
+	}
 
 	store := &hybridJSONFileStore{
 		filePath:      filePath,
 		config:        config,
 		dimensionSet:  config.GetDimensionSet(),
 		canonicalView: canonicalView,
+		unique:        unique,
 		idGenerator:   idGen,
 		queryProc:     query.NewProcessor(config.GetDimensionSet(), idGen),
 		lockManager:   storage.NewLockManager(),
@@ -118,6 +115,15 @@ func newHybridJSONFileStore(filePath string, config Config, opts ...HybridJSONFi
 		store.hybridData.Metadata.BodyStorageConfig.BodiesDir = "bodies"
 	}
 
+	// Stable positions are read from the metadata loaded below
+	if idGen.Scheme().StablePositions {
+		idGen.WithPositions(func(uuid string, partition string) (int, bool) {
+			return lookupPosition(store.hybridData.Metadata.Positions, uuid, partition)
+		}, func(partition string) int {
+			return lookupCounter(store.hybridData.Metadata.Positions, partition)
+		})
+	}
+
 	// Initialize preprocessor
 	store.preprocessor = newHybridCommandPreprocessor(store)
 
@@ -182,6 +188,7 @@ func (s *hybridJSONFileStore) load() error {
 			}
 		}
 
+		s.syncPositions()
 		return nil
 	}
 
@@ -193,6 +200,7 @@ func (s *hybridJSONFileStore) load() error {
 
 	// Convert legacy format to hybrid format
 	s.hybridData = s.convertLegacyToHybrid(&legacyData)
+	s.syncPositions()
 
 	// Mark for save to persist in new format
 	// This will happen on the next write operation
@@ -209,6 +217,8 @@ func (s *hybridJSONFileStore) convertLegacyToHybrid(legacy *storage.StoreData) *
 			StorageVersion: "hybrid_v1",
 			CreatedAt:      legacy.Metadata.CreatedAt,
 			UpdatedAt:      legacy.Metadata.UpdatedAt,
+			Views:          legacy.Metadata.Views,
+			Positions:      legacy.Metadata.Positions,
 		},
 	}
 
@@ -249,6 +259,13 @@ func (s *hybridJSONFileStore) convertLegacyToHybrid(legacy *storage.StoreData) *
 	return hybrid
 }
 
+// syncPositions updates the persisted positions when the store keeps them
+func (s *hybridJSONFileStore) syncPositions() {
+	if s.idGenerator.Scheme().StablePositions {
+		syncPositions(&s.hybridData.Metadata.Positions, s.standardDocuments(), s.dimensionSet)
+	}
+}
+
 // saveWithLock saves the data with proper locking
 func (s *hybridJSONFileStore) saveWithLock() error {
 	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
@@ -268,6 +285,7 @@ func (s *hybridJSONFileStore) saveWithLock() error {
 func (s *hybridJSONFileStore) save() error {
 	// Update metadata
 	s.hybridData.Metadata.UpdatedAt = s.timeFunc()
+	s.syncPositions()
 
 	// Marshal to JSON with pretty printing
 	data, err := json.MarshalIndent(s.hybridData, "", "  ")
@@ -315,7 +333,8 @@ func (s *hybridJSONFileStore) List(opts types.ListOptions) ([]types.Document, er
 		standardDocs := make([]types.Document, len(s.hybridData.Documents))
 		for i, hdoc := range s.hybridData.Documents {
 			// Load body content if needed
-			if hdoc.BodyMeta != nil {
+			// Bodies are skipped when the caller excludes them and no search needs them
+			if hdoc.BodyMeta != nil && (!opts.ExcludeBody || opts.FilterBySearch != "") {
 				body, err := s.bodyStorage.ReadBody(*hdoc.BodyMeta, hdoc.Body)
 				if err != nil {
 					// Log error but continue with empty body
@@ -342,8 +361,22 @@ func (s *hybridJSONFileStore) List(opts types.ListOptions) ([]types.Document, er
 	return result, nil
 }
 
+// standardDocuments converts the hybrid documents to standard documents
+// without loading external bodies, for evaluations that only need metadata
+func (s *hybridJSONFileStore) standardDocuments() []types.Document {
+	docs := make([]types.Document, len(s.hybridData.Documents))
+	for i, hdoc := range s.hybridData.Documents {
+		docs[i] = hdoc.ToStandardDocument()
+	}
+	return docs
+}
+
 // Add creates a new document
 func (s *hybridJSONFileStore) Add(title string, dimensions map[string]interface{}) (string, error) {
+	if err := rejectComputed(s.dimensionSet, dimensions); err != nil {
+		return "", err
+	}
+
 	// Extract body from dimensions if present
 	body := ""
 	if bodyVal, ok := dimensions["_body"]; ok {
@@ -364,115 +397,238 @@ func (s *hybridJSONFileStore) Add(title string, dimensions map[string]interface{
 		return "", err
 	}
 
-	var newID string
+	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
+		return s.addLocked(&cmd)
+	})
+
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+// addLocked creates a new document from a preprocessed command. The caller
+// must hold the write lock.
+func (s *hybridJSONFileStore) addLocked(cmd *AddCommand) (string, error) {
+	doc, err := s.newDocumentLocked(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	// Add to store
+	s.hybridData.Documents = append(s.hybridData.Documents, doc)
+
+	// Save to file
+	if err := s.saveWithLock(); err != nil {
+		// Remove the document we just added
+		s.hybridData.Documents = s.hybridData.Documents[:len(s.hybridData.Documents)-1]
+		// Clean up body file if created
+		if doc.BodyMeta != nil {
+			_ = s.bodyStorage.DeleteBody(*doc.BodyMeta)
+		}
+		return "", fmt.Errorf("failed to save: %w", err)
+	}
+
+	return doc.UUID, nil
+}
+
+// AddDocument creates a new document with its body, stored in a file when
+// it is too large to embed
+func (s *hybridJSONFileStore) AddDocument(doc types.NewDocument) (string, error) {
+	cmd, err := prepareDocument(s.dimensionSet, doc, s.preprocessor.preprocessCommand)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
+		return s.addLocked(cmd)
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+// AddMany adds a batch of documents, saving once
+func (s *hybridJSONFileStore) AddMany(docs []types.NewDocument, atomic bool) ([]types.AddResult, error) {
+	commands, results := prepareBatch(s.dimensionSet, docs, s.preprocessor.preprocessCommand)
+	if atomic {
+		if err := batchError(results); err != nil {
+			failBatch(results, err)
+			return results, err
+		}
+	}
+
 	err := s.lockManager.Execute(storage.WriteOperation, func() error {
-		// Create new document
-		doc := HybridDocument{
-			UUID:       uuid.New().String(),
-			Title:      cmd.Title,
-			Dimensions: make(map[string]interface{}),
-			CreatedAt:  s.timeFunc(),
-			UpdatedAt:  s.timeFunc(),
-		}
-
-		// Handle body if provided
-		if cmd.Body != "" {
-			format := BodyFormatText // Default format
-			if formatStr, ok := cmd.Dimensions["_body.format"].(string); ok {
-				if parsed, err := ParseBodyFormat(formatStr); err == nil {
-					format = parsed
+		count := len(s.hybridData.Documents)
+		rollback := func(err error) error {
+			for _, doc := range s.hybridData.Documents[count:] {
+				if doc.BodyMeta != nil {
+					_ = s.bodyStorage.DeleteBody(*doc.BodyMeta)
 				}
-				// Remove format from dimensions as it's metadata
-				delete(cmd.Dimensions, "_body.format")
 			}
+			s.hybridData.Documents = s.hybridData.Documents[:count]
+			failBatch(results, err)
+			return err
+		}
 
-			// Determine if we should force embed
-			forceEmbed := false
-			if force, ok := cmd.Dimensions["_body.embed"].(bool); ok {
-				forceEmbed = force
-				delete(cmd.Dimensions, "_body.embed")
+		for i, cmd := range commands {
+			if cmd == nil {
+				continue
 			}
-
-			// Write body
-			bodyMeta, embeddedBody, err := s.bodyStorage.WriteBody(doc.UUID, cmd.Body, format, forceEmbed)
+			if err := setBatchParent(s.dimensionSet, docs[i], cmd, results); err != nil {
+				results[i].Error = err
+				continue
+			}
+			doc, err := s.newDocumentLocked(cmd)
 			if err != nil {
-				return fmt.Errorf("failed to write body: %w", err)
+				results[i].Error = err
+				if atomic {
+					break
+				}
+				continue
 			}
-			doc.BodyMeta = &bodyMeta
-			doc.Body = embeddedBody
+			s.hybridData.Documents = append(s.hybridData.Documents, doc)
+			results[i].UUID = doc.UUID
 		}
 
-		// Validate dimensions
-		for name, value := range cmd.Dimensions {
-			// Skip validation for _data fields - they can be any type
-			if strings.HasPrefix(name, "_data.") {
-				continue
-			}
-			if err := validation.ValidateSimpleType(value, name); err != nil {
-				return err
+		if atomic {
+			if err := batchError(results); err != nil {
+				return rollback(err)
 			}
 		}
+		if len(s.hybridData.Documents) == count {
+			return nil
+		}
+		if err := s.saveWithLock(); err != nil {
+			return rollback(fmt.Errorf("failed to save: %w", err))
+		}
+		return nil
+	})
+	return results, err
+}
 
-		// Apply dimension values
-		for _, dimConfig := range s.dimensionSet.All() {
-			switch dimConfig.Type {
-			case types.Enumerated:
-				// Check if value was provided
-				if val, exists := cmd.Dimensions[dimConfig.Name]; exists {
-					// Validate the value
-					strVal := fmt.Sprintf("%v", val)
-					// For dimensions with empty Values array (simple dimensions like pointer types),
-					// allow any value. Otherwise, validate against the predefined values.
-					if len(dimConfig.Values) > 0 && !contains(dimConfig.Values, strVal) {
-						return fmt.Errorf("invalid value %q for dimension %q", strVal, dimConfig.Name)
-					}
-					doc.Dimensions[dimConfig.Name] = strVal
-				} else if dimConfig.DefaultValue != "" {
-					// Use default value
-					doc.Dimensions[dimConfig.Name] = dimConfig.DefaultValue
-				}
-			case types.Hierarchical:
-				// Handle parent reference
-				// ID resolution already handled by preprocessor
-				if val, exists := cmd.Dimensions[dimConfig.RefField]; exists {
-					doc.Dimensions[dimConfig.RefField] = fmt.Sprintf("%v", val)
-				}
+// newDocumentLocked builds and validates the document a preprocessed
+// command adds, writing its body, without adding it. The caller must hold
+// the write lock.
+func (s *hybridJSONFileStore) newDocumentLocked(cmd *AddCommand) (_ HybridDocument, err error) {
+	// Create new document, dated as the command asks when it does
+	now := s.timeFunc()
+	if !cmd.CreatedAt.IsZero() {
+		now = cmd.CreatedAt
+	}
+	doc := HybridDocument{
+		UUID:       uuid.New().String(),
+		Title:      cmd.Title,
+		Dimensions: make(map[string]interface{}),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	// Clean up the body file of a document that is rejected
+	defer func() {
+		if err != nil && doc.BodyMeta != nil {
+			_ = s.bodyStorage.DeleteBody(*doc.BodyMeta)
+		}
+	}()
+
+	// Handle body if provided
+	if cmd.Body != "" {
+		format := BodyFormatText // Default format
+		if formatStr, ok := cmd.Dimensions["_body.format"].(string); ok {
+			if parsed, err := ParseBodyFormat(formatStr); err == nil {
+				format = parsed
 			}
+			// Remove format from dimensions as it's metadata
+			delete(cmd.Dimensions, "_body.format")
 		}
 
-		// Also store any _data prefixed values directly
-		for key, value := range cmd.Dimensions {
-			if strings.HasPrefix(key, "_data.") {
-				doc.Dimensions[key] = value
-			}
+		// Determine if we should force embed
+		forceEmbed := false
+		if force, ok := cmd.Dimensions["_body.embed"].(bool); ok {
+			forceEmbed = force
+			delete(cmd.Dimensions, "_body.embed")
 		}
 
-		// Add to store
-		s.hybridData.Documents = append(s.hybridData.Documents, doc)
-		newID = doc.UUID
+		// Write body
+		bodyMeta, embeddedBody, err := s.bodyStorage.WriteBody(doc.UUID, cmd.Body, format, forceEmbed)
+		if err != nil {
+			return HybridDocument{}, fmt.Errorf("failed to write body: %w", err)
+		}
+		doc.BodyMeta = &bodyMeta
+		doc.Body = embeddedBody
+	}
 
-		// Save to file
-		if err := s.saveWithLock(); err != nil {
-			// Remove the document we just added
-			s.hybridData.Documents = s.hybridData.Documents[:len(s.hybridData.Documents)-1]
-			// Clean up body file if created
-			if doc.BodyMeta != nil {
-				_ = s.bodyStorage.DeleteBody(*doc.BodyMeta)
-			}
-			return fmt.Errorf("failed to save: %w", err)
+	// Validate dimensions
+	for name, value := range cmd.Dimensions {
+		// Skip validation for _data fields - they can be any type
+		// Set dimensions hold lists and are validated below
+		if strings.HasPrefix(name, "_data.") || isSetDimension(s.dimensionSet, name) {
+			continue
 		}
+		if err := validation.ValidateSimpleType(value, name); err != nil {
+			return HybridDocument{}, err
+		}
+	}
 
-		return nil
-	})
+	// Apply dimension values
+	for _, dimConfig := range s.dimensionSet.All() {
+		switch dimConfig.Type {
+		case types.Enumerated:
+			// Check if value was provided
+			if val, exists := cmd.Dimensions[dimConfig.Name]; exists {
+				// Validate the value
+				strVal := fmt.Sprintf("%v", val)
+				// For dimensions with empty Values array (simple dimensions like pointer types),
+				// allow any value. Otherwise, validate against the predefined values.
+				if len(dimConfig.Values) > 0 && !contains(dimConfig.Values, strVal) {
+					return HybridDocument{}, fmt.Errorf("invalid value %q for dimension %q", strVal, dimConfig.Name)
+				}
+				doc.Dimensions[dimConfig.Name] = strVal
+			} else if dimConfig.DefaultValue != "" && !dimConfig.IsComputed() {
+				// Use default value
+				doc.Dimensions[dimConfig.Name] = dimConfig.DefaultValue
+			}
+		case types.Hierarchical:
+			// Handle parent reference
+			// ID resolution already handled by preprocessor
+			if val, exists := cmd.Dimensions[dimConfig.RefField]; exists {
+				doc.Dimensions[dimConfig.RefField] = fmt.Sprintf("%v", val)
+			}
+		case types.Set:
+			if val, exists := cmd.Dimensions[dimConfig.Name]; exists {
+				change, err := parseSetChange(&dimConfig, val)
+				if err != nil {
+					return HybridDocument{}, err
+				}
+				applySetChanges(&doc.Dimensions, map[string]setChange{dimConfig.Name: change})
+			}
+		}
+	}
 
-	if err != nil {
-		return "", err
+	// Also store any _data prefixed values directly
+	for key, value := range cmd.Dimensions {
+		if strings.HasPrefix(key, "_data.") {
+			doc.Dimensions[key] = value
+		}
 	}
-	return newID, nil
+	applyStamps(&doc.Dimensions, creationStamps(s.dimensionSet, doc.CreatedAt))
+	if err := checkUnique(s.unique, parentKey(s.dimensionSet), s.standardDocuments(), doc.ToStandardDocument()); err != nil {
+		return HybridDocument{}, err
+	}
+	return doc, nil
 }
 
 // Update modifies an existing document
 func (s *hybridJSONFileStore) Update(id string, updates types.UpdateRequest) error {
+	if err := rejectComputed(s.dimensionSet, updates.Dimensions); err != nil {
+		return err
+	}
+	updates, sets, err := splitSetUpdates(s.dimensionSet, updates)
+	if err != nil {
+		return err
+	}
+
 	// Extract body from Dimensions if present
 	if updates.Dimensions != nil {
 		if bodyVal, ok := updates.Dimensions["_body"]; ok {
@@ -486,104 +642,226 @@ func (s *hybridJSONFileStore) Update(id string, updates types.UpdateRequest) err
 	cmd := UpdateCommand{
 		ID:      id,
 		Request: updates,
+		sets:    sets,
 	}
 
 	// Preprocess the command
 	if err := s.preprocessor.preprocessCommand(&cmd); err != nil {
 		return err
 	}
-	updates = cmd.Request
-
 	return s.lockManager.Execute(storage.WriteOperation, func() error {
-		// Find the document
-		var found bool
-		var docIndex int
-		for i, doc := range s.hybridData.Documents {
-			if doc.UUID == cmd.ID {
-				found = true
-				docIndex = i
-				break
-			}
+		return s.updateLocked(&cmd)
+	})
+}
+
+// updateLocked applies a preprocessed update to the document with the
+// command's UUID. The caller must hold the write lock.
+func (s *hybridJSONFileStore) updateLocked(cmd *UpdateCommand) error {
+	updates := cmd.Request
+
+	// Find the document
+	var found bool
+	var docIndex int
+	for i, doc := range s.hybridData.Documents {
+		if doc.UUID == cmd.ID {
+			found = true
+			docIndex = i
+			break
 		}
+	}
+
+	if !found {
+		return fmt.Errorf("document not found: %s", cmd.ID)
+	}
+
+	// Update the document
+	doc := &s.hybridData.Documents[docIndex]
+	current := doc.ToStandardDocument()
+	if err := checkTransitions(s.dimensionSet, current, updates.Dimensions); err != nil {
+		return err
+	}
+	projected := projectUpdate(s.dimensionSet, current, updates.Dimensions)
+	if err := checkUnique(s.unique, parentKey(s.dimensionSet), s.standardDocuments(), projected); err != nil {
+		return err
+	}
+	doc.UpdatedAt = s.timeFunc()
+	stamps := transitionStamps(s.dimensionSet, current, updates.Dimensions, doc.UpdatedAt)
+
+	// Update title if provided
+	if updates.Title != nil && *updates.Title != "" {
+		doc.Title = *updates.Title
+	}
+
+	// Update body if provided
+	if updates.Body != nil {
+		newBody := *updates.Body
 
-		if !found {
-			return fmt.Errorf("document not found: %s", cmd.ID)
+		// Determine format
+		format := BodyFormatText
+		if doc.BodyMeta != nil {
+			format = doc.BodyMeta.Format
+		}
+		if formatStr, ok := updates.Dimensions["_body.format"].(string); ok {
+			if parsed, err := ParseBodyFormat(formatStr); err == nil {
+				format = parsed
+			}
+			delete(updates.Dimensions, "_body.format")
 		}
 
-		// Update the document
-		doc := &s.hybridData.Documents[docIndex]
-		doc.UpdatedAt = s.timeFunc()
+		// Determine if we should force embed
+		forceEmbed := false
+		if force, ok := updates.Dimensions["_body.embed"].(bool); ok {
+			forceEmbed = force
+			delete(updates.Dimensions, "_body.embed")
+		}
 
-		// Update title if provided
-		if updates.Title != nil && *updates.Title != "" {
-			doc.Title = *updates.Title
+		// Delete old body if it was in a file
+		if doc.BodyMeta != nil && doc.BodyMeta.Type == BodyStorageFile {
+			_ = s.bodyStorage.DeleteBody(*doc.BodyMeta)
 		}
 
-		// Update body if provided
-		if updates.Body != nil {
-			newBody := *updates.Body
+		// Write new body
+		bodyMeta, embeddedBody, err := s.bodyStorage.WriteBody(doc.UUID, newBody, format, forceEmbed)
+		if err != nil {
+			return fmt.Errorf("failed to write body: %w", err)
+		}
+		doc.BodyMeta = &bodyMeta
+		doc.Body = embeddedBody
+	}
 
-			// Determine format
-			format := BodyFormatText
-			if doc.BodyMeta != nil {
-				format = doc.BodyMeta.Format
-			}
-			if formatStr, ok := updates.Dimensions["_body.format"].(string); ok {
-				if parsed, err := ParseBodyFormat(formatStr); err == nil {
-					format = parsed
+	// Apply dimension updates
+	if updates.Dimensions != nil {
+		// Validate updates
+		for name, value := range updates.Dimensions {
+			if !strings.HasPrefix(name, "_data.") {
+				if err := validation.ValidateSimpleType(value, name); err != nil {
+					return err
 				}
-				delete(updates.Dimensions, "_body.format")
 			}
+		}
 
-			// Determine if we should force embed
-			forceEmbed := false
-			if force, ok := updates.Dimensions["_body.embed"].(bool); ok {
-				forceEmbed = force
-				delete(updates.Dimensions, "_body.embed")
+		// Apply dimension updates
+		for key, value := range updates.Dimensions {
+			if value == nil {
+				// nil value means delete the dimension
+				delete(doc.Dimensions, key)
+			} else {
+				doc.Dimensions[key] = value
 			}
+		}
+	}
+	applyStamps(&doc.Dimensions, stamps)
+	applySetChanges(&doc.Dimensions, cmd.sets)
 
-			// Delete old body if it was in a file
-			if doc.BodyMeta != nil && doc.BodyMeta.Type == BodyStorageFile {
-				_ = s.bodyStorage.DeleteBody(*doc.BodyMeta)
-			}
+	// Save to file
+	if err := s.saveWithLock(); err != nil {
+		return fmt.Errorf("failed to save: %w", err)
+	}
 
-			// Write new body
-			bodyMeta, embeddedBody, err := s.bodyStorage.WriteBody(doc.UUID, newBody, format, forceEmbed)
-			if err != nil {
-				return fmt.Errorf("failed to write body: %w", err)
-			}
-			doc.BodyMeta = &bodyMeta
-			doc.Body = embeddedBody
+	return nil
+}
+
+// Upsert updates the document matching key or adds a new one
+func (s *hybridJSONFileStore) Upsert(key map[string]interface{}, create types.NewDocument, update types.UpdateRequest) (string, bool, error) {
+	if len(key) == 0 {
+		return "", false, fmt.Errorf("upsert key cannot be empty")
+	}
+	if err := rejectComputed(s.dimensionSet, update.Dimensions); err != nil {
+		return "", false, err
+	}
+	update, sets, err := splitSetUpdates(s.dimensionSet, update)
+	if err != nil {
+		return "", false, err
+	}
+	addCmd, err := prepareDocument(s.dimensionSet, create, s.preprocessor.preprocessCommand)
+	if err != nil {
+		return "", false, err
+	}
+
+	// Update bodies may travel in the dimensions, as they do for Update
+	if body, ok := update.Dimensions["_body"].(string); ok {
+		update.Body = &body
+		delete(update.Dimensions, "_body")
+	}
+	updateCmd := &UpdateCommand{Request: update, sets: sets}
+
+	// Preprocess the key and the update to resolve IDs
+	keyCmd := &AddCommand{Dimensions: key}
+	for _, cmd := range []interface{}{keyCmd, updateCmd} {
+		if err := s.preprocessor.preprocessCommand(cmd); err != nil {
+			return "", false, err
 		}
+	}
 
-		// Apply dimension updates
-		if updates.Dimensions != nil {
-			// Validate updates
-			for name, value := range updates.Dimensions {
-				if !strings.HasPrefix(name, "_data.") {
-					if err := validation.ValidateSimpleType(value, name); err != nil {
-						return err
-					}
-				}
-			}
+	created := false
+	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
+		existing, err := findByKey(s.standardDocuments(), keyCmd.Dimensions)
+		if err != nil {
+			return "", err
+		}
+		if existing == "" {
+			created = true
+			return s.addLocked(addCmd)
+		}
+		updateCmd.ID = existing
+		return existing, s.updateLocked(updateCmd)
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return result.(string), created, nil
+}
 
-			// Apply dimension updates
-			for key, value := range updates.Dimensions {
-				if value == nil {
-					// nil value means delete the dimension
-					delete(doc.Dimensions, key)
-				} else {
-					doc.Dimensions[key] = value
-				}
+// Modify applies the update fn returns for the current document, under the
+// write lock
+func (s *hybridJSONFileStore) Modify(id string, fn func(doc types.Document) (types.UpdateRequest, error)) error {
+	return s.lockManager.Execute(storage.WriteOperation, func() error {
+		target := &UpdateCommand{ID: id}
+		if err := s.preprocessor.preprocessCommand(target); err != nil {
+			return err
+		}
+
+		var current *HybridDocument
+		for i := range s.hybridData.Documents {
+			if s.hybridData.Documents[i].UUID == target.ID {
+				current = &s.hybridData.Documents[i]
+				break
 			}
 		}
+		if current == nil {
+			return fmt.Errorf("document not found: %s", id)
+		}
 
-		// Save to file
-		if err := s.saveWithLock(); err != nil {
-			return fmt.Errorf("failed to save: %w", err)
+		doc := current.ToStandardDocument()
+		doc.Dimensions = maps.Clone(doc.Dimensions)
+		if current.BodyMeta != nil {
+			body, err := s.bodyStorage.ReadBody(*current.BodyMeta, current.Body)
+			if err != nil {
+				return fmt.Errorf("failed to read body: %w", err)
+			}
+			doc.Body = body
+		}
+		updates, err := fn(doc)
+		if err != nil {
+			return err
 		}
 
-		return nil
+		if err := rejectComputed(s.dimensionSet, updates.Dimensions); err != nil {
+			return err
+		}
+		updates, sets, err := splitSetUpdates(s.dimensionSet, updates)
+		if err != nil {
+			return err
+		}
+		if body, ok := updates.Dimensions["_body"].(string); ok {
+			updates.Body = &body
+			delete(updates.Dimensions, "_body")
+		}
+		cmd := &UpdateCommand{ID: target.ID, Request: updates, sets: sets}
+		if err := s.preprocessor.preprocessCommand(cmd); err != nil {
+			return err
+		}
+		return s.updateLocked(cmd)
 	})
 }
 
@@ -710,6 +988,22 @@ func (s *hybridJSONFileStore) ResolveUUID(simpleID string) (string, error) {
 	return s.idGenerator.ResolveID(simpleID, standardDocs)
 }
 
+// ResolveUUIDInView converts a simple ID from an alternate canonical view to a UUID
+func (s *hybridJSONFileStore) ResolveUUIDInView(simpleID string, view *types.CanonicalView) (string, error) {
+	if view == nil {
+		return s.ResolveUUID(simpleID)
+	}
+	if err := view.Validate(s.dimensionSet); err != nil {
+		return "", err
+	}
+
+	standardDocs := make([]types.Document, len(s.hybridData.Documents))
+	for i, hdoc := range s.hybridData.Documents {
+		standardDocs[i] = hdoc.ToStandardDocument()
+	}
+	return s.idGenerator.ForView(view).ResolveID(simpleID, standardDocs)
+}
+
 // DeleteByDimension removes all documents matching filters
 func (s *hybridJSONFileStore) DeleteByDimension(filters map[string]interface{}) (int, error) {
 	return 0, errors.New("DeleteByDimension not implemented in hybrid store")
@@ -726,7 +1020,7 @@ func (s *hybridJSONFileStore) DeleteWhere(whereClause string, args ...interface{
 		return 0, errors.New("WHERE clause cannot be empty")
 	}
 
-	evaluator := NewWhereEvaluator(whereClause, args...)
+	evaluator := NewWhereEvaluator(whereClause, args...).WithClock(s.timeFunc)
 
 	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
 		// Load current data
@@ -734,22 +1028,14 @@ func (s *hybridJSONFileStore) DeleteWhere(whereClause string, args ...interface{
 		if err != nil {
 			return 0, fmt.Errorf("failed to load data: %w", err)
 		}
+		if evaluator.UsesVirtualFields() {
+			evaluator.WithVirtualFields(query.NewVirtualFields(s.standardDocuments(), s.dimensionSet))
+		}
 
 		var matchingUUIDs []string
 
-		// Find documents that match the WHERE clause
-		for _, hybridDoc := range s.hybridData.Documents {
-			// Convert HybridDocument to types.Document for evaluation
-			doc := types.Document{
-				UUID:       hybridDoc.UUID,
-				SimpleID:   hybridDoc.SimpleID,
-				Title:      hybridDoc.Title,
-				Body:       hybridDoc.Body,
-				CreatedAt:  hybridDoc.CreatedAt,
-				UpdatedAt:  hybridDoc.UpdatedAt,
-				Dimensions: hybridDoc.Dimensions,
-			}
-
+		// Find documents that match the WHERE clause, computed dimensions included
+		for _, doc := range types.ComputeDimensions(s.standardDocuments(), s.dimensionSet) {
 			matches, err := evaluator.EvaluateDocument(&doc)
 			if err != nil {
 				return 0, fmt.Errorf("failed to evaluate WHERE clause for document %s: %w", doc.UUID, err)
@@ -805,7 +1091,15 @@ func (s *hybridJSONFileStore) UpdateWhere(whereClause string, updates types.Upda
 		return 0, errors.New("WHERE clause cannot be empty")
 	}
 
-	evaluator := NewWhereEvaluator(whereClause, args...)
+	if err := rejectComputed(s.dimensionSet, updates.Dimensions); err != nil {
+		return 0, err
+	}
+	updates, sets, err := splitSetUpdates(s.dimensionSet, updates)
+	if err != nil {
+		return 0, err
+	}
+
+	evaluator := NewWhereEvaluator(whereClause, args...).WithClock(s.timeFunc)
 
 	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
 		// Load current data
@@ -813,6 +1107,9 @@ func (s *hybridJSONFileStore) UpdateWhere(whereClause string, updates types.Upda
 		if err != nil {
 			return 0, fmt.Errorf("failed to load data: %w", err)
 		}
+		if evaluator.UsesVirtualFields() {
+			evaluator.WithVirtualFields(query.NewVirtualFields(s.standardDocuments(), s.dimensionSet))
+		}
 
 		// Validate update dimensions if provided
 		if updates.Dimensions != nil {
@@ -829,47 +1126,58 @@ func (s *hybridJSONFileStore) UpdateWhere(whereClause string, updates types.Upda
 			}
 		}
 
-		updatedCount := 0
-
-		// Update documents that match the WHERE clause
+		// Find documents that match the WHERE clause, computed dimensions included
+		computedDocs := types.ComputeDimensions(s.standardDocuments(), s.dimensionSet)
+		var matching []int
+		var projected []types.Document
 		for i, hybridDoc := range s.hybridData.Documents {
-			// Convert HybridDocument to types.Document for evaluation
-			doc := types.Document{
-				UUID:       hybridDoc.UUID,
-				SimpleID:   hybridDoc.SimpleID,
-				Title:      hybridDoc.Title,
-				Body:       hybridDoc.Body,
-				CreatedAt:  hybridDoc.CreatedAt,
-				UpdatedAt:  hybridDoc.UpdatedAt,
-				Dimensions: hybridDoc.Dimensions,
-			}
-
-			matches, err := evaluator.EvaluateDocument(&doc)
+			doc := hybridDoc.ToStandardDocument()
+			matches, err := evaluator.EvaluateDocument(&computedDocs[i])
 			if err != nil {
 				return 0, fmt.Errorf("failed to evaluate WHERE clause for document %s: %w", doc.UUID, err)
 			}
 
 			if matches {
-				// Apply updates to this document
-				if updates.Title != nil {
-					s.hybridData.Documents[i].Title = *updates.Title
-				}
-				if updates.Body != nil {
-					s.hybridData.Documents[i].Body = *updates.Body
+				// Transition rules are checked for every match before anything changes
+				if err := checkTransitions(s.dimensionSet, doc, updates.Dimensions); err != nil {
+					return 0, err
 				}
-				if updates.Dimensions != nil {
-					// Update dimensions
-					for key, value := range updates.Dimensions {
-						if s.hybridData.Documents[i].Dimensions == nil {
-							s.hybridData.Documents[i].Dimensions = make(map[string]interface{})
-						}
-						s.hybridData.Documents[i].Dimensions[key] = value
+				matching = append(matching, i)
+				projected = append(projected, projectUpdate(s.dimensionSet, doc, updates.Dimensions))
+			}
+		}
+		if err := checkUnique(s.unique, parentKey(s.dimensionSet), s.standardDocuments(), projected...); err != nil {
+			return 0, err
+		}
+
+		updatedCount := 0
+
+		// Update the matching documents
+		for _, i := range matching {
+			now := s.timeFunc()
+			stamps := transitionStamps(s.dimensionSet, s.hybridData.Documents[i].ToStandardDocument(), updates.Dimensions, now)
+
+			// Apply updates to this document
+			if updates.Title != nil {
+				s.hybridData.Documents[i].Title = *updates.Title
+			}
+			if updates.Body != nil {
+				s.hybridData.Documents[i].Body = *updates.Body
+			}
+			if updates.Dimensions != nil {
+				// Update dimensions
+				for key, value := range updates.Dimensions {
+					if s.hybridData.Documents[i].Dimensions == nil {
+						s.hybridData.Documents[i].Dimensions = make(map[string]interface{})
 					}
+					s.hybridData.Documents[i].Dimensions[key] = value
 				}
-				// Update timestamp
-				s.hybridData.Documents[i].UpdatedAt = s.timeFunc()
-				updatedCount++
 			}
+			// Update timestamp
+			s.hybridData.Documents[i].UpdatedAt = now
+			applyStamps(&s.hybridData.Documents[i].Dimensions, stamps)
+			applySetChanges(&s.hybridData.Documents[i].Dimensions, sets)
+			updatedCount++
 		}
 
 		if updatedCount > 0 {
@@ -899,3 +1207,66 @@ func (s *hybridJSONFileStore) UpdateByUUIDs(uuids []string, updates types.Update
 func (s *hybridJSONFileStore) DeleteByUUIDs(uuids []string) (int, error) {
 	return 0, errors.New("DeleteByUUIDs not implemented in hybrid store")
 }
+
+// SaveView stores list options under a name in the store's metadata
+func (s *hybridJSONFileStore) SaveView(name string, opts types.ListOptions) error {
+	return s.lockManager.Execute(storage.WriteOperation, func() error {
+		if err := saveView(&s.hybridData.Metadata.Views, name, opts); err != nil {
+			return err
+		}
+		return s.saveWithLock()
+	})
+}
+
+// GetView returns the list options saved under a name
+func (s *hybridJSONFileStore) GetView(name string) (types.ListOptions, error) {
+	var opts types.ListOptions
+	err := s.lockManager.Execute(storage.ReadOperation, func() error {
+		var err error
+		opts, err = getView(s.hybridData.Metadata.Views, name)
+		return err
+	})
+	return opts, err
+}
+
+// ListViews returns the names of the saved views, sorted
+func (s *hybridJSONFileStore) ListViews() ([]string, error) {
+	var names []string
+	err := s.lockManager.Execute(storage.ReadOperation, func() error {
+		names = viewNames(s.hybridData.Metadata.Views)
+		return nil
+	})
+	return names, err
+}
+
+// DeleteView removes a saved view
+func (s *hybridJSONFileStore) DeleteView(name string) error {
+	return s.lockManager.Execute(storage.WriteOperation, func() error {
+		if _, exists := s.hybridData.Metadata.Views[name]; !exists {
+			return fmt.Errorf("%w: %q", ErrViewNotFound, name)
+		}
+		delete(s.hybridData.Metadata.Views, name)
+		return s.saveWithLock()
+	})
+}
+
+// RunView lists the documents matching a saved view
+func (s *hybridJSONFileStore) RunView(name string) ([]types.Document, error) {
+	opts, err := s.GetView(name)
+	if err != nil {
+		return nil, err
+	}
+	return runView(s.List, s.dimensionSet, opts, s.timeFunc)
+}
+
+// Compact renumbers the positions of every partition from 1 when the store
+// keeps stable positions; otherwise positions are already packed
+func (s *hybridJSONFileStore) Compact() error {
+	if !s.idGenerator.Scheme().StablePositions {
+		return nil
+	}
+	return s.lockManager.Execute(storage.WriteOperation, func() error {
+		compactPositions(&s.hybridData.Metadata.Positions, s.standardDocuments(), s.dimensionSet)
+		return s.saveWithLock()
+	})
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestIDScheme(t *testing.T) {
+	newStore := func(t *testing.T, scheme *types.IDScheme, prefixes map[string]string) (Store, error) {
+		t.Helper()
+		return NewWithOptions("test.json", &types.Config{
+			Dimensions: []types.DimensionConfig{
+				{Name: "status", Type: types.Enumerated, Values: []string{"pending", "done"}, Prefixes: prefixes, DefaultValue: "pending"},
+				{Name: "parent", Type: types.Hierarchical, RefField: "parent_id"},
+			},
+			IDScheme: scheme,
+		}, WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory()))
+	}
+	listIDs := func(t *testing.T, s Store) []string {
+		t.Helper()
+		docs, err := s.List(types.ListOptions{OrderBy: []types.OrderClause{{Column: "simple_id"}}})
+		if err != nil {
+			t.Fatalf("list failed: %v", err)
+		}
+		ids := []string{}
+		for _, doc := range docs {
+			ids = append(ids, doc.SimpleID)
+		}
+		return ids
+	}
+
+	t.Run("CustomScheme", func(t *testing.T) {
+		s, err := newStore(t, &types.IDScheme{Separator: "/", Padding: 2, PrefixPlacement: types.PrefixAfter}, map[string]string{"done": "d"})
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+		defer func() { _ = s.Close() }()
+
+		for i := 0; i < 10; i++ {
+			if _, err := s.Add("Root", map[string]interface{}{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := s.Add("Done child", map[string]interface{}{"parent_id": "02", "status": "done"}); err != nil {
+			t.Fatalf("failed to add child: %v", err)
+		}
+		if _, err := s.Add("Grandchild", map[string]interface{}{"parent_id": "02/01d"}); err != nil {
+			t.Fatalf("failed to add grandchild: %v", err)
+		}
+
+		expected := []string{"01", "02", "02/01d", "02/01d/01", "03", "04", "05", "06", "07", "08", "09", "10"}
+		if got := listIDs(t, s); !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+
+		uuid, err := s.ResolveUUID("02/01d/01")
+		if err != nil {
+			t.Fatalf("failed to resolve: %v", err)
+		}
+		doc, err := s.GetByID(uuid)
+		if err != nil || doc == nil || doc.Title != "Grandchild" {
+			t.Errorf("expected 02/01d/01 to resolve to Grandchild, got %v (%v)", doc, err)
+		}
+	})
+
+	t.Run("LetterNumbering", func(t *testing.T) {
+		s, err := newStore(t, &types.IDScheme{Separator: "-", Numbering: types.NumberingLetters}, nil)
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+		defer func() { _ = s.Close() }()
+
+		for _, title := range []string{"First", "Second"} {
+			if _, err := s.Add(title, map[string]interface{}{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := s.Add("Child", map[string]interface{}{"parent_id": "b"}); err != nil {
+			t.Fatalf("failed to add child: %v", err)
+		}
+
+		expected := []string{"a", "b", "b-a"}
+		if got := listIDs(t, s); !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("RejectsAmbiguousScheme", func(t *testing.T) {
+		_, err := newStore(t, &types.IDScheme{Numbering: types.NumberingBase36}, map[string]string{"done": "d"})
+		if err == nil || !strings.Contains(err.Error(), "ambiguous") {
+			t.Errorf("expected an ambiguity error, got %v", err)
+		}
+	})
+}
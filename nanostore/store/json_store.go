@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"strings"
 	"time"
@@ -23,6 +24,7 @@ type jsonFileStore struct {
 	config        Config
 	dimensionSet  *types.DimensionSet
 	canonicalView *types.CanonicalView
+	unique        []types.UniqueConstraint
 	idGenerator   *ids.IDGenerator
 	preprocessor  *commandPreprocessor
 	queryProc     query.Processor
@@ -40,34 +42,27 @@ type jsonFileStore struct {
 
 // newJSONFileStore creates a new JSON file store
 func newJSONFileStore(filePath string, config Config, opts ...JSONFileStoreOption) (*jsonFileStore, error) {
+	canonicalView, err := canonicalViewFor(config)
+	if err != nil {
+		return nil, err
+	}
 
-	// Create canonical view from config
-	// Default canonical view based on dimension defaults
-	var filters []types.CanonicalFilter
-	for _, dim := range config.GetDimensionSet().Enumerated() {
-		if dim.DefaultValue != "" {
-			filters = append(filters, types.CanonicalFilter{
-				Dimension: dim.Name,
-				Value:     dim.DefaultValue,
-			})
-		}
-	}
-	// Hierarchical dimensions default to "*" (any value)
-	for _, dim := range config.GetDimensionSet().Hierarchical() {
-		filters = append(filters, types.CanonicalFilter{
-			Dimension: dim.Name,
-			Value:     "*",
-		})
+	idGen, err := ids.NewIDGeneratorWithScheme(config.GetDimensionSet(), canonicalView, idSchemeFor(config))
+	if err != nil {
+		return nil, err
 	}
-	canonicalView := types.NewCanonicalView(filters...)
 
-	idGen := ids.NewIDGenerator(config.GetDimensionSet(), canonicalView)
+	unique, err := uniqueConstraintsFor(config)
+	if err != nil {
+		return nil, err
+	}
 
 	store := &jsonFileStore{
 		filePath:      filePath,
 		config:        config,
 		dimensionSet:  config.GetDimensionSet(),
 		canonicalView: canonicalView,
+		unique:        unique,
 		idGenerator:   idGen,
 		queryProc:     query.NewProcessor(config.GetDimensionSet(), idGen),
 		lockManager:   storage.NewLockManager(),
@@ -99,6 +94,15 @@ func newJSONFileStore(filePath string, config Config, opts ...JSONFileStoreOptio
 	lockPath := filePath + ".lock"
 	store.fileLock = store.lockFactory.New(lockPath)
 
+	// Stable positions are read from the metadata loaded below
+	if idGen.Scheme().StablePositions {
+		idGen.WithPositions(func(uuid string, partition string) (int, bool) {
+			return lookupPosition(store.data.Metadata.Positions, uuid, partition)
+		}, func(partition string) int {
+			return lookupCounter(store.data.Metadata.Positions, partition)
+		})
+	}
+
 	// Initialize preprocessor
 	store.preprocessor = newCommandPreprocessor(store)
 
@@ -197,9 +201,17 @@ func (s *jsonFileStore) load() error {
 	}
 
 	s.data = &storeData
+	s.syncPositions()
 	return nil
 }
 
+// syncPositions updates the persisted positions when the store keeps them
+func (s *jsonFileStore) syncPositions() {
+	if s.idGenerator.Scheme().StablePositions {
+		syncPositions(&s.data.Metadata.Positions, s.data.Documents, s.dimensionSet)
+	}
+}
+
 // saveWithLock saves the data with proper locking
 func (s *jsonFileStore) saveWithLock() error {
 	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
@@ -221,6 +233,7 @@ func (s *jsonFileStore) save() error {
 
 	// Update metadata
 	s.data.Metadata.UpdatedAt = s.timeFunc()
+	s.syncPositions()
 
 	// Marshal to JSON with pretty printing
 	data, err := json.MarshalIndent(s.data, "", "  ")
@@ -264,6 +277,10 @@ func (s *jsonFileStore) List(opts types.ListOptions) ([]types.Document, error) {
 
 // Add creates a new document
 func (s *jsonFileStore) Add(title string, dimensions map[string]interface{}) (string, error) {
+	if err := rejectComputed(s.dimensionSet, dimensions); err != nil {
+		return "", err
+	}
+
 	// Preprocess command to resolve IDs in dimensions
 	cmd := &AddCommand{
 		Title:      title,
@@ -274,219 +291,431 @@ func (s *jsonFileStore) Add(title string, dimensions map[string]interface{}) (st
 	}
 
 	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
+		return s.addLocked(cmd)
+	})
 
-		// Generate UUID
-		docUUID := uuid.New().String()
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
 
-		// Create document
-		now := s.timeFunc()
-		doc := types.Document{
-			UUID:       docUUID,
-			Title:      cmd.Title,
-			Body:       "", // Empty body by default
-			CreatedAt:  now,
-			UpdatedAt:  now,
-			Dimensions: make(map[string]interface{}),
+// addLocked creates a new document from a preprocessed command. The caller
+// must hold the write lock.
+func (s *jsonFileStore) addLocked(cmd *AddCommand) (string, error) {
+	doc, err := s.newDocumentLocked(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	// Add to store
+	s.data.Documents = append(s.data.Documents, doc)
+
+	// Save to file
+	if err := s.saveWithLock(); err != nil {
+		// Remove the document on save failure
+		s.data.Documents = s.data.Documents[:len(s.data.Documents)-1]
+		return "", fmt.Errorf("failed to save: %w", err)
+	}
+
+	return doc.UUID, nil
+}
+
+// AddDocument creates a new document with its body
+func (s *jsonFileStore) AddDocument(doc types.NewDocument) (string, error) {
+	cmd, err := prepareDocument(s.dimensionSet, doc, s.preprocessor.preprocessCommand)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
+		return s.addLocked(cmd)
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+// AddMany adds a batch of documents, saving once
+func (s *jsonFileStore) AddMany(docs []types.NewDocument, atomic bool) ([]types.AddResult, error) {
+	commands, results := prepareBatch(s.dimensionSet, docs, s.preprocessor.preprocessCommand)
+	if atomic {
+		if err := batchError(results); err != nil {
+			failBatch(results, err)
+			return results, err
 		}
+	}
 
-		// Validate all provided dimensions are simple types
-		for name, value := range cmd.Dimensions {
-			// Skip validation for _data fields - they can be any type
-			if strings.HasPrefix(name, "_data.") {
+	err := s.lockManager.Execute(storage.WriteOperation, func() error {
+		count := len(s.data.Documents)
+		for i, cmd := range commands {
+			if cmd == nil {
 				continue
 			}
-			if err := validation.ValidateSimpleType(value, name); err != nil {
-				return "", err
+			if err := setBatchParent(s.dimensionSet, docs[i], cmd, results); err != nil {
+				results[i].Error = err
+				continue
 			}
-		}
-
-		// Apply dimension values
-		for _, dimConfig := range s.dimensionSet.All() {
-			switch dimConfig.Type {
-			case types.Enumerated:
-				// Check if value was provided
-				if val, exists := cmd.Dimensions[dimConfig.Name]; exists {
-					// Validate the value
-					strVal := fmt.Sprintf("%v", val)
-					// For dimensions with empty Values array (simple dimensions like pointer types),
-					// allow any value. Otherwise, validate against the predefined values.
-					if len(dimConfig.Values) > 0 && !contains(dimConfig.Values, strVal) {
-						return "", fmt.Errorf("invalid value %q for dimension %q", strVal, dimConfig.Name)
-					}
-					doc.Dimensions[dimConfig.Name] = strVal
-				} else if dimConfig.DefaultValue != "" {
-					// Use default value
-					doc.Dimensions[dimConfig.Name] = dimConfig.DefaultValue
-				}
-			case types.Hierarchical:
-				// Handle parent reference
-				// ID resolution already handled by preprocessor
-				if val, exists := cmd.Dimensions[dimConfig.RefField]; exists {
-					doc.Dimensions[dimConfig.RefField] = fmt.Sprintf("%v", val)
+			doc, err := s.newDocumentLocked(cmd)
+			if err != nil {
+				results[i].Error = err
+				if atomic {
+					break
 				}
+				continue
 			}
+			s.data.Documents = append(s.data.Documents, doc)
+			results[i].UUID = doc.UUID
 		}
 
-		// Also store any _data prefixed values directly
-		for key, value := range cmd.Dimensions {
-			if strings.HasPrefix(key, "_data.") {
-				doc.Dimensions[key] = value
+		if atomic {
+			if err := batchError(results); err != nil {
+				s.data.Documents = s.data.Documents[:count]
+				failBatch(results, err)
+				return err
 			}
 		}
+		if len(s.data.Documents) == count {
+			return nil
+		}
+		if err := s.saveWithLock(); err != nil {
+			s.data.Documents = s.data.Documents[:count]
+			err = fmt.Errorf("failed to save: %w", err)
+			failBatch(results, err)
+			return err
+		}
+		return nil
+	})
+	return results, err
+}
 
-		// Add to store
-		s.data.Documents = append(s.data.Documents, doc)
+// newDocumentLocked builds and validates the document a preprocessed
+// command adds, without adding it. The caller must hold the write lock.
+func (s *jsonFileStore) newDocumentLocked(cmd *AddCommand) (types.Document, error) {
+	// Create document, dated as the command asks when it does
+	now := s.timeFunc()
+	if !cmd.CreatedAt.IsZero() {
+		now = cmd.CreatedAt
+	}
+	doc := types.Document{
+		UUID:       uuid.New().String(),
+		Title:      cmd.Title,
+		Body:       cmd.Body,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Dimensions: make(map[string]interface{}),
+	}
 
-		// Save to file
-		if err := s.saveWithLock(); err != nil {
-			// Remove the document on save failure
-			s.data.Documents = s.data.Documents[:len(s.data.Documents)-1]
-			return "", fmt.Errorf("failed to save: %w", err)
+	// Validate all provided dimensions are simple types
+	for name, value := range cmd.Dimensions {
+		// Skip validation for _data fields - they can be any type
+		// Set dimensions hold lists and are validated below
+		if strings.HasPrefix(name, "_data.") || isSetDimension(s.dimensionSet, name) {
+			continue
+		}
+		if err := validation.ValidateSimpleType(value, name); err != nil {
+			return types.Document{}, err
 		}
+	}
 
-		return docUUID, nil
-	})
+	// Apply dimension values
+	for _, dimConfig := range s.dimensionSet.All() {
+		switch dimConfig.Type {
+		case types.Enumerated:
+			// Check if value was provided
+			if val, exists := cmd.Dimensions[dimConfig.Name]; exists {
+				// Validate the value
+				strVal := fmt.Sprintf("%v", val)
+				// For dimensions with empty Values array (simple dimensions like pointer types),
+				// allow any value. Otherwise, validate against the predefined values.
+				if len(dimConfig.Values) > 0 && !contains(dimConfig.Values, strVal) {
+					return types.Document{}, fmt.Errorf("invalid value %q for dimension %q", strVal, dimConfig.Name)
+				}
+				doc.Dimensions[dimConfig.Name] = strVal
+			} else if dimConfig.DefaultValue != "" && !dimConfig.IsComputed() {
+				// Use default value
+				doc.Dimensions[dimConfig.Name] = dimConfig.DefaultValue
+			}
+		case types.Hierarchical:
+			// Handle parent reference
+			// ID resolution already handled by preprocessor
+			if val, exists := cmd.Dimensions[dimConfig.RefField]; exists {
+				doc.Dimensions[dimConfig.RefField] = fmt.Sprintf("%v", val)
+			}
+		case types.Set:
+			if val, exists := cmd.Dimensions[dimConfig.Name]; exists {
+				change, err := parseSetChange(&dimConfig, val)
+				if err != nil {
+					return types.Document{}, err
+				}
+				applySetChanges(&doc.Dimensions, map[string]setChange{dimConfig.Name: change})
+			}
+		}
+	}
 
-	if err != nil {
-		return "", err
+	// Also store any _data prefixed values directly
+	for key, value := range cmd.Dimensions {
+		if strings.HasPrefix(key, "_data.") {
+			doc.Dimensions[key] = value
+		}
 	}
-	return result.(string), nil
+	applyStamps(&doc.Dimensions, creationStamps(s.dimensionSet, now))
+	if err := checkUnique(s.unique, parentKey(s.dimensionSet), s.data.Documents, doc); err != nil {
+		return types.Document{}, err
+	}
+	return doc, nil
 }
 
 // Update modifies an existing document
 func (s *jsonFileStore) Update(id string, updates types.UpdateRequest) error {
+	if err := rejectComputed(s.dimensionSet, updates.Dimensions); err != nil {
+		return err
+	}
+	updates, sets, err := splitSetUpdates(s.dimensionSet, updates)
+	if err != nil {
+		return err
+	}
+
 	// Preprocess command to resolve IDs
 	cmd := &UpdateCommand{
 		ID:      id,
 		Request: updates,
+		sets:    sets,
 	}
 	if err := s.preprocessor.preprocessCommand(cmd); err != nil {
 		return fmt.Errorf("preprocessing failed: %w", err)
 	}
 
 	return s.lockManager.Execute(storage.WriteOperation, func() error {
-		// Find the document by UUID
-		var found bool
-		var docIndex int
-		for i, doc := range s.data.Documents {
-			if doc.UUID == cmd.ID {
-				found = true
-				docIndex = i
-				break
-			}
-		}
+		return s.updateLocked(id, cmd)
+	})
+}
 
-		if !found {
-			return fmt.Errorf("document not found: %s", id)
+// updateLocked applies a preprocessed update to the document with the
+// command's UUID; id is the ID the caller asked for, used in errors. The
+// caller must hold the write lock.
+func (s *jsonFileStore) updateLocked(id string, cmd *UpdateCommand) error {
+	// Find the document by UUID
+	var found bool
+	var docIndex int
+	for i, doc := range s.data.Documents {
+		if doc.UUID == cmd.ID {
+			found = true
+			docIndex = i
+			break
 		}
+	}
 
-		// Apply updates
-		doc := &s.data.Documents[docIndex]
-		doc.UpdatedAt = s.timeFunc()
+	if !found {
+		return fmt.Errorf("document not found: %s", id)
+	}
 
-		// Update title if provided
-		if cmd.Request.Title != nil {
-			doc.Title = *cmd.Request.Title
-		}
+	// Transition rules and unique constraints are checked before anything changes
+	if err := checkTransitions(s.dimensionSet, s.data.Documents[docIndex], cmd.Request.Dimensions); err != nil {
+		return err
+	}
+	projected := projectUpdate(s.dimensionSet, s.data.Documents[docIndex], cmd.Request.Dimensions)
+	if err := checkUnique(s.unique, parentKey(s.dimensionSet), s.data.Documents, projected); err != nil {
+		return err
+	}
 
-		// Update body if provided
-		if cmd.Request.Body != nil {
-			doc.Body = *cmd.Request.Body
-		}
+	// Apply updates
+	doc := &s.data.Documents[docIndex]
+	doc.UpdatedAt = s.timeFunc()
+	stamps := transitionStamps(s.dimensionSet, *doc, cmd.Request.Dimensions, doc.UpdatedAt)
 
-		// Update dimensions if provided
-		if cmd.Request.Dimensions != nil {
-			// Validate all dimensions are simple types
-			for name, value := range cmd.Request.Dimensions {
-				// Skip validation for _data fields - they can be any type
-				if strings.HasPrefix(name, "_data.") {
-					continue
+	// Update title if provided
+	if cmd.Request.Title != nil {
+		doc.Title = *cmd.Request.Title
+	}
+
+	// Update body if provided
+	if cmd.Request.Body != nil {
+		doc.Body = *cmd.Request.Body
+	}
+
+	// Update dimensions if provided
+	if cmd.Request.Dimensions != nil {
+		// Validate all dimensions are simple types
+		for name, value := range cmd.Request.Dimensions {
+			// Skip validation for _data fields - they can be any type
+			if strings.HasPrefix(name, "_data.") {
+				continue
+			}
+			if value != nil {
+				if err := validation.ValidateSimpleType(value, name); err != nil {
+					return err
 				}
+			}
+		}
+
+		// First handle _data prefixed values (no validation needed)
+		for dimName, value := range cmd.Request.Dimensions {
+			if strings.HasPrefix(dimName, "_data.") {
 				if value != nil {
-					if err := validation.ValidateSimpleType(value, name); err != nil {
-						return err
-					}
+					doc.Dimensions[dimName] = value
+				} else {
+					delete(doc.Dimensions, dimName)
 				}
 			}
+		}
 
-			// First handle _data prefixed values (no validation needed)
-			for dimName, value := range cmd.Request.Dimensions {
-				if strings.HasPrefix(dimName, "_data.") {
-					if value != nil {
-						doc.Dimensions[dimName] = value
-					} else {
-						delete(doc.Dimensions, dimName)
-					}
-				}
+		// Then validate and process dimension updates
+		for dimName, value := range cmd.Request.Dimensions {
+			// Skip _data prefixed fields (already handled)
+			if strings.HasPrefix(dimName, "_data.") {
+				continue
 			}
 
-			// Then validate and process dimension updates
-			for dimName, value := range cmd.Request.Dimensions {
-				// Skip _data prefixed fields (already handled)
-				if strings.HasPrefix(dimName, "_data.") {
-					continue
+			// Find dimension config
+			dim, found := s.dimensionSet.Get(dimName)
+			var dimConfig *types.DimensionConfig
+			if found {
+				dimConfig = &types.DimensionConfig{
+					Name:         dim.Name,
+					Type:         dim.Type,
+					Values:       dim.Values,
+					Prefixes:     dim.Prefixes,
+					DefaultValue: dim.DefaultValue,
+					RefField:     dim.RefField,
 				}
-
-				// Find dimension config
-				dim, found := s.dimensionSet.Get(dimName)
-				var dimConfig *types.DimensionConfig
-				if found {
-					dimConfig = &types.DimensionConfig{
-						Name:         dim.Name,
-						Type:         dim.Type,
-						Values:       dim.Values,
-						Prefixes:     dim.Prefixes,
-						DefaultValue: dim.DefaultValue,
-						RefField:     dim.RefField,
-					}
-				} else {
-					// Try by RefField for hierarchical dimensions
-					for _, dc := range s.dimensionSet.Hierarchical() {
-						if dc.RefField == dimName {
-							dimConfig = &types.DimensionConfig{
-								Name:         dc.Name,
-								Type:         dc.Type,
-								Values:       dc.Values,
-								Prefixes:     dc.Prefixes,
-								DefaultValue: dc.DefaultValue,
-								RefField:     dc.RefField,
-							}
-							break
+			} else {
+				// Try by RefField for hierarchical dimensions
+				for _, dc := range s.dimensionSet.Hierarchical() {
+					if dc.RefField == dimName {
+						dimConfig = &types.DimensionConfig{
+							Name:         dc.Name,
+							Type:         dc.Type,
+							Values:       dc.Values,
+							Prefixes:     dc.Prefixes,
+							DefaultValue: dc.DefaultValue,
+							RefField:     dc.RefField,
 						}
+						break
 					}
 				}
+			}
 
-				if dimConfig == nil {
-					return fmt.Errorf("unknown dimension: %s", dimName)
-				}
+			if dimConfig == nil {
+				return fmt.Errorf("unknown dimension: %s", dimName)
+			}
 
-				// Validate enumerated dimension values
-				if dimConfig.Type == types.Enumerated && value != nil {
-					strVal := fmt.Sprintf("%v", value)
-					// For dimensions with empty Values array (simple dimensions like pointer types),
-					// allow any value. Otherwise, validate against the predefined values.
-					if len(dimConfig.Values) > 0 && !contains(dimConfig.Values, strVal) {
-						return fmt.Errorf("invalid value %q for dimension %q", strVal, dimName)
-					}
-					doc.Dimensions[dimName] = strVal
-				} else if dimConfig.Type == types.Hierarchical {
-					// Store hierarchical dimension value
-					// ID resolution already handled by preprocessor
-					if value != nil {
-						doc.Dimensions[dimConfig.RefField] = fmt.Sprintf("%v", value)
-					} else {
-						delete(doc.Dimensions, dimConfig.RefField)
-					}
+			// Validate enumerated dimension values
+			if dimConfig.Type == types.Enumerated && value != nil {
+				strVal := fmt.Sprintf("%v", value)
+				// For dimensions with empty Values array (simple dimensions like pointer types),
+				// allow any value. Otherwise, validate against the predefined values.
+				if len(dimConfig.Values) > 0 && !contains(dimConfig.Values, strVal) {
+					return fmt.Errorf("invalid value %q for dimension %q", strVal, dimName)
+				}
+				doc.Dimensions[dimName] = strVal
+			} else if dimConfig.Type == types.Hierarchical {
+				// Store hierarchical dimension value
+				// ID resolution already handled by preprocessor
+				if value != nil {
+					doc.Dimensions[dimConfig.RefField] = fmt.Sprintf("%v", value)
+				} else {
+					delete(doc.Dimensions, dimConfig.RefField)
 				}
 			}
 		}
+	}
+	applyStamps(&doc.Dimensions, stamps)
+	applySetChanges(&doc.Dimensions, cmd.sets)
 
-		// Save to file
-		if err := s.saveWithLock(); err != nil {
-			return fmt.Errorf("failed to save: %w", err)
+	// Save to file
+	if err := s.saveWithLock(); err != nil {
+		return fmt.Errorf("failed to save: %w", err)
+	}
+
+	return nil
+}
+
+// Upsert updates the document matching key or adds a new one
+func (s *jsonFileStore) Upsert(key map[string]interface{}, create types.NewDocument, update types.UpdateRequest) (string, bool, error) {
+	if len(key) == 0 {
+		return "", false, fmt.Errorf("upsert key cannot be empty")
+	}
+	if err := rejectComputed(s.dimensionSet, update.Dimensions); err != nil {
+		return "", false, err
+	}
+	update, sets, err := splitSetUpdates(s.dimensionSet, update)
+	if err != nil {
+		return "", false, err
+	}
+	addCmd, err := prepareDocument(s.dimensionSet, create, s.preprocessor.preprocessCommand)
+	if err != nil {
+		return "", false, err
+	}
+
+	// Preprocess the key and the update to resolve IDs
+	keyCmd := &AddCommand{Dimensions: key}
+	updateCmd := &UpdateCommand{Request: update, sets: sets}
+	for _, cmd := range []interface{}{keyCmd, updateCmd} {
+		if err := s.preprocessor.preprocessCommand(cmd); err != nil {
+			return "", false, fmt.Errorf("preprocessing failed: %w", err)
 		}
+	}
 
-		return nil
+	created := false
+	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
+		existing, err := findByKey(s.data.Documents, keyCmd.Dimensions)
+		if err != nil {
+			return "", err
+		}
+		if existing == "" {
+			created = true
+			return s.addLocked(addCmd)
+		}
+		updateCmd.ID = existing
+		return existing, s.updateLocked(existing, updateCmd)
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return result.(string), created, nil
+}
+
+// Modify applies the update fn returns for the current document, under the
+// write lock
+func (s *jsonFileStore) Modify(id string, fn func(doc types.Document) (types.UpdateRequest, error)) error {
+	return s.lockManager.Execute(storage.WriteOperation, func() error {
+		target := &UpdateCommand{ID: id}
+		if err := s.preprocessor.preprocessCommand(target); err != nil {
+			return fmt.Errorf("preprocessing failed: %w", err)
+		}
+
+		var current *types.Document
+		for i := range s.data.Documents {
+			if s.data.Documents[i].UUID == target.ID {
+				current = &s.data.Documents[i]
+				break
+			}
+		}
+		if current == nil {
+			return fmt.Errorf("document not found: %s", id)
+		}
+
+		doc := *current
+		doc.Dimensions = maps.Clone(current.Dimensions)
+		updates, err := fn(doc)
+		if err != nil {
+			return err
+		}
+
+		if err := rejectComputed(s.dimensionSet, updates.Dimensions); err != nil {
+			return err
+		}
+		updates, sets, err := splitSetUpdates(s.dimensionSet, updates)
+		if err != nil {
+			return err
+		}
+		cmd := &UpdateCommand{ID: target.ID, Request: updates, sets: sets}
+		if err := s.preprocessor.preprocessCommand(cmd); err != nil {
+			return fmt.Errorf("preprocessing failed: %w", err)
+		}
+		return s.updateLocked(id, cmd)
 	})
 }
 
@@ -501,6 +730,26 @@ func (s *jsonFileStore) ResolveUUID(simpleID string) (string, error) {
 	return result.(string), nil
 }
 
+// ResolveUUIDInView converts a simple ID from an alternate canonical view to a UUID
+func (s *jsonFileStore) ResolveUUIDInView(simpleID string, view *types.CanonicalView) (string, error) {
+	if view == nil {
+		return s.ResolveUUID(simpleID)
+	}
+	if err := view.Validate(s.dimensionSet); err != nil {
+		return "", err
+	}
+
+	result, err := s.lockManager.ExecuteWithResult(storage.ReadOperation, func() (interface{}, error) {
+		allDocs := make([]types.Document, len(s.data.Documents))
+		copy(allDocs, s.data.Documents)
+		return s.idGenerator.ForView(view).ResolveID(simpleID, allDocs)
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
 // resolveUUIDInternal is the internal version that doesn't take locks
 func (s *jsonFileStore) resolveUUIDInternal(simpleID string) (string, error) {
 	// Get all documents
@@ -615,9 +864,9 @@ func (s *jsonFileStore) deleteInternal(id string, cascade bool) error {
 // DeleteByDimension removes documents matching dimension filters
 func (s *jsonFileStore) DeleteByDimension(filters map[string]interface{}) (int, error) {
 	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
-		// Find all documents matching the filters
+		// Find all documents matching the filters, computed dimensions included
 		toDelete := []int{}
-		for i, doc := range s.data.Documents {
+		for i, doc := range types.ComputeDimensions(s.data.Documents, s.dimensionSet) {
 			if s.queryProc.MatchesFilters(doc, filters) {
 				toDelete = append(toDelete, i)
 			}
@@ -653,7 +902,7 @@ func (s *jsonFileStore) DeleteWhere(whereClause string, args ...interface{}) (in
 		return 0, errors.New("WHERE clause cannot be empty")
 	}
 
-	evaluator := NewWhereEvaluator(whereClause, args...)
+	evaluator := NewWhereEvaluator(whereClause, args...).WithClock(s.timeFunc)
 
 	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
 		// Load current data
@@ -661,11 +910,14 @@ func (s *jsonFileStore) DeleteWhere(whereClause string, args ...interface{}) (in
 		if err != nil {
 			return 0, fmt.Errorf("failed to load data: %w", err)
 		}
+		if evaluator.UsesVirtualFields() {
+			evaluator.WithVirtualFields(query.NewVirtualFields(s.data.Documents, s.dimensionSet))
+		}
 
 		var matchingUUIDs []string
 
-		// Find documents that match the WHERE clause
-		for _, doc := range s.data.Documents {
+		// Find documents that match the WHERE clause, computed dimensions included
+		for _, doc := range types.ComputeDimensions(s.data.Documents, s.dimensionSet) {
 			matches, err := evaluator.EvaluateDocument(&doc)
 			if err != nil {
 				return 0, fmt.Errorf("failed to evaluate WHERE clause for document %s: %w", doc.UUID, err)
@@ -762,6 +1014,14 @@ func (s *jsonFileStore) DeleteByUUIDs(uuids []string) (int, error) {
 
 // UpdateByDimension updates documents matching dimension filters
 func (s *jsonFileStore) UpdateByDimension(filters map[string]interface{}, updates types.UpdateRequest) (int, error) {
+	if err := rejectComputed(s.dimensionSet, updates.Dimensions); err != nil {
+		return 0, err
+	}
+	updates, sets, err := splitSetUpdates(s.dimensionSet, updates)
+	if err != nil {
+		return 0, err
+	}
+
 	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
 
 		// Validate update dimensions if provided
@@ -779,14 +1039,34 @@ func (s *jsonFileStore) UpdateByDimension(filters map[string]interface{}, update
 			}
 		}
 
+		// Documents are matched with their computed dimensions filled in, and
+		// transition rules and unique constraints are checked for every match
+		// before anything changes
+		matched := make(map[int]bool)
+		var projected []types.Document
+		for i, computed := range types.ComputeDimensions(s.data.Documents, s.dimensionSet) {
+			if s.queryProc.MatchesFilters(computed, filters) {
+				doc := s.data.Documents[i]
+				matched[i] = true
+				if err := checkTransitions(s.dimensionSet, doc, updates.Dimensions); err != nil {
+					return 0, err
+				}
+				projected = append(projected, projectUpdate(s.dimensionSet, doc, updates.Dimensions))
+			}
+		}
+		if err := checkUnique(s.unique, parentKey(s.dimensionSet), s.data.Documents, projected...); err != nil {
+			return 0, err
+		}
+
 		// Find and update all matching documents
 		updatedCount := 0
 		now := s.timeFunc()
 
 		for i := range s.data.Documents {
-			if s.queryProc.MatchesFilters(s.data.Documents[i], filters) {
+			if matched[i] {
 				doc := &s.data.Documents[i]
 				doc.UpdatedAt = now
+				stamps := transitionStamps(s.dimensionSet, *doc, updates.Dimensions, now)
 
 				// Update title if provided
 				if updates.Title != nil {
@@ -878,6 +1158,8 @@ func (s *jsonFileStore) UpdateByDimension(filters map[string]interface{}, update
 						}
 					}
 				}
+				applyStamps(&doc.Dimensions, stamps)
+				applySetChanges(&doc.Dimensions, sets)
 
 				updatedCount++
 			}
@@ -905,7 +1187,15 @@ func (s *jsonFileStore) UpdateWhere(whereClause string, updates types.UpdateRequ
 		return 0, errors.New("WHERE clause cannot be empty")
 	}
 
-	evaluator := NewWhereEvaluator(whereClause, args...)
+	if err := rejectComputed(s.dimensionSet, updates.Dimensions); err != nil {
+		return 0, err
+	}
+	updates, sets, err := splitSetUpdates(s.dimensionSet, updates)
+	if err != nil {
+		return 0, err
+	}
+
+	evaluator := NewWhereEvaluator(whereClause, args...).WithClock(s.timeFunc)
 
 	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
 		// Load current data
@@ -913,6 +1203,9 @@ func (s *jsonFileStore) UpdateWhere(whereClause string, updates types.UpdateRequ
 		if err != nil {
 			return 0, fmt.Errorf("failed to load data: %w", err)
 		}
+		if evaluator.UsesVirtualFields() {
+			evaluator.WithVirtualFields(query.NewVirtualFields(s.data.Documents, s.dimensionSet))
+		}
 
 		// Validate update dimensions if provided
 		if updates.Dimensions != nil {
@@ -929,36 +1222,57 @@ func (s *jsonFileStore) UpdateWhere(whereClause string, updates types.UpdateRequ
 			}
 		}
 
-		updatedCount := 0
-
-		// Update documents that match the WHERE clause
+		// Find documents that match the WHERE clause, computed dimensions included
+		computedDocs := types.ComputeDimensions(s.data.Documents, s.dimensionSet)
+		var matching []int
+		var projected []types.Document
 		for i, doc := range s.data.Documents {
-			matches, err := evaluator.EvaluateDocument(&doc)
+			matches, err := evaluator.EvaluateDocument(&computedDocs[i])
 			if err != nil {
 				return 0, fmt.Errorf("failed to evaluate WHERE clause for document %s: %w", doc.UUID, err)
 			}
 
 			if matches {
-				// Apply updates to this document
-				if updates.Title != nil {
-					s.data.Documents[i].Title = *updates.Title
+				// Transition rules are checked for every match before anything changes
+				if err := checkTransitions(s.dimensionSet, doc, updates.Dimensions); err != nil {
+					return 0, err
 				}
-				if updates.Body != nil {
-					s.data.Documents[i].Body = *updates.Body
-				}
-				if updates.Dimensions != nil {
-					// Update dimensions
-					for key, value := range updates.Dimensions {
-						if s.data.Documents[i].Dimensions == nil {
-							s.data.Documents[i].Dimensions = make(map[string]interface{})
-						}
-						s.data.Documents[i].Dimensions[key] = value
+				matching = append(matching, i)
+				projected = append(projected, projectUpdate(s.dimensionSet, doc, updates.Dimensions))
+			}
+		}
+		if err := checkUnique(s.unique, parentKey(s.dimensionSet), s.data.Documents, projected...); err != nil {
+			return 0, err
+		}
+
+		updatedCount := 0
+
+		// Update the matching documents
+		for _, i := range matching {
+			now := s.timeFunc()
+			stamps := transitionStamps(s.dimensionSet, s.data.Documents[i], updates.Dimensions, now)
+
+			// Apply updates to this document
+			if updates.Title != nil {
+				s.data.Documents[i].Title = *updates.Title
+			}
+			if updates.Body != nil {
+				s.data.Documents[i].Body = *updates.Body
+			}
+			if updates.Dimensions != nil {
+				// Update dimensions
+				for key, value := range updates.Dimensions {
+					if s.data.Documents[i].Dimensions == nil {
+						s.data.Documents[i].Dimensions = make(map[string]interface{})
 					}
+					s.data.Documents[i].Dimensions[key] = value
 				}
-				// Update timestamp
-				s.data.Documents[i].UpdatedAt = s.timeFunc()
-				updatedCount++
 			}
+			// Update timestamp
+			s.data.Documents[i].UpdatedAt = now
+			applyStamps(&s.data.Documents[i].Dimensions, stamps)
+			applySetChanges(&s.data.Documents[i].Dimensions, sets)
+			updatedCount++
 		}
 
 		if updatedCount > 0 {
@@ -985,6 +1299,14 @@ func (s *jsonFileStore) UpdateByUUIDs(uuids []string, updates types.UpdateReques
 		return 0, nil
 	}
 
+	if err := rejectComputed(s.dimensionSet, updates.Dimensions); err != nil {
+		return 0, err
+	}
+	updates, sets, err := splitSetUpdates(s.dimensionSet, updates)
+	if err != nil {
+		return 0, err
+	}
+
 	result, err := s.lockManager.ExecuteWithResult(storage.WriteOperation, func() (interface{}, error) {
 		// Validate update dimensions if provided
 		if updates.Dimensions != nil {
@@ -1007,6 +1329,21 @@ func (s *jsonFileStore) UpdateByUUIDs(uuids []string, updates types.UpdateReques
 			uuidMap[uuid] = true
 		}
 
+		// Transition rules and unique constraints are checked for every match
+		// before anything changes
+		var projected []types.Document
+		for _, doc := range s.data.Documents {
+			if uuidMap[doc.UUID] {
+				if err := checkTransitions(s.dimensionSet, doc, updates.Dimensions); err != nil {
+					return 0, err
+				}
+				projected = append(projected, projectUpdate(s.dimensionSet, doc, updates.Dimensions))
+			}
+		}
+		if err := checkUnique(s.unique, parentKey(s.dimensionSet), s.data.Documents, projected...); err != nil {
+			return 0, err
+		}
+
 		// Find and update all matching documents
 		updatedCount := 0
 		now := s.timeFunc()
@@ -1015,6 +1352,7 @@ func (s *jsonFileStore) UpdateByUUIDs(uuids []string, updates types.UpdateReques
 			doc := &s.data.Documents[i]
 			if uuidMap[doc.UUID] {
 				doc.UpdatedAt = now
+				stamps := transitionStamps(s.dimensionSet, *doc, updates.Dimensions, now)
 
 				// Update title if provided
 				if updates.Title != nil {
@@ -1101,6 +1439,8 @@ func (s *jsonFileStore) UpdateByUUIDs(uuids []string, updates types.UpdateReques
 						}
 					}
 				}
+				applyStamps(&doc.Dimensions, stamps)
+				applySetChanges(&doc.Dimensions, sets)
 
 				updatedCount++
 			}
@@ -1163,3 +1503,66 @@ func contains(slice []string, str string) bool {
 	}
 	return false
 }
+
+// SaveView stores list options under a name in the store's metadata
+func (s *jsonFileStore) SaveView(name string, opts types.ListOptions) error {
+	return s.lockManager.Execute(storage.WriteOperation, func() error {
+		if err := saveView(&s.data.Metadata.Views, name, opts); err != nil {
+			return err
+		}
+		return s.saveWithLock()
+	})
+}
+
+// GetView returns the list options saved under a name
+func (s *jsonFileStore) GetView(name string) (types.ListOptions, error) {
+	var opts types.ListOptions
+	err := s.lockManager.Execute(storage.ReadOperation, func() error {
+		var err error
+		opts, err = getView(s.data.Metadata.Views, name)
+		return err
+	})
+	return opts, err
+}
+
+// ListViews returns the names of the saved views, sorted
+func (s *jsonFileStore) ListViews() ([]string, error) {
+	var names []string
+	err := s.lockManager.Execute(storage.ReadOperation, func() error {
+		names = viewNames(s.data.Metadata.Views)
+		return nil
+	})
+	return names, err
+}
+
+// DeleteView removes a saved view
+func (s *jsonFileStore) DeleteView(name string) error {
+	return s.lockManager.Execute(storage.WriteOperation, func() error {
+		if _, exists := s.data.Metadata.Views[name]; !exists {
+			return fmt.Errorf("%w: %q", ErrViewNotFound, name)
+		}
+		delete(s.data.Metadata.Views, name)
+		return s.saveWithLock()
+	})
+}
+
+// RunView lists the documents matching a saved view
+func (s *jsonFileStore) RunView(name string) ([]types.Document, error) {
+	opts, err := s.GetView(name)
+	if err != nil {
+		return nil, err
+	}
+	return runView(s.List, s.dimensionSet, opts, s.timeFunc)
+}
+
+// Compact renumbers the positions of every partition from 1 when the store
+// keeps stable positions; otherwise positions are already packed
+func (s *jsonFileStore) Compact() error {
+	if !s.idGenerator.Scheme().StablePositions {
+		return nil
+	}
+	return s.lockManager.Execute(storage.WriteOperation, func() error {
+		compactPositions(&s.data.Metadata.Positions, s.data.Documents, s.dimensionSet)
+		return s.saveWithLock()
+	})
+}
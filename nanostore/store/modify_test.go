@@ -0,0 +1,122 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func modifyConfig() *types.Config {
+	return &types.Config{
+		Dimensions: []types.DimensionConfig{
+			{
+				Name:         "status",
+				Type:         types.Enumerated,
+				Values:       []string{"pending", "done"},
+				DefaultValue: "pending",
+			},
+		},
+	}
+}
+
+// increment adds one to the _data.count of a document
+func increment(doc types.Document) (types.UpdateRequest, error) {
+	count, _ := doc.Dimensions["_data.count"].(float64)
+	if n, ok := doc.Dimensions["_data.count"].(int); ok {
+		count = float64(n)
+	}
+	return types.UpdateRequest{Dimensions: map[string]interface{}{"_data.count": count + 1}}, nil
+}
+
+func testModify(t *testing.T, st Store) {
+	id, err := st.Add("Counter", map[string]interface{}{"_data.count": 0})
+	if err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	t.Run("ConcurrentIncrements", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := st.Modify(id, increment); err != nil {
+					t.Errorf("failed to modify: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		docs, err := st.List(types.ListOptions{})
+		if err != nil || len(docs) != 1 {
+			t.Fatalf("expected one document, got %d (%v)", len(docs), err)
+		}
+		if got := fmt.Sprint(docs[0].Dimensions["_data.count"]); got != "20" {
+			t.Errorf("expected a count of 20, got %s", got)
+		}
+	})
+
+	t.Run("SimpleIDAndBody", func(t *testing.T) {
+		body := "counted"
+		err := st.Modify("1", func(doc types.Document) (types.UpdateRequest, error) {
+			if doc.UUID != id {
+				return types.UpdateRequest{}, fmt.Errorf("expected %s, got %s", id, doc.UUID)
+			}
+			return types.UpdateRequest{Body: &body, Dimensions: map[string]interface{}{"status": "done"}}, nil
+		})
+		if err != nil {
+			t.Fatalf("failed to modify: %v", err)
+		}
+		docs, err := st.List(types.ListOptions{})
+		if err != nil || docs[0].Body != body || docs[0].Dimensions["status"] != "done" {
+			t.Errorf("expected the body and status to be updated, got %+v (%v)", docs, err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		abort := errors.New("abort")
+		err := st.Modify(id, func(doc types.Document) (types.UpdateRequest, error) {
+			doc.Dimensions["status"] = "pending"
+			return types.UpdateRequest{}, abort
+		})
+		if !errors.Is(err, abort) {
+			t.Errorf("expected the abort error, got %v", err)
+		}
+		docs, err := st.List(types.ListOptions{})
+		if err != nil || docs[0].Dimensions["status"] != "done" {
+			t.Errorf("expected the document to be unchanged, got %+v (%v)", docs, err)
+		}
+
+		if err := st.Modify("missing", increment); err == nil {
+			t.Error("expected an error for a missing document")
+		}
+	})
+}
+
+func TestModify(t *testing.T) {
+	st, err := NewWithOptions("test.json", modifyConfig(), WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	testModify(t, st)
+
+	err = st.Modify("1", func(doc types.Document) (types.UpdateRequest, error) {
+		return types.UpdateRequest{Dimensions: map[string]interface{}{"status": "unknown"}}, nil
+	})
+	if err == nil {
+		t.Error("expected an invalid status to be rejected")
+	}
+}
+
+func TestHybridModify(t *testing.T) {
+	st, err := NewHybridWithOptions("/test/store.json", modifyConfig(), WithFileSystemExt(NewMockFileSystemExt()), WithHybridFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	testModify(t, st)
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/arthur-debert/nanostore/nanostore/ids"
+	"github.com/arthur-debert/nanostore/nanostore/storage"
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// positionPartitionKey identifies the partition a document is numbered in.
+// Parents are referenced by UUID, so the key does not change when a parent's
+// SimpleID does.
+func positionPartitionKey(doc types.Document, dimensionSet *types.DimensionSet) string {
+	return ids.BuildPartitionForDocument(doc, dimensionSet).Key()
+}
+
+// syncPositions brings a position table up to date with the documents:
+// entries of deleted documents are dropped, and documents that are new or
+// have moved to another partition get the next position of their partition.
+// Documents are numbered in creation order, so enabling stable positions on
+// an existing store keeps its current IDs.
+func syncPositions(table **storage.PositionTable, docs []types.Document, dimensionSet *types.DimensionSet) {
+	if *table == nil {
+		*table = &storage.PositionTable{}
+	}
+	t := *table
+	docs = types.ComputeDimensions(docs, dimensionSet)
+	if t.Counters == nil {
+		t.Counters = make(map[string]int)
+	}
+	if t.Documents == nil {
+		t.Documents = make(map[string]storage.DocumentPosition)
+	}
+
+	present := make(map[string]bool, len(docs))
+	var unplaced []types.Document
+	for _, doc := range docs {
+		present[doc.UUID] = true
+		entry, exists := t.Documents[doc.UUID]
+		if !exists || entry.Partition != positionPartitionKey(doc, dimensionSet) {
+			unplaced = append(unplaced, doc)
+		}
+	}
+
+	for uuid := range t.Documents {
+		if !present[uuid] {
+			delete(t.Documents, uuid)
+		}
+	}
+
+	sort.SliceStable(unplaced, func(i, j int) bool {
+		if !unplaced[i].CreatedAt.Equal(unplaced[j].CreatedAt) {
+			return unplaced[i].CreatedAt.Before(unplaced[j].CreatedAt)
+		}
+		return unplaced[i].UUID < unplaced[j].UUID
+	})
+	for _, doc := range unplaced {
+		key := positionPartitionKey(doc, dimensionSet)
+		t.Counters[key]++
+		t.Documents[doc.UUID] = storage.DocumentPosition{Partition: key, Position: t.Counters[key]}
+	}
+}
+
+// compactPositions renumbers every partition from 1, keeping the order of
+// the documents, and resets the counters so the next position follows the
+// last document
+func compactPositions(table **storage.PositionTable, docs []types.Document, dimensionSet *types.DimensionSet) {
+	syncPositions(table, docs, dimensionSet)
+	t := *table
+
+	partitions := make(map[string][]string)
+	for uuid, entry := range t.Documents {
+		partitions[entry.Partition] = append(partitions[entry.Partition], uuid)
+	}
+
+	t.Counters = make(map[string]int, len(partitions))
+	for key, uuids := range partitions {
+		sort.Slice(uuids, func(i, j int) bool {
+			return t.Documents[uuids[i]].Position < t.Documents[uuids[j]].Position
+		})
+		for i, uuid := range uuids {
+			t.Documents[uuid] = storage.DocumentPosition{Partition: key, Position: i + 1}
+		}
+		t.Counters[key] = len(uuids)
+	}
+}
+
+// lookupPosition returns a document's persisted position, if it has one in
+// the given partition
+func lookupPosition(table *storage.PositionTable, uuid string, partition string) (int, bool) {
+	if table == nil {
+		return 0, false
+	}
+	entry, exists := table.Documents[uuid]
+	if !exists || entry.Partition != partition {
+		return 0, false
+	}
+	return entry.Position, true
+}
+
+// lookupCounter returns the last position handed out in a partition
+func lookupCounter(table *storage.PositionTable, partition string) int {
+	if table == nil {
+		return 0
+	}
+	return table.Counters[partition]
+}
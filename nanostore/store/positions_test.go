@@ -0,0 +1,294 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestStablePositions(t *testing.T) {
+	newStore := func(t *testing.T, fs FileSystem, stable bool) Store {
+		t.Helper()
+		s, err := NewWithOptions("test.json", &types.Config{
+			Dimensions: []types.DimensionConfig{
+				{Name: "status", Type: types.Enumerated, Values: []string{"pending", "done"}, Prefixes: map[string]string{"done": "d"}, DefaultValue: "pending"},
+				{Name: "parent", Type: types.Hierarchical, RefField: "parent_id"},
+			},
+			IDScheme: &types.IDScheme{StablePositions: stable},
+		}, WithFileSystem(fs), WithFileLockFactory(NewMockFileLockFactory()))
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+		return s
+	}
+	listIDs := func(t *testing.T, s Store) []string {
+		t.Helper()
+		docs, err := s.List(types.ListOptions{OrderBy: []types.OrderClause{{Column: "simple_id"}}})
+		if err != nil {
+			t.Fatalf("list failed: %v", err)
+		}
+		ids := []string{}
+		for _, doc := range docs {
+			ids = append(ids, doc.SimpleID)
+		}
+		return ids
+	}
+	addDocs := func(t *testing.T, s Store, titles ...string) {
+		t.Helper()
+		for _, title := range titles {
+			if _, err := s.Add(title, map[string]interface{}{}); err != nil {
+				t.Fatalf("failed to add %s: %v", title, err)
+			}
+		}
+	}
+	deleteID := func(t *testing.T, s Store, simpleID string) {
+		t.Helper()
+		uuid, err := s.ResolveUUID(simpleID)
+		if err != nil {
+			t.Fatalf("failed to resolve %s: %v", simpleID, err)
+		}
+		if err := s.Delete(uuid, false); err != nil {
+			t.Fatalf("failed to delete %s: %v", simpleID, err)
+		}
+	}
+
+	t.Run("DeletionKeepsPositions", func(t *testing.T) {
+		s := newStore(t, NewMockFileSystem(), true)
+		defer func() { _ = s.Close() }()
+
+		addDocs(t, s, "One", "Two", "Three")
+		deleteID(t, s, "2")
+
+		if got, expected := listIDs(t, s), []string{"1", "3"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+
+		// Freed positions are not handed out again
+		addDocs(t, s, "Four")
+		if got, expected := listIDs(t, s), []string{"1", "3", "4"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("PositionsSurviveRestart", func(t *testing.T) {
+		fs := NewMockFileSystem()
+		s := newStore(t, fs, true)
+		addDocs(t, s, "One", "Two", "Three")
+		deleteID(t, s, "1")
+		_ = s.Close()
+
+		reopened := newStore(t, fs, true)
+		defer func() { _ = reopened.Close() }()
+
+		if got, expected := listIDs(t, reopened), []string{"2", "3"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+		addDocs(t, reopened, "Four")
+		if got, expected := listIDs(t, reopened), []string{"2", "3", "4"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("PartitionsCountSeparately", func(t *testing.T) {
+		s := newStore(t, NewMockFileSystem(), true)
+		defer func() { _ = s.Close() }()
+
+		addDocs(t, s, "One", "Two")
+		uuid, err := s.ResolveUUID("1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Update(uuid, types.UpdateRequest{Dimensions: map[string]interface{}{"status": "done"}}); err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+		if _, err := s.Add("Child", map[string]interface{}{"parent_id": "2"}); err != nil {
+			t.Fatalf("failed to add child: %v", err)
+		}
+
+		if got, expected := listIDs(t, s), []string{"2", "2.1", "d1"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("CompactRepacksPositions", func(t *testing.T) {
+		fs := NewMockFileSystem()
+		s := newStore(t, fs, true)
+		addDocs(t, s, "One", "Two", "Three", "Four")
+		deleteID(t, s, "1")
+		deleteID(t, s, "3")
+
+		if err := s.Compact(); err != nil {
+			t.Fatalf("compact failed: %v", err)
+		}
+		if got, expected := listIDs(t, s), []string{"1", "2"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+		doc, err := s.ResolveUUID("2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.GetByID(doc)
+		if err != nil || got.Title != "Four" {
+			t.Errorf("expected Four at 2 after compaction, got %v (%v)", got, err)
+		}
+		_ = s.Close()
+
+		// The compacted numbering is persisted, and counting resumes after it
+		reopened := newStore(t, fs, true)
+		defer func() { _ = reopened.Close() }()
+		addDocs(t, reopened, "Five")
+		if got, expected := listIDs(t, reopened), []string{"1", "2", "3"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("ComputedPositionsByDefault", func(t *testing.T) {
+		s := newStore(t, NewMockFileSystem(), false)
+		defer func() { _ = s.Close() }()
+
+		addDocs(t, s, "One", "Two", "Three")
+		deleteID(t, s, "2")
+
+		if got, expected := listIDs(t, s), []string{"1", "2"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+		if err := s.Compact(); err != nil {
+			t.Errorf("compact should be a no-op, got %v", err)
+		}
+	})
+
+	t.Run("HybridStore", func(t *testing.T) {
+		s, err := NewHybridWithOptions("/test/store.json", &types.Config{
+			Dimensions: []types.DimensionConfig{
+				{Name: "status", Type: types.Enumerated, Values: []string{"pending", "done"}, DefaultValue: "pending"},
+			},
+			IDScheme: &types.IDScheme{StablePositions: true},
+		}, WithFileSystemExt(NewMockFileSystemExt()), WithHybridFileLockFactory(NewMockFileLockFactory()))
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+		defer func() { _ = s.Close() }()
+
+		addDocs(t, s, "One", "Two", "Three")
+		deleteID(t, s, "2")
+		if got, expected := listIDs(t, s), []string{"1", "3"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+		if err := s.Compact(); err != nil {
+			t.Fatalf("compact failed: %v", err)
+		}
+		if got, expected := listIDs(t, s), []string{"1", "2"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+}
+
+func TestStablePositionsWithComputedPartitions(t *testing.T) {
+	// overdue stands in for a clock: documents become overdue without any
+	// save that would renumber them
+	overdue := map[string]bool{"Past": true}
+	s, err := NewWithOptions("test.json", &types.Config{
+		Dimensions: []types.DimensionConfig{
+			{Name: "overdue", Type: types.Enumerated, Values: []string{"no", "yes"}, Prefixes: map[string]string{"yes": "o"}, DefaultValue: "no",
+				Compute: types.PerDocument(func(doc types.Document) string {
+					if overdue[doc.Title] {
+						return "yes"
+					}
+					return "no"
+				})},
+		},
+		IDScheme: &types.IDScheme{StablePositions: true},
+	}, WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	for _, title := range []string{"Past", "A", "B"} {
+		if _, err := s.Add(title, nil); err != nil {
+			t.Fatalf("failed to add %s: %v", title, err)
+		}
+	}
+	idsByTitle := func(t *testing.T) map[string]string {
+		t.Helper()
+		docs, err := s.List(types.ListOptions{})
+		if err != nil {
+			t.Fatalf("list failed: %v", err)
+		}
+		ids := make(map[string]string, len(docs))
+		for _, doc := range docs {
+			ids[doc.Title] = doc.SimpleID
+		}
+		return ids
+	}
+	if got, want := idsByTitle(t), map[string]string{"Past": "o1", "A": "1", "B": "2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	overdue["A"], overdue["B"] = true, true
+	if got, want := idsByTitle(t), map[string]string{"Past": "o1", "A": "o2", "B": "o3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected moved documents to take the next free positions %v, got %v", want, got)
+	}
+}
+
+func TestStablePositionsSkipDeletedAfterMove(t *testing.T) {
+	overdue := map[string]bool{"Past": true, "Older": true}
+	s, err := NewWithOptions("test.json", &types.Config{
+		Dimensions: []types.DimensionConfig{
+			{Name: "overdue", Type: types.Enumerated, Values: []string{"no", "yes"}, Prefixes: map[string]string{"yes": "o"}, DefaultValue: "no",
+				Compute: types.PerDocument(func(doc types.Document) string {
+					if overdue[doc.Title] {
+						return "yes"
+					}
+					return "no"
+				})},
+		},
+		IDScheme: &types.IDScheme{StablePositions: true},
+	}, WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	for _, title := range []string{"Past", "Older", "A"} {
+		if _, err := s.Add(title, nil); err != nil {
+			t.Fatalf("failed to add %s: %v", title, err)
+		}
+	}
+	uuid, err := s.ResolveUUID("o2")
+	if err != nil {
+		t.Fatalf("failed to resolve o2: %v", err)
+	}
+	if err := s.Delete(uuid, false); err != nil {
+		t.Fatalf("failed to delete o2: %v", err)
+	}
+
+	idOf := func(t *testing.T, title string) string {
+		t.Helper()
+		docs, err := s.List(types.ListOptions{})
+		if err != nil {
+			t.Fatalf("list failed: %v", err)
+		}
+		for _, doc := range docs {
+			if doc.Title == title {
+				return doc.SimpleID
+			}
+		}
+		t.Fatalf("%s not found", title)
+		return ""
+	}
+
+	// A moves into the partition whose last document was deleted: it must
+	// not take the deleted document's ID, and keeps its ID once persisted
+	overdue["A"] = true
+	if got := idOf(t, "A"); got != "o3" {
+		t.Errorf("expected A to follow the partition counter as o3, got %s", got)
+	}
+	if _, err := s.Add("B", nil); err != nil {
+		t.Fatalf("failed to add B: %v", err)
+	}
+	if got := idOf(t, "A"); got != "o3" {
+		t.Errorf("expected A to keep o3 after a save, got %s", got)
+	}
+}
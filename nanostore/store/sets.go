@@ -0,0 +1,120 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// setChange is a validated change of a set dimension: either new members
+// replacing the stored ones, or members to add and remove
+type setChange struct {
+	replace bool
+	members []string
+	update  types.SetUpdate
+}
+
+// apply returns the members of a set after the change
+func (c setChange) apply(current interface{}) []string {
+	if c.replace {
+		return c.members
+	}
+	return c.update.Apply(types.SetMembers(current))
+}
+
+// isSetDimension reports whether name is a set dimension
+func isSetDimension(dimensionSet *types.DimensionSet, name string) bool {
+	dim, found := dimensionSet.Get(name)
+	return found && dim.Type == types.Set
+}
+
+// parseSetChange validates the value given for a set dimension: a
+// types.SetUpdate, a list of members, a comma-separated string, or nil to
+// remove every member
+func parseSetChange(dim *types.Dimension, value interface{}) (setChange, error) {
+	if update, ok := value.(types.SetUpdate); ok {
+		update.Add = types.NormalizeSet(update.Add)
+		if err := validateSetMembers(dim, update.Add); err != nil {
+			return setChange{}, err
+		}
+		return setChange{update: update}, nil
+	}
+
+	if value != nil {
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Map, reflect.Struct, reflect.Ptr:
+			return setChange{}, fmt.Errorf("set dimension '%s' takes a list of values, got %T", dim.Name, value)
+		}
+	}
+	members := types.NormalizeSet(types.SetMembers(value))
+	if err := validateSetMembers(dim, members); err != nil {
+		return setChange{}, err
+	}
+	return setChange{replace: true, members: members}, nil
+}
+
+// validateSetMembers checks members against the values of a set dimension
+func validateSetMembers(dim *types.Dimension, members []string) error {
+	for _, member := range members {
+		if strings.Contains(member, ",") {
+			return fmt.Errorf("invalid value %q for set dimension %q: values cannot contain commas", member, dim.Name)
+		}
+		if !dim.IsValid(member) {
+			return fmt.Errorf("invalid value %q for set dimension %q (valid values: %s)", member, dim.Name, strings.Join(dim.Values, ", "))
+		}
+	}
+	return nil
+}
+
+// splitSetUpdates takes the set dimensions out of an update, returning the
+// remaining update and the validated set changes. The caller's map is left
+// unchanged.
+func splitSetUpdates(dimensionSet *types.DimensionSet, updates types.UpdateRequest) (types.UpdateRequest, map[string]setChange, error) {
+	var changes map[string]setChange
+	for name, value := range updates.Dimensions {
+		dim, found := dimensionSet.Get(name)
+		if !found || dim.Type != types.Set {
+			continue
+		}
+		change, err := parseSetChange(dim, value)
+		if err != nil {
+			return updates, nil, err
+		}
+		if changes == nil {
+			changes = make(map[string]setChange)
+		}
+		changes[name] = change
+	}
+	if changes == nil {
+		return updates, nil, nil
+	}
+
+	rest := make(map[string]interface{}, len(updates.Dimensions)-len(changes))
+	for name, value := range updates.Dimensions {
+		if _, isSet := changes[name]; !isSet {
+			rest[name] = value
+		}
+	}
+	updates.Dimensions = rest
+	return updates, changes, nil
+}
+
+// applySetChanges stores the members of each changed set, removing sets left
+// without members
+func applySetChanges(dimensions *map[string]interface{}, changes map[string]setChange) {
+	if len(changes) == 0 {
+		return
+	}
+	if *dimensions == nil {
+		*dimensions = make(map[string]interface{})
+	}
+	for name, change := range changes {
+		if members := change.apply((*dimensions)[name]); len(members) > 0 {
+			(*dimensions)[name] = members
+		} else {
+			delete(*dimensions, name)
+		}
+	}
+}
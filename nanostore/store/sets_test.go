@@ -0,0 +1,191 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func setConfig() *types.Config {
+	return &types.Config{
+		Dimensions: []types.DimensionConfig{
+			{
+				Name:         "status",
+				Type:         types.Enumerated,
+				Values:       []string{"pending", "done"},
+				DefaultValue: "pending",
+			},
+			{
+				Name:   "labels",
+				Type:   types.Set,
+				Values: []string{"bug", "feature", "docs"},
+			},
+			{
+				Name: "tags",
+				Type: types.Set,
+			},
+		},
+	}
+}
+
+func expectMembers(t *testing.T, st Store, id, dimension string, want []string) {
+	t.Helper()
+	doc, err := st.GetByID(id)
+	if err != nil {
+		t.Fatalf("failed to get %s: %v", id, err)
+	}
+	if got := types.SetMembers(doc.Dimensions[dimension]); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %s to be %v, got %v", dimension, want, got)
+	}
+}
+
+func expectTitles(t *testing.T, docs []types.Document, err error, want ...string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	var got []string
+	for _, doc := range docs {
+		got = append(got, doc.Title)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestSetDimensions(t *testing.T) {
+	st, err := NewWithOptions("test.json", setConfig(), WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	crash, err := st.Add("Crash", map[string]interface{}{"labels": []string{"bug", "bug", " docs"}, "tags": "urgent, backend"})
+	if err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	if _, err := st.Add("Login", map[string]interface{}{"labels": []interface{}{"feature"}, "tags": []string{"backend"}}); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	if _, err := st.Add("Untagged", nil); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	t.Run("AddNormalizesMembers", func(t *testing.T) {
+		expectMembers(t, st, crash, "labels", []string{"bug", "docs"})
+		expectMembers(t, st, crash, "tags", []string{"backend", "urgent"})
+	})
+
+	t.Run("AddRejectsInvalidMembers", func(t *testing.T) {
+		if _, err := st.Add("Bad", map[string]interface{}{"labels": []string{"chore"}}); err == nil {
+			t.Error("expected an error for a member outside the values")
+		}
+		if _, err := st.Add("Bad", map[string]interface{}{"tags": map[string]interface{}{"a": 1}}); err == nil {
+			t.Error("expected an error for a map value")
+		}
+	})
+
+	t.Run("ListFilters", func(t *testing.T) {
+		docs, err := st.List(types.ListOptions{Filters: map[string]interface{}{"tags": "backend"}, OrderBy: []types.OrderClause{{Column: "title"}}})
+		expectTitles(t, docs, err, "Crash", "Login")
+
+		docs, err = st.List(types.ListOptions{Filters: map[string]interface{}{"labels": []string{"feature", "docs"}}, OrderBy: []types.OrderClause{{Column: "title"}}})
+		expectTitles(t, docs, err, "Crash", "Login")
+
+		docs, err = st.List(types.ListOptions{Filters: map[string]interface{}{"labels": types.ContainsAll("bug", "docs")}})
+		expectTitles(t, docs, err, "Crash")
+
+		docs, err = st.List(types.ListOptions{Filters: map[string]interface{}{"labels": types.ContainsAny("feature", "bug"), "tags": types.Contains("urgent")}})
+		expectTitles(t, docs, err, "Crash")
+	})
+
+	t.Run("WhereContains", func(t *testing.T) {
+		count, err := st.UpdateWhere("tags CONTAINS ?", types.UpdateRequest{Dimensions: map[string]interface{}{"status": "done"}}, "urgent")
+		if err != nil || count != 1 {
+			t.Fatalf("expected 1 update, got %d (%v)", count, err)
+		}
+		count, err = st.DeleteWhere("labels CONTAINS ALL 'bug,feature'")
+		if err != nil || count != 0 {
+			t.Errorf("expected no document with both labels, got %d (%v)", count, err)
+		}
+		count, err = st.UpdateWhere("labels CONTAINS ANY ? AND status = ?", types.UpdateRequest{Dimensions: map[string]interface{}{"status": "done"}}, []string{"feature", "docs"}, "pending")
+		if err != nil || count != 1 {
+			t.Errorf("expected 1 update, got %d (%v)", count, err)
+		}
+	})
+
+	t.Run("UpdateAddsAndRemoves", func(t *testing.T) {
+		err := st.Update(crash, types.UpdateRequest{Dimensions: map[string]interface{}{
+			"labels": types.AddToSet("feature"),
+			"tags":   types.SetUpdate{Add: []string{"frontend"}, Remove: []string{"backend"}},
+		}})
+		if err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+		expectMembers(t, st, crash, "labels", []string{"bug", "docs", "feature"})
+		expectMembers(t, st, crash, "tags", []string{"frontend", "urgent"})
+
+		if err := st.Update(crash, types.UpdateRequest{Dimensions: map[string]interface{}{"labels": types.AddToSet("chore")}}); err == nil {
+			t.Error("expected an error when adding a member outside the values")
+		}
+	})
+
+	t.Run("UpdateReplacesAndClears", func(t *testing.T) {
+		if err := st.Update(crash, types.UpdateRequest{Dimensions: map[string]interface{}{"labels": []string{"docs"}, "tags": nil}}); err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+		expectMembers(t, st, crash, "labels", []string{"docs"})
+		doc, _ := st.GetByID(crash)
+		if _, exists := doc.Dimensions["tags"]; exists {
+			t.Errorf("expected cleared tags to be removed, got %v", doc.Dimensions["tags"])
+		}
+	})
+
+	t.Run("BulkUpdates", func(t *testing.T) {
+		count, err := st.UpdateByDimension(map[string]interface{}{"status": "done"}, types.UpdateRequest{Dimensions: map[string]interface{}{"tags": types.AddToSet("reviewed")}})
+		if err != nil || count != 2 {
+			t.Fatalf("expected 2 updates, got %d (%v)", count, err)
+		}
+		docs, err := st.List(types.ListOptions{Filters: map[string]interface{}{"tags": "reviewed"}, OrderBy: []types.OrderClause{{Column: "title"}}})
+		expectTitles(t, docs, err, "Crash", "Login")
+		expectMembers(t, st, crash, "tags", []string{"reviewed"})
+	})
+
+	t.Run("ViewsKeepSetFilters", func(t *testing.T) {
+		if err := st.SaveView("reviewed-docs", types.ListOptions{Filters: map[string]interface{}{"tags": types.ContainsAll("reviewed"), "labels": types.ContainsAny("docs")}}); err != nil {
+			t.Fatalf("failed to save view: %v", err)
+		}
+		docs, err := st.RunView("reviewed-docs")
+		expectTitles(t, docs, err, "Crash")
+	})
+}
+
+func TestSetDimensionsHybrid(t *testing.T) {
+	s, err := NewHybridWithOptions("/test/store.json", setConfig(), WithFileSystemExt(NewMockFileSystemExt()), WithHybridFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	id, err := s.Add("Crash", map[string]interface{}{"labels": []string{"bug"}})
+	if err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	if err := s.Update(id, types.UpdateRequest{Dimensions: map[string]interface{}{"labels": types.AddToSet("docs")}}); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if _, err := s.UpdateWhere("labels CONTAINS ?", types.UpdateRequest{Dimensions: map[string]interface{}{"tags": types.AddToSet("triaged")}}, "docs"); err != nil {
+		t.Fatalf("failed to update where: %v", err)
+	}
+	expectMembers(t, s, id, "labels", []string{"bug", "docs"})
+	expectMembers(t, s, id, "tags", []string{"triaged"})
+}
+
+func TestSetDimensionsValidation(t *testing.T) {
+	config := setConfig()
+	config.Dimensions[1].Prefixes = map[string]string{"bug": "b"}
+	if _, err := NewWithOptions("test.json", config, WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory())); err == nil {
+		t.Error("expected an error for a set dimension with prefixes")
+	}
+}
@@ -25,12 +25,29 @@ type Store interface {
 	// Returns the UUID of the created document
 	Add(title string, dimensions map[string]interface{}) (string, error)
 
+	// AddDocument adds a document with its body, an optional creation time
+	// and dimensions in one operation, returning its UUID. Add leaves the
+	// body empty.
+	AddDocument(doc types.NewDocument) (string, error)
+
+	// AddMany adds documents in one locked operation that saves the store
+	// once, returning a result for each document in order. When atomic is
+	// true, a document that cannot be added fails the whole batch and none
+	// are; otherwise the others are added. A document whose parent in the
+	// batch fails, fails too. The error reports a failed batch or save.
+	AddMany(docs []types.NewDocument, atomic bool) ([]types.AddResult, error)
+
 	// Update modifies an existing document
 	Update(id string, updates types.UpdateRequest) error
 
 	// ResolveUUID converts a simple ID (e.g., "1.2.c3") to a UUID
 	ResolveUUID(simpleID string) (string, error)
 
+	// ResolveUUIDInView converts a simple ID listed through an alternate
+	// canonical view (ListOptions.CanonicalView) to a UUID
+	// A nil view behaves like ResolveUUID
+	ResolveUUIDInView(simpleID string, view *types.CanonicalView) (string, error)
+
 	// Delete removes a document and optionally its children
 	Delete(id string, cascade bool) error
 
@@ -55,6 +72,40 @@ type Store interface {
 	// GetByID retrieves a single document by its UUID
 	GetByID(id string) (*types.Document, error)
 
+	// Upsert updates the document whose values match every entry of key, or
+	// adds create, with its title, body and dimensions, when none does, in
+	// one locked operation. It returns the document's UUID and whether it
+	// was created. A key matching more than one document is an error.
+	Upsert(key map[string]interface{}, create types.NewDocument, update types.UpdateRequest) (string, bool, error)
+
+	// Modify reads the document with the given UUID or SimpleID and applies
+	// the update fn returns for it, in one locked operation, so concurrent
+	// read-modify-write cycles never lose updates. fn receives a copy of the
+	// document; when it returns an error the document is left unchanged.
+	Modify(id string, fn func(doc types.Document) (types.UpdateRequest, error)) error
+
+	// SaveView stores list options under a name in the store's metadata,
+	// replacing any view with that name. A WHERE clause can be kept with the
+	// filters under WhereFilterKey. Pagination cursors are not saved.
+	SaveView(name string, opts types.ListOptions) error
+
+	// RunView lists the documents matching a saved view
+	RunView(name string) ([]types.Document, error)
+
+	// GetView returns the list options saved under a name
+	GetView(name string) (types.ListOptions, error)
+
+	// ListViews returns the names of the saved views, sorted
+	ListViews() ([]string, error)
+
+	// DeleteView removes a saved view
+	DeleteView(name string) error
+
+	// Compact re-packs SimpleID positions so every partition is numbered
+	// from 1 again. It only has an effect when the ID scheme keeps stable
+	// positions; IDs may change, so it is never done implicitly.
+	Compact() error
+
 	// Close releases any resources held by the store
 	Close() error
 }
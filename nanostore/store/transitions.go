@@ -0,0 +1,83 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// dimensionChange returns the current and new value of an enumerated
+// dimension under updates. A nil update resets the dimension to its default.
+func dimensionChange(dim types.Dimension, doc types.Document, updates map[string]interface{}) (from, to string, changes bool) {
+	value, exists := updates[dim.Name]
+	if !exists {
+		return "", "", false
+	}
+
+	from = dim.DefaultValue
+	if current, ok := doc.Dimensions[dim.Name]; ok && current != nil {
+		from = fmt.Sprintf("%v", current)
+	}
+	to = dim.DefaultValue
+	if value != nil {
+		to = fmt.Sprintf("%v", value)
+	}
+	return from, to, from != to
+}
+
+// checkTransitions returns a *types.TransitionError when the updates would
+// change an enumerated dimension of doc along a transition its rules do not
+// allow
+func checkTransitions(dimensionSet *types.DimensionSet, doc types.Document, updates map[string]interface{}) error {
+	for _, dim := range dimensionSet.Enumerated() {
+		if !dim.HasTransitions() {
+			continue
+		}
+		if from, to, changes := dimensionChange(dim, doc, updates); changes {
+			if err := dim.CheckTransition(from, to); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// transitionStamps returns the change times to record for the tracked
+// dimensions the updates change, keyed by types.ChangedAtKey. It must be
+// called before the updates are applied.
+func transitionStamps(dimensionSet *types.DimensionSet, doc types.Document, updates map[string]interface{}, now time.Time) map[string]interface{} {
+	stamps := make(map[string]interface{})
+	for _, dim := range dimensionSet.Enumerated() {
+		if !dim.TrackChanges {
+			continue
+		}
+		if _, _, changes := dimensionChange(dim, doc, updates); changes {
+			stamps[types.ChangedAtKey(dim.Name)] = now.Format(time.RFC3339)
+		}
+	}
+	return stamps
+}
+
+// creationStamps returns the change times recorded for the tracked
+// dimensions of a new document
+func creationStamps(dimensionSet *types.DimensionSet, now time.Time) map[string]interface{} {
+	stamps := make(map[string]interface{})
+	for _, dim := range dimensionSet.Enumerated() {
+		if dim.TrackChanges {
+			stamps[types.ChangedAtKey(dim.Name)] = now.Format(time.RFC3339)
+		}
+	}
+	return stamps
+}
+
+// applyStamps records change times in a document's dimensions, after any
+// update of its data fields so they are not cleared
+func applyStamps(dimensions *map[string]interface{}, stamps map[string]interface{}) {
+	for key, value := range stamps {
+		if *dimensions == nil {
+			*dimensions = make(map[string]interface{})
+		}
+		(*dimensions)[key] = value
+	}
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func transitionConfig() *types.Config {
+	return &types.Config{
+		Dimensions: []types.DimensionConfig{
+			{
+				Name:         "status",
+				Type:         types.Enumerated,
+				Values:       []string{"pending", "active", "done", "archived"},
+				DefaultValue: "pending",
+				Transitions: []types.Transition{
+					{From: "pending", To: "active"},
+					{From: "active", To: "done"},
+					{From: types.AnyValue, To: "archived"},
+				},
+				TrackChanges: true,
+			},
+		},
+	}
+}
+
+func TestTransitionRules(t *testing.T) {
+	st, err := NewWithOptions("test.json", transitionConfig(), WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+	s := st.(TestStore)
+
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	s.SetTimeFunc(func() time.Time { return created })
+	first, err := s.Add("First", nil)
+	if err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	second, err := s.Add("Second", map[string]interface{}{"status": "active"})
+	if err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	doc, _ := s.GetByID(first)
+	if doc.Dimensions[types.ChangedAtKey("status")] != created.Format(time.RFC3339) {
+		t.Errorf("expected a change time on creation, got %v", doc.Dimensions)
+	}
+
+	t.Run("UpdateRejectsDisallowedTransition", func(t *testing.T) {
+		err := s.Update(first, types.UpdateRequest{Dimensions: map[string]interface{}{"status": "done"}})
+		var transitionErr *types.TransitionError
+		if !errors.As(err, &transitionErr) {
+			t.Fatalf("expected a TransitionError, got %v", err)
+		}
+		if transitionErr.From != "pending" || transitionErr.To != "done" {
+			t.Errorf("unexpected transition in error: %+v", transitionErr)
+		}
+	})
+
+	t.Run("UpdateWhereIsAtomic", func(t *testing.T) {
+		// pending->done is rejected for First, so Second must not change either
+		_, err := s.UpdateWhere("status != ?", types.UpdateRequest{Dimensions: map[string]interface{}{"status": "done"}}, "archived")
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		doc, _ := s.GetByID(second)
+		if doc.Dimensions["status"] != "active" {
+			t.Errorf("expected Second to stay active, got %v", doc.Dimensions["status"])
+		}
+	})
+
+	t.Run("AllowedTransitionsRecordChangeTime", func(t *testing.T) {
+		changed := created.Add(time.Hour)
+		s.SetTimeFunc(func() time.Time { return changed })
+
+		if err := s.Update(first, types.UpdateRequest{Dimensions: map[string]interface{}{"status": "active"}}); err != nil {
+			t.Fatalf("expected pending->active to be allowed: %v", err)
+		}
+		if count, err := s.UpdateWhere("status != ?", types.UpdateRequest{Dimensions: map[string]interface{}{"status": "archived"}}, "archived"); err != nil || count != 2 {
+			t.Fatalf("expected *->archived to be allowed for both, got %d (%v)", count, err)
+		}
+
+		doc, _ := s.GetByID(second)
+		if doc.Dimensions[types.ChangedAtKey("status")] != changed.Format(time.RFC3339) {
+			t.Errorf("expected the change time to be updated, got %v", doc.Dimensions)
+		}
+	})
+
+	t.Run("TitleOnlyUpdateKeepsChangeTime", func(t *testing.T) {
+		before, _ := s.GetByID(first)
+		s.SetTimeFunc(func() time.Time { return created.Add(2 * time.Hour) })
+		title := "Renamed"
+		if err := s.Update(first, types.UpdateRequest{Title: &title}); err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+		after, _ := s.GetByID(first)
+		key := types.ChangedAtKey("status")
+		if after.Dimensions[key] != before.Dimensions[key] {
+			t.Errorf("expected the change time to stay %v, got %v", before.Dimensions[key], after.Dimensions[key])
+		}
+	})
+}
+
+func TestTransitionRulesHybrid(t *testing.T) {
+	s, err := NewHybridWithOptions("/test/store.json", transitionConfig(), WithFileSystemExt(NewMockFileSystemExt()), WithHybridFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	id, err := s.Add("Task", nil)
+	if err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	var transitionErr *types.TransitionError
+	if err := s.Update(id, types.UpdateRequest{Dimensions: map[string]interface{}{"status": "done"}}); !errors.As(err, &transitionErr) {
+		t.Errorf("expected a TransitionError from Update, got %v", err)
+	}
+	if _, err := s.UpdateWhere("status != ?", types.UpdateRequest{Dimensions: map[string]interface{}{"status": "done"}}, "archived"); !errors.As(err, &transitionErr) {
+		t.Errorf("expected a TransitionError from UpdateWhere, got %v", err)
+	}
+	if err := s.Update(id, types.UpdateRequest{Dimensions: map[string]interface{}{"status": "active"}}); err != nil {
+		t.Errorf("expected pending->active to be allowed: %v", err)
+	}
+
+	doc, _ := s.GetByID(id)
+	if doc == nil || doc.Dimensions["status"] != "active" || doc.Dimensions[types.ChangedAtKey("status")] == nil {
+		t.Errorf("expected an active document with a change time, got %+v", doc)
+	}
+}
+
+func TestTransitionRulesValidation(t *testing.T) {
+	config := transitionConfig()
+	config.Dimensions[0].Transitions = append(config.Dimensions[0].Transitions, types.Transition{From: "done", To: "reopened"})
+	if _, err := NewWithOptions("test.json", config, WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory())); err == nil {
+		t.Error("expected an error for a rule naming an unknown value")
+	}
+}
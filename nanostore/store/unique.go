@@ -0,0 +1,195 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// uniqueConstraintsConfig is implemented by configs that declare unique
+// constraints, such as *types.Config
+type uniqueConstraintsConfig interface {
+	GetUniqueConstraints() []types.UniqueConstraint
+}
+
+// uniqueConstraintsFor returns the unique constraints of a configuration
+// after checking that they name known fields
+func uniqueConstraintsFor(config Config) ([]types.UniqueConstraint, error) {
+	c, ok := config.(uniqueConstraintsConfig)
+	if !ok {
+		return nil, nil
+	}
+
+	dimensionSet := config.GetDimensionSet()
+	names := make(map[string]bool)
+	for _, constraint := range c.GetUniqueConstraints() {
+		if constraint.Name == "" {
+			return nil, fmt.Errorf("unique constraint must have a name")
+		}
+		if names[constraint.Name] {
+			return nil, fmt.Errorf("duplicate unique constraint %q", constraint.Name)
+		}
+		names[constraint.Name] = true
+
+		if len(constraint.Fields) == 0 {
+			return nil, fmt.Errorf("unique constraint %q has no fields", constraint.Name)
+		}
+		for _, field := range constraint.Fields {
+			if !strings.HasPrefix(field, "_data.") && !isDimensionKey(dimensionSet, field) {
+				return nil, fmt.Errorf("unique constraint %q: unknown field %q", constraint.Name, field)
+			}
+		}
+		if constraint.PerParent && len(dimensionSet.Hierarchical()) == 0 {
+			return nil, fmt.Errorf("unique constraint %q is per parent, but there is no hierarchical dimension", constraint.Name)
+		}
+	}
+	return c.GetUniqueConstraints(), nil
+}
+
+// isDimensionKey reports whether key is stored for a dimension: the name of
+// an enumerated dimension or the ref field of a hierarchical one
+func isDimensionKey(dimensionSet *types.DimensionSet, key string) bool {
+	if dim, found := dimensionSet.Get(key); found && dim.Type == types.Enumerated {
+		return true
+	}
+	for _, dim := range dimensionSet.Hierarchical() {
+		if dim.RefField == key {
+			return true
+		}
+	}
+	return false
+}
+
+// parentKey returns the key where documents store their parent
+func parentKey(dimensionSet *types.DimensionSet) string {
+	if hierarchical := dimensionSet.Hierarchical(); len(hierarchical) > 0 {
+		return hierarchical[0].RefField
+	}
+	return ""
+}
+
+// uniqueValues returns the values of a constraint's fields in doc, and false
+// when any of them is unset
+func uniqueValues(constraint types.UniqueConstraint, doc types.Document) ([]string, bool) {
+	values := make([]string, len(constraint.Fields))
+	for i, field := range constraint.Fields {
+		value, exists := doc.Dimensions[field]
+		if !exists || value == nil {
+			return nil, false
+		}
+		values[i] = fmt.Sprintf("%v", value)
+		if values[i] == "" {
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// projectUpdate returns a copy of doc with the dimension updates applied, so
+// constraints can be checked before the document changes. A nil update
+// removes the value, as it does when the update is applied.
+func projectUpdate(dimensionSet *types.DimensionSet, doc types.Document, updates map[string]interface{}) types.Document {
+	projected := doc
+	projected.Dimensions = make(map[string]interface{}, len(doc.Dimensions)+len(updates))
+	for key, value := range doc.Dimensions {
+		projected.Dimensions[key] = value
+	}
+	for key, value := range updates {
+		if dim, found := dimensionSet.Get(key); found && dim.Type == types.Hierarchical {
+			key = dim.RefField
+		}
+		if value == nil {
+			delete(projected.Dimensions, key)
+		} else {
+			projected.Dimensions[key] = value
+		}
+	}
+	return projected
+}
+
+// checkUnique returns a *types.UniqueConflictError when a candidate shares
+// the key of a unique constraint with another document. Candidates stand in
+// for the stored documents with the same UUID.
+func checkUnique(constraints []types.UniqueConstraint, parentField string, docs []types.Document, candidates ...types.Document) error {
+	if len(constraints) == 0 || len(candidates) == 0 {
+		return nil
+	}
+
+	byUUID := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		byUUID[candidate.UUID] = true
+	}
+	all := make([]types.Document, 0, len(docs)+len(candidates))
+	for _, doc := range docs {
+		if !byUUID[doc.UUID] {
+			all = append(all, doc)
+		}
+	}
+	all = append(all, candidates...)
+
+	for _, constraint := range constraints {
+		seen := make(map[string]string, len(all))
+		// Candidates come last, so a conflict names the stored document
+		for _, doc := range all {
+			values, ok := uniqueValues(constraint, doc)
+			if !ok {
+				continue
+			}
+			key := strings.Join(values, "\x00")
+			if constraint.PerParent {
+				parent := ""
+				if value := doc.Dimensions[parentField]; value != nil {
+					parent = fmt.Sprintf("%v", value)
+				}
+				key = parent + "\x00" + key
+			}
+			if existing, exists := seen[key]; exists {
+				// Stored documents that already conflict are left alone
+				if !byUUID[doc.UUID] {
+					continue
+				}
+				return &types.UniqueConflictError{
+					Constraint:   constraint.Name,
+					Fields:       constraint.Fields,
+					Values:       values,
+					ExistingUUID: existing,
+				}
+			}
+			seen[key] = doc.UUID
+		}
+	}
+	return nil
+}
+
+// findByKey returns the UUID of the document whose values match every entry
+// of key, or "" when none does. Unset values match nil and empty entries.
+// More than one match is an error, since upserts must pick one document.
+func findByKey(docs []types.Document, key map[string]interface{}) (string, error) {
+	found := ""
+	for _, doc := range docs {
+		matches := true
+		for field, want := range key {
+			if keyValue(doc.Dimensions[field]) != keyValue(want) {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		if found != "" {
+			return "", fmt.Errorf("upsert key matches more than one document (%s and %s)", found, doc.UUID)
+		}
+		found = doc.UUID
+	}
+	return found, nil
+}
+
+// keyValue returns the string form of a key value, "" for nil
+func keyValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
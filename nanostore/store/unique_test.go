@@ -0,0 +1,190 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func uniqueConfig() *types.Config {
+	return &types.Config{
+		Dimensions: []types.DimensionConfig{
+			{
+				Name:         "status",
+				Type:         types.Enumerated,
+				Values:       []string{"pending", "done", "archived"},
+				DefaultValue: "pending",
+			},
+			{
+				Name:     "parent",
+				Type:     types.Hierarchical,
+				RefField: "parent_uuid",
+			},
+		},
+		Unique: []types.UniqueConstraint{
+			{Name: "slug", Fields: []string{"_data.slug"}},
+			{Name: "external", Fields: []string{"_data.source", "_data.key"}},
+			{Name: "name", Fields: []string{"_data.name"}, PerParent: true},
+		},
+	}
+}
+
+func expectConflict(t *testing.T, err error, constraint, existing string) {
+	t.Helper()
+	var conflict *types.UniqueConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected a UniqueConflictError, got %v", err)
+	}
+	if conflict.Constraint != constraint || conflict.ExistingUUID != existing {
+		t.Errorf("expected a conflict on %s with %s, got %+v", constraint, existing, conflict)
+	}
+}
+
+func TestUniqueConstraints(t *testing.T) {
+	st, err := NewWithOptions("test.json", uniqueConfig(), WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	first, err := st.Add("First", map[string]interface{}{"_data.slug": "first", "_data.source": "jira", "_data.key": "P-1"})
+	if err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	second, err := st.Add("Second", map[string]interface{}{"_data.slug": "second", "_data.source": "github", "_data.key": "P-1"})
+	if err != nil {
+		t.Fatalf("expected a different compound key to be accepted: %v", err)
+	}
+
+	t.Run("AddRejectsDuplicates", func(t *testing.T) {
+		_, err := st.Add("Copy", map[string]interface{}{"_data.slug": "first"})
+		expectConflict(t, err, "slug", first)
+
+		_, err = st.Add("Copy", map[string]interface{}{"_data.source": "jira", "_data.key": "P-1"})
+		expectConflict(t, err, "external", first)
+
+		// Unset values are not constrained
+		if _, err := st.Add("No slug", nil); err != nil {
+			t.Errorf("expected a document without a slug to be added: %v", err)
+		}
+		if _, err := st.Add("No slug either", nil); err != nil {
+			t.Errorf("expected a second document without a slug to be added: %v", err)
+		}
+	})
+
+	t.Run("UpdatesRejectDuplicates", func(t *testing.T) {
+		err := st.Update(second, types.UpdateRequest{Dimensions: map[string]interface{}{"_data.slug": "first"}})
+		expectConflict(t, err, "slug", first)
+
+		// Documents keep their own key
+		if err := st.Update(first, types.UpdateRequest{Dimensions: map[string]interface{}{"_data.slug": "first", "status": "done"}}); err != nil {
+			t.Errorf("expected an update keeping the slug to succeed: %v", err)
+		}
+
+		// Updating several documents to the same key conflicts among them
+		_, err = st.UpdateWhere("status != ?", types.UpdateRequest{Dimensions: map[string]interface{}{"_data.slug": "shared"}}, "archived")
+		var conflict *types.UniqueConflictError
+		if !errors.As(err, &conflict) {
+			t.Fatalf("expected a UniqueConflictError, got %v", err)
+		}
+		doc, _ := st.(TestStore).GetByID(first)
+		if doc.Dimensions["_data.slug"] != "first" {
+			t.Errorf("expected the failed update to change nothing, got %v", doc.Dimensions["_data.slug"])
+		}
+	})
+
+	t.Run("PerParentScope", func(t *testing.T) {
+		if _, err := st.Add("Child", map[string]interface{}{"parent_uuid": first, "_data.name": "notes"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := st.Add("Other child", map[string]interface{}{"parent_uuid": second, "_data.name": "notes"}); err != nil {
+			t.Errorf("expected the same name under another parent to be accepted: %v", err)
+		}
+		if _, err := st.Add("Sibling", map[string]interface{}{"parent_uuid": first, "_data.name": "notes"}); err == nil {
+			t.Error("expected the same name among siblings to conflict")
+		}
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		uuid, created, err := st.Upsert(map[string]interface{}{"_data.slug": "third"},
+			types.NewDocument{Title: "Third", Dimensions: map[string]interface{}{"_data.slug": "third"}},
+			types.UpdateRequest{Dimensions: map[string]interface{}{"status": "done"}})
+		if err != nil || !created {
+			t.Fatalf("expected a new document, got %v, %v", created, err)
+		}
+
+		again, created, err := st.Upsert(map[string]interface{}{"_data.slug": "third"},
+			types.NewDocument{Title: "Third", Dimensions: map[string]interface{}{"_data.slug": "third"}},
+			types.UpdateRequest{Dimensions: map[string]interface{}{"status": "done"}})
+		if err != nil || created || again != uuid {
+			t.Fatalf("expected %s to be updated, got %s, %v, %v", uuid, again, created, err)
+		}
+		doc, _ := st.(TestStore).GetByID(uuid)
+		if doc.Dimensions["status"] != "done" {
+			t.Errorf("expected the update to apply, got %v", doc.Dimensions)
+		}
+
+		_, _, err = st.Upsert(map[string]interface{}{"_data.slug": "third"}, types.NewDocument{Title: "Third"},
+			types.UpdateRequest{Dimensions: map[string]interface{}{"_data.slug": "first"}})
+		expectConflict(t, err, "slug", first)
+	})
+}
+
+func TestHybridUniqueConstraints(t *testing.T) {
+	st, err := NewHybridWithOptions("/test/store.json", uniqueConfig(), WithFileSystemExt(NewMockFileSystemExt()), WithHybridFileLockFactory(NewMockFileLockFactory()))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	first, err := st.Add("First", map[string]interface{}{"_data.slug": "first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = st.Add("Copy", map[string]interface{}{"_data.slug": "first"})
+	expectConflict(t, err, "slug", first)
+
+	uuid, created, err := st.Upsert(map[string]interface{}{"_data.slug": "first"}, types.NewDocument{Title: "First"},
+		types.UpdateRequest{Dimensions: map[string]interface{}{"_body": "updated body"}})
+	if err != nil || created || uuid != first {
+		t.Fatalf("expected %s to be updated, got %s, %v, %v", first, uuid, created, err)
+	}
+
+	uuid, created, err = st.Upsert(map[string]interface{}{"_data.slug": "second"},
+		types.NewDocument{Title: "Second", Body: "second body", Dimensions: map[string]interface{}{"_data.slug": "second"}},
+		types.UpdateRequest{})
+	if err != nil || !created {
+		t.Fatalf("expected a new document, got %v, %v", created, err)
+	}
+	if doc, err := st.GetByID(uuid); err != nil || doc.Body != "second body" {
+		t.Errorf("expected the document to be created with its body, got %+v (%v)", doc, err)
+	}
+}
+
+func TestUniqueConstraintConfigErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint types.UniqueConstraint
+	}{
+		{"NoName", types.UniqueConstraint{Fields: []string{"_data.slug"}}},
+		{"NoFields", types.UniqueConstraint{Name: "slug"}},
+		{"UnknownField", types.UniqueConstraint{Name: "slug", Fields: []string{"slug"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := uniqueConfig()
+			config.Unique = []types.UniqueConstraint{tt.constraint}
+			if _, err := NewWithOptions("test.json", config, WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory())); err == nil {
+				t.Error("expected an invalid constraint to be rejected")
+			}
+		})
+	}
+
+	config := uniqueConfig()
+	config.Dimensions = config.Dimensions[:1]
+	config.Unique = []types.UniqueConstraint{{Name: "name", Fields: []string{"_data.name"}, PerParent: true}}
+	if _, err := NewWithOptions("test.json", config, WithFileSystem(NewMockFileSystem()), WithFileLockFactory(NewMockFileLockFactory())); err == nil {
+		t.Error("expected a per parent constraint without a hierarchy to be rejected")
+	}
+}
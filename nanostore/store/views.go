@@ -0,0 +1,141 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arthur-debert/nanostore/nanostore/query"
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// ErrViewNotFound is returned when no saved view has the requested name
+var ErrViewNotFound = errors.New("view not found")
+
+// WhereFilterKey is the ListOptions filter key holding a WHERE clause. Its
+// value is a map with a "clause" string and an "args" slice, evaluated with
+// WhereEvaluator after the other options. Saved views use it to keep WHERE
+// clauses next to their filters.
+const WhereFilterKey = "__where_clause__"
+
+// validateViewName checks that a view name can be used on the command line
+func validateViewName(name string) error {
+	if name == "" {
+		return fmt.Errorf("view name cannot be empty")
+	}
+	if strings.ContainsFunc(name, func(r rune) bool { return r == ' ' || r == '\t' || r == '\n' || r == '/' }) {
+		return fmt.Errorf("invalid view name %q: names cannot contain spaces or slashes", name)
+	}
+	return nil
+}
+
+// cloneViewOptions copies list options through their JSON form, so saved
+// views hold exactly what is persisted and callers cannot alias them. The
+// cursor of a page is not part of a view.
+func cloneViewOptions(opts types.ListOptions) (types.ListOptions, error) {
+	opts.Cursor = ""
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return types.ListOptions{}, fmt.Errorf("view options cannot be saved: %w", err)
+	}
+
+	var clone types.ListOptions
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return types.ListOptions{}, fmt.Errorf("view options cannot be saved: %w", err)
+	}
+	if clone.Filters == nil {
+		clone.Filters = make(map[string]interface{})
+	}
+	return clone, nil
+}
+
+// saveView stores a view in a views map, creating the map when needed
+func saveView(views *map[string]types.ListOptions, name string, opts types.ListOptions) error {
+	if err := validateViewName(name); err != nil {
+		return err
+	}
+	clone, err := cloneViewOptions(opts)
+	if err != nil {
+		return err
+	}
+	if *views == nil {
+		*views = make(map[string]types.ListOptions)
+	}
+	(*views)[name] = clone
+	return nil
+}
+
+// getView returns a copy of a saved view
+func getView(views map[string]types.ListOptions, name string) (types.ListOptions, error) {
+	opts, exists := views[name]
+	if !exists {
+		return types.ListOptions{}, fmt.Errorf("%w: %q", ErrViewNotFound, name)
+	}
+	return cloneViewOptions(opts)
+}
+
+// viewNames returns the names of the saved views, sorted
+func viewNames(views map[string]types.ListOptions) []string {
+	names := make([]string, 0, len(views))
+	for name := range views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runView lists the documents matching a view. A WHERE clause stored under
+// WhereFilterKey is evaluated after listing, with limit and offset applied
+// to the documents that match it.
+func runView(list func(types.ListOptions) ([]types.Document, error), dimensionSet *types.DimensionSet, opts types.ListOptions, now func() time.Time) ([]types.Document, error) {
+	where, hasWhere := opts.Filters[WhereFilterKey]
+	if !hasWhere {
+		return list(opts)
+	}
+	delete(opts.Filters, WhereFilterKey)
+
+	whereMap, _ := where.(map[string]interface{})
+	clause, _ := whereMap["clause"].(string)
+	args, _ := whereMap["args"].([]interface{})
+	evaluator := NewWhereEvaluator(clause, args...).WithClock(now)
+
+	if evaluator.UsesVirtualFields() {
+		all, err := list(types.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		evaluator.WithVirtualFields(query.NewVirtualFields(all, dimensionSet))
+	}
+
+	limit, offset := opts.Limit, opts.Offset
+	opts.Limit, opts.Offset = nil, nil
+	candidates, err := list(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]types.Document, 0, len(candidates))
+	for i := range candidates {
+		matches, err := evaluator.EvaluateDocument(&candidates[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate WHERE clause: %w", err)
+		}
+		if matches {
+			matched = append(matched, candidates[i])
+		}
+	}
+
+	if offset != nil && *offset > 0 {
+		if *offset >= len(matched) {
+			return []types.Document{}, nil
+		}
+		matched = matched[*offset:]
+	}
+	if limit != nil && *limit > 0 && *limit < len(matched) {
+		matched = matched[:*limit]
+	}
+	return matched, nil
+}
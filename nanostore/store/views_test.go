@@ -0,0 +1,174 @@
+package store
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestViews(t *testing.T) {
+	mockFS := NewMockFileSystem()
+	mockLockFactory := NewMockFileLockFactory()
+	config := &mockTestConfig{
+		dimensions: []types.DimensionConfig{
+			{Name: "status", Type: types.Enumerated, Values: []string{"pending", "done"}, DefaultValue: "pending"},
+			{Name: "priority", Type: types.Enumerated, Values: []string{"low", "high"}, DefaultValue: "low"},
+		},
+	}
+	open := func() Store {
+		s, err := NewWithOptions("test.json", config,
+			WithFileSystem(mockFS),
+			WithFileLockFactory(mockLockFactory),
+		)
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+		return s
+	}
+
+	s := open()
+	docs := []struct {
+		title      string
+		dimensions map[string]interface{}
+	}{
+		{"Write report", map[string]interface{}{"status": "pending", "priority": "high", "_data.assignee": "alice"}},
+		{"Review report", map[string]interface{}{"status": "pending", "priority": "high", "_data.assignee": "bob"}},
+		{"File taxes", map[string]interface{}{"status": "pending", "priority": "low", "_data.assignee": "alice"}},
+		{"Buy milk", map[string]interface{}{"status": "done", "priority": "high", "_data.assignee": "alice"}},
+	}
+	for _, doc := range docs {
+		if _, err := s.Add(doc.title, doc.dimensions); err != nil {
+			t.Fatalf("failed to add %q: %v", doc.title, err)
+		}
+	}
+
+	titles := func(docs []types.Document) []string {
+		result := []string{}
+		for _, doc := range docs {
+			result = append(result, doc.Title)
+		}
+		return result
+	}
+
+	limit := 1
+	views := map[string]types.ListOptions{
+		"urgent": {
+			Filters: map[string]interface{}{"status": "pending", "priority": "high"},
+			OrderBy: []types.OrderClause{{Column: "title"}},
+		},
+		"alice": {
+			Filters: map[string]interface{}{
+				WhereFilterKey: map[string]interface{}{"clause": "_data.assignee = ?", "args": []interface{}{"alice"}},
+			},
+			OrderBy: []types.OrderClause{{Column: "title", Descending: true}},
+			Limit:   &limit,
+		},
+	}
+	for name, opts := range views {
+		if err := s.SaveView(name, opts); err != nil {
+			t.Fatalf("failed to save view %q: %v", name, err)
+		}
+	}
+	_ = s.Close()
+
+	// Views are persisted with the store
+	s = open()
+	defer func() { _ = s.Close() }()
+
+	t.Run("ListViews", func(t *testing.T) {
+		names, err := s.ListViews()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := []string{"alice", "urgent"}; !reflect.DeepEqual(names, expected) {
+			t.Errorf("expected %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("RunView", func(t *testing.T) {
+		results, err := s.RunView("urgent")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := []string{"Review report", "Write report"}; !reflect.DeepEqual(titles(results), expected) {
+			t.Errorf("expected %v, got %v", expected, titles(results))
+		}
+	})
+
+	t.Run("RunViewWithWhereAndLimit", func(t *testing.T) {
+		results, err := s.RunView("alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := []string{"Write report"}; !reflect.DeepEqual(titles(results), expected) {
+			t.Errorf("expected %v, got %v", expected, titles(results))
+		}
+	})
+
+	t.Run("RunViewWithWhereAndZeroLimit", func(t *testing.T) {
+		// A zero limit means no limit, as it does for List
+		zero := 0
+		if err := s.SaveView("alice-all", types.ListOptions{
+			Filters: map[string]interface{}{
+				WhereFilterKey: map[string]interface{}{"clause": "_data.assignee = ?", "args": []interface{}{"alice"}},
+			},
+			OrderBy: []types.OrderClause{{Column: "title"}},
+			Limit:   &zero,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = s.DeleteView("alice-all") }()
+
+		results, err := s.RunView("alice-all")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := []string{"Buy milk", "File taxes", "Write report"}; !reflect.DeepEqual(titles(results), expected) {
+			t.Errorf("expected %v, got %v", expected, titles(results))
+		}
+	})
+
+	t.Run("GetViewReturnsACopy", func(t *testing.T) {
+		opts, err := s.GetView("urgent")
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts.Filters["status"] = "done"
+
+		again, err := s.GetView("urgent")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again.Filters["status"] != "pending" {
+			t.Errorf("expected saved view to be unchanged, got %v", again.Filters)
+		}
+	})
+
+	t.Run("DeleteView", func(t *testing.T) {
+		if err := s.DeleteView("urgent"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.RunView("urgent"); !errors.Is(err, ErrViewNotFound) {
+			t.Errorf("expected ErrViewNotFound, got %v", err)
+		}
+		if err := s.DeleteView("urgent"); !errors.Is(err, ErrViewNotFound) {
+			t.Errorf("expected ErrViewNotFound deleting twice, got %v", err)
+		}
+		names, _ := s.ListViews()
+		sort.Strings(names)
+		if expected := []string{"alice"}; !reflect.DeepEqual(names, expected) {
+			t.Errorf("expected %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("InvalidNames", func(t *testing.T) {
+		for _, name := range []string{"", "my view", "a/b"} {
+			if err := s.SaveView(name, types.ListOptions{}); err == nil {
+				t.Errorf("expected an error saving view %q", name)
+			}
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/arthur-debert/nanostore/nanostore/query"
 	"github.com/arthur-debert/nanostore/types"
 )
 
@@ -23,11 +24,16 @@ import (
 // - Parameters are bound safely during evaluation, not during parsing
 // - This prevents injection attacks where malicious parameters could alter the query structure
 //
-// Supported operators: =, !=, >, >=, <, <=, LIKE, NOT LIKE, IS NULL, IS NOT NULL
+// Supported operators: =, !=, >, >=, <, <=, LIKE, NOT LIKE, IS NULL, IS NOT NULL,
+// and CONTAINS, CONTAINS ANY, CONTAINS ALL for set dimensions
+// Time values also accept relative date expressions such as "today" or "-7d"
+// (see ParseDateExpression), resolved against the evaluator's clock.
 // Supported logic: AND (OR is not supported for security simplicity)
 type WhereEvaluator struct {
-	whereClause string        // The WHERE clause template with ? placeholders
-	args        []interface{} // Parameter values to bind to ? placeholders
+	whereClause string               // The WHERE clause template with ? placeholders
+	args        []interface{}        // Parameter values to bind to ? placeholders
+	virtual     *query.VirtualFields // Resolves virtual fields such as depth or parent.status
+	now         func() time.Time     // Clock for relative date expressions, time.Now if nil
 }
 
 // NewWhereEvaluator creates a new WHERE clause evaluator
@@ -38,6 +44,48 @@ func NewWhereEvaluator(whereClause string, args ...interface{}) *WhereEvaluator
 	}
 }
 
+// WithVirtualFields enables virtual fields (depth, child_count, parent.<field>, ...)
+// in the clause, computed from the given document set
+func (we *WhereEvaluator) WithVirtualFields(vf *query.VirtualFields) *WhereEvaluator {
+	we.virtual = vf
+	return we
+}
+
+// WithClock sets the clock that relative date expressions ("today", "-7d",
+// ...) are resolved against. A nil clock keeps the system time.
+func (we *WhereEvaluator) WithClock(now func() time.Time) *WhereEvaluator {
+	if now != nil {
+		we.now = now
+	}
+	return we
+}
+
+// currentTime returns the time relative date expressions are resolved against
+func (we *WhereEvaluator) currentTime() time.Time {
+	if we.now != nil {
+		return we.now()
+	}
+	return time.Now()
+}
+
+// UsesVirtualFields reports whether any condition of the clause refers to a
+// virtual field. Clauses that cannot be parsed report false; the parse error
+// surfaces on evaluation.
+func (we *WhereEvaluator) UsesVirtualFields() bool {
+	// Parse a copy so argument filtering does not affect later evaluation
+	probe := &WhereEvaluator{whereClause: we.whereClause, args: we.args}
+	conditions, err := probe.parseConditions(probe.whereClause)
+	if err != nil {
+		return false
+	}
+	for _, condition := range conditions {
+		if query.IsVirtualField(condition.Field) {
+			return true
+		}
+	}
+	return false
+}
+
 // EvaluateDocument checks if a document matches the WHERE clause
 func (we *WhereEvaluator) EvaluateDocument(doc *types.Document) (bool, error) {
 	if we.whereClause == "" {
@@ -203,7 +251,10 @@ func (we *WhereEvaluator) parseConditionWithParamTracking(condition string, star
 	}{
 		{" IS NOT NULL", " is not null"}, // Must come before " IS "
 		{" IS NULL", " is null"},
-		{" NOT LIKE ", " not like "}, // Must come before " LIKE "
+		{" NOT LIKE ", " not like "},         // Must come before " LIKE "
+		{" CONTAINS ANY ", " contains any "}, // Must come before " CONTAINS "
+		{" CONTAINS ALL ", " contains all "},
+		{" CONTAINS ", " contains "},
 		{"!=", "!="},
 		{"<=", "<="},
 		{">=", ">="},
@@ -274,6 +325,14 @@ func (we *WhereEvaluator) evaluateCondition(doc *types.Document, condition Condi
 		return false, err
 	}
 
+	// Set operators take a list of values rather than a single one
+	if filter, ok := we.setFilter(condition); ok {
+		if values, isPath := actualValue.(pathValues); isPath {
+			actualValue = []interface{}(values)
+		}
+		return filter.Matches(types.SetMembers(actualValue)), nil
+	}
+
 	// Get the expected value (either literal or from parameters)
 	expectedValue := condition.Value
 	if condition.IsParameter {
@@ -297,9 +356,58 @@ func (we *WhereEvaluator) evaluateCondition(doc *types.Document, condition Condi
 	}
 
 	// Compare based on operator
+	if values, isPath := actualValue.(pathValues); isPath {
+		return we.compareAny(values, condition.Operator, expectedValue)
+	}
 	return we.compareValues(actualValue, condition.Operator, expectedValue)
 }
 
+// pathValues holds the values a dot path reaches through nested lists
+type pathValues []interface{}
+
+// compareAny compares the values of a path with array-any semantics: the
+// condition holds when any value satisfies it. Negated operators hold when
+// no value satisfies their positive form.
+func (we *WhereEvaluator) compareAny(values pathValues, operator, expected string) (bool, error) {
+	positive, negated := operator, false
+	switch operator {
+	case "!=":
+		positive, negated = "=", true
+	case "not like":
+		positive, negated = "like", true
+	}
+
+	for _, value := range values {
+		match, err := we.compareValues(value, positive, expected)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return !negated, nil
+		}
+	}
+	return negated, nil
+}
+
+// setFilter returns the SetFilter of a CONTAINS condition. Its values are a
+// comma-separated literal, or a parameter holding a value or a slice.
+func (we *WhereEvaluator) setFilter(condition Condition) (types.SetFilter, bool) {
+	var values []string
+	if condition.IsParameter && condition.ParamIndex < len(we.args) {
+		values = types.SetMembers(we.args[condition.ParamIndex])
+	} else if !condition.IsParameter {
+		values = types.SetMembers(condition.Value)
+	}
+
+	switch condition.Operator {
+	case "contains", "contains all":
+		return types.ContainsAll(values...), true
+	case "contains any":
+		return types.ContainsAny(values...), true
+	}
+	return types.SetFilter{}, false
+}
+
 // getDocumentValue extracts a field value from a document
 func (we *WhereEvaluator) getDocumentValue(doc *types.Document, field string) (interface{}, error) {
 	switch field {
@@ -324,8 +432,18 @@ func (we *WhereEvaluator) getDocumentValue(doc *types.Document, field string) (i
 		if strings.HasPrefix(field, "_data.") {
 			// Look for the field as-is in Dimensions (with _data. prefix)
 			if value, exists := doc.Dimensions[field]; exists {
+				if list, isList := value.([]interface{}); isList {
+					return pathValues(list), nil
+				}
 				return value, nil
 			}
+			// Dot paths reach into nested data, possibly through lists
+			if values, found := types.PathValues(doc.Dimensions, field); found {
+				if len(values) == 1 {
+					return values[0], nil
+				}
+				return pathValues(values), nil
+			}
 			// Field exists in schema but not set, return nil
 			return nil, nil
 		}
@@ -335,6 +453,11 @@ func (we *WhereEvaluator) getDocumentValue(doc *types.Document, field string) (i
 			return value, nil
 		}
 
+		// Computed fields derived from the document hierarchy
+		if we.virtual != nil && query.IsVirtualField(field) {
+			return we.virtual.Value(*doc, field), nil
+		}
+
 		// Field not found
 		return nil, nil
 	}
@@ -371,6 +494,18 @@ func (we *WhereEvaluator) compareValues(actual interface{}, operator, expected s
 		return we.compareTimeValues(actualTime, operator, expected)
 	}
 
+	// Timestamps stored as text, such as time.Time data fields, compare as
+	// times when the expected value is a date expression. Both sides read
+	// times without a zone in the clock's location.
+	if text, ok := actual.(string); ok && operator != "like" && operator != "not like" {
+		now := we.currentTime()
+		if actualTime, ok := parseTimestamp(text, now.Location()); ok {
+			if _, err := ParseDateExpression(expected, now); err == nil {
+				return we.compareTimeValues(actualTime, operator, expected)
+			}
+		}
+	}
+
 	// Convert actual value to string for comparison
 	actualStr := fmt.Sprintf("%v", actual)
 
@@ -475,25 +610,14 @@ func (we *WhereEvaluator) matchLike(actual, pattern string) (bool, error) {
 	return matched, nil
 }
 
-// compareTimeValues compares time.Time values with proper parsing of expected string values
+// compareTimeValues compares time.Time values against an expected value
+// given as a date expression: an absolute timestamp or a relative expression
+// such as "today" or "-7d" (see ParseDateExpression)
 func (we *WhereEvaluator) compareTimeValues(actualTime time.Time, operator, expected string) (bool, error) {
-	// Try to parse expected value as time using common formats
-	var expectedTime time.Time
-	var err error
-
-	// Try RFC3339 format first (most common for parameters)
-	expectedTime, err = time.Parse(time.RFC3339, expected)
+	expectedTime, err := ParseDateExpression(expected, we.currentTime())
 	if err != nil {
-		// Try RFC3339Nano format
-		expectedTime, err = time.Parse(time.RFC3339Nano, expected)
-		if err != nil {
-			// Try Go's default time format (for string literals)
-			expectedTime, err = time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", expected)
-			if err != nil {
-				// If we can't parse as time, fall back to string comparison
-				return we.compareStrings(actualTime.Format(time.RFC3339), expected), nil
-			}
-		}
+		// If we can't parse as time, fall back to string comparison
+		return we.compareStrings(actualTime.Format(time.RFC3339), expected), nil
 	}
 
 	switch operator {
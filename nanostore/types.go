@@ -11,6 +11,7 @@ type DimensionType = types.DimensionType
 const (
 	Enumerated   = types.Enumerated
 	Hierarchical = types.Hierarchical
+	Set          = types.Set
 )
 
 // Document is an alias for the types.Document
@@ -22,9 +23,39 @@ type ListOptions = types.ListOptions
 // OrderClause is an alias for types.OrderClause
 type OrderClause = types.OrderClause
 
+// NullsOrder is an alias for types.NullsOrder
+type NullsOrder = types.NullsOrder
+
+const (
+	NullsDefault = types.NullsDefault
+	NullsFirst   = types.NullsFirst
+	NullsLast    = types.NullsLast
+)
+
+// Collation is an alias for types.Collation
+type Collation = types.Collation
+
+const (
+	CollationBinary          = types.CollationBinary
+	CollationCaseInsensitive = types.CollationCaseInsensitive
+	CollationNatural         = types.CollationNatural
+)
+
 // UpdateRequest is an alias for types.UpdateRequest
 type UpdateRequest = types.UpdateRequest
 
+// NewDocument is an alias for types.NewDocument
+type NewDocument = types.NewDocument
+
+// AddResult is an alias for types.AddResult
+type AddResult = types.AddResult
+
+// SetUpdate is an alias for types.SetUpdate
+type SetUpdate = types.SetUpdate
+
+// SetFilter is an alias for types.SetFilter
+type SetFilter = types.SetFilter
+
 // DimensionConfig is an alias for types.DimensionConfig
 type DimensionConfig = types.DimensionConfig
 
@@ -44,32 +44,15 @@ func (app *NoteApp) Close() error {
 
 // AddNote creates a new note
 func (app *NoteApp) AddNote(title string, body string) (string, error) {
-	note := &Note{
-		Status: "active",
-		Pinned: false,
-	}
-
-	// Create with title and let the system set the body through the Document
-	id, err := app.store.Create(title, note)
-	if err != nil {
-		return "", err
-	}
-
-	// If we have a body, update the note to include it
-	if body != "" {
-		// Get the created note and update its body
-		createdNote, err := app.store.Get(id)
-		if err != nil {
-			return id, err // Return the ID even if update fails
-		}
-		createdNote.Body = body
-		// Update with the body
-		if _, err := app.store.Update(id, createdNote); err != nil {
-			return id, err // Return the ID even if update fails
-		}
-	}
-
-	return id, nil
+	// The body is stored with the note, so no note is ever saved without it
+	return app.store.CreateDocument(api.NewDoc[Note]{
+		Title: title,
+		Body:  body,
+		Data: &Note{
+			Status: "active",
+			Pinned: false,
+		},
+	})
 }
 
 // DeleteNote soft deletes a note by setting status to deleted
@@ -15,9 +15,14 @@ var searchCmd = &cobra.Command{
 
 By default, searches only active notes. Use --all to include deleted notes.
 
+Queries support quoted phrases, field scopes (title:, body:, pinned:, ...),
+-exclusions and OR between terms.
+
 Example:
   nanonotes search "meeting"
-  nanonotes search "project" --all`,
+  nanonotes search "project" --all
+  nanonotes search '"weekly sync" -draft'
+  nanonotes search 'title:groceries OR title:shopping'`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		app, err := getApp()
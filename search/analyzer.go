@@ -0,0 +1,230 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a normalized term together with its byte offsets in the source text
+type token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// analyzer turns text into index terms: it splits on anything that is not a
+// letter or digit, lowercases, folds accented Latin letters to ASCII and
+// optionally applies English stemming.
+type analyzer struct {
+	stem bool
+}
+
+// tokens returns the terms of text with their positions in the original string
+func (a analyzer) tokens(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = a.appendToken(tokens, text, start, i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = a.appendToken(tokens, text, start, len(text))
+	}
+	return tokens
+}
+
+// terms returns only the normalized terms of text
+func (a analyzer) terms(text string) []string {
+	tokens := a.tokens(text)
+	terms := make([]string, len(tokens))
+	for i, tok := range tokens {
+		terms[i] = tok.Term
+	}
+	return terms
+}
+
+func (a analyzer) appendToken(tokens []token, text string, start, end int) []token {
+	term := a.normalize(text[start:end])
+	if term == "" {
+		return tokens
+	}
+	return append(tokens, token{Term: term, Start: start, End: end})
+}
+
+// normalize lowercases, folds and stems a single word
+func (a analyzer) normalize(word string) string {
+	term := foldText(strings.ToLower(word))
+	if a.stem {
+		term = stemEnglish(term)
+	}
+	return term
+}
+
+// foldings maps accented Latin letters to their ASCII base letters
+var foldings = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'ĉ': "c", 'ċ': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g",
+	'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i",
+	'ĵ': "j",
+	'ķ': "k",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ŏ': "o", 'ő': "o",
+	'œ': "oe",
+	'ŕ': "r", 'ŗ': "r", 'ř': "r",
+	'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s", 'ß': "ss",
+	'ţ': "t", 'ť': "t", 'ŧ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ŵ': "w",
+	'ý': "y", 'ÿ': "y", 'ŷ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// foldText replaces accented letters so "café" and "cafe" index the same term.
+// Combining marks left over from decomposed input are dropped.
+func foldText(text string) string {
+	ascii := true
+	for i := 0; i < len(text); i++ {
+		if text[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return text
+	}
+
+	var builder strings.Builder
+	for _, r := range text {
+		if folded, ok := foldings[r]; ok {
+			builder.WriteString(folded)
+		} else if !unicode.Is(unicode.Mn, r) {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// stemEnglish applies steps 1a-1c of the Porter stemmer, which conflate the
+// inflected forms of English words ("meetings", "meeting" -> "meet";
+// "planned" -> "plan") without the aggressive derivational rewriting of the
+// later steps. Words that are not plain ASCII are returned unchanged.
+func stemEnglish(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	// Step 1a: plurals
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ies"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"):
+	case strings.HasSuffix(word, "s"):
+		word = word[:len(word)-1]
+	}
+
+	// Step 1b: past tense and gerunds
+	trimmed := false
+	switch {
+	case strings.HasSuffix(word, "eed"):
+		if measure(word[:len(word)-3]) > 0 {
+			word = word[:len(word)-1]
+		}
+	case strings.HasSuffix(word, "ed") && hasVowel(word[:len(word)-2]):
+		word = word[:len(word)-2]
+		trimmed = true
+	case strings.HasSuffix(word, "ing") && hasVowel(word[:len(word)-3]):
+		word = word[:len(word)-3]
+		trimmed = true
+	}
+	if trimmed {
+		switch {
+		case strings.HasSuffix(word, "at"), strings.HasSuffix(word, "bl"), strings.HasSuffix(word, "iz"):
+			word += "e"
+		case endsWithDoubleConsonant(word) && !strings.ContainsAny(word[len(word)-1:], "lsz"):
+			word = word[:len(word)-1]
+		case measure(word) == 1 && endsCVC(word):
+			word += "e"
+		}
+	}
+
+	// Step 1c: terminal y
+	if strings.HasSuffix(word, "y") && hasVowel(word[:len(word)-1]) {
+		word = word[:len(word)-1] + "i"
+	}
+
+	return word
+}
+
+// isConsonant reports whether word[i] is a consonant in the Porter sense
+func isConsonant(word string, i int) bool {
+	switch word[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !isConsonant(word, i-1)
+	}
+	return true
+}
+
+// measure counts the vowel-consonant sequences in word
+func measure(word string) int {
+	m := 0
+	vowel := false
+	for i := range word {
+		if isConsonant(word, i) {
+			if vowel {
+				m++
+			}
+			vowel = false
+		} else {
+			vowel = true
+		}
+	}
+	return m
+}
+
+func hasVowel(word string) bool {
+	for i := range word {
+		if !isConsonant(word, i) {
+			return true
+		}
+	}
+	return false
+}
+
+func endsWithDoubleConsonant(word string) bool {
+	n := len(word)
+	return n >= 2 && word[n-1] == word[n-2] && isConsonant(word, n-1)
+}
+
+// endsCVC reports whether word ends consonant-vowel-consonant, where the
+// final consonant is not w, x or y
+func endsCVC(word string) bool {
+	n := len(word)
+	if n < 3 || !isConsonant(word, n-1) || isConsonant(word, n-2) || !isConsonant(word, n-3) {
+		return false
+	}
+	return !strings.ContainsAny(word[n-1:], "wxy")
+}
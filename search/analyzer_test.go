@@ -0,0 +1,60 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzer_Tokens(t *testing.T) {
+	text := "Café meeting: Q3-plans, déjà vu!"
+	tokens := analyzer{}.tokens(text)
+
+	expected := []string{"cafe", "meeting", "q3", "plans", "deja", "vu"}
+	var terms []string
+	for _, tok := range tokens {
+		terms = append(terms, tok.Term)
+	}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Errorf("Expected terms %v, got %v", expected, terms)
+	}
+
+	// Offsets point at the original (unfolded) text
+	if got := text[tokens[0].Start:tokens[0].End]; got != "Café" {
+		t.Errorf("Expected first token text 'Café', got %q", got)
+	}
+	if got := text[tokens[4].Start:tokens[4].End]; got != "déjà" {
+		t.Errorf("Expected fifth token text 'déjà', got %q", got)
+	}
+}
+
+func TestAnalyzer_Stemming(t *testing.T) {
+	tests := map[string]string{
+		"meetings":   "meet",
+		"meeting":    "meet",
+		"planned":    "plan",
+		"planning":   "plan",
+		"plans":      "plan",
+		"caresses":   "caress",
+		"ponies":     "poni",
+		"pony":       "poni",
+		"agreed":     "agree",
+		"hoping":     "hope",
+		"filing":     "file",
+		"falling":    "fall",
+		"conflated":  "conflate",
+		"is":         "is",
+		"sing":       "sing",
+		"naïvetés":   "naivete",
+		"résumé":     "resume",
+		"versions2":  "versions2",
+		"quarterly":  "quarterli",
+		"quarterlys": "quarterli",
+	}
+
+	a := analyzer{stem: true}
+	for word, expected := range tests {
+		if got := a.normalize(word); got != expected {
+			t.Errorf("normalize(%q) = %q, expected %q", word, got, expected)
+		}
+	}
+}
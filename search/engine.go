@@ -20,12 +20,25 @@ func NewEngine(provider DocumentProvider) *Engine {
 	}
 }
 
-// Search performs a search and returns ranked results
+// Search performs a search and returns ranked results.
+//
+// options.Query is parsed with ParseQuery, so it may use phrases, field
+// scopes, exclusions and OR; each term is matched with the substring or
+// fuzzy rules selected by the options. With ExactMatch, the query is taken
+// literally and must equal a whole field.
 func (e *Engine) Search(options SearchOptions, filters map[string]interface{}) ([]SearchResult, error) {
 	if options.Query == "" {
 		return []SearchResult{}, nil
 	}
 
+	query := ParseQuery(options.Query)
+	if options.ExactMatch {
+		query = &Query{Root: &Term{Text: options.Query}}
+	}
+	if query.IsEmpty() {
+		return []SearchResult{}, nil
+	}
+
 	// Get documents from provider (may already be filtered)
 	documents, err := e.provider.GetDocuments(filters)
 	if err != nil {
@@ -34,11 +47,6 @@ func (e *Engine) Search(options SearchOptions, filters map[string]interface{}) (
 
 	// Search through documents
 	var results []SearchResult
-	query := options.Query
-	if !options.CaseSensitive {
-		query = strings.ToLower(query)
-	}
-
 	for _, doc := range documents {
 		if result := e.searchDocument(doc, query, options); result != nil {
 			results = append(results, *result)
@@ -58,11 +66,42 @@ func (e *Engine) Search(options SearchOptions, filters map[string]interface{}) (
 	return results, nil
 }
 
-// searchDocument searches a single document and returns a result if it matches
-func (e *Engine) searchDocument(doc types.Document, query string, options SearchOptions) *SearchResult {
+// searchDocument evaluates the query against a single document and returns a
+// result if it matches. Matches of all non-negated terms are reported, even
+// those of OR alternatives that were not needed to decide the match.
+func (e *Engine) searchDocument(doc types.Document, query *Query, options SearchOptions) *SearchResult {
+	termMatches := make(map[*Term][]FieldMatch)
+	matchTerm := func(term *Term) bool {
+		matches, ok := termMatches[term]
+		if !ok {
+			matches = e.matchTerm(doc, term, options)
+			termMatches[term] = matches
+		}
+		return len(matches) > 0
+	}
+
+	if !query.Evaluate(matchTerm) {
+		return nil
+	}
+
+	// Merge the matches of every term, per field
 	var fieldMatches []FieldMatch
-	var bestMatchType MatchType
-	var maxScore float64
+	fieldIndex := make(map[string]int)
+	for _, term := range query.Terms() {
+		matchTerm(term)
+		for _, match := range termMatches[term] {
+			i, ok := fieldIndex[match.FieldName]
+			if !ok {
+				fieldIndex[match.FieldName] = len(fieldMatches)
+				fieldMatches = append(fieldMatches, match)
+				continue
+			}
+			fieldMatches[i].Matches = append(fieldMatches[i].Matches, match.Matches...)
+			if match.FieldScore > fieldMatches[i].FieldScore {
+				fieldMatches[i].FieldScore = match.FieldScore
+			}
+		}
+	}
 
 	// Set default highlight markers
 	startMarker := options.HighlightStartMarker
@@ -74,28 +113,19 @@ func (e *Engine) searchDocument(doc types.Document, query string, options Search
 		endMarker = "**"
 	}
 
-	// Determine which fields to search
-	fieldsToSearch := options.Fields
-	if len(fieldsToSearch) == 0 {
-		fieldsToSearch = e.getAllSearchableFields(doc)
-	}
-
-	// Search each field
-	for _, field := range fieldsToSearch {
-		if fieldMatch := e.searchFieldDetailed(doc, field, query, options, startMarker, endMarker); fieldMatch != nil {
-			fieldMatches = append(fieldMatches, *fieldMatch)
-			if fieldMatch.FieldScore > maxScore {
-				maxScore = fieldMatch.FieldScore
-				if len(fieldMatch.Matches) > 0 {
-					bestMatchType = fieldMatch.Matches[0].MatchType
-				}
-			}
+	var bestMatchType MatchType
+	var maxScore float64
+	for i := range fieldMatches {
+		fieldMatch := &fieldMatches[i]
+		fieldMatch.Matches = withoutOverlaps(fieldMatch.Matches)
+		fieldMatch.HighlightedText = fieldMatch.OriginalText
+		if options.EnableHighlight {
+			fieldMatch.HighlightedText = highlightRanges(fieldMatch.OriginalText, fieldMatch.Matches, startMarker, endMarker)
+		}
+		if fieldMatch.FieldScore > maxScore {
+			maxScore = fieldMatch.FieldScore
+			bestMatchType = fieldMatch.Matches[0].MatchType
 		}
-	}
-
-	// No matches found
-	if len(fieldMatches) == 0 {
-		return nil
 	}
 
 	// Build result
@@ -123,44 +153,106 @@ func (e *Engine) searchDocument(doc types.Document, query string, options Search
 		}
 	}
 
+	addSnippets(result, fieldMatches, options, startMarker, endMarker)
+
 	return result
 }
 
-// searchFieldDetailed searches within a specific field and returns detailed match information
-func (e *Engine) searchFieldDetailed(doc types.Document, fieldName, query string, options SearchOptions, startMarker, endMarker string) *FieldMatch {
-	// Get field value and match type using centralized logic
-	fieldValue, baseMatchType, exists := e.getFieldInfo(doc, fieldName)
-	if !exists {
-		return nil // Field not found
+// addSnippets fills result.Snippets from the field matches when requested
+func addSnippets(result *SearchResult, fieldMatches []FieldMatch, options SearchOptions, startMarker, endMarker string) {
+	if options.SnippetContext <= 0 {
+		return
+	}
+	if !options.EnableHighlight {
+		startMarker, endMarker = "", ""
 	}
 
-	// Find all match positions
-	matches := e.findMatches(fieldValue, query, options, baseMatchType)
-	if len(matches) == 0 {
-		return nil
+	result.Snippets = make(map[string][]string, len(fieldMatches))
+	for _, fieldMatch := range fieldMatches {
+		result.Snippets[fieldMatch.FieldName] = buildSnippets(fieldMatch.OriginalText, fieldMatch.Matches, options.SnippetContext, startMarker, endMarker)
 	}
+}
 
-	// Calculate field score (highest match score)
-	fieldScore := 0.0
-	for _, match := range matches {
-		if match.Score > fieldScore {
-			fieldScore = match.Score
+// matchTerm finds the matches of a single query term in a document. Unscoped
+// terms search options.Fields (or all fields); scoped terms search their field
+// only, and dimension scopes must equal the dimension value.
+func (e *Engine) matchTerm(doc types.Document, term *Term, options SearchOptions) []FieldMatch {
+	fields := options.Fields
+	if term.Field != "" {
+		key, ok := resolveField(doc, term.Field)
+		if !ok {
+			return nil
 		}
+		fields = []string{key}
+	} else if len(fields) == 0 {
+		fields = e.getAllSearchableFields(doc)
 	}
 
-	// Generate highlighted text
-	highlightedText := fieldValue
-	if options.EnableHighlight {
-		highlightedText = e.highlightMatchesWithMarkers(fieldValue, query, options.CaseSensitive, startMarker, endMarker)
+	var fieldMatches []FieldMatch
+	for _, field := range fields {
+		// Get field value and match type using centralized logic
+		fieldValue, baseMatchType, exists := e.getFieldInfo(doc, field)
+		if !exists {
+			continue
+		}
+
+		var matches []MatchInfo
+		if term.Field != "" && baseMatchType == MatchDimension {
+			if matchScopedTerm(doc, term) {
+				matches = []MatchInfo{{
+					Start:     0,
+					End:       len(fieldValue),
+					Text:      fieldValue,
+					Score:     1.0,
+					MatchType: MatchDimension,
+				}}
+			}
+		} else {
+			matches = e.findMatches(fieldValue, term.Text, options, baseMatchType)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		// Calculate field score (highest match score)
+		fieldScore := 0.0
+		for _, match := range matches {
+			if match.Score > fieldScore {
+				fieldScore = match.Score
+			}
+		}
+
+		fieldMatches = append(fieldMatches, FieldMatch{
+			FieldName:    field,
+			OriginalText: fieldValue,
+			Matches:      matches,
+			FieldScore:   fieldScore,
+		})
 	}
 
-	return &FieldMatch{
-		FieldName:       fieldName,
-		OriginalText:    fieldValue,
-		Matches:         matches,
-		HighlightedText: highlightedText,
-		FieldScore:      fieldScore,
+	return fieldMatches
+}
+
+// withoutOverlaps sorts matches by position and drops those overlapping an
+// earlier, longer match
+func withoutOverlaps(matches []MatchInfo) []MatchInfo {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End > matches[j].End
+	})
+
+	result := matches[:0]
+	lastEnd := -1
+	for _, match := range matches {
+		if match.Start < lastEnd {
+			continue
+		}
+		result = append(result, match)
+		lastEnd = match.End
 	}
+	return result
 }
 
 // calculateScore computes a relevance score for a match
@@ -235,6 +327,8 @@ func (e *Engine) findMatches(text, query string, options SearchOptions, baseMatc
 				MatchType: matchType,
 			})
 		}
+	} else if options.wordMatching() {
+		matches = e.findWordMatches(text, query, options, baseMatchType)
 	} else {
 		// Find all substring matches
 		for i := 0; i <= len(searchText)-queryLen; i++ {
@@ -266,61 +360,6 @@ func (e *Engine) findMatches(text, query string, options SearchOptions, baseMatc
 	return matches
 }
 
-// highlightMatchesWithMarkers adds configurable highlight markers around matches
-func (e *Engine) highlightMatchesWithMarkers(text, query string, caseSensitive bool, startMarker, endMarker string) string {
-	if query == "" {
-		return text
-	}
-
-	searchText := text
-	searchQuery := query
-	if !caseSensitive {
-		searchText = strings.ToLower(text)
-		searchQuery = strings.ToLower(query)
-	}
-
-	queryLen := len(query)
-	if queryLen == 0 {
-		return text
-	}
-
-	// Find all match positions first
-	var matchPositions []int
-	for i := 0; i <= len(searchText)-queryLen; i++ {
-		if searchText[i:i+queryLen] == searchQuery {
-			matchPositions = append(matchPositions, i)
-			i += queryLen - 1 // Skip overlapping matches
-		}
-	}
-
-	if len(matchPositions) == 0 {
-		return text
-	}
-
-	// Build result string efficiently using strings.Builder
-	var builder strings.Builder
-	lastEnd := 0
-
-	for _, start := range matchPositions {
-		end := start + queryLen
-
-		// Add text before match
-		builder.WriteString(text[lastEnd:start])
-
-		// Add highlighted match
-		builder.WriteString(startMarker)
-		builder.WriteString(text[start:end])
-		builder.WriteString(endMarker)
-
-		lastEnd = end
-	}
-
-	// Add remaining text after last match
-	builder.WriteString(text[lastEnd:])
-
-	return builder.String()
-}
-
 // getAllSearchableFields returns all fields that can be searched in a document
 func (e *Engine) getAllSearchableFields(doc types.Document) []string {
 	fields := []string{"title", "body"}
@@ -0,0 +1,226 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// wordMatch describes how a field word matched a query word
+type wordMatch int
+
+const (
+	wordNoMatch wordMatch = iota
+	wordExact
+	wordPrefix
+	wordFuzzy
+)
+
+// Relative quality of each kind of word match, used in scores
+const (
+	exactWordQuality  = 1.0
+	prefixWordQuality = 0.85
+	fuzzyWordQuality  = 0.75
+	fuzzyEditPenalty  = 0.1
+)
+
+// wordMatching reports whether the options require word-level matching
+// instead of substring matching
+func (o SearchOptions) wordMatching() bool {
+	return o.MaxEdits > 0 || o.Prefix || o.AnyOrder
+}
+
+// matchWord compares a field word with a query word under the fuzzy options
+// and returns the kind of match and its quality (0 when they do not match)
+func matchWord(word, query string, options SearchOptions) (wordMatch, float64) {
+	if word == query {
+		return wordExact, exactWordQuality
+	}
+	if options.Prefix && strings.HasPrefix(word, query) {
+		return wordPrefix, prefixWordQuality
+	}
+	if options.MaxEdits > 0 {
+		allowed := options.MaxEdits
+		if limit := utf8.RuneCountInString(query) - 1; limit < allowed {
+			allowed = limit
+		}
+		if allowed > 0 {
+			if distance := editDistance(word, query, allowed); distance <= allowed {
+				return wordFuzzy, fuzzyWordQuality - fuzzyEditPenalty*float64(distance-1)
+			}
+		}
+	}
+	return wordNoMatch, 0
+}
+
+// editDistance returns the optimal string alignment (restricted
+// Damerau-Levenshtein) distance between a and b, counting adjacent
+// transpositions as one edit. Computation stops early once the distance is
+// known to exceed max, in which case max+1 is returned.
+func editDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > max || -diff > max {
+		return max + 1
+	}
+
+	// Three rows are enough: transpositions look two rows back
+	previous2 := make([]int, len(rb)+1)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				current[j] = min(current[j], previous2[j-2]+1)
+			}
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		previous2, previous, current = previous, current, previous2
+	}
+
+	if previous[len(rb)] > max {
+		return max + 1
+	}
+	return previous[len(rb)]
+}
+
+// fieldWord is a word of a field value with its position and comparison form
+type fieldWord struct {
+	token
+	Text string
+}
+
+// splitWords tokenizes text for word matching. Words are lowercased and
+// accent-folded unless the search is case-sensitive.
+func splitWords(text string, caseSensitive bool) []fieldWord {
+	tokens := analyzer{}.tokens(text)
+	words := make([]fieldWord, len(tokens))
+	for i, tok := range tokens {
+		words[i] = fieldWord{token: tok, Text: tok.Term}
+		if caseSensitive {
+			words[i].Text = text[tok.Start:tok.End]
+		}
+	}
+	return words
+}
+
+// findWordMatches matches the query words against the words of text. By
+// default the query words must match consecutive words, producing one match
+// spanning the phrase; with AnyOrder each query word may match anywhere and
+// every matching word is reported separately.
+func (e *Engine) findWordMatches(text, query string, options SearchOptions, baseMatchType MatchType) []MatchInfo {
+	queryWords := splitWords(query, options.CaseSensitive)
+	if len(queryWords) == 0 {
+		return nil
+	}
+	words := splitWords(text, options.CaseSensitive)
+
+	fieldWeight := 0.7
+	if baseMatchType == MatchPartialTitle {
+		fieldWeight = 1.0
+	}
+
+	var matches []MatchInfo
+	if options.AnyOrder {
+		matched := make(map[int]MatchInfo)
+		for _, queryWord := range queryWords {
+			found := false
+			for i, word := range words {
+				kind, quality := matchWord(word.Text, queryWord.Text, options)
+				if kind == wordNoMatch {
+					continue
+				}
+				found = true
+				score := fieldWeight * quality
+				if existing, ok := matched[i]; ok && existing.Score >= score {
+					continue
+				}
+				matched[i] = MatchInfo{
+					Start:     word.Start,
+					End:       word.End,
+					Text:      text[word.Start:word.End],
+					Score:     score,
+					MatchType: wordMatchType(baseMatchType, kind),
+				}
+			}
+			if !found {
+				return nil
+			}
+		}
+
+		for _, match := range matched {
+			matches = append(matches, match)
+		}
+		sort.Slice(matches, func(i, j int) bool {
+			return matches[i].Start < matches[j].Start
+		})
+		return matches
+	}
+
+	for i := 0; i+len(queryWords) <= len(words); i++ {
+		quality := 0.0
+		kind := wordExact
+		for k, queryWord := range queryWords {
+			wordKind, wordQuality := matchWord(words[i+k].Text, queryWord.Text, options)
+			if wordKind == wordNoMatch {
+				quality = 0
+				break
+			}
+			quality += wordQuality
+			if wordKind > kind {
+				kind = wordKind
+			}
+		}
+		if quality == 0 {
+			continue
+		}
+
+		start, end := words[i].Start, words[i+len(queryWords)-1].End
+		matches = append(matches, MatchInfo{
+			Start:     start,
+			End:       end,
+			Text:      text[start:end],
+			Score:     fieldWeight * quality / float64(len(queryWords)),
+			MatchType: wordMatchType(baseMatchType, kind),
+		})
+
+		// Skip overlapping matches
+		i += len(queryWords) - 1
+	}
+	return matches
+}
+
+// wordMatchType refines a field's match type with the kind of word match.
+// Dimension and custom data matches keep their field type.
+func wordMatchType(base MatchType, kind wordMatch) MatchType {
+	switch kind {
+	case wordFuzzy:
+		switch base {
+		case MatchPartialTitle:
+			return MatchFuzzyTitle
+		case MatchPartialBody:
+			return MatchFuzzyBody
+		}
+	case wordPrefix:
+		switch base {
+		case MatchPartialTitle:
+			return MatchPrefixTitle
+		case MatchPartialBody:
+			return MatchPrefixBody
+		}
+	}
+	return base
+}
@@ -0,0 +1,243 @@
+package search
+
+import (
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		max      int
+		expected int
+	}{
+		{"meeting", "meeting", 2, 0},
+		{"meeting", "meting", 2, 1},   // deletion
+		{"meeting", "meetingg", 2, 1}, // insertion
+		{"meeting", "meetang", 2, 1},  // substitution
+		{"meeting", "meetign", 2, 1},  // transposition
+		{"budget", "bugdet", 2, 1},
+		{"café", "cafe", 2, 1},
+		{"meeting", "greeting", 2, 2},
+		{"meeting", "standup", 2, 3}, // exceeds max
+		{"a", "abcdef", 2, 3},        // length difference alone exceeds max
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.max); got != tt.expected {
+			t.Errorf("editDistance(%q, %q, %d) = %d, expected %d", tt.a, tt.b, tt.max, got, tt.expected)
+		}
+	}
+}
+
+func TestEngine_Search_Fuzzy(t *testing.T) {
+	provider := NewMockDocumentProvider(SampleDocuments())
+	engine := NewEngine(provider)
+
+	// Without fuzzy matching a typo finds nothing
+	results, err := engine.Search(SearchOptions{Query: "meetign"}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no results without fuzzy matching, got %d", len(results))
+	}
+
+	results, err = engine.Search(SearchOptions{
+		Query:               "meetign",
+		MaxEdits:            1,
+		IncludeMatchDetails: true,
+		EnableHighlight:     true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Expected all 4 meeting documents, got %d", len(results))
+	}
+
+	for _, result := range results {
+		if result.Document.UUID != "1" {
+			continue
+		}
+		if result.MatchType != MatchFuzzyTitle {
+			t.Errorf("Expected fuzzy title match, got %s", result.MatchType)
+		}
+		if result.Highlights["title"] != "Important **Meeting**" {
+			t.Errorf("Unexpected highlight %q", result.Highlights["title"])
+		}
+		match := result.FieldMatches[0].Matches[0]
+		if match.Start != 10 || match.End != 17 || match.Text != "Meeting" {
+			t.Errorf("Unexpected match position %+v", match)
+		}
+	}
+
+	// Short words do not match everything
+	results, err = engine.Search(SearchOptions{Query: "xy", MaxEdits: 2, Fields: []string{"title"}}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected short query not to match, got %d results", len(results))
+	}
+}
+
+func TestEngine_Search_FuzzyRanksExactFirst(t *testing.T) {
+	provider := NewMockDocumentProvider(SampleDocuments())
+	engine := NewEngine(provider)
+
+	results, err := engine.Search(SearchOptions{Query: "budget", MaxEdits: 2, Fields: []string{"title"}}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Document.UUID != "2" {
+		t.Fatalf("Expected only Budget Review, got %d results", len(results))
+	}
+	if results[0].MatchType != MatchPartialTitle {
+		t.Errorf("Expected exact word match type, got %s", results[0].MatchType)
+	}
+
+	exact := results[0].Score
+	results, err = engine.Search(SearchOptions{Query: "bugdet", MaxEdits: 2, Fields: []string{"title"}}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Score >= exact {
+		t.Errorf("Expected fuzzy match to score below exact match %f, got %+v", exact, results)
+	}
+}
+
+func TestEngine_Search_Prefix(t *testing.T) {
+	provider := NewMockDocumentProvider(SampleDocuments())
+	engine := NewEngine(provider)
+
+	results, err := engine.Search(SearchOptions{
+		Query:               "stand",
+		Prefix:              true,
+		Fields:              []string{"title"},
+		IncludeMatchDetails: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Document.UUID != "3" {
+		t.Fatalf("Expected Team Standup, got %d results", len(results))
+	}
+	if results[0].MatchType != MatchPrefixTitle {
+		t.Errorf("Expected prefix title match, got %s", results[0].MatchType)
+	}
+	match := results[0].FieldMatches[0].Matches[0]
+	if match.Text != "Standup" {
+		t.Errorf("Expected whole word to be reported, got %q", match.Text)
+	}
+
+	// Prefixes only match at word starts
+	results, err = engine.Search(SearchOptions{Query: "tand", Prefix: true, Fields: []string{"title"}}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no mid-word prefix matches, got %d", len(results))
+	}
+}
+
+func TestEngine_Search_AnyOrder(t *testing.T) {
+	provider := NewMockDocumentProvider(SampleDocuments())
+	engine := NewEngine(provider)
+
+	// Quoted phrases require order and adjacency
+	results, err := engine.Search(SearchOptions{Query: `"notes meeting"`, Prefix: true, Fields: []string{"body"}}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no ordered phrase match, got %d", len(results))
+	}
+
+	results, err = engine.Search(SearchOptions{
+		Query:               `"notes meeting"`,
+		AnyOrder:            true,
+		Fields:              []string{"body"},
+		EnableHighlight:     true,
+		IncludeMatchDetails: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Document.UUID != "2" {
+		t.Fatalf("Expected Budget Review, got %d results", len(results))
+	}
+	if got := results[0].Highlights["body"]; got != "Review the **meeting** **notes** from last quarter" {
+		t.Errorf("Unexpected highlight %q", got)
+	}
+	if len(results[0].FieldMatches[0].Matches) != 2 {
+		t.Errorf("Expected a match per word, got %+v", results[0].FieldMatches[0].Matches)
+	}
+
+	// Every word must be present
+	results, err = engine.Search(SearchOptions{Query: "notes standup", AnyOrder: true}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no document with both words, got %d", len(results))
+	}
+
+	// Combined with fuzzy matching
+	results, err = engine.Search(SearchOptions{Query: `"budgte qaurterly"`, AnyOrder: true, MaxEdits: 2, Fields: []string{"body"}}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Document.UUID != "1" {
+		t.Errorf("Expected fuzzy any-order match on document 1, got %d results", len(results))
+	}
+}
+
+func TestEngine_Search_PhraseSpan(t *testing.T) {
+	provider := NewMockDocumentProvider(SampleDocuments())
+	engine := NewEngine(provider)
+
+	results, err := engine.Search(SearchOptions{
+		Query:               `"quartely budgte"`,
+		MaxEdits:            1,
+		Fields:              []string{"body"},
+		IncludeMatchDetails: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	match := results[0].FieldMatches[0].Matches[0]
+	if match.Text != "quarterly budget" || match.MatchType != MatchFuzzyBody {
+		t.Errorf("Expected fuzzy phrase match spanning both words, got %+v", match)
+	}
+}
+
+func TestIndexSearcher_FuzzyAndPrefix(t *testing.T) {
+	index := newTestIndex(t, IndexOptions{})
+	index.Sync(SampleDocuments())
+	searcher := NewIndexSearcher(index, NewMockDocumentProvider(SampleDocuments()))
+
+	results, err := searcher.Search(SearchOptions{Query: "standpu", MaxEdits: 1, IncludeMatchDetails: true}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Document.UUID != "3" {
+		t.Fatalf("Expected Team Standup, got %d results", len(results))
+	}
+	if results[0].MatchType != MatchFuzzyTitle {
+		t.Errorf("Expected fuzzy title match type, got %s", results[0].MatchType)
+	}
+
+	results, err = searcher.Search(SearchOptions{Query: "budg", Prefix: true}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 budget documents, got %d", len(results))
+	}
+	if results[0].Document.UUID != "2" || results[0].MatchType != MatchPrefixTitle {
+		t.Errorf("Expected Budget Review first with prefix title match, got %s (%s)", results[0].Document.UUID, results[0].MatchType)
+	}
+}
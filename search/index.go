@@ -0,0 +1,467 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// indexFormatVersion is bumped whenever the persisted layout changes; older
+// files are discarded and rebuilt
+const indexFormatVersion = 2
+
+// Default BM25 parameters
+const (
+	DefaultK1 = 1.2
+	DefaultB  = 0.75
+)
+
+// DefaultFieldBoosts weights title matches above body and data matches
+var DefaultFieldBoosts = map[string]float64{
+	"title": 2.0,
+	"body":  1.0,
+	"_data": 0.5,
+}
+
+// IndexOptions configures an Index
+type IndexOptions struct {
+	// Stemming enables English stemming, so "meetings" matches "meeting".
+	// Changing it invalidates a persisted index, which is then rebuilt.
+	Stemming bool
+
+	// FieldBoosts multiplies the score of matches in each field.
+	// Keys are "title", "body" or "_data.fieldname"; the "_data" key applies
+	// to every data field without its own entry. Missing fields get 1.0.
+	// nil uses DefaultFieldBoosts.
+	FieldBoosts map[string]float64
+
+	// K1 controls term frequency saturation (0 uses DefaultK1)
+	K1 float64
+
+	// B controls field length normalization (0 uses DefaultB)
+	B float64
+
+	// StorePath is the file of the store the index covers. When set, the
+	// index records the store file's revision each time it is synced, and
+	// reports itself stale once the file changes (see Index.Stale).
+	StorePath string
+}
+
+// IndexPath returns the conventional location of the index for a store file:
+// "tasks.json" is indexed in "tasks.index.json" in the same directory.
+func IndexPath(storePath string) string {
+	return strings.TrimSuffix(storePath, filepath.Ext(storePath)) + ".index.json"
+}
+
+// indexedDocument records what the index knows about a document
+type indexedDocument struct {
+	// Hash fingerprints the indexed text, so unchanged documents are skipped
+	Hash uint64 `json:"hash"`
+
+	// Lengths holds the number of terms in each indexed field
+	Lengths map[string]int `json:"lengths"`
+
+	// Terms lists the distinct terms of the document, so removing it only
+	// visits its own postings
+	Terms []string `json:"terms"`
+}
+
+// indexFile is the persisted form of an Index
+type indexFile struct {
+	Version   int                                  `json:"version"`
+	Stemming  bool                                 `json:"stemming"`
+	Revision  string                               `json:"store_revision,omitempty"`
+	Documents map[string]*indexedDocument          `json:"documents"`
+	Postings  map[string]map[string]map[string]int `json:"postings"`
+}
+
+// Index is an inverted full-text index over document titles, bodies and
+// custom data fields, scored with BM25.
+//
+// The index is kept in memory and persisted as JSON with Save(). Documents are
+// added and refreshed with Put() or reconciled in bulk with Sync(); both only
+// re-tokenize documents whose content changed. IndexedStore keeps the index
+// current for the mutations made through it. Writes made any other way, such
+// as through api.Store[T], the CLI or another process, are caught through
+// IndexOptions.StorePath: the index then knows which revision of the store
+// file it was synced with, and IndexSearcher syncs a stale index before
+// searching it. Without a StorePath, callers run Sync after such writes.
+//
+// Index is safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	path     string
+	options  IndexOptions
+	analyzer analyzer
+	// revision is the store file revision the index was last synced with
+	revision string
+
+	documents map[string]*indexedDocument
+	// postings maps term -> document UUID -> field -> term frequency
+	postings map[string]map[string]map[string]int
+	// totalLengths is the sum of field lengths across documents
+	totalLengths map[string]int
+}
+
+// OpenIndex loads the index persisted at path, or starts an empty one when
+// the file does not exist. A file written with different analysis settings or
+// an older format is ignored, leaving an empty index to be rebuilt.
+func OpenIndex(path string, options IndexOptions) (*Index, error) {
+	ix := &Index{
+		path:         path,
+		options:      options,
+		analyzer:     analyzer{stem: options.Stemming},
+		documents:    make(map[string]*indexedDocument),
+		postings:     make(map[string]map[string]map[string]int),
+		totalLengths: make(map[string]int),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ix, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var file indexFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse index %s: %w", path, err)
+	}
+	if file.Version != indexFormatVersion || file.Stemming != options.Stemming {
+		return ix, nil
+	}
+
+	ix.revision = file.Revision
+	if file.Documents != nil {
+		ix.documents = file.Documents
+	}
+	if file.Postings != nil {
+		ix.postings = file.Postings
+	}
+	for _, doc := range ix.documents {
+		for field, length := range doc.Lengths {
+			ix.totalLengths[field] += length
+		}
+	}
+
+	return ix, nil
+}
+
+// Len returns the number of indexed documents
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.documents)
+}
+
+// Put adds or refreshes a document. It reports whether the index changed.
+func (ix *Index) Put(doc types.Document) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return ix.put(doc)
+}
+
+// Remove drops a document from the index. It reports whether the index changed.
+func (ix *Index) Remove(uuid string) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return ix.remove(uuid)
+}
+
+// Sync reconciles the index with the complete set of documents in a store:
+// new and modified documents are (re)indexed and documents that are no longer
+// present are removed, and the store revision is recorded. It reports whether
+// the index changed.
+func (ix *Index) Sync(docs []types.Document) bool {
+	revision := ix.storeRevision()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	changed := revision != ix.revision
+	ix.revision = revision
+	present := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		present[doc.UUID] = true
+		if ix.put(doc) {
+			changed = true
+		}
+	}
+	for uuid := range ix.documents {
+		if !present[uuid] && ix.remove(uuid) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Stale reports whether the store file changed since the index was last
+// synced with it. An index without IndexOptions.StorePath is never stale.
+func (ix *Index) Stale() bool {
+	if ix.options.StorePath == "" {
+		return false
+	}
+	revision := ix.storeRevision()
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return revision != ix.revision
+}
+
+// storeRevision identifies the current contents of the store file by its
+// modification time and size; a missing file has an empty revision
+func (ix *Index) storeRevision() string {
+	if ix.options.StorePath == "" {
+		return ""
+	}
+	info, err := os.Stat(ix.options.StorePath)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size())
+}
+
+// Save writes the index to its file, replacing the previous contents atomically
+func (ix *Index) Save() error {
+	ix.mu.RLock()
+	data, err := json.Marshal(indexFile{
+		Version:   indexFormatVersion,
+		Stemming:  ix.options.Stemming,
+		Revision:  ix.revision,
+		Documents: ix.documents,
+		Postings:  ix.postings,
+	})
+	ix.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+
+	tmp := ix.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := os.Rename(tmp, ix.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// put indexes doc unless its content is unchanged; the caller holds the lock
+func (ix *Index) put(doc types.Document) bool {
+	fields := indexableFields(doc)
+	hash := hashFields(fields)
+	if existing, ok := ix.documents[doc.UUID]; ok {
+		if existing.Hash == hash {
+			return false
+		}
+		ix.remove(doc.UUID)
+	}
+
+	entry := &indexedDocument{Hash: hash, Lengths: make(map[string]int)}
+	for field, text := range fields {
+		terms := ix.analyzer.terms(text)
+		if len(terms) == 0 {
+			continue
+		}
+		entry.Lengths[field] = len(terms)
+		ix.totalLengths[field] += len(terms)
+		for _, term := range terms {
+			docs, ok := ix.postings[term]
+			if !ok {
+				docs = make(map[string]map[string]int)
+				ix.postings[term] = docs
+			}
+			frequencies, ok := docs[doc.UUID]
+			if !ok {
+				frequencies = make(map[string]int)
+				docs[doc.UUID] = frequencies
+				entry.Terms = append(entry.Terms, term)
+			}
+			frequencies[field]++
+		}
+	}
+	ix.documents[doc.UUID] = entry
+	return true
+}
+
+// remove drops a document's postings; the caller holds the lock
+func (ix *Index) remove(uuid string) bool {
+	entry, ok := ix.documents[uuid]
+	if !ok {
+		return false
+	}
+
+	for field, length := range entry.Lengths {
+		ix.totalLengths[field] -= length
+		if ix.totalLengths[field] <= 0 {
+			delete(ix.totalLengths, field)
+		}
+	}
+	for _, term := range entry.Terms {
+		docs := ix.postings[term]
+		delete(docs, uuid)
+		if len(docs) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	delete(ix.documents, uuid)
+	return true
+}
+
+// scoredDocument is the BM25 score of one document for a query
+type scoredDocument struct {
+	Score float64
+	// FieldScores holds each matching field's contribution to Score
+	FieldScores map[string]float64
+}
+
+// queryTerm is an index term matched by a query word
+type queryTerm struct {
+	// Weight scales the term's contribution (1.0 for exact matches)
+	Weight float64
+	Kind   wordMatch
+}
+
+// expand maps the analyzed query words to the index terms they match. Without
+// fuzzy or prefix options each word only matches itself; otherwise the
+// vocabulary is scanned for terms within the edit distance or starting with
+// the word. With stemming, these comparisons apply to word stems.
+func (ix *Index) expand(words []string, options SearchOptions) map[string]queryTerm {
+	terms := make(map[string]queryTerm, len(words))
+	if options.MaxEdits == 0 && !options.Prefix {
+		for _, word := range words {
+			terms[word] = queryTerm{Weight: exactWordQuality, Kind: wordExact}
+		}
+		return terms
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	for _, word := range words {
+		for term := range ix.postings {
+			kind, quality := matchWord(term, word, options)
+			if kind != wordNoMatch && quality > terms[term].Weight {
+				terms[term] = queryTerm{Weight: quality, Kind: kind}
+			}
+		}
+	}
+	return terms
+}
+
+// score ranks the indexed documents against the query terms with BM25,
+// computed per field and combined with the field boosts and term weights.
+// When fields is not empty only those fields are considered. Documents
+// without any match are omitted.
+func (ix *Index) score(terms map[string]queryTerm, fields []string) map[string]*scoredDocument {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	k1 := ix.options.K1
+	if k1 == 0 {
+		k1 = DefaultK1
+	}
+	b := ix.options.B
+	if b == 0 {
+		b = DefaultB
+	}
+
+	var allowed map[string]bool
+	if len(fields) > 0 {
+		allowed = make(map[string]bool, len(fields))
+		for _, field := range fields {
+			allowed[field] = true
+		}
+	}
+
+	n := float64(len(ix.documents))
+	results := make(map[string]*scoredDocument)
+	for term, query := range terms {
+		docs := ix.postings[term]
+		if len(docs) == 0 {
+			continue
+		}
+		df := float64(len(docs))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+		for uuid, frequencies := range docs {
+			for field, tf := range frequencies {
+				if allowed != nil && !allowed[field] {
+					continue
+				}
+				avgLength := float64(ix.totalLengths[field]) / n
+				length := float64(ix.documents[uuid].Lengths[field])
+				frequency := float64(tf)
+				contribution := query.Weight * ix.boost(field) * idf * frequency * (k1 + 1) /
+					(frequency + k1*(1-b+b*length/avgLength))
+
+				result, ok := results[uuid]
+				if !ok {
+					result = &scoredDocument{FieldScores: make(map[string]float64)}
+					results[uuid] = result
+				}
+				result.Score += contribution
+				result.FieldScores[field] += contribution
+			}
+		}
+	}
+	return results
+}
+
+// boost returns the configured weight of a field
+func (ix *Index) boost(field string) float64 {
+	boosts := ix.options.FieldBoosts
+	if boosts == nil {
+		boosts = DefaultFieldBoosts
+	}
+	if boost, ok := boosts[field]; ok {
+		return boost
+	}
+	if strings.HasPrefix(field, "_data.") {
+		if boost, ok := boosts["_data"]; ok {
+			return boost
+		}
+	}
+	return 1.0
+}
+
+// indexableFields returns the text of the fields covered by the index: title,
+// body and custom data fields
+func indexableFields(doc types.Document) map[string]string {
+	fields := map[string]string{
+		"title": doc.Title,
+		"body":  doc.Body,
+	}
+	for key, value := range doc.Dimensions {
+		if strings.HasPrefix(key, "_data.") && value != nil {
+			fields[key] = fmt.Sprintf("%v", value)
+		}
+	}
+	return fields
+}
+
+// hashFields fingerprints field contents independently of map order
+func hashFields(fields map[string]string) uint64 {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := fnv.New64a()
+	for _, name := range names {
+		_, _ = h.Write([]byte(name))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(fields[name]))
+		_, _ = h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,401 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// IndexSearcher implements the Searcher interface on top of an Index.
+//
+// The query language is the same as Engine's (see Query). Words are matched
+// as whole terms after normalization (lowercasing, accent folding and, when
+// the index uses it, stemming), and results are ranked with BM25, so
+// documents containing more of the query words, rarer words, or matches in
+// boosted fields rank higher. Scores are normalized so the best result scores
+// 1.0. MaxEdits and Prefix also match similar terms, which contribute less
+// than exact ones.
+//
+// The provider supplies the documents for the filters, as with Engine, but
+// is only asked for the documents the index scored, through a "uuid" filter
+// listing them, unless the query can match documents without any of its
+// words (such as "-draft"). Documents the index does not know about never
+// match. A stale index (see Index.Stale) is first synced with every document
+// of the provider and saved. Searches the index cannot answer (CaseSensitive
+// or ExactMatch) fall back to the scanning Engine.
+type IndexSearcher struct {
+	index    *Index
+	provider DocumentProvider
+}
+
+// NewIndexSearcher creates a searcher that ranks documents from provider using index
+func NewIndexSearcher(index *Index, provider DocumentProvider) *IndexSearcher {
+	return &IndexSearcher{
+		index:    index,
+		provider: provider,
+	}
+}
+
+// syncIfStale brings a stale index up to date with the provider's documents
+func (s *IndexSearcher) syncIfStale() error {
+	if !s.index.Stale() {
+		return nil
+	}
+	documents, err := s.provider.GetDocuments(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to sync index: %w", err)
+	}
+	if s.index.Sync(documents) {
+		return s.index.Save()
+	}
+	return nil
+}
+
+// Search performs a search and returns ranked results
+func (s *IndexSearcher) Search(options SearchOptions, filters map[string]interface{}) ([]SearchResult, error) {
+	if options.Query == "" {
+		return []SearchResult{}, nil
+	}
+	if options.CaseSensitive || options.ExactMatch {
+		return NewEngine(s.provider).Search(options, filters)
+	}
+	if err := s.syncIfStale(); err != nil {
+		return nil, err
+	}
+
+	query := ParseQuery(options.Query)
+	if query.IsEmpty() {
+		return []SearchResult{}, nil
+	}
+
+	// Expand the words of every term once; positive words also drive ranking
+	words := make(map[string]map[string]queryTerm)
+	for _, term := range s.allTerms(query) {
+		for _, word := range s.index.analyzer.terms(term.Text) {
+			if _, ok := words[word]; !ok {
+				words[word] = s.index.expand([]string{word}, options)
+			}
+		}
+	}
+	terms := make(map[string]queryTerm)
+	for _, term := range query.Terms() {
+		for _, word := range s.index.analyzer.terms(term.Text) {
+			for indexTerm, match := range words[word] {
+				if match.Weight > terms[indexTerm].Weight {
+					terms[indexTerm] = match
+				}
+			}
+		}
+	}
+	scores := s.index.score(terms, options.Fields)
+
+	// Unless the query can match without any positive term (e.g. "-draft"),
+	// only documents with a scored term can match
+	requiresTerms := requiresPositiveTerm(query.Root)
+	if requiresTerms && len(scores) == 0 {
+		return []SearchResult{}, nil
+	}
+
+	documents, err := s.provider.GetDocuments(s.candidateFilters(filters, scores, requiresTerms))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+
+	results := make([]SearchResult, 0, len(scores))
+	maxScore := 0.0
+	for _, doc := range documents {
+		scored, ok := scores[doc.UUID]
+		if !ok {
+			if requiresTerms {
+				continue
+			}
+			scored = &scoredDocument{FieldScores: map[string]float64{}}
+		}
+		if !query.Evaluate(func(term *Term) bool { return s.matchTerm(doc, term, words, options) }) {
+			continue
+		}
+		results = append(results, s.buildResult(doc, scored, terms, options))
+		if scored.Score > maxScore {
+			maxScore = scored.Score
+		}
+	}
+
+	// Sort by score (highest first)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	// Apply max results limit
+	if options.MaxResults != nil && *options.MaxResults > 0 && len(results) > *options.MaxResults {
+		results = results[:*options.MaxResults]
+	}
+
+	if maxScore > 0 {
+		for i := range results {
+			results[i].Score /= maxScore
+			for j := range results[i].FieldMatches {
+				results[i].FieldMatches[j].FieldScore /= maxScore
+				for k := range results[i].FieldMatches[j].Matches {
+					results[i].FieldMatches[j].Matches[k].Score /= maxScore
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// candidateFilters narrows filters to the scored documents when only those
+// can match, so the provider does not load the whole store. A "uuid" filter
+// from the caller is kept as is.
+func (s *IndexSearcher) candidateFilters(filters map[string]interface{}, scores map[string]*scoredDocument, requiresTerms bool) map[string]interface{} {
+	if !requiresTerms {
+		return filters
+	}
+	if _, ok := filters["uuid"]; ok {
+		return filters
+	}
+	uuids := make([]string, 0, len(scores))
+	for uuid := range scores {
+		uuids = append(uuids, uuid)
+	}
+	narrowed := make(map[string]interface{}, len(filters)+1)
+	for key, value := range filters {
+		narrowed[key] = value
+	}
+	narrowed["uuid"] = uuids
+	return narrowed
+}
+
+// allTerms returns every term of the query, including negated ones
+func (s *IndexSearcher) allTerms(query *Query) []*Term {
+	var terms []*Term
+	var walk func(node Node)
+	walk = func(node Node) {
+		switch n := node.(type) {
+		case *Term:
+			terms = append(terms, n)
+		case *And:
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *Or:
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *Not:
+			walk(n.Node)
+		}
+	}
+	walk(query.Root)
+	return terms
+}
+
+// requiresPositiveTerm reports whether every document matching node contains
+// at least one of its non-negated terms
+func requiresPositiveTerm(node Node) bool {
+	switch n := node.(type) {
+	case *Term:
+		return true
+	case *And:
+		for _, child := range n.Nodes {
+			if requiresPositiveTerm(child) {
+				return true
+			}
+		}
+		return false
+	case *Or:
+		for _, child := range n.Nodes {
+			if !requiresPositiveTerm(child) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// matchTerm decides whether a document matches one query term using the
+// index's analysis: each word of the term must match (exactly or through the
+// fuzzy and prefix expansions) a word of the same field, and the words of a
+// phrase must be adjacent and in order unless AnyOrder is set. Dimension
+// scopes compare whole values, as with Engine.
+func (s *IndexSearcher) matchTerm(doc types.Document, term *Term, words map[string]map[string]queryTerm, options SearchOptions) bool {
+	texts := indexableFields(doc)
+	fields := options.Fields
+	if term.Field != "" {
+		key, ok := resolveField(doc, term.Field)
+		if !ok {
+			return false
+		}
+		if _, indexed := texts[key]; !indexed {
+			return matchScopedTerm(doc, term)
+		}
+		fields = []string{key}
+	} else if len(fields) == 0 {
+		for field := range texts {
+			fields = append(fields, field)
+		}
+	}
+
+	termWords := s.index.analyzer.terms(term.Text)
+	if len(termWords) == 0 {
+		return false
+	}
+	matchesWord := func(fieldTerm string, i int) bool {
+		_, ok := words[termWords[i]][fieldTerm]
+		return ok
+	}
+
+	for _, field := range fields {
+		fieldTerms := s.index.analyzer.terms(texts[field])
+		if term.Phrase && !options.AnyOrder {
+			for start := 0; start+len(termWords) <= len(fieldTerms); start++ {
+				matched := true
+				for i := range termWords {
+					if !matchesWord(fieldTerms[start+i], i) {
+						matched = false
+						break
+					}
+				}
+				if matched {
+					return true
+				}
+			}
+			continue
+		}
+
+		found := 0
+		for i := range termWords {
+			for _, fieldTerm := range fieldTerms {
+				if matchesWord(fieldTerm, i) {
+					found++
+					break
+				}
+			}
+		}
+		if found == len(termWords) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildResult assembles the result for a scored document. Scores are raw
+// BM25 values; Search normalizes them once the maximum is known.
+func (s *IndexSearcher) buildResult(doc types.Document, scored *scoredDocument, terms map[string]queryTerm, options SearchOptions) SearchResult {
+	// Set default highlight markers
+	startMarker := options.HighlightStartMarker
+	endMarker := options.HighlightEndMarker
+	if startMarker == "" {
+		startMarker = "**"
+	}
+	if endMarker == "" {
+		endMarker = "**"
+	}
+
+	fields := make([]string, 0, len(scored.FieldScores))
+	for field := range scored.FieldScores {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if scored.FieldScores[fields[i]] != scored.FieldScores[fields[j]] {
+			return scored.FieldScores[fields[i]] > scored.FieldScores[fields[j]]
+		}
+		return fields[i] < fields[j]
+	})
+
+	result := SearchResult{
+		Document:      doc,
+		Score:         scored.Score,
+		MatchedFields: fields,
+	}
+	if len(fields) > 0 {
+		result.MatchType = fieldMatchType(fields[0])
+	}
+	if options.EnableHighlight {
+		result.Highlights = make(map[string]string)
+	}
+
+	texts := indexableFields(doc)
+	fieldMatches := make([]FieldMatch, 0, len(fields))
+	for _, field := range fields {
+		text := texts[field]
+		matches := s.termMatches(text, field, terms, scored.FieldScores[field])
+		if field == fields[0] && len(matches) > 0 {
+			result.MatchType = matches[0].MatchType
+		}
+
+		highlighted := text
+		if options.EnableHighlight {
+			highlighted = highlightRanges(text, matches, startMarker, endMarker)
+			result.Highlights[field] = highlighted
+		}
+		fieldMatches = append(fieldMatches, FieldMatch{
+			FieldName:       field,
+			OriginalText:    text,
+			Matches:         matches,
+			HighlightedText: highlighted,
+			FieldScore:      scored.FieldScores[field],
+		})
+	}
+
+	if options.IncludeMatchDetails {
+		result.FieldMatches = fieldMatches
+	}
+	addSnippets(&result, fieldMatches, options, startMarker, endMarker)
+
+	return result
+}
+
+// termMatches locates the words of text that match a query term
+func (s *IndexSearcher) termMatches(text, field string, terms map[string]queryTerm, fieldScore float64) []MatchInfo {
+	var matches []MatchInfo
+	for _, tok := range s.index.analyzer.tokens(text) {
+		if term, ok := terms[tok.Term]; ok {
+			matches = append(matches, MatchInfo{
+				Start:     tok.Start,
+				End:       tok.End,
+				Text:      text[tok.Start:tok.End],
+				Score:     fieldScore * term.Weight,
+				MatchType: wordMatchType(fieldMatchType(field), term.Kind),
+			})
+		}
+	}
+	return matches
+}
+
+// fieldMatchType returns the match type reported for matches in a field
+func fieldMatchType(field string) MatchType {
+	switch {
+	case field == "title":
+		return MatchPartialTitle
+	case field == "body":
+		return MatchPartialBody
+	case strings.HasPrefix(field, "_data."):
+		return MatchCustomData
+	default:
+		return MatchDimension
+	}
+}
+
+// highlightRanges wraps each match of text with the highlight markers
+func highlightRanges(text string, matches []MatchInfo, startMarker, endMarker string) string {
+	if len(matches) == 0 {
+		return text
+	}
+
+	var builder strings.Builder
+	lastEnd := 0
+	for _, match := range matches {
+		builder.WriteString(text[lastEnd:match.Start])
+		builder.WriteString(startMarker)
+		builder.WriteString(text[match.Start:match.End])
+		builder.WriteString(endMarker)
+		lastEnd = match.End
+	}
+	builder.WriteString(text[lastEnd:])
+	return builder.String()
+}
@@ -0,0 +1,505 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func newTestIndex(t *testing.T, options IndexOptions) *Index {
+	t.Helper()
+	index, err := OpenIndex(filepath.Join(t.TempDir(), "store.index.json"), options)
+	if err != nil {
+		t.Fatalf("Failed to open index: %v", err)
+	}
+	return index
+}
+
+func TestIndexPath(t *testing.T) {
+	if got := IndexPath("/data/tasks.json"); got != "/data/tasks.index.json" {
+		t.Errorf("Expected /data/tasks.index.json, got %s", got)
+	}
+}
+
+func TestIndexSearcher_BM25Ranking(t *testing.T) {
+	index := newTestIndex(t, IndexOptions{})
+	index.Sync(SampleDocuments())
+	searcher := NewIndexSearcher(index, NewMockDocumentProvider(SampleDocuments()))
+
+	results, err := searcher.Search(SearchOptions{Query: "budget OR meeting"}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	// Every sample document mentions at least one of the words
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(results))
+	}
+
+	// Documents containing both words in boosted fields rank first
+	top := map[string]bool{results[0].Document.UUID: true, results[1].Document.UUID: true}
+	if !top["1"] || !top["2"] {
+		t.Errorf("Expected documents 1 and 2 to rank first, got %s and %s",
+			results[0].Document.UUID, results[1].Document.UUID)
+	}
+	if results[0].Score != 1.0 {
+		t.Errorf("Expected best score to be normalized to 1.0, got %f", results[0].Score)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Score > results[i-1].Score {
+			t.Errorf("Results not sorted by score at %d", i)
+		}
+	}
+}
+
+func TestIndexSearcher_FieldBoosts(t *testing.T) {
+	docs := []types.Document{
+		{UUID: "a", Title: "Budget", Body: "notes"},
+		{UUID: "b", Title: "Notes", Body: "budget"},
+	}
+
+	// Title matches win with the default boosts
+	index := newTestIndex(t, IndexOptions{})
+	index.Sync(docs)
+	results, err := NewIndexSearcher(index, NewMockDocumentProvider(docs)).Search(SearchOptions{Query: "budget"}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Document.UUID != "a" {
+		t.Fatalf("Expected title match first, got %+v", results)
+	}
+	if results[0].MatchType != MatchPartialTitle || results[1].MatchType != MatchPartialBody {
+		t.Errorf("Unexpected match types %s, %s", results[0].MatchType, results[1].MatchType)
+	}
+
+	// Custom boosts can favour the body
+	index = newTestIndex(t, IndexOptions{FieldBoosts: map[string]float64{"title": 1, "body": 3}})
+	index.Sync(docs)
+	results, err = NewIndexSearcher(index, NewMockDocumentProvider(docs)).Search(SearchOptions{Query: "budget"}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Document.UUID != "b" {
+		t.Errorf("Expected body match first with body boost, got %+v", results)
+	}
+}
+
+func TestIndexSearcher_DataFieldsAndFieldFilter(t *testing.T) {
+	index := newTestIndex(t, IndexOptions{})
+	index.Sync(SampleDocuments())
+	searcher := NewIndexSearcher(index, NewMockDocumentProvider(SampleDocuments()))
+
+	results, err := searcher.Search(SearchOptions{Query: "alice"}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results for data field match, got %d", len(results))
+	}
+	if results[0].MatchType != MatchCustomData {
+		t.Errorf("Expected custom data match, got %s", results[0].MatchType)
+	}
+
+	results, err = searcher.Search(SearchOptions{Query: "meeting", Fields: []string{"title"}}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected 2 title matches, got %d", len(results))
+	}
+}
+
+func TestIndexSearcher_StemmingAndFolding(t *testing.T) {
+	docs := []types.Document{
+		{UUID: "1", Title: "Planning the café meetings"},
+		{UUID: "2", Title: "Unrelated"},
+	}
+	index := newTestIndex(t, IndexOptions{Stemming: true})
+	index.Sync(docs)
+	searcher := NewIndexSearcher(index, NewMockDocumentProvider(docs))
+
+	for _, query := range []string{"planned", "CAFE", "meeting"} {
+		results, err := searcher.Search(SearchOptions{Query: query}, nil)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(results) != 1 || results[0].Document.UUID != "1" {
+			t.Errorf("Expected %q to match document 1, got %+v", query, results)
+		}
+	}
+}
+
+func TestIndexSearcher_HighlightsAndDetails(t *testing.T) {
+	index := newTestIndex(t, IndexOptions{})
+	index.Sync(SampleDocuments())
+	searcher := NewIndexSearcher(index, NewMockDocumentProvider(SampleDocuments()))
+
+	results, err := searcher.Search(SearchOptions{
+		Query:               "standup",
+		EnableHighlight:     true,
+		IncludeMatchDetails: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+
+	result := results[0]
+	if result.Highlights["title"] != "Team **Standup**" {
+		t.Errorf("Unexpected title highlight %q", result.Highlights["title"])
+	}
+	if result.Highlights["body"] != "Daily **standup** meeting for development team" {
+		t.Errorf("Unexpected body highlight %q", result.Highlights["body"])
+	}
+	if len(result.FieldMatches) != 2 {
+		t.Fatalf("Expected 2 field matches, got %d", len(result.FieldMatches))
+	}
+	for _, fieldMatch := range result.FieldMatches {
+		match := fieldMatch.Matches[0]
+		if !strings.EqualFold(fieldMatch.OriginalText[match.Start:match.End], "standup") {
+			t.Errorf("Match position %d-%d does not point at the word in %q", match.Start, match.End, fieldMatch.OriginalText)
+		}
+	}
+}
+
+func TestIndexSearcher_Filters(t *testing.T) {
+	index := newTestIndex(t, IndexOptions{})
+	index.Sync(SampleDocuments())
+
+	// The provider decides which documents pass the filters
+	provider := NewMockDocumentProvider(SampleDocuments()[:1])
+	results, err := NewIndexSearcher(index, provider).Search(SearchOptions{Query: "meeting"}, map[string]interface{}{"status": "pending"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Document.UUID != "1" {
+		t.Errorf("Expected only document 1, got %+v", results)
+	}
+}
+
+func TestIndex_SyncIsIncremental(t *testing.T) {
+	index := newTestIndex(t, IndexOptions{})
+	docs := SampleDocuments()
+
+	if !index.Sync(docs) {
+		t.Fatal("Expected initial sync to change the index")
+	}
+	if index.Sync(docs) {
+		t.Error("Expected re-sync of unchanged documents to be a no-op")
+	}
+
+	docs[0].Title = "Renamed"
+	docs = docs[:3]
+	if !index.Sync(docs) {
+		t.Error("Expected sync to pick up changes")
+	}
+	if index.Len() != 3 {
+		t.Errorf("Expected 3 indexed documents, got %d", index.Len())
+	}
+	if _, ok := index.postings["caps"]; ok {
+		t.Error("Expected the terms of the removed document to leave the postings")
+	}
+
+	results, err := NewIndexSearcher(index, NewMockDocumentProvider(docs)).Search(SearchOptions{Query: "important"}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected old title terms to be removed, got %d results", len(results))
+	}
+}
+
+func TestIndex_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.index.json")
+	index, err := OpenIndex(path, IndexOptions{Stemming: true})
+	if err != nil {
+		t.Fatalf("Failed to open index: %v", err)
+	}
+	index.Sync(SampleDocuments())
+	if err := index.Save(); err != nil {
+		t.Fatalf("Failed to save index: %v", err)
+	}
+
+	reopened, err := OpenIndex(path, IndexOptions{Stemming: true})
+	if err != nil {
+		t.Fatalf("Failed to reopen index: %v", err)
+	}
+	if reopened.Len() != 4 {
+		t.Errorf("Expected 4 documents after reload, got %d", reopened.Len())
+	}
+	if reopened.Sync(SampleDocuments()) {
+		t.Error("Expected reloaded index to be up to date")
+	}
+	results, err := NewIndexSearcher(reopened, NewMockDocumentProvider(SampleDocuments())).Search(SearchOptions{Query: "reviewing"}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Document.UUID != "2" {
+		t.Errorf("Expected stemmed search on reloaded index to match document 2, got %+v", results)
+	}
+
+	// Different analysis settings discard the persisted index
+	unstemmed, err := OpenIndex(path, IndexOptions{})
+	if err != nil {
+		t.Fatalf("Failed to reopen index: %v", err)
+	}
+	if unstemmed.Len() != 0 {
+		t.Errorf("Expected index with other settings to start empty, got %d documents", unstemmed.Len())
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenIndex(path, IndexOptions{}); err == nil {
+		t.Error("Expected error for corrupt index file")
+	}
+}
+
+func TestIndexedStore_MaintainsIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.index.json")
+	index, err := OpenIndex(path, IndexOptions{})
+	if err != nil {
+		t.Fatalf("Failed to open index: %v", err)
+	}
+
+	store := &MockStore{}
+	_, _ = store.Add("Existing note", map[string]interface{}{"_body": "added before indexing"})
+
+	indexed, err := NewIndexedStore(store, index)
+	if err != nil {
+		t.Fatalf("Failed to create indexed store: %v", err)
+	}
+	search := func(query string) []SearchResult {
+		t.Helper()
+		results, err := indexed.Searcher().Search(SearchOptions{Query: query}, nil)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		return results
+	}
+
+	if len(search("existing")) != 1 {
+		t.Error("Expected documents present before attaching to be indexed")
+	}
+
+	uuid, err := indexed.Add("Grocery list", map[string]interface{}{"_body": "apples and pears"})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if len(search("pears")) != 1 {
+		t.Error("Expected added document to be searchable")
+	}
+
+	if _, err := indexed.AddDocument(types.NewDocument{Title: "Reading list", Body: "novels and poetry"}); err != nil {
+		t.Fatalf("AddDocument failed: %v", err)
+	}
+	if len(search("poetry")) != 1 {
+		t.Error("Expected document added with its body to be searchable")
+	}
+
+	body := "oranges only"
+	if err := indexed.Update(uuid, types.UpdateRequest{Body: &body}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if len(search("pears")) != 0 || len(search("oranges")) != 1 {
+		t.Error("Expected update to be reflected in the index")
+	}
+
+	if err := indexed.Delete(uuid, false); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if len(search("oranges")) != 0 {
+		t.Error("Expected deleted document to leave the index")
+	}
+
+	// The index was persisted along the way
+	reopened, err := OpenIndex(path, IndexOptions{})
+	if err != nil {
+		t.Fatalf("Failed to reopen index: %v", err)
+	}
+	if reopened.Len() != 2 {
+		t.Errorf("Expected 2 persisted documents, got %d", reopened.Len())
+	}
+}
+
+func TestIndexSearcher_FetchesScoredDocuments(t *testing.T) {
+	index := newTestIndex(t, IndexOptions{})
+	index.Sync(SampleDocuments())
+	provider := &recordingProvider{MockDocumentProvider: NewMockDocumentProvider(SampleDocuments())}
+	searcher := NewIndexSearcher(index, provider)
+
+	results, err := searcher.Search(SearchOptions{Query: "budget"}, map[string]interface{}{"status": "active"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected 2 results, got %d", len(results))
+	}
+	uuids, _ := provider.filters[0]["uuid"].([]string)
+	slices.Sort(uuids)
+	if !slices.Equal(uuids, []string{"1", "2"}) || provider.filters[0]["status"] != "active" {
+		t.Errorf("Expected only the scored documents to be fetched, got filters %v", provider.filters[0])
+	}
+
+	if _, err := searcher.Search(SearchOptions{Query: "-budget"}, nil); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if _, ok := provider.filters[1]["uuid"]; ok {
+		t.Error("Expected a negated query to fetch every document")
+	}
+}
+
+func TestIndexedStore_ReindexesTouchedDocuments(t *testing.T) {
+	store := &MockStore{}
+	parent, _ := store.Add("Trip", map[string]interface{}{"_body": "pack bags"})
+	child, _ := store.Add("Tickets", map[string]interface{}{"parent_uuid": parent, "_body": "book train"})
+	_, _ = store.Add("Other", map[string]interface{}{"_body": "unrelated"})
+
+	index := newTestIndex(t, IndexOptions{})
+	indexed, err := NewIndexedStore(store, index)
+	if err != nil {
+		t.Fatalf("Failed to create indexed store: %v", err)
+	}
+	listsEverything := func() bool {
+		for _, filters := range store.filters {
+			if len(filters) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	store.filters = nil
+	title := "Plane tickets"
+	if err := indexed.Update(child, types.UpdateRequest{Title: &title}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if listsEverything() {
+		t.Errorf("Expected only the updated document to be read, got %v", store.filters)
+	}
+
+	if err := indexed.Delete(parent, true); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if listsEverything() {
+		t.Errorf("Expected only the deleted documents to be read, got %v", store.filters)
+	}
+	if index.Len() != 1 {
+		t.Errorf("Expected the parent and its child to leave the index, got %d documents", index.Len())
+	}
+}
+
+func TestIndexedStore_BatchMutations(t *testing.T) {
+	indexed, err := NewIndexedStore(&MockStore{}, newTestIndex(t, IndexOptions{}))
+	if err != nil {
+		t.Fatalf("Failed to create indexed store: %v", err)
+	}
+	search := func(query string) int {
+		t.Helper()
+		results, err := indexed.Searcher().Search(SearchOptions{Query: query}, nil)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		return len(results)
+	}
+
+	results, err := indexed.AddMany([]types.NewDocument{{Title: "Garden", Body: "plant tulips"}, {Title: "Kitchen", Body: "fix sink"}}, true)
+	if err != nil {
+		t.Fatalf("AddMany failed: %v", err)
+	}
+	if search("tulips") != 1 || search("sink") != 1 {
+		t.Error("Expected documents added in a batch to be searchable")
+	}
+
+	body := "plant roses"
+	if _, created, err := indexed.Upsert(map[string]interface{}{"uuid": results[0].UUID}, types.NewDocument{}, types.UpdateRequest{Body: &body}); err != nil || created {
+		t.Fatalf("Upsert failed: created %v, %v", created, err)
+	}
+	if search("tulips") != 0 || search("roses") != 1 {
+		t.Error("Expected upserted document to be reindexed")
+	}
+	if _, created, err := indexed.Upsert(map[string]interface{}{"uuid": "missing"}, types.NewDocument{Title: "Garage", Body: "sweep floor"}, types.UpdateRequest{}); err != nil || !created {
+		t.Fatalf("Upsert failed: created %v, %v", created, err)
+	}
+	if search("sweep") != 1 {
+		t.Error("Expected document created by upsert to be searchable")
+	}
+
+	err = indexed.Modify(results[1].UUID, func(doc types.Document) (types.UpdateRequest, error) {
+		body := doc.Body + " and tap"
+		return types.UpdateRequest{Body: &body}, nil
+	})
+	if err != nil {
+		t.Fatalf("Modify failed: %v", err)
+	}
+	if search("tap") != 1 {
+		t.Error("Expected modified document to be reindexed")
+	}
+}
+
+func TestIndexSearcher_SyncsStaleIndex(t *testing.T) {
+	dir := t.TempDir()
+	storePath := filepath.Join(dir, "store.json")
+	options := IndexOptions{StorePath: storePath}
+	writeStore := func(contents string) {
+		t.Helper()
+		if err := os.WriteFile(storePath, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := &MockStore{}
+	_, _ = store.Add("Existing note", nil)
+	writeStore("1")
+
+	index, err := OpenIndex(IndexPath(storePath), options)
+	if err != nil {
+		t.Fatalf("Failed to open index: %v", err)
+	}
+	if !index.Stale() {
+		t.Error("Expected a new index to be stale")
+	}
+	if _, err := NewIndexedStore(store, index); err != nil {
+		t.Fatalf("Failed to create indexed store: %v", err)
+	}
+	if index.Stale() {
+		t.Error("Expected a synced index to be current")
+	}
+
+	// The revision is persisted with the index
+	reopened, err := OpenIndex(IndexPath(storePath), options)
+	if err != nil {
+		t.Fatalf("Failed to reopen index: %v", err)
+	}
+	if reopened.Stale() {
+		t.Error("Expected the reopened index to be current")
+	}
+
+	// A write that bypasses the indexed store is picked up by the next search
+	_, _ = store.Add("Written elsewhere", nil)
+	writeStore("12")
+	if !reopened.Stale() {
+		t.Fatal("Expected the index to be stale after the store file changed")
+	}
+	results, err := NewIndexSearcher(reopened, NewNanostoreAdapter(store)).Search(SearchOptions{Query: "elsewhere"}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Expected the stale index to be synced before searching, got %d results", len(results))
+	}
+	if reopened.Stale() {
+		t.Error("Expected the index to be current after searching")
+	}
+
+	// Without a store path the index cannot tell, and is never stale
+	if newTestIndex(t, IndexOptions{}).Stale() {
+		t.Error("Expected an index without a store path never to be stale")
+	}
+}
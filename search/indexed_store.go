@@ -0,0 +1,305 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// IndexedStore wraps a Store and keeps an Index up to date with its mutations.
+//
+// Every mutating call is forwarded to the wrapped store and the documents it
+// touched, known from its IDs, UUIDs or filters, are then reindexed; WHERE
+// mutations, whose documents cannot be told in advance, reconcile the whole
+// index. Only documents whose content changed are re-tokenized, and the index
+// is saved when anything changed. Reads are forwarded unchanged.
+//
+// The batch, upsert and modify methods of store.Store are covered too when
+// the wrapped store has them. Writes made directly on the wrapped store or
+// through other APIs, such as api.Store[T] or another process, are only seen
+// when the index knows the store file (IndexOptions.StorePath): the searcher
+// then syncs the index before searching it.
+//
+// # Usage Examples
+//
+//	index, err := search.OpenIndex(search.IndexPath("tasks.json"), search.IndexOptions{Stemming: true, StorePath: "tasks.json"})
+//	if err != nil {
+//	    return err
+//	}
+//	indexed, err := search.NewIndexedStore(store, index)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	_, _ = indexed.Add("Quarterly planning", nil)
+//	results, err := indexed.Searcher().Search(search.SearchOptions{Query: "plans"}, nil)
+type IndexedStore struct {
+	types.Store
+	index *Index
+}
+
+// batchStore holds the mutations of store.Store beyond types.Store
+type batchStore interface {
+	AddMany(docs []types.NewDocument, atomic bool) ([]types.AddResult, error)
+	Upsert(key map[string]interface{}, create types.NewDocument, update types.UpdateRequest) (string, bool, error)
+	Modify(id string, fn func(doc types.Document) (types.UpdateRequest, error)) error
+}
+
+// NewIndexedStore wraps store and brings index up to date with its current
+// contents, so changes made while the index was not attached are picked up.
+func NewIndexedStore(store types.Store, index *Index) (*IndexedStore, error) {
+	s := &IndexedStore{Store: store, index: index}
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Index returns the maintained index
+func (s *IndexedStore) Index() *Index {
+	return s.index
+}
+
+// Searcher returns an IndexSearcher over the wrapped store
+func (s *IndexedStore) Searcher() *IndexSearcher {
+	return NewIndexSearcher(s.index, NewNanostoreAdapter(s.Store))
+}
+
+// Add creates a document and indexes it
+func (s *IndexedStore) Add(title string, dimensions map[string]interface{}) (string, error) {
+	uuid, err := s.Store.Add(title, dimensions)
+	if err != nil {
+		return uuid, err
+	}
+	return uuid, s.reindex([]string{uuid})
+}
+
+// AddDocument creates a document with its body and indexes it
+func (s *IndexedStore) AddDocument(doc types.NewDocument) (string, error) {
+	uuid, err := s.Store.AddDocument(doc)
+	if err != nil {
+		return uuid, err
+	}
+	return uuid, s.reindex([]string{uuid})
+}
+
+// AddMany creates a batch of documents and indexes the ones added
+func (s *IndexedStore) AddMany(docs []types.NewDocument, atomic bool) ([]types.AddResult, error) {
+	store, err := s.batchStore("AddMany")
+	if err != nil {
+		return nil, err
+	}
+	results, err := store.AddMany(docs, atomic)
+	var uuids []string
+	for _, result := range results {
+		if result.Error == nil && result.UUID != "" {
+			uuids = append(uuids, result.UUID)
+		}
+	}
+	return results, s.afterMutation(uuids, err)
+}
+
+// Upsert updates the document matching key or adds a new one, and indexes it
+func (s *IndexedStore) Upsert(key map[string]interface{}, create types.NewDocument, update types.UpdateRequest) (string, bool, error) {
+	store, err := s.batchStore("Upsert")
+	if err != nil {
+		return "", false, err
+	}
+	uuid, created, err := store.Upsert(key, create, update)
+	if uuid == "" {
+		return uuid, created, err
+	}
+	return uuid, created, s.afterMutation([]string{uuid}, err)
+}
+
+// Modify updates a document from its current contents and reindexes it
+func (s *IndexedStore) Modify(id string, fn func(doc types.Document) (types.UpdateRequest, error)) error {
+	store, err := s.batchStore("Modify")
+	if err != nil {
+		return err
+	}
+	uuids := s.resolve(id)
+	return s.afterMutation(uuids, store.Modify(id, fn))
+}
+
+// Update modifies a document and reindexes it
+func (s *IndexedStore) Update(id string, updates types.UpdateRequest) error {
+	uuids := s.resolve(id)
+	return s.afterMutation(uuids, s.Store.Update(id, updates))
+}
+
+// Delete removes a document (and optionally its children) and drops them
+// from the index
+func (s *IndexedStore) Delete(id string, cascade bool) error {
+	uuids := s.resolve(id)
+	if cascade && len(uuids) > 0 {
+		descendants, err := s.descendants(uuids[0])
+		if err != nil {
+			return err
+		}
+		uuids = append(uuids, descendants...)
+	}
+	return s.afterMutation(uuids, s.Store.Delete(id, cascade))
+}
+
+// DeleteByDimension removes matching documents and drops them from the index
+func (s *IndexedStore) DeleteByDimension(filters map[string]interface{}) (int, error) {
+	uuids, err := s.matching(filters)
+	if err != nil {
+		return 0, err
+	}
+	count, err := s.Store.DeleteByDimension(filters)
+	return count, s.afterMutation(uuids, err)
+}
+
+// DeleteWhere removes matching documents and refreshes the index
+func (s *IndexedStore) DeleteWhere(whereClause string, args ...interface{}) (int, error) {
+	count, err := s.Store.DeleteWhere(whereClause, args...)
+	return count, s.afterBulkMutation(err)
+}
+
+// UpdateByDimension updates matching documents and reindexes them
+func (s *IndexedStore) UpdateByDimension(filters map[string]interface{}, updates types.UpdateRequest) (int, error) {
+	uuids, err := s.matching(filters)
+	if err != nil {
+		return 0, err
+	}
+	count, err := s.Store.UpdateByDimension(filters, updates)
+	return count, s.afterMutation(uuids, err)
+}
+
+// UpdateWhere updates matching documents and refreshes the index
+func (s *IndexedStore) UpdateWhere(whereClause string, updates types.UpdateRequest, args ...interface{}) (int, error) {
+	count, err := s.Store.UpdateWhere(whereClause, updates, args...)
+	return count, s.afterBulkMutation(err)
+}
+
+// UpdateByUUIDs updates the given documents and reindexes them
+func (s *IndexedStore) UpdateByUUIDs(uuids []string, updates types.UpdateRequest) (int, error) {
+	count, err := s.Store.UpdateByUUIDs(uuids, updates)
+	return count, s.afterMutation(uuids, err)
+}
+
+// DeleteByUUIDs deletes the given documents and drops them from the index
+func (s *IndexedStore) DeleteByUUIDs(uuids []string) (int, error) {
+	count, err := s.Store.DeleteByUUIDs(uuids)
+	return count, s.afterMutation(uuids, err)
+}
+
+// batchStore returns the wrapped store's batch mutations, failing the named
+// method when it has none
+func (s *IndexedStore) batchStore(method string) (batchStore, error) {
+	store, ok := s.Store.(batchStore)
+	if !ok {
+		return nil, fmt.Errorf("wrapped store does not support %s", method)
+	}
+	return store, nil
+}
+
+// afterMutation reindexes the documents a mutation touched. Failed
+// mutations may still have changed some of them, so they are reindexed
+// regardless and the mutation error takes precedence.
+func (s *IndexedStore) afterMutation(uuids []string, mutationErr error) error {
+	if err := s.reindex(uuids); err != nil && mutationErr == nil {
+		return err
+	}
+	return mutationErr
+}
+
+// afterBulkMutation refreshes the whole index after a WHERE mutation, whose
+// documents the wrapped store does not report
+func (s *IndexedStore) afterBulkMutation(mutationErr error) error {
+	if err := s.refresh(); err != nil && mutationErr == nil {
+		return err
+	}
+	return mutationErr
+}
+
+// resolve returns the UUID of the document id refers to, or none when it
+// does not exist, in which case the mutation fails without touching the index
+func (s *IndexedStore) resolve(id string) []string {
+	uuid, err := s.Store.ResolveUUID(id)
+	if err != nil {
+		return nil
+	}
+	return []string{uuid}
+}
+
+// matching returns the UUIDs of the documents matching filters, read before
+// a mutation changes or removes them
+func (s *IndexedStore) matching(filters map[string]interface{}) ([]string, error) {
+	docs, err := s.Store.List(types.ListOptions{Filters: filters})
+	if err != nil {
+		return nil, fmt.Errorf("failed to index documents: %w", err)
+	}
+	uuids := make([]string, len(docs))
+	for i, doc := range docs {
+		uuids[i] = doc.UUID
+	}
+	return uuids, nil
+}
+
+// descendants returns the UUIDs of the documents below uuid, one level of
+// the hierarchy at a time
+func (s *IndexedStore) descendants(uuid string) ([]string, error) {
+	var found []string
+	seen := map[string]bool{uuid: true}
+	parents := []string{uuid}
+	for len(parents) > 0 {
+		children, err := s.matching(map[string]interface{}{"parent.uuid": parents})
+		if err != nil {
+			return nil, err
+		}
+		parents = nil
+		for _, child := range children {
+			if !seen[child] {
+				seen[child] = true
+				found = append(found, child)
+				parents = append(parents, child)
+			}
+		}
+	}
+	return found, nil
+}
+
+// reindex brings the given documents up to date, dropping the ones no
+// longer in the store, and saves the index when it changed
+func (s *IndexedStore) reindex(uuids []string) error {
+	if len(uuids) == 0 {
+		return nil
+	}
+	docs, err := s.Store.List(types.ListOptions{Filters: map[string]interface{}{"uuid": uuids}})
+	if err != nil {
+		return fmt.Errorf("failed to index documents: %w", err)
+	}
+
+	changed := false
+	present := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		present[doc.UUID] = true
+		if s.index.Put(doc) {
+			changed = true
+		}
+	}
+	for _, uuid := range uuids {
+		if !present[uuid] && s.index.Remove(uuid) {
+			changed = true
+		}
+	}
+	if changed {
+		return s.index.Save()
+	}
+	return nil
+}
+
+// refresh reconciles the index with the store and saves it when it changed
+func (s *IndexedStore) refresh() error {
+	docs, err := s.Store.List(types.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to index documents: %w", err)
+	}
+	if s.index.Sync(docs) {
+		return s.index.Save()
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package search
 
 import (
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/arthur-debert/nanostore/types"
@@ -90,3 +92,156 @@ func SampleDocuments() []types.Document {
 		},
 	}
 }
+
+// MockStore is a minimal in-memory types.Store for testing IndexedStore.
+// Only the methods used by the tests are implemented.
+type MockStore struct {
+	types.Store
+	documents []types.Document
+	nextID    int
+
+	// filters records the filters of every List call
+	filters []map[string]interface{}
+}
+
+// List returns the stored documents, honouring "uuid" and "parent.uuid"
+// filters, with a single value or a list of them
+func (m *MockStore) List(opts types.ListOptions) ([]types.Document, error) {
+	m.filters = append(m.filters, opts.Filters)
+	var result []types.Document
+	for _, doc := range m.documents {
+		if uuids, ok := opts.Filters["uuid"]; ok && !mockMatches(doc.UUID, uuids) {
+			continue
+		}
+		if parents, ok := opts.Filters["parent.uuid"]; ok && !mockMatches(fmt.Sprint(doc.Dimensions["parent_uuid"]), parents) {
+			continue
+		}
+		result = append(result, doc)
+	}
+	return result, nil
+}
+
+// mockMatches checks a value against a filter holding one value or a list
+func mockMatches(value string, filter interface{}) bool {
+	if list, ok := filter.([]string); ok {
+		return slices.Contains(list, value)
+	}
+	return filter == value
+}
+
+// ResolveUUID returns the UUID of an existing document
+func (m *MockStore) ResolveUUID(id string) (string, error) {
+	for _, doc := range m.documents {
+		if doc.UUID == id {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("document not found: %s", id)
+}
+
+// Add appends a document, taking the body from the "_body" dimension
+func (m *MockStore) Add(title string, dimensions map[string]interface{}) (string, error) {
+	m.nextID++
+	doc := types.Document{
+		UUID:       fmt.Sprintf("uuid-%d", m.nextID),
+		Title:      title,
+		Dimensions: make(map[string]interface{}),
+	}
+	for key, value := range dimensions {
+		if key == "_body" {
+			doc.Body = value.(string)
+			continue
+		}
+		doc.Dimensions[key] = value
+	}
+	m.documents = append(m.documents, doc)
+	return doc.UUID, nil
+}
+
+// AddDocument appends a document with its body
+func (m *MockStore) AddDocument(doc types.NewDocument) (string, error) {
+	uuid, err := m.Add(doc.Title, doc.Dimensions)
+	if err != nil {
+		return "", err
+	}
+	m.documents[len(m.documents)-1].Body = doc.Body
+	return uuid, nil
+}
+
+// Update applies title and body changes
+func (m *MockStore) Update(id string, updates types.UpdateRequest) error {
+	for i := range m.documents {
+		if m.documents[i].UUID == id {
+			if updates.Title != nil {
+				m.documents[i].Title = *updates.Title
+			}
+			if updates.Body != nil {
+				m.documents[i].Body = *updates.Body
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("document not found: %s", id)
+}
+
+// Delete removes a document and, with cascade, its children
+func (m *MockStore) Delete(id string, cascade bool) error {
+	for i := range m.documents {
+		if m.documents[i].UUID == id {
+			m.documents = append(m.documents[:i], m.documents[i+1:]...)
+			if cascade {
+				for _, doc := range slices.Clone(m.documents) {
+					if doc.Dimensions["parent_uuid"] == id {
+						_ = m.Delete(doc.UUID, true)
+					}
+				}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("document not found: %s", id)
+}
+
+// AddMany appends a batch of documents
+func (m *MockStore) AddMany(docs []types.NewDocument, atomic bool) ([]types.AddResult, error) {
+	results := make([]types.AddResult, len(docs))
+	for i, doc := range docs {
+		results[i].UUID, results[i].Error = m.AddDocument(doc)
+	}
+	return results, nil
+}
+
+// Upsert updates the document whose UUID is key["uuid"], or adds create
+func (m *MockStore) Upsert(key map[string]interface{}, create types.NewDocument, update types.UpdateRequest) (string, bool, error) {
+	if uuid, err := m.ResolveUUID(fmt.Sprint(key["uuid"])); err == nil {
+		return uuid, false, m.Update(uuid, update)
+	}
+	uuid, err := m.AddDocument(create)
+	return uuid, true, err
+}
+
+// Modify applies the update fn returns for the current document
+func (m *MockStore) Modify(id string, fn func(doc types.Document) (types.UpdateRequest, error)) error {
+	for _, doc := range m.documents {
+		if doc.UUID == id {
+			updates, err := fn(doc)
+			if err != nil {
+				return err
+			}
+			return m.Update(id, updates)
+		}
+	}
+	return fmt.Errorf("document not found: %s", id)
+}
+
+// recordingProvider is a DocumentProvider that records the filters it gets
+type recordingProvider struct {
+	*MockDocumentProvider
+	filters []map[string]interface{}
+}
+
+// GetDocuments records filters and returns the mock documents
+func (p *recordingProvider) GetDocuments(filters map[string]interface{}) ([]types.Document, error) {
+	p.filters = append(p.filters, filters)
+	return p.MockDocumentProvider.GetDocuments(filters)
+}
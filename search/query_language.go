@@ -0,0 +1,488 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+// Query is a parsed search query.
+//
+// The query language combines plain words with a few operators:
+//
+//	budget review          both words (implicit AND)
+//	"quarterly budget"     exact phrase
+//	title:groceries        word scoped to a field
+//	title:"weekly plan"    phrase scoped to a field
+//	status:done            dimension value
+//	_data.assignee:alice   custom data field (also assignee:alice)
+//	-archived              exclusion (also NOT archived)
+//	budget OR review       either side
+//	(budget OR cost) -draft grouping
+//
+// OR binds looser than the implicit AND. Field scopes name "title", "body",
+// a dimension, or a custom data field with or without its "_data." prefix.
+// Dimension scopes compare whole values; other scopes match within the text.
+// Parsing is lenient and never fails: unbalanced quotes and parentheses are
+// closed at the end of the input, and dangling operators are ignored.
+type Query struct {
+	// Root is the top-level node, or nil when the query has no terms
+	Root Node
+}
+
+// Node is a node of a parsed query: *Term, *And, *Or or *Not
+type Node interface {
+	String() string
+}
+
+// Term matches a word or phrase, optionally scoped to a field
+type Term struct {
+	// Field is the scope of the term; empty means the default search fields
+	Field string
+
+	// Text is the word or phrase to match
+	Text string
+
+	// Phrase is true for quoted terms, whose words must appear together and
+	// in order
+	Phrase bool
+}
+
+// And matches when all its nodes match
+type And struct {
+	Nodes []Node
+}
+
+// Or matches when any of its nodes matches
+type Or struct {
+	Nodes []Node
+}
+
+// Not matches when its node does not match
+type Not struct {
+	Node Node
+}
+
+// String returns the term in query syntax
+func (t *Term) String() string {
+	text := t.Text
+	if t.Phrase {
+		text = `"` + text + `"`
+	}
+	if t.Field != "" {
+		return t.Field + ":" + text
+	}
+	return text
+}
+
+// String returns the conjunction in query syntax
+func (a *And) String() string {
+	parts := make([]string, len(a.Nodes))
+	for i, node := range a.Nodes {
+		parts[i] = node.String()
+		if _, ok := node.(*Or); ok {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// String returns the disjunction in query syntax
+func (o *Or) String() string {
+	parts := make([]string, len(o.Nodes))
+	for i, node := range o.Nodes {
+		parts[i] = node.String()
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// String returns the negation in query syntax
+func (n *Not) String() string {
+	switch n.Node.(type) {
+	case *And, *Or:
+		return "-(" + n.Node.String() + ")"
+	}
+	return "-" + n.Node.String()
+}
+
+// String returns the query in normalized query syntax
+func (q *Query) String() string {
+	if q.Root == nil {
+		return ""
+	}
+	return q.Root.String()
+}
+
+// IsEmpty reports whether the query has no terms
+func (q *Query) IsEmpty() bool {
+	return q.Root == nil
+}
+
+// Terms returns the terms that contribute matches, i.e. those that are not
+// negated, in query order
+func (q *Query) Terms() []*Term {
+	var terms []*Term
+	var walk func(node Node)
+	walk = func(node Node) {
+		switch n := node.(type) {
+		case *Term:
+			terms = append(terms, n)
+		case *And:
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *Or:
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		}
+	}
+	if q.Root != nil {
+		walk(q.Root)
+	}
+	return terms
+}
+
+// Evaluate reports whether the query matches, using match to decide
+// whether each term matches. An empty query matches everything.
+func (q *Query) Evaluate(match func(term *Term) bool) bool {
+	if q.Root == nil {
+		return true
+	}
+	return evaluate(q.Root, match)
+}
+
+func evaluate(node Node, match func(term *Term) bool) bool {
+	switch n := node.(type) {
+	case *Term:
+		return match(n)
+	case *And:
+		for _, child := range n.Nodes {
+			if !evaluate(child, match) {
+				return false
+			}
+		}
+		return true
+	case *Or:
+		for _, child := range n.Nodes {
+			if evaluate(child, match) {
+				return true
+			}
+		}
+		return false
+	case *Not:
+		return !evaluate(n.Node, match)
+	}
+	return false
+}
+
+// Matches reports whether doc satisfies the query. Unscoped terms match as
+// case-insensitive substrings of the title or body; scoped terms follow the
+// rules described on Query.
+func (q *Query) Matches(doc types.Document) bool {
+	return q.Evaluate(func(term *Term) bool {
+		if term.Field == "" {
+			text := strings.ToLower(term.Text)
+			return strings.Contains(strings.ToLower(doc.Title), text) ||
+				strings.Contains(strings.ToLower(doc.Body), text)
+		}
+		return matchScopedTerm(doc, term)
+	})
+}
+
+// matchScopedTerm matches a field-scoped term case-insensitively
+func matchScopedTerm(doc types.Document, term *Term) bool {
+	key, ok := resolveField(doc, term.Field)
+	if !ok {
+		return false
+	}
+
+	text := strings.ToLower(term.Text)
+	switch key {
+	case "title":
+		return strings.Contains(strings.ToLower(doc.Title), text)
+	case "body":
+		return strings.Contains(strings.ToLower(doc.Body), text)
+	}
+
+	for _, value := range valueStrings(doc.Dimensions[key]) {
+		value = strings.ToLower(value)
+		if value == text || (strings.HasPrefix(key, "_data.") && strings.Contains(value, text)) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveField maps a field scope to "title", "body" or a Dimensions key.
+// Custom data fields may be named with or without their "_data." prefix.
+func resolveField(doc types.Document, field string) (string, bool) {
+	switch field {
+	case "title", "body":
+		return field, true
+	}
+	if _, ok := doc.Dimensions[field]; ok {
+		return field, true
+	}
+	if _, ok := doc.Dimensions["_data."+field]; ok {
+		return "_data." + field, true
+	}
+	return "", false
+}
+
+// valueStrings returns the string forms of a field value; slices yield one
+// string per element
+func valueStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprintf("%v", item))
+		}
+		return values
+	}
+	return []string{fmt.Sprintf("%v", value)}
+}
+
+// queryTokenKind identifies the lexical elements of the query language
+type queryTokenKind int
+
+const (
+	queryTokenTerm queryTokenKind = iota
+	queryTokenOpen
+	queryTokenClose
+	queryTokenOr
+	queryTokenNot
+)
+
+type queryToken struct {
+	kind queryTokenKind
+	term *Term
+}
+
+// ParseQuery parses text in the search query language described on Query
+func ParseQuery(text string) *Query {
+	parser := &queryParser{tokens: lexQuery(text)}
+
+	var nodes []Node
+	for {
+		if node := parser.parseOr(); node != nil {
+			nodes = append(nodes, node)
+		}
+		if parser.pos >= len(parser.tokens) {
+			break
+		}
+		parser.pos++ // Skip an unbalanced closing parenthesis
+	}
+
+	switch len(nodes) {
+	case 0:
+		return &Query{}
+	case 1:
+		return &Query{Root: nodes[0]}
+	}
+	return &Query{Root: &And{Nodes: nodes}}
+}
+
+// lexQuery splits query text into tokens
+func lexQuery(text string) []queryToken {
+	var tokens []queryToken
+	runes := []rune(text)
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: queryTokenOpen})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: queryTokenClose})
+			i++
+		case r == '-':
+			// A leading dash negates what follows it directly
+			if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+				tokens = append(tokens, queryToken{kind: queryTokenNot})
+			}
+			i++
+		case r == '"':
+			phrase, next := readPhrase(runes, i+1)
+			if phrase != "" {
+				tokens = append(tokens, queryToken{kind: queryTokenTerm, term: &Term{Text: phrase, Phrase: true}})
+			}
+			i = next
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()"`, runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+
+			// field:"phrase"
+			if field, ok := fieldScope(word); ok && i < len(runes) && runes[i] == '"' {
+				phrase, next := readPhrase(runes, i+1)
+				i = next
+				if phrase != "" {
+					tokens = append(tokens, queryToken{kind: queryTokenTerm, term: &Term{Field: field, Text: phrase, Phrase: true}})
+				}
+				continue
+			}
+
+			switch word {
+			case "OR":
+				tokens = append(tokens, queryToken{kind: queryTokenOr})
+				continue
+			case "NOT":
+				tokens = append(tokens, queryToken{kind: queryTokenNot})
+				continue
+			case "AND":
+				// AND is implicit between terms
+				continue
+			}
+
+			// A "name:" prefix followed by "/" starts a URL, not a scope
+			term := &Term{Text: word}
+			if colon := strings.IndexRune(word, ':'); colon > 0 && colon < len(word)-1 && word[colon+1] != '/' {
+				if field, ok := fieldScope(word[:colon+1]); ok {
+					term = &Term{Field: field, Text: word[colon+1:]}
+				}
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenTerm, term: term})
+		}
+	}
+	return tokens
+}
+
+// readPhrase reads up to the closing quote (or the end of input) and returns
+// the trimmed phrase and the position after it
+func readPhrase(runes []rune, start int) (string, int) {
+	end := start
+	for end < len(runes) && runes[end] != '"' {
+		end++
+	}
+	next := end
+	if next < len(runes) {
+		next++ // Skip the closing quote
+	}
+	return strings.TrimSpace(string(runes[start:end])), next
+}
+
+// fieldScope recognizes "name:" prefixes, where name is an identifier
+// optionally containing dots (e.g. "_data.assignee"). Words such as "10:30"
+// or "http://..." are not scopes.
+func fieldScope(word string) (string, bool) {
+	if !strings.HasSuffix(word, ":") {
+		return "", false
+	}
+	name := strings.TrimSuffix(word, ":")
+	if name == "" {
+		return "", false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case i > 0 && (unicode.IsDigit(r) || r == '.'):
+		default:
+			return "", false
+		}
+	}
+	return name, true
+}
+
+// queryParser is a recursive descent parser over query tokens
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() (queryToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return queryToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// parseOr parses and-expressions separated by OR
+func (p *queryParser) parseOr() Node {
+	var nodes []Node
+	for {
+		if node := p.parseAnd(); node != nil {
+			nodes = append(nodes, node)
+		}
+		tok, ok := p.peek()
+		if !ok || tok.kind != queryTokenOr {
+			break
+		}
+		p.pos++
+	}
+
+	switch len(nodes) {
+	case 0:
+		return nil
+	case 1:
+		return nodes[0]
+	}
+	return &Or{Nodes: nodes}
+}
+
+// parseAnd parses a sequence of unary expressions up to OR, a closing
+// parenthesis or the end of input
+func (p *queryParser) parseAnd() Node {
+	var nodes []Node
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind == queryTokenOr {
+			break
+		}
+		if tok.kind == queryTokenClose {
+			break
+		}
+		if node := p.parseUnary(); node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+
+	switch len(nodes) {
+	case 0:
+		return nil
+	case 1:
+		return nodes[0]
+	}
+	return &And{Nodes: nodes}
+}
+
+// parseUnary parses an optionally negated term or group
+func (p *queryParser) parseUnary() Node {
+	tok, _ := p.peek()
+	p.pos++
+
+	switch tok.kind {
+	case queryTokenNot:
+		next, ok := p.peek()
+		if !ok || next.kind == queryTokenOr || next.kind == queryTokenClose {
+			return nil
+		}
+		if node := p.parseUnary(); node != nil {
+			return &Not{Node: node}
+		}
+		return nil
+	case queryTokenOpen:
+		node := p.parseOr()
+		if next, ok := p.peek(); ok && next.kind == queryTokenClose {
+			p.pos++
+		}
+		return node
+	case queryTokenTerm:
+		return tok.term
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"budget", "budget"},
+		{"budget review", "budget review"},
+		{`"quarterly budget"`, `"quarterly budget"`},
+		{`title:groceries`, "title:groceries"},
+		{`title:"weekly plan" -done`, `title:"weekly plan" -done`},
+		{"_data.assignee:alice", "_data.assignee:alice"},
+		{"budget OR review", "budget OR review"},
+		{"a b OR c", "a b OR c"},
+		{"(budget OR cost) -draft", "(budget OR cost) -draft"},
+		{"-(draft OR archived) plan", "-(draft OR archived) plan"},
+		{"NOT draft", "-draft"},
+		{"budget AND review", "budget review"},
+
+		// Words that are not field scopes stay literal
+		{"10:30", "10:30"},
+		{"http://example.com", "http://example.com"},
+		{"title:", "title:"},
+
+		// Lenient parsing
+		{`"unclosed phrase`, `"unclosed phrase"`},
+		{"(budget OR cost", "budget OR cost"},
+		{"budget ) review", "budget review"},
+		{"OR budget OR", "budget"},
+		{"budget -", "budget"},
+		{`""`, ""},
+	}
+
+	for _, tt := range tests {
+		if got := ParseQuery(tt.input).String(); got != tt.expected {
+			t.Errorf("ParseQuery(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestParseQuery_Terms(t *testing.T) {
+	query := ParseQuery(`budget -draft (status:done OR "weekly plan")`)
+
+	var terms []string
+	for _, term := range query.Terms() {
+		terms = append(terms, term.String())
+	}
+
+	expected := []string{"budget", "status:done", `"weekly plan"`}
+	if len(terms) != len(expected) {
+		t.Fatalf("Expected terms %v, got %v", expected, terms)
+	}
+	for i := range expected {
+		if terms[i] != expected[i] {
+			t.Errorf("Expected terms %v, got %v", expected, terms)
+			break
+		}
+	}
+}
+
+func TestParseQuery_URLs(t *testing.T) {
+	const url = "https://example.com/docs"
+
+	term, ok := ParseQuery(url).Root.(*Term)
+	if !ok || term.Field != "" || term.Text != url {
+		t.Fatalf("Expected an unscoped term for %q, got %#v", url, ParseQuery(url).Root)
+	}
+
+	docs := []types.Document{
+		{UUID: "1", Title: "Docs site", Body: "Published at " + url},
+		{UUID: "2", Title: "Other site", Body: "Published at https://other.org"},
+	}
+	if !ParseQuery(url).Matches(docs[0]) || ParseQuery(url).Matches(docs[1]) {
+		t.Errorf("Expected %q to match the document containing it only", url)
+	}
+
+	index := newTestIndex(t, IndexOptions{})
+	index.Sync(docs)
+	provider := NewMockDocumentProvider(docs)
+	for name, searcher := range map[string]Searcher{
+		"Engine":        NewEngine(provider),
+		"IndexSearcher": NewIndexSearcher(index, provider),
+	} {
+		results, err := searcher.Search(SearchOptions{Query: url}, nil)
+		if err != nil {
+			t.Fatalf("%s: search failed: %v", name, err)
+		}
+		if len(results) != 1 || results[0].Document.UUID != "1" {
+			t.Errorf("%s: expected the document containing %q, got %d results", name, url, len(results))
+		}
+	}
+}
+
+func TestQuery_Matches(t *testing.T) {
+	doc := types.Document{
+		Title: "Weekly groceries",
+		Body:  "Buy whole milk and bread",
+		Dimensions: map[string]interface{}{
+			"status":         "pending",
+			"_data.assignee": "Alice Smith",
+			"_data.tags":     []interface{}{"urgent", "home"},
+		},
+	}
+
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{"groceries", true},
+		{"GROCERIES milk", true},
+		{"groceries cheese", false},
+		{`"whole milk"`, true},
+		{`"milk whole"`, false},
+		{"title:groceries", true},
+		{"title:milk", false},
+		{`body:"and bread"`, true},
+		{"status:pending", true},
+		{"status:pend", false}, // dimensions compare whole values
+		{"status:done", false},
+		{"assignee:alice", true}, // data fields match within the text
+		{"_data.assignee:smith", true},
+		{"tags:urgent", true},
+		{"tags:work", false},
+		{"unknown:value", false},
+		{"-milk", false},
+		{"-cheese", true},
+		{"cheese OR milk", true},
+		{"cheese OR wine", false},
+		{"(cheese OR milk) -bread", false},
+		{"groceries -(cheese OR wine)", true},
+	}
+
+	for _, tt := range tests {
+		if got := ParseQuery(tt.query).Matches(doc); got != tt.expected {
+			t.Errorf("Matches(%q) = %v, expected %v", tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestEngine_Search_QueryLanguage(t *testing.T) {
+	provider := NewMockDocumentProvider(SampleDocuments())
+	engine := NewEngine(provider)
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"budget meeting", []string{"1", "2"}},
+		{`"meeting notes"`, []string{"2"}},
+		{"title:meeting", []string{"1", "4"}},
+		{"meeting -budget", []string{"3", "4"}},
+		{"status:done", []string{"3"}},
+		{"meeting status:pending", []string{"1", "4"}},
+		{"assigned_to:alice", []string{"1", "3"}},
+		{"standup OR review", []string{"2", "3"}},
+		{"(standup OR review) -title:budget", []string{"3"}},
+	}
+
+	for _, tt := range tests {
+		results, err := engine.Search(SearchOptions{Query: tt.query}, nil)
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", tt.query, err)
+		}
+
+		got := make(map[string]bool)
+		for _, result := range results {
+			got[result.Document.UUID] = true
+		}
+		if len(got) != len(tt.expected) {
+			t.Errorf("Search(%q) returned %d documents, expected %v", tt.query, len(got), tt.expected)
+			continue
+		}
+		for _, uuid := range tt.expected {
+			if !got[uuid] {
+				t.Errorf("Search(%q) is missing document %s", tt.query, uuid)
+			}
+		}
+	}
+}
+
+func TestEngine_Search_QueryLanguageHighlights(t *testing.T) {
+	provider := NewMockDocumentProvider(SampleDocuments())
+	engine := NewEngine(provider)
+
+	results, err := engine.Search(SearchOptions{
+		Query:               `budget "the meeting" -standup`,
+		EnableHighlight:     true,
+		IncludeMatchDetails: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Document.UUID != "2" {
+		t.Fatalf("Expected Budget Review only, got %d results", len(results))
+	}
+
+	result := results[0]
+	if result.Highlights["title"] != "**Budget** Review" {
+		t.Errorf("Unexpected title highlight %q", result.Highlights["title"])
+	}
+	if result.Highlights["body"] != "Review **the meeting** notes from last quarter" {
+		t.Errorf("Unexpected body highlight %q", result.Highlights["body"])
+	}
+	if result.MatchType != MatchPartialTitle {
+		t.Errorf("Expected title to be the best match, got %s", result.MatchType)
+	}
+}
+
+func TestIndexSearcher_QueryLanguage(t *testing.T) {
+	index := newTestIndex(t, IndexOptions{})
+	index.Sync(SampleDocuments())
+	searcher := NewIndexSearcher(index, NewMockDocumentProvider(SampleDocuments()))
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"budget meeting", []string{"1", "2"}},
+		{`"meeting notes"`, []string{"2"}},
+		{`"notes meeting"`, nil},
+		{"title:meeting", []string{"1", "4"}},
+		{"meeting -budget", []string{"3", "4"}},
+		{"meeting status:done", []string{"3"}},
+		{"-meeting", nil},
+		{"-standup", []string{"1", "2", "4"}},
+	}
+
+	for _, tt := range tests {
+		results, err := searcher.Search(SearchOptions{Query: tt.query}, nil)
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", tt.query, err)
+		}
+
+		got := make(map[string]bool)
+		for _, result := range results {
+			got[result.Document.UUID] = true
+		}
+		if len(got) != len(tt.expected) {
+			t.Errorf("Search(%q) returned %d documents, expected %v", tt.query, len(got), tt.expected)
+			continue
+		}
+		for _, uuid := range tt.expected {
+			if !got[uuid] {
+				t.Errorf("Search(%q) is missing document %s", tt.query, uuid)
+			}
+		}
+	}
+}
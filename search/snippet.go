@@ -0,0 +1,130 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// snippetEllipsis marks text cut from either side of a snippet
+const snippetEllipsis = "..."
+
+// buildSnippets extracts excerpts of text around the matches: each match is
+// extended by up to context characters on both sides, windows that overlap
+// are merged, and cuts are moved inwards so they never split a word. When
+// startMarker and endMarker are set, matches are wrapped with them. Matches
+// must be sorted and must not overlap.
+func buildSnippets(text string, matches []MatchInfo, context int, startMarker, endMarker string) []string {
+	type window struct {
+		start, end int
+		matches    []MatchInfo
+	}
+
+	var windows []window
+	for _, match := range matches {
+		start := wordStartAfter(text, backRunes(text, match.Start, context), match.Start)
+		end := wordEndBefore(text, forwardRunes(text, match.End, context), match.End)
+
+		if n := len(windows); n > 0 && start <= windows[n-1].end {
+			last := &windows[n-1]
+			if end > last.end {
+				last.end = end
+			}
+			last.matches = append(last.matches, match)
+			continue
+		}
+		windows = append(windows, window{start: start, end: end, matches: []MatchInfo{match}})
+	}
+
+	snippets := make([]string, 0, len(windows))
+	for _, w := range windows {
+		var builder strings.Builder
+		if w.start > 0 {
+			builder.WriteString(snippetEllipsis)
+		}
+
+		position := w.start
+		for _, match := range w.matches {
+			builder.WriteString(text[position:match.Start])
+			builder.WriteString(startMarker)
+			builder.WriteString(text[match.Start:match.End])
+			builder.WriteString(endMarker)
+			position = match.End
+		}
+		builder.WriteString(text[position:w.end])
+
+		if w.end < len(text) {
+			builder.WriteString(snippetEllipsis)
+		}
+		snippets = append(snippets, builder.String())
+	}
+	return snippets
+}
+
+// backRunes returns the byte offset n runes before position (or 0)
+func backRunes(text string, position, n int) int {
+	for ; n > 0 && position > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(text[:position])
+		position -= size
+	}
+	return position
+}
+
+// forwardRunes returns the byte offset n runes after position (or len(text))
+func forwardRunes(text string, position, n int) int {
+	for ; n > 0 && position < len(text); n-- {
+		_, size := utf8.DecodeRuneInString(text[position:])
+		position += size
+	}
+	return position
+}
+
+// wordStartAfter moves a snippet start forward, up to limit, so it does not
+// begin in the middle of a word, and skips leading spaces and punctuation
+func wordStartAfter(text string, start, limit int) int {
+	if start > 0 {
+		previous, _ := utf8.DecodeLastRuneInString(text[:start])
+		for start < limit && isWordRune(previous) {
+			r, size := utf8.DecodeRuneInString(text[start:])
+			if !isWordRune(r) {
+				break
+			}
+			start += size
+		}
+	}
+	for start < limit {
+		r, size := utf8.DecodeRuneInString(text[start:])
+		if isWordRune(r) {
+			break
+		}
+		start += size
+	}
+	return start
+}
+
+// wordEndBefore moves a snippet end backwards, down to limit, so it does not
+// end in the middle of a word, and drops trailing spaces
+func wordEndBefore(text string, end, limit int) int {
+	if end < len(text) {
+		next, _ := utf8.DecodeRuneInString(text[end:])
+		for end > limit && isWordRune(next) {
+			r, size := utf8.DecodeLastRuneInString(text[:end])
+			if !isWordRune(r) {
+				break
+			}
+			end -= size
+		}
+	}
+	for end > limit {
+		r, size := utf8.DecodeLastRuneInString(text[:end])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		end -= size
+	}
+	return end
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,145 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// matchesOf returns non-overlapping matches for each occurrence of the words
+func matchesOf(text string, words ...string) []MatchInfo {
+	var matches []MatchInfo
+	for _, word := range words {
+		offset := 0
+		for {
+			i := strings.Index(text[offset:], word)
+			if i < 0 {
+				break
+			}
+			start := offset + i
+			matches = append(matches, MatchInfo{Start: start, End: start + len(word), Text: word})
+			offset = start + len(word)
+		}
+	}
+	return withoutOverlaps(matches)
+}
+
+func TestBuildSnippets(t *testing.T) {
+	text := "The quarterly budget review covers spending, forecasts and the budget for next year."
+
+	tests := []struct {
+		name     string
+		words    []string
+		context  int
+		expected []string
+	}{
+		{
+			name:     "word boundaries",
+			words:    []string{"spending"},
+			context:  10,
+			expected: []string{"...covers **spending**,..."},
+		},
+		{
+			name:     "separate windows",
+			words:    []string{"quarterly", "next"},
+			context:  5,
+			expected: []string{"The **quarterly**...", "...for **next** year..."},
+		},
+		{
+			name:     "overlapping windows merge",
+			words:    []string{"budget", "review"},
+			context:  12,
+			expected: []string{"...quarterly **budget** **review** covers...", "...and the **budget** for next..."},
+		},
+		{
+			name:     "match at text edges",
+			words:    []string{"The", "year"},
+			context:  4,
+			expected: []string{"**The**...", "...**year**."},
+		},
+		{
+			name:     "short context keeps the match",
+			words:    []string{"forecasts"},
+			context:  1,
+			expected: []string{"...**forecasts**..."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildSnippets(text, matchesOf(text, tt.words...), tt.context, "**", "**")
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuildSnippets_Unicode(t *testing.T) {
+	text := "Réunion à Zürich: déjà vu über alles"
+	got := buildSnippets(text, matchesOf(text, "Zürich"), 4, "[", "]")
+	expected := []string{"...à [Zürich]:..."}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestEngine_Search_Snippets(t *testing.T) {
+	provider := NewMockDocumentProvider(SampleDocuments())
+	engine := NewEngine(provider)
+
+	results, err := engine.Search(SearchOptions{
+		Query:          "meeting",
+		Fields:         []string{"body"},
+		SnippetContext: 8,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	for _, result := range results {
+		if result.Document.UUID != "3" {
+			continue
+		}
+		expected := []string{"...standup meeting for..."}
+		if !reflect.DeepEqual(result.Snippets["body"], expected) {
+			t.Errorf("Expected plain snippets %q, got %q", expected, result.Snippets["body"])
+		}
+	}
+
+	results, err = engine.Search(SearchOptions{
+		Query:           "budget",
+		SnippetContext:  10,
+		EnableHighlight: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	for _, result := range results {
+		if result.Document.UUID != "1" {
+			continue
+		}
+		expected := []string{"...quarterly **budget** and..."}
+		if !reflect.DeepEqual(result.Snippets["body"], expected) {
+			t.Errorf("Expected highlighted snippets %q, got %q", expected, result.Snippets["body"])
+		}
+	}
+}
+
+func TestIndexSearcher_Snippets(t *testing.T) {
+	index := newTestIndex(t, IndexOptions{})
+	index.Sync(SampleDocuments())
+	searcher := NewIndexSearcher(index, NewMockDocumentProvider(SampleDocuments()))
+
+	results, err := searcher.Search(SearchOptions{Query: "notes", SnippetContext: 10, EnableHighlight: true}, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	expected := []string{"...meeting **notes** from last..."}
+	if !reflect.DeepEqual(results[0].Snippets["body"], expected) {
+		t.Errorf("Expected %q, got %q", expected, results[0].Snippets["body"])
+	}
+}
@@ -4,7 +4,8 @@ import "github.com/arthur-debert/nanostore/types"
 
 // SearchOptions configures search behavior
 type SearchOptions struct {
-	// Query is the search term(s) to look for
+	// Query is the search term(s) to look for, in the query language
+	// described on Query (phrases, field scopes, -exclusions and OR)
 	Query string
 
 	// Fields specifies which fields to search in
@@ -17,8 +18,25 @@ type SearchOptions struct {
 
 	// ExactMatch requires the entire field to match the query
 	// When false, performs partial/substring matching
+	// ExactMatch takes precedence over MaxEdits, Prefix and AnyOrder
 	ExactMatch bool
 
+	// MaxEdits enables typo-tolerant matching: query words match field words
+	// within this many Damerau-Levenshtein edits (insertions, deletions,
+	// substitutions or transpositions of adjacent characters). 0 disables it.
+	// Words are never allowed as many edits as they have characters.
+	MaxEdits int
+
+	// Prefix matches query words against the beginning of field words, for
+	// search-as-you-type ("meet" matches "meeting")
+	Prefix bool
+
+	// AnyOrder relaxes quoted phrases: they match when every word of the
+	// phrase appears somewhere in the field, regardless of order or adjacency
+	// ("\"budget review\"" matches "review the quarterly budget"). Unquoted
+	// query words always match independently of each other.
+	AnyOrder bool
+
 	// EnableHighlight includes highlighted match text in results
 	EnableHighlight bool
 
@@ -31,6 +49,12 @@ type SearchOptions struct {
 	// IncludeMatchDetails includes structured match position data in results
 	IncludeMatchDetails bool
 
+	// SnippetContext adds context snippets to results when positive: excerpts
+	// of up to this many characters on each side of every match, merged when
+	// they overlap and never cutting words. Matches inside snippets carry the
+	// highlight markers when EnableHighlight is true.
+	SnippetContext int
+
 	// MaxResults limits the number of search results
 	// nil means no limit
 	MaxResults *int
@@ -90,6 +114,11 @@ type SearchResult struct {
 	// Only populated if IncludeMatchDetails is true
 	FieldMatches []FieldMatch
 
+	// Snippets contains the context snippets of each matched field, in text
+	// order, with "..." marking cut text
+	// Only populated if SnippetContext is positive
+	Snippets map[string][]string
+
 	// MatchType describes the primary/best match type found
 	MatchType MatchType
 
@@ -105,6 +134,10 @@ const (
 	MatchPartialTitle MatchType = "partial_title"
 	MatchExactBody    MatchType = "exact_body"
 	MatchPartialBody  MatchType = "partial_body"
+	MatchFuzzyTitle   MatchType = "fuzzy_title"
+	MatchFuzzyBody    MatchType = "fuzzy_body"
+	MatchPrefixTitle  MatchType = "prefix_title"
+	MatchPrefixBody   MatchType = "prefix_body"
 	MatchDimension    MatchType = "dimension"
 	MatchCustomData   MatchType = "custom_data"
 )
@@ -113,7 +146,8 @@ const (
 // This allows for dependency injection and easy mocking in tests
 type DocumentProvider interface {
 	// GetDocuments returns all documents that match the given filters
-	// This integrates with existing nanostore filtering
+	// This integrates with existing nanostore filtering, including a "uuid"
+	// filter holding a single UUID or a list of them
 	GetDocuments(filters map[string]interface{}) ([]types.Document, error)
 }
 
@@ -7,15 +7,15 @@ import (
 
 // CanonicalFilter represents a filter for the canonical view
 type CanonicalFilter struct {
-	Dimension string
-	Value     string
+	Dimension string `json:"dimension"`
+	Value     string `json:"value"`
 }
 
 // CanonicalView defines which documents appear in the canonical (default) view
 type CanonicalView struct {
 	// Filters define the dimension filters for the canonical view
 	// Only documents matching ALL filters appear in the canonical view
-	Filters []CanonicalFilter
+	Filters []CanonicalFilter `json:"filters"`
 }
 
 // NewCanonicalView creates a new canonical view with the given filters
@@ -25,6 +25,87 @@ func NewCanonicalView(filters ...CanonicalFilter) *CanonicalView {
 	}
 }
 
+// DefaultCanonicalView returns the canonical view derived from a dimension
+// set: each enumerated dimension at its default value and any value for
+// hierarchical dimensions
+func DefaultCanonicalView(ds *DimensionSet) *CanonicalView {
+	var filters []CanonicalFilter
+	for _, dim := range ds.Enumerated() {
+		if dim.DefaultValue != "" {
+			filters = append(filters, CanonicalFilter{
+				Dimension: dim.Name,
+				Value:     dim.DefaultValue,
+			})
+		}
+	}
+	// Hierarchical dimensions default to "*" (any value)
+	for _, dim := range ds.Hierarchical() {
+		filters = append(filters, CanonicalFilter{
+			Dimension: dim.Name,
+			Value:     "*",
+		})
+	}
+	return NewCanonicalView(filters...)
+}
+
+// Validate checks that the view's filters name configured dimensions and,
+// for enumerated dimensions, one of their values. "*" matches any value, so
+// every value of that dimension keeps its prefix in SimpleIDs.
+func (cv *CanonicalView) Validate(ds *DimensionSet) error {
+	if cv == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range cv.Filters {
+		dim, exists := ds.Get(f.Dimension)
+		if !exists {
+			return fmt.Errorf("canonical view: unknown dimension %q", f.Dimension)
+		}
+		if seen[f.Dimension] {
+			return fmt.Errorf("canonical view: dimension %q is listed more than once", f.Dimension)
+		}
+		seen[f.Dimension] = true
+
+		switch dim.Type {
+		case Enumerated:
+			if f.Value != "*" && len(dim.Values) > 0 && !dim.IsValid(f.Value) {
+				return fmt.Errorf("canonical view: invalid value %q for dimension %q (valid values: %s)",
+					f.Value, f.Dimension, strings.Join(dim.Values, ", "))
+			}
+		case Hierarchical:
+			if f.Value != "*" {
+				return fmt.Errorf("canonical view: hierarchical dimension %q only accepts \"*\"", f.Dimension)
+			}
+		case Set:
+			return fmt.Errorf("canonical view: set dimension %q does not appear in SimpleIDs", f.Dimension)
+		}
+	}
+	return nil
+}
+
+// With returns a copy of the view with the filter for dimension set to value,
+// leaving the view itself unchanged
+//
+//	lens := store.CanonicalView().With("status", "done")
+func (cv *CanonicalView) With(dimension, value string) *CanonicalView {
+	view := &CanonicalView{}
+	replaced := false
+	if cv != nil {
+		for _, f := range cv.Filters {
+			if f.Dimension == dimension {
+				f.Value = value
+				replaced = true
+			}
+			view.Filters = append(view.Filters, f)
+		}
+	}
+	if !replaced {
+		view.Filters = append(view.Filters, CanonicalFilter{Dimension: dimension, Value: value})
+	}
+	return view
+}
+
 // String returns a string representation of the canonical view
 func (cv *CanonicalView) String() string {
 	if cv == nil || len(cv.Filters) == 0 {
@@ -67,24 +148,7 @@ type ConfigWithCanonicalView struct {
 // GetCanonicalView returns the canonical view, creating a default if needed
 func (c *ConfigWithCanonicalView) GetCanonicalView() *CanonicalView {
 	if c.CanonicalView == nil {
-		// Default canonical view based on dimension defaults
-		var filters []CanonicalFilter
-		for _, dim := range c.GetDimensionSet().Enumerated() {
-			if dim.DefaultValue != "" {
-				filters = append(filters, CanonicalFilter{
-					Dimension: dim.Name,
-					Value:     dim.DefaultValue,
-				})
-			}
-		}
-		// Hierarchical dimensions default to "*" (any value)
-		for _, dim := range c.GetDimensionSet().Hierarchical() {
-			filters = append(filters, CanonicalFilter{
-				Dimension: dim.Name,
-				Value:     "*",
-			})
-		}
-		c.CanonicalView = NewCanonicalView(filters...)
+		c.CanonicalView = DefaultCanonicalView(c.GetDimensionSet())
 	}
 	return c.CanonicalView
 }
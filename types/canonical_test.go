@@ -132,4 +132,69 @@ func TestCanonicalView(t *testing.T) {
 			t.Errorf("Default parent filter should be '*', got %q", val)
 		}
 	})
+
+	t.Run("With", func(t *testing.T) {
+		cv := NewCanonicalView(
+			CanonicalFilter{Dimension: "status", Value: "pending"},
+			CanonicalFilter{Dimension: "parent", Value: "*"},
+		)
+
+		lens := cv.With("status", "done").With("priority", "high")
+		if got := lens.String(); got != "canonical:status:done,parent:*,priority:high" {
+			t.Errorf("With(): got %q", got)
+		}
+		if got := cv.String(); got != "canonical:status:pending,parent:*" {
+			t.Errorf("With() should not change the original view, got %q", got)
+		}
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		config := Config{
+			Dimensions: []DimensionConfig{
+				{Name: "status", Type: Enumerated, Values: []string{"pending", "active", "done"}, DefaultValue: "pending"},
+				{Name: "parent", Type: Hierarchical, RefField: "parent_uuid"},
+			},
+		}
+		ds := config.GetDimensionSet()
+
+		valid := []*CanonicalView{
+			nil,
+			NewCanonicalView(),
+			NewCanonicalView(CanonicalFilter{Dimension: "status", Value: "active"}),
+			NewCanonicalView(CanonicalFilter{Dimension: "status", Value: "*"}, CanonicalFilter{Dimension: "parent", Value: "*"}),
+		}
+		for _, cv := range valid {
+			if err := cv.Validate(ds); err != nil {
+				t.Errorf("Validate(%s): unexpected error %v", cv, err)
+			}
+		}
+
+		invalid := []*CanonicalView{
+			NewCanonicalView(CanonicalFilter{Dimension: "owner", Value: "alice"}),
+			NewCanonicalView(CanonicalFilter{Dimension: "status", Value: "archived"}),
+			NewCanonicalView(CanonicalFilter{Dimension: "parent", Value: "1"}),
+			NewCanonicalView(CanonicalFilter{Dimension: "status", Value: "done"}, CanonicalFilter{Dimension: "status", Value: "active"}),
+		}
+		for _, cv := range invalid {
+			if err := cv.Validate(ds); err == nil {
+				t.Errorf("Validate(%s): expected an error", cv)
+			}
+		}
+	})
+
+	t.Run("ConfigCanonicalView", func(t *testing.T) {
+		config := &Config{
+			Dimensions: []DimensionConfig{
+				{Name: "status", Type: Enumerated, Values: []string{"pending", "active"}, DefaultValue: "pending"},
+			},
+		}
+		if got := config.GetCanonicalView().String(); got != "canonical:status:pending" {
+			t.Errorf("default view: got %q", got)
+		}
+
+		config.CanonicalView = NewCanonicalView(CanonicalFilter{Dimension: "status", Value: "active"})
+		if got := config.GetCanonicalView().String(); got != "canonical:status:active" {
+			t.Errorf("configured view: got %q", got)
+		}
+	})
 }
@@ -13,6 +13,9 @@ const (
 	Enumerated DimensionType = iota
 	// Hierarchical dimensions create parent-child relationships
 	Hierarchical
+	// Set dimensions hold any number of values (e.g., tags, labels), stored
+	// as a JSON array. They filter documents but never partition SimpleIDs
+	Set
 )
 
 // String returns the string representation of the DimensionType
@@ -22,6 +25,8 @@ func (dt DimensionType) String() string {
 		return "enumerated"
 	case Hierarchical:
 		return "hierarchical"
+	case Set:
+		return "set"
 	default:
 		return "unknown"
 	}
@@ -44,8 +49,10 @@ func (dt *DimensionType) UnmarshalJSON(data []byte) error {
 		*dt = Enumerated
 	case "hierarchical":
 		*dt = Hierarchical
+	case "set":
+		*dt = Set
 	default:
-		return fmt.Errorf("invalid dimension type: %q (must be 'enumerated' or 'hierarchical')", s)
+		return fmt.Errorf("invalid dimension type: %q (must be 'enumerated', 'hierarchical' or 'set')", s)
 	}
 
 	return nil
@@ -59,7 +66,8 @@ type DimensionConfig struct {
 	// Type specifies whether this is an enumerated or hierarchical dimension
 	Type DimensionType `json:"type"`
 
-	// Values lists the valid values for enumerated dimensions
+	// Values lists the valid values for enumerated dimensions, and the valid
+	// members of set dimensions (empty allows any member)
 	// Ignored for hierarchical dimensions
 	Values []string `json:"values,omitempty"`
 
@@ -76,6 +84,19 @@ type DimensionConfig struct {
 	// DefaultValue specifies the default value for enumerated dimensions
 	// Used when inserting new documents without explicit value
 	DefaultValue string `json:"default_value,omitempty"`
+
+	// Transitions lists the allowed value changes of an enumerated dimension
+	// as "from->to" rules, where "*" matches any value
+	// Empty allows every change
+	Transitions []Transition `json:"transitions,omitempty"`
+
+	// TrackChanges records when the value of an enumerated dimension last
+	// changed, under the "_data.<name>_changed_at" key
+	TrackChanges bool `json:"track_changes,omitempty"`
+
+	// Compute makes an enumerated dimension computed: its value is derived
+	// from the documents at query time and never stored
+	Compute ComputeFunc `json:"-"`
 }
 
 // Config defines the overall configuration for the nanostore
@@ -83,6 +104,17 @@ type Config struct {
 	// Dimensions defines the ID partitioning dimensions
 	Dimensions []DimensionConfig `json:"dimensions"`
 
+	// CanonicalView decides which dimension values are omitted from SimpleIDs
+	// nil derives it from the dimension defaults
+	CanonicalView *CanonicalView `json:"canonical_view,omitempty"`
+
+	// IDScheme decides how SimpleIDs are written
+	// nil uses DefaultIDScheme ("1.d3")
+	IDScheme *IDScheme `json:"id_scheme,omitempty"`
+
+	// Unique lists the constraints keeping data fields unique across documents
+	Unique []UniqueConstraint `json:"unique,omitempty"`
+
 	// dimensionSet is the new internal representation
 	// Will be populated from Dimensions during initialization
 	dimensionSet *DimensionSet `json:"-"`
@@ -120,6 +152,23 @@ func (c Config) GetDimension(name string) (*DimensionConfig, bool) {
 	return nil, false
 }
 
+// GetCanonicalView returns the configured canonical view, or the one derived
+// from the dimension defaults when none is set
+func (c *Config) GetCanonicalView() *CanonicalView {
+	if c.CanonicalView != nil {
+		return c.CanonicalView
+	}
+	return DefaultCanonicalView(c.GetDimensionSet())
+}
+
+// GetIDScheme returns the configured ID scheme, or the default one
+func (c *Config) GetIDScheme() IDScheme {
+	if c.IDScheme != nil {
+		return *c.IDScheme
+	}
+	return DefaultIDScheme()
+}
+
 // GetDimensionSet returns the dimension set, initializing it if needed
 func (c *Config) GetDimensionSet() *DimensionSet {
 	if c.dimensionSet == nil {
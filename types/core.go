@@ -18,33 +18,97 @@ type ListOptions struct {
 	// Filters allows filtering by any configured dimension
 	// Key is dimension name, value can be a single value or slice of values
 	// Example: {"status": []string{"active", "pending"}, "priority": "high"}
-	Filters map[string]interface{}
+	Filters map[string]interface{} `json:"filters,omitempty"`
 
 	// FilterBySearch performs a text search on title and body
+	// The text uses the search query language: "exact phrases", field scopes
+	// such as title:groceries or status:done, -exclusions and OR
 	// Empty string returns all documents (no filtering)
-	FilterBySearch string
+	FilterBySearch string `json:"search,omitempty"`
 
 	// OrderBy specifies the order of results
 	// Each OrderClause contains a field name and direction
-	OrderBy []OrderClause
+	OrderBy []OrderClause `json:"order_by,omitempty"`
 
 	// Limit specifies the maximum number of results to return
 	// nil or negative values mean no limit
 	// 0 returns no results
-	Limit *int
+	Limit *int `json:"limit,omitempty"`
 
 	// Offset specifies the number of results to skip
 	// nil or negative values mean no offset (start from beginning)
 	// Values greater than result count return empty results
-	Offset *int
+	Offset *int `json:"offset,omitempty"`
+
+	// Cursor resumes a previous page of results (keyset pagination)
+	// The value is an opaque token produced by query.EncodeCursor for the last
+	// document of the previous page, using the same OrderBy clauses
+	// Only documents sorting strictly after the cursor are returned, so pages
+	// stay stable when documents are added or removed between requests
+	// Without OrderBy, cursors follow creation order (created_at, then uuid)
+	// Empty string means start from the beginning
+	Cursor string `json:"cursor,omitempty"`
+
+	// ExcludeBody leaves Document.Body empty in the results
+	// Stores that keep bodies outside the main file skip reading them,
+	// unless FilterBySearch needs the body content
+	ExcludeBody bool `json:"exclude_body,omitempty"`
+
+	// CanonicalView lists the documents through an alternate canonical view
+	// SimpleIDs in the results omit the prefixes of this view's values
+	// instead of the store's, and Store.ResolveUUIDInView accepts them
+	// nil uses the store's canonical view
+	CanonicalView *CanonicalView `json:"canonical_view,omitempty"`
 }
 
 // OrderClause represents a single ORDER BY clause
+//
+// Values are compared by type: numbers numerically, times chronologically,
+// enumerated dimensions by their declared value order and everything else as text
+//
+// Two special columns follow the SimpleIDs users see:
+//   - "simple_id" orders IDs naturally, segment by segment, with numeric
+//     positions and prefixed segments grouped after unprefixed ones (1, 2, 10, d1)
+//   - "tree" orders depth-first, each document after its parent and siblings
+//     by position (1, 1.1, d1, 2)
 type OrderClause struct {
-	Column     string
-	Descending bool
+	Column     string `json:"column"`
+	Descending bool   `json:"descending,omitempty"`
+
+	// Nulls controls where documents without a value for Column are placed
+	// By default missing values sort as if larger than any other value
+	// (last when ascending, first when descending)
+	Nulls NullsOrder `json:"nulls,omitempty"`
+
+	// Collation controls how text values are compared
+	Collation Collation `json:"collation,omitempty"`
 }
 
+// NullsOrder specifies the placement of missing values in an ordering
+type NullsOrder int
+
+const (
+	// NullsDefault treats missing values as larger than any other value
+	NullsDefault NullsOrder = iota
+	// NullsFirst places missing values first regardless of direction
+	NullsFirst
+	// NullsLast places missing values last regardless of direction
+	NullsLast
+)
+
+// Collation specifies how text values are compared when ordering
+type Collation int
+
+const (
+	// CollationBinary compares text byte by byte (the default)
+	CollationBinary Collation = iota
+	// CollationCaseInsensitive compares text ignoring letter case
+	CollationCaseInsensitive
+	// CollationNatural compares text ignoring case, with runs of digits
+	// compared by numeric value ("item 2" sorts before "item 10")
+	CollationNatural
+)
+
 // NewListOptions creates a new ListOptions with empty filters
 func NewListOptions() ListOptions {
 	return ListOptions{
@@ -59,6 +123,29 @@ type UpdateRequest struct {
 	Dimensions map[string]interface{} // Optional: dimension values to update (e.g., "status": "completed", "parent_uuid": "some-uuid")
 }
 
+// NewDocument describes a document to add with its body, in one operation
+// or a batch
+type NewDocument struct {
+	Title      string
+	Body       string
+	Dimensions map[string]interface{}
+
+	// CreatedAt, when set, is recorded as the creation time instead of the
+	// current time, as imports need
+	CreatedAt time.Time
+
+	// ParentIndex, when set, makes the document a child of the document at
+	// that index of the same batch, which must come before it
+	ParentIndex *int
+}
+
+// AddResult holds the outcome of adding one document of a batch: its UUID,
+// or the error that kept it from being added
+type AddResult struct {
+	UUID  string
+	Error error
+}
+
 // Store defines the public interface for the document store
 type Store interface {
 	// List returns documents based on the provided options
@@ -73,6 +160,10 @@ type Store interface {
 	// Returns the UUID of the created document
 	Add(title string, dimensions map[string]interface{}) (string, error)
 
+	// AddDocument adds a document with its body, an optional creation time
+	// and dimensions in one operation, returning its UUID
+	AddDocument(doc NewDocument) (string, error)
+
 	// Update modifies an existing document
 	Update(id string, updates UpdateRequest) error
 
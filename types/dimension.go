@@ -1,5 +1,7 @@
 package types
 
+import "fmt"
+
 // DimensionMetadata holds metadata about a dimension
 type DimensionMetadata struct {
 	// Order in which this dimension appears in partitioning
@@ -11,6 +13,20 @@ type DimensionMetadata struct {
 	IsCanonical bool
 }
 
+// ComputeFunc derives the values of a computed dimension. It is called with
+// every document being evaluated, so values can depend on related documents
+// such as children, and returns the function giving each document's value.
+// An empty value, or one the dimension does not declare, stands for the
+// dimension's default.
+type ComputeFunc func(docs []Document) func(doc Document) string
+
+// PerDocument adapts a function of a single document to a ComputeFunc
+func PerDocument(fn func(doc Document) string) ComputeFunc {
+	return func([]Document) func(Document) string {
+		return fn
+	}
+}
+
 // Dimension represents a single dimension in the partitioning system
 type Dimension struct {
 	// Name is the identifier for this dimension
@@ -27,15 +43,29 @@ type Dimension struct {
 	Prefixes     map[string]string // Value to prefix mapping
 	DefaultValue string            // Default when not specified
 
+	// Compute derives the value at query time for computed dimensions,
+	// which are never stored
+	Compute ComputeFunc
+
+	// Transitions restrict how the value may change on update
+	// Empty allows every change
+	Transitions []Transition
+
+	// TrackChanges records the time of each change under ChangedAtKey
+	TrackChanges bool
+
 	// For hierarchical dimensions
 	RefField string // Foreign key field name (e.g., "parent_uuid")
 }
 
 // IsValid checks if a value is valid for this dimension
 func (d *Dimension) IsValid(value string) bool {
-	if d.Type != Enumerated {
+	if d.Type == Hierarchical {
 		return true // Hierarchical dimensions accept any value
 	}
+	if d.Type == Set && len(d.Values) == 0 {
+		return true // Free-form sets accept any member
+	}
 
 	for _, v := range d.Values {
 		if v == value {
@@ -58,6 +88,11 @@ func (d *Dimension) GetPrefix(value string) string {
 	return prefix
 }
 
+// IsComputed reports whether the dimension's values are derived at query time
+func (d *Dimension) IsComputed() bool {
+	return d.Compute != nil
+}
+
 // HasPrefix checks if this dimension has any prefixes defined
 func (d *Dimension) HasPrefix() bool {
 	return len(d.Prefixes) > 0
@@ -118,6 +153,82 @@ func (ds *DimensionSet) Hierarchical() []Dimension {
 	return result
 }
 
+// Sets returns only set dimensions
+func (ds *DimensionSet) Sets() []Dimension {
+	var result []Dimension
+	for _, dim := range ds.dimensions {
+		if dim.Type == Set {
+			result = append(result, dim)
+		}
+	}
+	return result
+}
+
+// Computed returns only computed dimensions
+func (ds *DimensionSet) Computed() []Dimension {
+	var result []Dimension
+	for _, dim := range ds.dimensions {
+		if dim.IsComputed() {
+			result = append(result, dim)
+		}
+	}
+	return result
+}
+
+// SetCompute makes an enumerated dimension computed. Register computations
+// before the set is used: stores read it without locking.
+func (ds *DimensionSet) SetCompute(name string, fn ComputeFunc) error {
+	dim, exists := ds.byName[name]
+	if !exists {
+		return fmt.Errorf("unknown dimension: %s", name)
+	}
+	if dim.Type != Enumerated {
+		return fmt.Errorf("dimension %q is not enumerated and cannot be computed", name)
+	}
+	if fn == nil {
+		return fmt.Errorf("dimension %q: compute function cannot be nil", name)
+	}
+	dim.Compute = fn
+	return nil
+}
+
+// ComputeDimensions returns the documents with the values of the computed
+// dimensions filled in. The documents passed in are not modified; when no
+// dimension is computed they are returned as is.
+func ComputeDimensions(docs []Document, ds *DimensionSet) []Document {
+	computed := ds.Computed()
+	if len(computed) == 0 {
+		return docs
+	}
+
+	valueFuncs := make([]func(Document) string, len(computed))
+	for i, dim := range computed {
+		valueFuncs[i] = dim.Compute(docs)
+	}
+
+	result := make([]Document, len(docs))
+	for i, doc := range docs {
+		dimensions := make(map[string]interface{}, len(doc.Dimensions)+len(computed))
+		for key, value := range doc.Dimensions {
+			dimensions[key] = value
+		}
+		for j, dim := range computed {
+			value := valueFuncs[j](doc)
+			if !dim.IsValid(value) {
+				value = dim.DefaultValue
+			}
+			if value == "" {
+				delete(dimensions, dim.Name)
+			} else {
+				dimensions[dim.Name] = value
+			}
+		}
+		doc.Dimensions = dimensions
+		result[i] = doc
+	}
+	return result
+}
+
 // Count returns the number of dimensions
 func (ds *DimensionSet) Count() int {
 	return len(ds.dimensions)
@@ -135,6 +246,9 @@ func DimensionSetFromConfig(config Config) *DimensionSet {
 			Prefixes:     dc.Prefixes,
 			DefaultValue: dc.DefaultValue,
 			RefField:     dc.RefField,
+			Compute:      dc.Compute,
+			Transitions:  dc.Transitions,
+			TrackChanges: dc.TrackChanges,
 			Meta: DimensionMetadata{
 				Order:       i,
 				IsCanonical: true, // Will be updated when we add canonical view
@@ -236,3 +236,64 @@ func TestNewListOptions(t *testing.T) {
 		t.Error("failed to add filter to initialized map")
 	}
 }
+
+func TestComputeDimensions(t *testing.T) {
+	config := types.Config{
+		Dimensions: []types.DimensionConfig{
+			{Name: "status", Type: types.Enumerated, Values: []string{"pending", "done"}, DefaultValue: "pending"},
+			{Name: "big", Type: types.Enumerated, Values: []string{"no", "yes"}, DefaultValue: "no"},
+			{Name: "parent", Type: types.Hierarchical, RefField: "parent_id"},
+		},
+	}
+	ds := config.GetDimensionSet()
+
+	docs := []types.Document{
+		{UUID: "a", Title: "A long title", Dimensions: map[string]interface{}{"status": "pending"}},
+		{UUID: "b", Title: "B", Dimensions: map[string]interface{}{"status": "done", "big": "yes"}},
+		{UUID: "c", Title: "C", Dimensions: map[string]interface{}{"status": "done"}},
+	}
+
+	// Without computed dimensions the documents are returned unchanged
+	if got := types.ComputeDimensions(docs, ds); &got[0] != &docs[0] {
+		t.Error("expected the same documents when nothing is computed")
+	}
+
+	if err := ds.SetCompute("parent", types.PerDocument(func(types.Document) string { return "" })); err == nil {
+		t.Error("expected an error computing a hierarchical dimension")
+	}
+	if err := ds.SetCompute("missing", types.PerDocument(func(types.Document) string { return "" })); err == nil {
+		t.Error("expected an error computing an unknown dimension")
+	}
+
+	err := ds.SetCompute("big", types.PerDocument(func(doc types.Document) string {
+		switch {
+		case len(doc.Title) > 5:
+			return "yes"
+		case doc.Title == "C":
+			return "maybe"
+		}
+		return ""
+	}))
+	if err != nil {
+		t.Fatalf("SetCompute failed: %v", err)
+	}
+	if len(ds.Computed()) != 1 {
+		t.Fatalf("expected one computed dimension, got %d", len(ds.Computed()))
+	}
+
+	computed := types.ComputeDimensions(docs, ds)
+	if computed[0].Dimensions["big"] != "yes" {
+		t.Errorf("expected A to be big, got %v", computed[0].Dimensions["big"])
+	}
+	// Empty values fall back to the default, replacing any stored value
+	if computed[1].Dimensions["big"] != "no" {
+		t.Errorf("expected B to use the default, got %v", computed[1].Dimensions["big"])
+	}
+	// So do values the dimension does not declare
+	if computed[2].Dimensions["big"] != "no" {
+		t.Errorf("expected C to use the default, got %v", computed[2].Dimensions["big"])
+	}
+	if _, exists := docs[0].Dimensions["big"]; exists || docs[1].Dimensions["big"] != "yes" {
+		t.Error("ComputeDimensions must not modify its input")
+	}
+}
@@ -0,0 +1,295 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IDNumbering defines how positions are written in SimpleIDs
+type IDNumbering int
+
+const (
+	// NumberingDecimal writes positions as base-10 numbers (1, 2, ... 10)
+	NumberingDecimal IDNumbering = iota
+	// NumberingLetters writes positions as lowercase letters (a, b, ... z, aa)
+	NumberingLetters
+	// NumberingBase36 writes positions with digits and lowercase letters (1, ... z, 10)
+	NumberingBase36
+)
+
+// String returns the string representation of the IDNumbering
+func (n IDNumbering) String() string {
+	switch n {
+	case NumberingDecimal:
+		return "decimal"
+	case NumberingLetters:
+		return "letters"
+	case NumberingBase36:
+		return "base36"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalJSON implements json.Marshaler for IDNumbering
+func (n IDNumbering) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler for IDNumbering
+func (n *IDNumbering) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	switch s {
+	case "decimal", "":
+		*n = NumberingDecimal
+	case "letters":
+		*n = NumberingLetters
+	case "base36":
+		*n = NumberingBase36
+	default:
+		return fmt.Errorf("invalid ID numbering: %q (must be 'decimal', 'letters' or 'base36')", s)
+	}
+
+	return nil
+}
+
+// PrefixPlacement defines where dimension prefixes go in a SimpleID segment
+type PrefixPlacement int
+
+const (
+	// PrefixBefore writes prefixes before the position (d3)
+	PrefixBefore PrefixPlacement = iota
+	// PrefixAfter writes prefixes after the position (3d)
+	PrefixAfter
+)
+
+// String returns the string representation of the PrefixPlacement
+func (p PrefixPlacement) String() string {
+	switch p {
+	case PrefixBefore:
+		return "before"
+	case PrefixAfter:
+		return "after"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalJSON implements json.Marshaler for PrefixPlacement
+func (p PrefixPlacement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler for PrefixPlacement
+func (p *PrefixPlacement) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	switch s {
+	case "before", "":
+		*p = PrefixBefore
+	case "after":
+		*p = PrefixAfter
+	default:
+		return fmt.Errorf("invalid prefix placement: %q (must be 'before' or 'after')", s)
+	}
+
+	return nil
+}
+
+// maxIDPadding bounds zero-padding to keep IDs short enough to type
+const maxIDPadding = 9
+
+// IDScheme defines how SimpleIDs are written: the separator between
+// hierarchy levels, how positions are numbered and padded, where dimension
+// prefixes go and whether positions are kept stable. The zero value is the
+// default scheme ("1.d3").
+//
+// Every scheme accepted by Validate round-trips: parsing an ID produces the
+// partition it was written from.
+type IDScheme struct {
+	// Separator joins hierarchy levels: ".", "/" or "-"
+	// Empty means "."
+	Separator string `json:"separator,omitempty"`
+
+	// Numbering writes positions in decimal, letters or base36
+	Numbering IDNumbering `json:"numbering,omitempty"`
+
+	// Padding is the minimum number of digits of a position, filled with
+	// leading zeros (3 writes position 7 as "007")
+	// Only decimal and base36 numbering can be padded
+	Padding int `json:"padding,omitempty"`
+
+	// PrefixPlacement puts dimension prefixes before or after the position
+	PrefixPlacement PrefixPlacement `json:"prefix_placement,omitempty"`
+
+	// StablePositions keeps each document's position until it leaves its
+	// partition and never hands a position out twice, instead of numbering
+	// the documents present from 1. Stores persist the positions and
+	// re-pack them only when asked to (Store.Compact).
+	StablePositions bool `json:"stable_positions,omitempty"`
+}
+
+// DefaultIDScheme returns the scheme used when none is configured
+func DefaultIDScheme() IDScheme {
+	return IDScheme{Separator: "."}
+}
+
+// GetSeparator returns the hierarchy separator, defaulting to "."
+func (s IDScheme) GetSeparator() string {
+	if s.Separator == "" {
+		return "."
+	}
+	return s.Separator
+}
+
+// Validate rejects schemes whose IDs could not be parsed back unambiguously
+// with the given dimensions
+func (s IDScheme) Validate(ds *DimensionSet) error {
+	switch s.GetSeparator() {
+	case ".", "/", "-":
+	default:
+		return fmt.Errorf("ID scheme: invalid separator %q (must be \".\", \"/\" or \"-\")", s.Separator)
+	}
+
+	switch s.Numbering {
+	case NumberingDecimal, NumberingLetters, NumberingBase36:
+	default:
+		return fmt.Errorf("ID scheme: invalid numbering %d", s.Numbering)
+	}
+
+	switch s.PrefixPlacement {
+	case PrefixBefore, PrefixAfter:
+	default:
+		return fmt.Errorf("ID scheme: invalid prefix placement %d", s.PrefixPlacement)
+	}
+
+	if s.Padding < 0 || s.Padding > maxIDPadding {
+		return fmt.Errorf("ID scheme: padding must be between 0 and %d, got %d", maxIDPadding, s.Padding)
+	}
+	if s.Padding > 0 && s.Numbering == NumberingLetters {
+		return fmt.Errorf("ID scheme: letter numbering cannot be padded")
+	}
+
+	// Letters in positions cannot be told apart from prefixes
+	if s.Numbering != NumberingDecimal && ds != nil {
+		for _, dim := range ds.Enumerated() {
+			if len(dim.Prefixes) > 0 {
+				return fmt.Errorf("ID scheme: %s numbering is ambiguous with the prefixes of dimension %q (use decimal numbering or remove the prefixes)",
+					s.Numbering, dim.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// FormatPosition writes a position (1 or more) using the scheme's numbering
+// and padding
+func (s IDScheme) FormatPosition(position int) string {
+	var text string
+	switch s.Numbering {
+	case NumberingLetters:
+		// Bijective base 26: a..z, aa..zz, aaa..
+		var letters []byte
+		for n := position; n > 0; n = (n - 1) / 26 {
+			letters = append([]byte{byte('a' + (n-1)%26)}, letters...)
+		}
+		return string(letters)
+	case NumberingBase36:
+		text = strconv.FormatInt(int64(position), 36)
+	default:
+		text = strconv.Itoa(position)
+	}
+
+	if len(text) < s.Padding {
+		text = strings.Repeat("0", s.Padding-len(text)) + text
+	}
+	return text
+}
+
+// ParsePosition reads a position written by FormatPosition. Text that
+// FormatPosition would not produce, such as missing padding, is rejected so
+// every position has exactly one spelling.
+func (s IDScheme) ParsePosition(text string) (int, error) {
+	if text == "" {
+		return 0, fmt.Errorf("missing position")
+	}
+
+	var position int
+	switch s.Numbering {
+	case NumberingLetters:
+		for _, r := range text {
+			if r < 'a' || r > 'z' {
+				return 0, fmt.Errorf("invalid position: %s", text)
+			}
+			position = position*26 + int(r-'a') + 1
+		}
+	case NumberingBase36:
+		n, err := strconv.ParseInt(text, 36, 0)
+		if err != nil || strings.ToLower(text) != text {
+			return 0, fmt.Errorf("invalid position: %s", text)
+		}
+		position = int(n)
+	default:
+		n, err := strconv.Atoi(text)
+		if err != nil || strings.ContainsAny(text, "+-") {
+			return 0, fmt.Errorf("invalid position: %s", text)
+		}
+		position = n
+	}
+
+	if position < 1 || s.FormatPosition(position) != text {
+		return 0, fmt.Errorf("invalid position: %s", text)
+	}
+	return position, nil
+}
+
+// SplitSegment splits the last segment of a SimpleID into its prefix letters
+// and position. ok is false when the segment does not follow the scheme.
+func (s IDScheme) SplitSegment(segment string) (prefix string, position int, ok bool) {
+	if s.Numbering != NumberingDecimal {
+		// Validate rules out prefixes with these numberings
+		position, err := s.ParsePosition(segment)
+		return "", position, err == nil
+	}
+
+	isDigit := func(b byte) bool { return b >= '0' && b <= '9' }
+	var digits string
+	if s.PrefixPlacement == PrefixAfter {
+		i := 0
+		for i < len(segment) && isDigit(segment[i]) {
+			i++
+		}
+		digits, prefix = segment[:i], segment[i:]
+	} else {
+		i := len(segment)
+		for i > 0 && isDigit(segment[i-1]) {
+			i--
+		}
+		prefix, digits = segment[:i], segment[i:]
+	}
+
+	position, err := s.ParsePosition(digits)
+	if err != nil {
+		return "", 0, false
+	}
+	return prefix, position, true
+}
+
+// JoinSegment writes the last segment of a SimpleID from its prefix letters
+// and position
+func (s IDScheme) JoinSegment(prefix string, position int) string {
+	if s.PrefixPlacement == PrefixAfter {
+		return s.FormatPosition(position) + prefix
+	}
+	return prefix + s.FormatPosition(position)
+}
@@ -0,0 +1,157 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIDScheme(t *testing.T) {
+	t.Run("FormatPosition", func(t *testing.T) {
+		tests := []struct {
+			scheme   IDScheme
+			position int
+			expected string
+		}{
+			{IDScheme{}, 7, "7"},
+			{IDScheme{Padding: 3}, 7, "007"},
+			{IDScheme{Padding: 2}, 123, "123"},
+			{IDScheme{Numbering: NumberingLetters}, 1, "a"},
+			{IDScheme{Numbering: NumberingLetters}, 26, "z"},
+			{IDScheme{Numbering: NumberingLetters}, 27, "aa"},
+			{IDScheme{Numbering: NumberingLetters}, 703, "aaa"},
+			{IDScheme{Numbering: NumberingBase36}, 35, "z"},
+			{IDScheme{Numbering: NumberingBase36}, 36, "10"},
+			{IDScheme{Numbering: NumberingBase36, Padding: 3}, 36, "010"},
+		}
+
+		for _, tt := range tests {
+			got := tt.scheme.FormatPosition(tt.position)
+			if got != tt.expected {
+				t.Errorf("%+v FormatPosition(%d): got %q, want %q", tt.scheme, tt.position, got, tt.expected)
+			}
+		}
+	})
+
+	t.Run("PositionsRoundTrip", func(t *testing.T) {
+		schemes := []IDScheme{
+			{},
+			{Padding: 4},
+			{Numbering: NumberingLetters},
+			{Numbering: NumberingBase36},
+			{Numbering: NumberingBase36, Padding: 2},
+		}
+		for _, scheme := range schemes {
+			for position := 1; position <= 2000; position++ {
+				text := scheme.FormatPosition(position)
+				got, err := scheme.ParsePosition(text)
+				if err != nil || got != position {
+					t.Fatalf("%+v: position %d written as %q parsed as %d (%v)", scheme, position, text, got, err)
+				}
+			}
+		}
+	})
+
+	t.Run("ParsePositionRejectsOtherSpellings", func(t *testing.T) {
+		tests := []struct {
+			scheme IDScheme
+			text   string
+		}{
+			{IDScheme{}, "0"},
+			{IDScheme{}, "07"},
+			{IDScheme{}, "+7"},
+			{IDScheme{Padding: 3}, "7"},
+			{IDScheme{Numbering: NumberingLetters}, "A"},
+			{IDScheme{Numbering: NumberingLetters}, "a1"},
+			{IDScheme{Numbering: NumberingBase36}, "Z"},
+			{IDScheme{Numbering: NumberingBase36}, "0z"},
+		}
+		for _, tt := range tests {
+			if _, err := tt.scheme.ParsePosition(tt.text); err == nil {
+				t.Errorf("%+v ParsePosition(%q): expected an error", tt.scheme, tt.text)
+			}
+		}
+	})
+
+	t.Run("Segments", func(t *testing.T) {
+		tests := []struct {
+			scheme   IDScheme
+			prefix   string
+			position int
+			segment  string
+		}{
+			{IDScheme{}, "dh", 3, "dh3"},
+			{IDScheme{PrefixPlacement: PrefixAfter}, "dh", 3, "3dh"},
+			{IDScheme{PrefixPlacement: PrefixAfter, Padding: 2}, "d", 3, "03d"},
+			{IDScheme{Numbering: NumberingLetters}, "", 28, "ab"},
+		}
+		for _, tt := range tests {
+			if got := tt.scheme.JoinSegment(tt.prefix, tt.position); got != tt.segment {
+				t.Errorf("%+v JoinSegment(%q, %d): got %q, want %q", tt.scheme, tt.prefix, tt.position, got, tt.segment)
+			}
+			prefix, position, ok := tt.scheme.SplitSegment(tt.segment)
+			if !ok || prefix != tt.prefix || position != tt.position {
+				t.Errorf("%+v SplitSegment(%q): got (%q, %d, %v)", tt.scheme, tt.segment, prefix, position, ok)
+			}
+		}
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		withPrefixes := DimensionSetFromConfig(Config{Dimensions: []DimensionConfig{
+			{Name: "status", Type: Enumerated, Values: []string{"pending", "done"}, Prefixes: map[string]string{"done": "d"}},
+		}})
+		withoutPrefixes := DimensionSetFromConfig(Config{Dimensions: []DimensionConfig{
+			{Name: "status", Type: Enumerated, Values: []string{"pending", "done"}},
+		}})
+
+		valid := []struct {
+			scheme IDScheme
+			ds     *DimensionSet
+		}{
+			{IDScheme{}, withPrefixes},
+			{IDScheme{Separator: "/", Padding: 3, PrefixPlacement: PrefixAfter}, withPrefixes},
+			{IDScheme{Separator: "-", Numbering: NumberingLetters}, withoutPrefixes},
+			{IDScheme{Numbering: NumberingBase36, Padding: 2}, withoutPrefixes},
+		}
+		for _, tt := range valid {
+			if err := tt.scheme.Validate(tt.ds); err != nil {
+				t.Errorf("%+v: unexpected error %v", tt.scheme, err)
+			}
+		}
+
+		invalid := []struct {
+			scheme IDScheme
+			ds     *DimensionSet
+		}{
+			{IDScheme{Separator: ":"}, withoutPrefixes},
+			{IDScheme{Numbering: NumberingLetters}, withPrefixes},
+			{IDScheme{Numbering: NumberingBase36}, withPrefixes},
+			{IDScheme{Numbering: NumberingLetters, Padding: 2}, withoutPrefixes},
+			{IDScheme{Padding: -1}, withoutPrefixes},
+			{IDScheme{Padding: 12}, withoutPrefixes},
+		}
+		for _, tt := range invalid {
+			if err := tt.scheme.Validate(tt.ds); err == nil {
+				t.Errorf("%+v: expected an error", tt.scheme)
+			}
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		scheme := IDScheme{Separator: "/", Numbering: NumberingBase36, Padding: 2, PrefixPlacement: PrefixAfter}
+		data, err := json.Marshal(scheme)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != `{"separator":"/","numbering":"base36","padding":2,"prefix_placement":"after"}` {
+			t.Errorf("unexpected JSON: %s", data)
+		}
+
+		var decoded IDScheme
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded != scheme {
+			t.Errorf("expected %+v, got %+v", scheme, decoded)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PathValues returns the values a dot path such as "_data.address.city"
+// addresses in a document's dimensions. A key matching the whole path is
+// returned as is. Otherwise the longest key prefixing the path is followed
+// through its nested maps: lists met on the way contribute every element
+// (array-any semantics), unless the segment is a numeric index, and lists
+// found at the end of the path are expanded into their elements.
+//
+// The second result reports whether the path addresses any value.
+func PathValues(dimensions map[string]interface{}, path string) ([]interface{}, bool) {
+	if value, exists := dimensions[path]; exists {
+		return []interface{}{value}, true
+	}
+
+	for i := strings.LastIndex(path, "."); i > 0; i = strings.LastIndex(path[:i], ".") {
+		root, exists := dimensions[path[:i]]
+		if !exists {
+			continue
+		}
+		values := []interface{}{root}
+		for _, segment := range strings.Split(path[i+1:], ".") {
+			var next []interface{}
+			for _, value := range values {
+				next = append(next, pathStep(value, segment)...)
+			}
+			values = next
+		}
+
+		var result []interface{}
+		for _, value := range values {
+			if list, isList := value.([]interface{}); isList {
+				result = append(result, list...)
+			} else {
+				result = append(result, value)
+			}
+		}
+		return result, len(result) > 0
+	}
+	return nil, false
+}
+
+// pathStep returns the values one path segment addresses in a value
+func pathStep(value interface{}, segment string) []interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if child, exists := v[segment]; exists {
+			return []interface{}{child}
+		}
+	case []interface{}:
+		if index, err := strconv.Atoi(segment); err == nil {
+			if index >= 0 && index < len(v) {
+				return []interface{}{v[index]}
+			}
+			return nil
+		}
+		var result []interface{}
+		for _, item := range v {
+			result = append(result, pathStep(item, segment)...)
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package types_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// EXCEPTION: This test validates utility functions and type conversions.
+// It doesn't require store operations or fixture data.
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestPathValues(t *testing.T) {
+	dimensions := map[string]interface{}{
+		"status":        "active",
+		"_data.address": map[string]interface{}{"city": "Paris", "geo": map[string]interface{}{"lat": 48.8}},
+		"_data.subtasks": []interface{}{
+			map[string]interface{}{"estimate": 2.0, "tags": []interface{}{"a", "b"}},
+			map[string]interface{}{"estimate": 5.0},
+		},
+	}
+
+	tests := []struct {
+		path  string
+		want  []interface{}
+		found bool
+	}{
+		{"status", []interface{}{"active"}, true},
+		{"_data.address.city", []interface{}{"Paris"}, true},
+		{"_data.address.geo.lat", []interface{}{48.8}, true},
+		{"_data.subtasks.estimate", []interface{}{2.0, 5.0}, true},
+		{"_data.subtasks.1.estimate", []interface{}{5.0}, true},
+		{"_data.subtasks.tags", []interface{}{"a", "b"}, true},
+		{"_data.subtasks.7.estimate", nil, false},
+		{"_data.address.zip", nil, false},
+		{"_data.missing.city", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, found := types.PathValues(dimensions, tt.path)
+			if found != tt.found || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v (%v), got %v (%v)", tt.want, tt.found, got, found)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SetUpdate adds and removes members of a set dimension. Used as the value of
+// a set dimension in UpdateRequest.Dimensions, it changes the stored members
+// without reading them first:
+//
+//	store.Update(id, UpdateRequest{Dimensions: map[string]interface{}{
+//	    "tags": AddToSet("urgent"),
+//	}})
+//
+// Any other value replaces the members: a []string, or a comma-separated string.
+type SetUpdate struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// AddToSet returns a SetUpdate adding values to a set dimension
+func AddToSet(values ...string) SetUpdate {
+	return SetUpdate{Add: values}
+}
+
+// RemoveFromSet returns a SetUpdate removing values from a set dimension
+func RemoveFromSet(values ...string) SetUpdate {
+	return SetUpdate{Remove: values}
+}
+
+// Apply returns the members after the update, sorted
+func (u SetUpdate) Apply(members []string) []string {
+	removed := make(map[string]bool, len(u.Remove))
+	for _, value := range u.Remove {
+		removed[value] = true
+	}
+	var result []string
+	for _, value := range append(append([]string{}, members...), u.Add...) {
+		if !removed[value] {
+			result = append(result, value)
+		}
+	}
+	return NormalizeSet(result)
+}
+
+// SetMatch selects how a SetFilter compares its values with a set's members
+type SetMatch string
+
+const (
+	// MatchAny matches sets holding at least one of the values
+	MatchAny SetMatch = "any"
+	// MatchAll matches sets holding every one of the values
+	MatchAll SetMatch = "all"
+)
+
+// SetFilter matches documents by the members of a set dimension. It is used
+// as a filter value in ListOptions.Filters:
+//
+//	store.List(ListOptions{Filters: map[string]interface{}{
+//	    "tags": ContainsAny("bug", "regression"),
+//	}})
+//
+// A plain value filters sets containing it, and a slice sets containing any
+// of its values.
+type SetFilter struct {
+	Match  SetMatch `json:"match"`
+	Values []string `json:"values"`
+}
+
+// Contains returns a SetFilter matching sets that contain value
+func Contains(value string) SetFilter {
+	return SetFilter{Match: MatchAll, Values: []string{value}}
+}
+
+// ContainsAny returns a SetFilter matching sets that contain any of values
+func ContainsAny(values ...string) SetFilter {
+	return SetFilter{Match: MatchAny, Values: values}
+}
+
+// ContainsAll returns a SetFilter matching sets that contain all of values
+func ContainsAll(values ...string) SetFilter {
+	return SetFilter{Match: MatchAll, Values: values}
+}
+
+// Matches reports whether a set's members satisfy the filter. A filter
+// without values matches nothing.
+func (f SetFilter) Matches(members []string) bool {
+	if len(f.Values) == 0 {
+		return false
+	}
+	present := make(map[string]bool, len(members))
+	for _, member := range members {
+		present[member] = true
+	}
+	for _, value := range f.Values {
+		if present[value] && f.Match == MatchAny {
+			return true
+		}
+		if !present[value] && f.Match != MatchAny {
+			return false
+		}
+	}
+	return f.Match != MatchAny
+}
+
+// SetFilterOf returns the SetFilter held by a filter value. Filters loaded
+// from JSON, such as saved views, hold it as a map.
+func SetFilterOf(value interface{}) (SetFilter, bool) {
+	switch v := value.(type) {
+	case SetFilter:
+		return v, true
+	case *SetFilter:
+		if v != nil {
+			return *v, true
+		}
+	case map[string]interface{}:
+		if _, hasValues := v["values"]; !hasValues || len(v) != 2 {
+			break
+		}
+		var filter SetFilter
+		data, err := json.Marshal(v)
+		if err == nil && json.Unmarshal(data, &filter) == nil && (filter.Match == MatchAny || filter.Match == MatchAll) {
+			return filter, true
+		}
+	}
+	return SetFilter{}, false
+}
+
+// SetMembers returns the members of a stored set value: a []string, or the
+// []interface{} it becomes once loaded from JSON. A string holds one member
+// per comma-separated entry. Unset values have no members.
+func SetMembers(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []string:
+		return v
+	case []interface{}:
+		members := make([]string, 0, len(v))
+		for _, item := range v {
+			if item != nil {
+				members = append(members, fmt.Sprintf("%v", item))
+			}
+		}
+		return members
+	case string:
+		var members []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				members = append(members, item)
+			}
+		}
+		return members
+	default:
+		return []string{fmt.Sprintf("%v", v)}
+	}
+}
+
+// NormalizeSet returns the distinct members, trimmed and sorted, without
+// empty entries
+func NormalizeSet(members []string) []string {
+	seen := make(map[string]bool, len(members))
+	var result []string
+	for _, member := range members {
+		member = strings.TrimSpace(member)
+		if member != "" && !seen[member] {
+			seen[member] = true
+			result = append(result, member)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
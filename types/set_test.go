@@ -0,0 +1,87 @@
+package types_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// EXCEPTION: This test validates utility functions and type conversions.
+// It doesn't require store operations or fixture data.
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestSetUpdateApply(t *testing.T) {
+	update := types.SetUpdate{Add: []string{"docs", "bug"}, Remove: []string{"feature"}}
+	got := update.Apply([]string{"feature", "bug"})
+	if !reflect.DeepEqual(got, []string{"bug", "docs"}) {
+		t.Errorf("expected [bug docs], got %v", got)
+	}
+	if got := types.RemoveFromSet("bug").Apply([]string{"bug"}); len(got) != 0 {
+		t.Errorf("expected no members left, got %v", got)
+	}
+}
+
+func TestSetFilterMatches(t *testing.T) {
+	members := []string{"bug", "docs"}
+	tests := []struct {
+		name   string
+		filter types.SetFilter
+		want   bool
+	}{
+		{"Contains", types.Contains("bug"), true},
+		{"ContainsMissing", types.Contains("feature"), false},
+		{"ContainsAny", types.ContainsAny("feature", "docs"), true},
+		{"ContainsAnyNone", types.ContainsAny("feature", "ui"), false},
+		{"ContainsAll", types.ContainsAll("bug", "docs"), true},
+		{"ContainsAllPartial", types.ContainsAll("bug", "feature"), false},
+		{"NoValues", types.ContainsAll(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(members); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSetFilterOfJSON(t *testing.T) {
+	// Saved views hold filters as decoded JSON
+	data, err := json.Marshal(types.ContainsAny("bug", "docs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	filter, ok := types.SetFilterOf(decoded)
+	if !ok || filter.Match != types.MatchAny || !reflect.DeepEqual(filter.Values, []string{"bug", "docs"}) {
+		t.Errorf("expected the ContainsAny filter back, got %+v (%v)", filter, ok)
+	}
+	if _, ok := types.SetFilterOf(map[string]interface{}{"values": "x", "other": 1}); ok {
+		t.Error("expected an unrelated map not to be a set filter")
+	}
+}
+
+func TestSetMembers(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  []string
+	}{
+		{nil, nil},
+		{[]string{"a", "b"}, []string{"a", "b"}},
+		{[]interface{}{"a", nil, "b"}, []string{"a", "b"}},
+		{"a, b,,c", []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		if got := types.SetMembers(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SetMembers(%#v): expected %v, got %v", tt.value, tt.want, got)
+		}
+	}
+}
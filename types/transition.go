@@ -0,0 +1,135 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AnyValue matches every value of a dimension in transition rules
+const AnyValue = "*"
+
+// Transition is an allowed change of an enumerated dimension's value,
+// written "from->to". AnyValue on either side matches every value.
+type Transition struct {
+	From string
+	To   string
+}
+
+// String returns the rule as "from->to"
+func (t Transition) String() string {
+	return t.From + "->" + t.To
+}
+
+// Matches reports whether the rule allows changing from one value to another
+func (t Transition) Matches(from, to string) bool {
+	return (t.From == AnyValue || t.From == from) && (t.To == AnyValue || t.To == to)
+}
+
+// MarshalJSON implements json.Marshaler for Transition
+func (t Transition) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler for Transition
+func (t *Transition) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseTransition(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// ParseTransition parses a single "from->to" rule
+func ParseTransition(rule string) (Transition, error) {
+	from, to, found := strings.Cut(rule, "->")
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if !found || from == "" || to == "" {
+		return Transition{}, fmt.Errorf("invalid transition %q (expected 'from->to')", rule)
+	}
+	return Transition{From: from, To: to}, nil
+}
+
+// ParseTransitions parses a comma-separated list of rules, such as
+// "pending->active,active->done,*->archived"
+func ParseTransitions(spec string) ([]Transition, error) {
+	var transitions []Transition
+	for _, rule := range strings.Split(spec, ",") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+		t, err := ParseTransition(rule)
+		if err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, nil
+}
+
+// ChangedAtKey returns the document key where the time of a tracked
+// dimension's last change is recorded, such as "_data.status_changed_at"
+func ChangedAtKey(dimension string) string {
+	return "_data." + dimension + "_changed_at"
+}
+
+// TransitionError reports an update that moves an enumerated dimension along
+// a transition its rules do not allow
+type TransitionError struct {
+	Dimension string
+	From      string
+	To        string
+
+	// Allowed lists the values the dimension can change to from From
+	Allowed []string
+}
+
+// Error implements the error interface
+func (e *TransitionError) Error() string {
+	allowed := "none"
+	if len(e.Allowed) > 0 {
+		allowed = strings.Join(e.Allowed, ", ")
+	}
+	return fmt.Sprintf("dimension %q cannot change from %q to %q (allowed from %q: %s)",
+		e.Dimension, e.From, e.To, e.From, allowed)
+}
+
+// HasTransitions reports whether the dimension restricts how its value changes
+func (d *Dimension) HasTransitions() bool {
+	return len(d.Transitions) > 0
+}
+
+// AllowsTransition reports whether the value can change from one value to
+// another. Dimensions without rules allow every change, and keeping the same
+// value is always allowed.
+func (d *Dimension) AllowsTransition(from, to string) bool {
+	if !d.HasTransitions() || from == to {
+		return true
+	}
+	for _, t := range d.Transitions {
+		if t.Matches(from, to) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransition returns a *TransitionError when the change is not allowed
+func (d *Dimension) CheckTransition(from, to string) error {
+	if d.AllowsTransition(from, to) {
+		return nil
+	}
+
+	var allowed []string
+	for _, value := range d.Values {
+		if value != from && d.AllowsTransition(from, value) {
+			allowed = append(allowed, value)
+		}
+	}
+	return &TransitionError{Dimension: d.Name, From: from, To: to, Allowed: allowed}
+}
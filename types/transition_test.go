@@ -0,0 +1,100 @@
+package types_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// EXCEPTION: This test validates utility functions and type conversions.
+// It doesn't require store operations or fixture data.
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestParseTransitions(t *testing.T) {
+	transitions, err := types.ParseTransitions("pending->active, active->done,*->archived")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []types.Transition{{From: "pending", To: "active"}, {From: "active", To: "done"}, {From: "*", To: "archived"}}
+	if len(transitions) != len(expected) {
+		t.Fatalf("expected %d transitions, got %v", len(expected), transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("transition %d: expected %v, got %v", i, expected[i], transitions[i])
+		}
+	}
+
+	for _, spec := range []string{"pending", "pending->", "->done", "pending-active"} {
+		if _, err := types.ParseTransitions(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+func TestTransitionJSON(t *testing.T) {
+	config := types.DimensionConfig{
+		Name:         "status",
+		Type:         types.Enumerated,
+		Values:       []string{"pending", "done"},
+		Transitions:  []types.Transition{{From: "pending", To: "done"}},
+		TrackChanges: true,
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	var decoded types.DimensionConfig
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if len(decoded.Transitions) != 1 || decoded.Transitions[0].String() != "pending->done" || !decoded.TrackChanges {
+		t.Errorf("expected the rules to round-trip, got %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"transitions": ["pending"]}`), &decoded); err == nil {
+		t.Error("expected an error for a malformed rule")
+	}
+}
+
+func TestCheckTransition(t *testing.T) {
+	dim := types.Dimension{
+		Name:   "status",
+		Type:   types.Enumerated,
+		Values: []string{"pending", "active", "done", "archived"},
+		Transitions: []types.Transition{
+			{From: "pending", To: "active"},
+			{From: "active", To: "done"},
+			{From: "*", To: "archived"},
+		},
+	}
+
+	allowed := [][2]string{{"pending", "active"}, {"active", "done"}, {"done", "archived"}, {"pending", "archived"}, {"done", "done"}}
+	for _, change := range allowed {
+		if err := dim.CheckTransition(change[0], change[1]); err != nil {
+			t.Errorf("expected %s->%s to be allowed, got %v", change[0], change[1], err)
+		}
+	}
+
+	err := dim.CheckTransition("done", "pending")
+	var transitionErr *types.TransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("expected a TransitionError, got %v", err)
+	}
+	if transitionErr.From != "done" || transitionErr.To != "pending" || len(transitionErr.Allowed) != 1 || transitionErr.Allowed[0] != "archived" {
+		t.Errorf("unexpected error details: %+v", transitionErr)
+	}
+	if err.Error() != `dimension "status" cannot change from "done" to "pending" (allowed from "done": archived)` {
+		t.Errorf("unexpected message: %v", err)
+	}
+
+	unrestricted := types.Dimension{Name: "priority", Values: []string{"low", "high"}}
+	if !unrestricted.AllowsTransition("high", "low") {
+		t.Error("dimensions without rules should allow every change")
+	}
+}
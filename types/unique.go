@@ -0,0 +1,46 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UniqueConstraint requires documents to differ in the combined values of
+// its fields. Fields name dimensions or data fields ("_data.slug").
+// Documents that leave any of the fields unset are not constrained.
+type UniqueConstraint struct {
+	// Name identifies the constraint in errors and upserts
+	Name string `json:"name"`
+
+	// Fields lists the document keys whose values form the constraint's key
+	Fields []string `json:"fields"`
+
+	// PerParent only requires documents with the same parent to differ
+	PerParent bool `json:"per_parent,omitempty"`
+}
+
+// UniqueConflictError reports a change that would give a document the same
+// key as another document under a unique constraint
+type UniqueConflictError struct {
+	Constraint string
+	Fields     []string
+	Values     []string
+
+	// ExistingUUID is the document already holding the key
+	ExistingUUID string
+}
+
+// Error implements the error interface
+func (e *UniqueConflictError) Error() string {
+	pairs := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		pairs[i] = fmt.Sprintf("%s=%q", strings.TrimPrefix(field, "_data."), e.Values[i])
+	}
+	return fmt.Sprintf("unique constraint %q violated: %s is already used by document %s",
+		e.Constraint, strings.Join(pairs, ", "), e.ExistingUUID)
+}
+
+// GetUniqueConstraints returns the configured unique constraints
+func (c *Config) GetUniqueConstraints() []UniqueConstraint {
+	return c.Unique
+}
@@ -0,0 +1,26 @@
+package types_test
+
+// IMPORTANT: This test must follow the testing patterns established in:
+// nanostore/testutil/model_test.go
+//
+// EXCEPTION: This test validates utility functions and type conversions.
+// It doesn't require store operations or fixture data.
+
+import (
+	"testing"
+
+	"github.com/arthur-debert/nanostore/types"
+)
+
+func TestUniqueConflictErrorMessage(t *testing.T) {
+	err := &types.UniqueConflictError{
+		Constraint:   "external",
+		Fields:       []string{"_data.source", "_data.key"},
+		Values:       []string{"jira", "P-1"},
+		ExistingUUID: "abc",
+	}
+	expected := `unique constraint "external" violated: source="jira", key="P-1" is already used by document abc`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}